@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// MinAPIKeyLength is the shortest token accepted in the key file, to keep
+// guessable keys out of it.
+const MinAPIKeyLength = 16
+
+var (
+	ErrMissingCredentials = errors.New("missing bearer token or API key")
+	ErrInvalidCredentials = errors.New("invalid bearer token or API key")
+	ErrPermissionDenied   = errors.New("resource belongs to another user")
+)
+
+type userKey struct{}
+
+// withUser returns a copy of ctx carrying the authenticated user's ID.
+func withUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// userFromContext returns the ID of the user making the call. It is empty
+// when authentication is disabled, which makes every caller the same
+// anonymous user.
+func userFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// apiKey is what a token in the key file grants its holder.
+type apiKey struct {
+	user      string
+	workspace string // the only workspace the key may use; empty for any
+	admin     bool   // may manage workspaces
+}
+
+type apiKeyKey struct{}
+
+// withAPIKey returns a copy of ctx carrying the key the caller authenticated
+// with and its user's ID.
+func withAPIKey(ctx context.Context, key apiKey) context.Context {
+	return context.WithValue(withUser(ctx, key.user), apiKeyKey{}, key)
+}
+
+// apiKeyFromContext returns the key the caller authenticated with. It
+// reports false when authentication is disabled.
+func apiKeyFromContext(ctx context.Context) (apiKey, bool) {
+	key, ok := ctx.Value(apiKeyKey{}).(apiKey)
+	return key, ok
+}
+
+// apiKeyFile is the layout of the file read by loadAPIKeys:
+//
+//	{"keys": [{"user": "alice", "token": "...", "workspace": "team-a"},
+//	          {"user": "root", "token": "...", "admin": true}]}
+type apiKeyFile struct {
+	Keys []struct {
+		User      string `json:"user"`
+		Token     string `json:"token"`
+		Workspace string `json:"workspace"`
+		Admin     bool   `json:"admin"`
+	} `json:"keys"`
+}
+
+// loadAPIKeys reads the tokens that identify each user from the JSON file at
+// path. A user may have several tokens, but a token names a single user.
+func loadAPIKeys(path string) (map[string]apiKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	var file apiKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+
+	keys := make(map[string]apiKey, len(file.Keys))
+	for i, k := range file.Keys {
+		switch {
+		case strings.TrimSpace(k.User) == "":
+			return nil, fmt.Errorf("key %d in %s has no user", i, path)
+		case len(k.Token) < MinAPIKeyLength:
+			return nil, fmt.Errorf("key %d in %s is shorter than %d characters", i, path, MinAPIKeyLength)
+		case k.Workspace != "" && !validWorkspaceID(k.Workspace):
+			return nil, fmt.Errorf("key %d in %s: %w", i, path, ErrInvalidWorkspaceID)
+		}
+		if _, dup := keys[k.Token]; dup {
+			return nil, fmt.Errorf("key %d in %s is already in use", i, path)
+		}
+		keys[k.Token] = apiKey{user: strings.TrimSpace(k.User), workspace: k.Workspace, admin: k.Admin}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("key file %s defines no keys", path)
+	}
+	return keys, nil
+}
+
+// authInterceptor authenticates every call with a bearer token
+// ("Authorization: Bearer <token>") or an API key ("X-Api-Key: <token>") and
+// records the caller's key and user ID in the context.
+type authInterceptor struct {
+	// keys maps the SHA-256 digest of each token to its key. Looking up
+	// digests keeps the time taken from depending on how much of a guessed
+	// token is right.
+	keys map[[sha256.Size]byte]apiKey
+}
+
+// newAuthInterceptor returns an interceptor accepting the tokens in keys.
+func newAuthInterceptor(keys map[string]apiKey) *authInterceptor {
+	digests := make(map[[sha256.Size]byte]apiKey, len(keys))
+	for token, key := range keys {
+		digests[sha256.Sum256([]byte(token))] = key
+	}
+	return &authInterceptor{keys: digests}
+}
+
+// authenticate returns the key identified by the credentials in header.
+func (a *authInterceptor) authenticate(header http.Header) (apiKey, error) {
+	token := header.Get("X-Api-Key")
+	if auth := header.Get("Authorization"); auth != "" {
+		scheme, credentials, ok := strings.Cut(auth, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return apiKey{}, connect.NewError(connect.CodeUnauthenticated, ErrInvalidCredentials)
+		}
+		token = strings.TrimSpace(credentials)
+	}
+	if token == "" {
+		return apiKey{}, connect.NewError(connect.CodeUnauthenticated, ErrMissingCredentials)
+	}
+	key, ok := a.keys[sha256.Sum256([]byte(token))]
+	if !ok {
+		return apiKey{}, connect.NewError(connect.CodeUnauthenticated, ErrInvalidCredentials)
+	}
+	return key, nil
+}
+
+func (a *authInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		key, err := a.authenticate(req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(withAPIKey(ctx, key), req)
+	}
+}
+
+func (a *authInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (a *authInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		key, err := a.authenticate(conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(withAPIKey(ctx, key), conn)
+	}
+}
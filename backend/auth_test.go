@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+const (
+	aliceToken = "alice-token-0123456789"
+	bobToken   = "bob-token-0123456789"
+)
+
+func TestAuthInterceptorAuthenticate(t *testing.T) {
+	auth := newAuthInterceptor(map[string]apiKey{aliceToken: {user: "alice"}})
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantUser string
+		wantErr  error
+	}{
+		{name: "bearer token", header: http.Header{"Authorization": {"Bearer " + aliceToken}}, wantUser: "alice"},
+		{name: "lower-case scheme", header: http.Header{"Authorization": {"bearer " + aliceToken}}, wantUser: "alice"},
+		{name: "API key", header: http.Header{"X-Api-Key": {aliceToken}}, wantUser: "alice"},
+		{name: "no credentials", header: http.Header{}, wantErr: ErrMissingCredentials},
+		{name: "unknown token", header: http.Header{"Authorization": {"Bearer " + bobToken}}, wantErr: ErrInvalidCredentials},
+		{name: "basic auth", header: http.Header{"Authorization": {"Basic YWxpY2U6cHc="}}, wantErr: ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := auth.authenticate(tt.header)
+			if tt.wantErr != nil {
+				if connect.CodeOf(err) != connect.CodeUnauthenticated || !errors.Is(err, tt.wantErr) {
+					t.Errorf("authenticate() error = %v, want unauthenticated %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || key.user != tt.wantUser {
+				t.Errorf("authenticate() = %q, %v, want %q", key.user, err, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: `{"keys": [{"user": "alice", "token": "` + aliceToken + `"}, {"user": "bob", "token": "` + bobToken + `"}]}`},
+		{name: "no keys", content: `{"keys": []}`, wantErr: true},
+		{name: "missing user", content: `{"keys": [{"token": "` + aliceToken + `"}]}`, wantErr: true},
+		{name: "short token", content: `{"keys": [{"user": "alice", "token": "short"}]}`, wantErr: true},
+		{name: "bad workspace", content: `{"keys": [{"user": "alice", "token": "` + aliceToken + `", "workspace": "Team A"}]}`, wantErr: true},
+		{name: "shared token", content: `{"keys": [{"user": "alice", "token": "` + aliceToken + `"}, {"user": "bob", "token": "` + aliceToken + `"}]}`, wantErr: true},
+		{name: "not JSON", content: `alice=secret`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			keys, err := loadAPIKeys(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadAPIKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (keys[aliceToken].user != "alice" || keys[bobToken].user != "bob") {
+				t.Errorf("loadAPIKeys() = %v, want alice and bob", keys)
+			}
+		})
+	}
+}
+
+// authTestServer serves server over HTTP, authenticating alice and bob.
+func authTestServer(t *testing.T, server *TodoServer) *httptest.Server {
+	t.Helper()
+	auth := newAuthInterceptor(map[string]apiKey{aliceToken: {user: "alice"}, bobToken: {user: "bob"}})
+	_, handler := todov1.NewTodoServiceHandler(server, todov1.WithInterceptors(auth, newPolicyInterceptor(server)))
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+// call makes a unary call to procedure on httpServer with the given bearer
+// token, or without credentials if token is empty.
+func call[Req, Res any](t *testing.T, httpServer *httptest.Server, procedure, token string, msg *Req) (*Res, error) {
+	t.Helper()
+	client := connect.NewClient[Req, Res](
+		httpServer.Client(),
+		httpServer.URL+"/todo.v1.TodoService/"+procedure,
+		connect.WithProtoJSON(),
+	)
+	req := connect.NewRequest(msg)
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.CallUnary(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+func TestAuthenticatedOwnership(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	httpServer := authTestServer(t, server)
+
+	_, err := call[todov1.GetTasksRequest, todov1.GetTasksResponse](t, httpServer, "GetTasks", "", &todov1.GetTasksRequest{})
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("GetTasks() without credentials error = %v, want code %v", err, connect.CodeUnauthenticated)
+	}
+
+	added, err := call[todov1.AddTaskRequest, todov1.AddTaskResponse](t, httpServer, "AddTask", aliceToken, &todov1.AddTaskRequest{Text: "Alice's task"})
+	if err != nil {
+		t.Fatalf("AddTask() as alice error = %v", err)
+	}
+	task := added.Task
+	if task.OwnerId != "alice" {
+		t.Errorf("AddTask() owner = %q, want alice", task.OwnerId)
+	}
+	list, err := call[todov1.CreateListRequest, todov1.CreateListResponse](t, httpServer, "CreateList", aliceToken, &todov1.CreateListRequest{Name: "Work"})
+	if err != nil {
+		t.Fatalf("CreateList() as alice error = %v", err)
+	}
+
+	// Bob can use the same list name, and sees none of Alice's data.
+	if _, err := call[todov1.CreateListRequest, todov1.CreateListResponse](t, httpServer, "CreateList", bobToken, &todov1.CreateListRequest{Name: "Work"}); err != nil {
+		t.Errorf("CreateList() as bob error = %v", err)
+	}
+	got, err := call[todov1.GetTasksRequest, todov1.GetTasksResponse](t, httpServer, "GetTasks", bobToken, &todov1.GetTasksRequest{})
+	if err != nil {
+		t.Fatalf("GetTasks() as bob error = %v", err)
+	}
+	if len(got.Tasks) != 0 {
+		t.Errorf("GetTasks() as bob = %v, want none", got.Tasks)
+	}
+	lists, err := call[todov1.GetListsRequest, todov1.GetListsResponse](t, httpServer, "GetLists", bobToken, &todov1.GetListsRequest{})
+	if err != nil {
+		t.Fatalf("GetLists() as bob error = %v", err)
+	}
+	if len(lists.Lists) != 1 || lists.Lists[0].OwnerId != "bob" {
+		t.Errorf("GetLists() as bob = %v, want only bob's list", lists.Lists)
+	}
+
+	denied := []struct {
+		name string
+		call func() error
+	}{
+		{"DeleteTask", func() error {
+			_, err := call[todov1.DeleteTaskRequest, todov1.DeleteTaskResponse](t, httpServer, "DeleteTask", bobToken, &todov1.DeleteTaskRequest{Id: task.Id})
+			return err
+		}},
+		{"CompleteTask", func() error {
+			_, err := call[todov1.CompleteTaskRequest, todov1.CompleteTaskResponse](t, httpServer, "CompleteTask", bobToken, &todov1.CompleteTaskRequest{Id: task.Id})
+			return err
+		}},
+		{"GetTasks in list", func() error {
+			_, err := call[todov1.GetTasksRequest, todov1.GetTasksResponse](t, httpServer, "GetTasks", bobToken, &todov1.GetTasksRequest{ListId: list.List.Id})
+			return err
+		}},
+		{"AddTask to list", func() error {
+			_, err := call[todov1.AddTaskRequest, todov1.AddTaskResponse](t, httpServer, "AddTask", bobToken, &todov1.AddTaskRequest{Text: "Sneaky", ListId: list.List.Id})
+			return err
+		}},
+		{"RenameList", func() error {
+			_, err := call[todov1.RenameListRequest, todov1.RenameListResponse](t, httpServer, "RenameList", bobToken, &todov1.RenameListRequest{Id: list.List.Id, Name: "Mine"})
+			return err
+		}},
+		{"DeleteList", func() error {
+			_, err := call[todov1.DeleteListRequest, todov1.DeleteListResponse](t, httpServer, "DeleteList", bobToken, &todov1.DeleteListRequest{Id: list.List.Id})
+			return err
+		}},
+	}
+	for _, tt := range denied {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); connect.CodeOf(err) != connect.CodePermissionDenied {
+				t.Errorf("%s as bob error = %v, want code %v", tt.name, err, connect.CodePermissionDenied)
+			}
+		})
+	}
+
+	if _, err := call[todov1.DeleteTaskRequest, todov1.DeleteTaskResponse](t, httpServer, "DeleteTask", aliceToken, &todov1.DeleteTaskRequest{Id: task.Id}); err != nil {
+		t.Errorf("DeleteTask() as alice error = %v", err)
+	}
+}
+
+func TestWatchTasksAuthenticated(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	httpServer := authTestServer(t, server)
+	client := connect.NewClient[todov1.WatchTasksRequest, todov1.WatchTasksResponse](
+		httpServer.Client(),
+		httpServer.URL+"/todo.v1.TodoService/WatchTasks",
+		connect.WithProtoJSON(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	anonymous, err := client.CallServerStream(ctx, connect.NewRequest(&todov1.WatchTasksRequest{}))
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	if anonymous.Receive() {
+		t.Fatalf("Receive() without credentials got %v, want end of stream", anonymous.Msg())
+	}
+	if code := connect.CodeOf(anonymous.Err()); code != connect.CodeUnauthenticated {
+		t.Errorf("stream error = %v, want code %v", anonymous.Err(), connect.CodeUnauthenticated)
+	}
+	anonymous.Close()
+
+	req := connect.NewRequest(&todov1.WatchTasksRequest{})
+	req.Header().Set("X-Api-Key", bobToken)
+	stream, err := client.CallServerStream(ctx, req)
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	defer stream.Close()
+	waitForWatchers(t, server, 1)
+
+	// Bob is only told about his own task.
+	for _, token := range []string{aliceToken, bobToken} {
+		if _, err := call[todov1.AddTaskRequest, todov1.AddTaskResponse](t, httpServer, "AddTask", token, &todov1.AddTaskRequest{Text: "New task"}); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if !stream.Receive() {
+		t.Fatalf("Receive() failed: %v", stream.Err())
+	}
+	if owner := stream.Msg().Event.Task.GetOwnerId(); owner != "bob" {
+		t.Errorf("bob received an event for a task owned by %q", owner)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"connectrpc.com/connect"
+
+	"todo-list/todo/v1"
+)
+
+// MaxBatchSize is the most requests a batch RPC accepts.
+const MaxBatchSize = 100
+
+var (
+	ErrEmptyBatch    = errors.New("batch has no requests")
+	ErrBatchTooLarge = fmt.Errorf("batch has more than %d requests", MaxBatchSize)
+)
+
+// itemError is the failure of the request at index in a batch.
+type itemError struct {
+	index int
+	err   error
+}
+
+// batchError returns an error with the given code that describes the failed
+// requests of a batch, first of all, with a BatchItemError detail for each.
+func batchError(code connect.Code, failed []itemError) error {
+	msg := fmt.Sprintf("request %d: %s", failed[0].index, errorMessage(failed[0].err))
+	if len(failed) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(failed)-1)
+	}
+	cerr := connect.NewError(code, errors.New(msg))
+	for _, f := range failed {
+		detail, err := connect.NewErrorDetail(&todov1.BatchItemError{
+			Index:   int32(f.index),
+			Code:    connect.CodeOf(f.err).String(),
+			Message: errorMessage(f.err),
+		})
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to describe batch error: %w", err))
+		}
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
+
+// errorMessage returns the message of err without the code prefix that
+// connect errors carry.
+func errorMessage(err error) string {
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		return cerr.Message()
+	}
+	return err.Error()
+}
+
+// validateBatch checks the size of a batch of n requests and runs validate
+// on each, reporting every request that fails.
+func validateBatch(n int, validate func(i int) error) error {
+	if n == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, ErrEmptyBatch)
+	}
+	if n > MaxBatchSize {
+		return connect.NewError(connect.CodeInvalidArgument, ErrBatchTooLarge)
+	}
+	var failed []itemError
+	for i := 0; i < n; i++ {
+		if err := validate(i); err != nil {
+			failed = append(failed, itemError{index: i, err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return batchError(connect.CodeInvalidArgument, failed)
+	}
+	return nil
+}
+
+// applyBatch runs apply for each of n requests in order under a single
+// acquisition of s.mu. If a request fails, the changes made by the requests
+// before it, and by the failed request itself, are reverted, nothing is
+// announced to watchers and describe turns the failure into the error to
+// return. A batch that succeeds is logged for user to undo as one operation
+// and recorded in the history with user as the actor.
+func (s *TodoServer) applyBatch(user string, n int, apply func(i int) error, describe func(code connect.Code, failed []itemError) error) error {
+	defer s.lockAs(user)()
+
+	s.recording = &undoEntry{}
+	s.holding = true
+	defer func() {
+		s.recording = nil
+		s.holding = false
+		s.held = nil
+		s.heldHistory = nil
+	}()
+
+	for i := 0; i < n; i++ {
+		err := apply(i)
+		if err == nil {
+			continue
+		}
+		e := s.recording
+		s.recording = nil
+		if rerr := s.rollback(e); rerr != nil {
+			log.Printf("Failed to roll back batch: %v", rerr)
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("request %d failed and the batch could not be rolled back: %w", i, rerr))
+		}
+		return describe(connect.CodeOf(err), []itemError{{index: i, err: err}})
+	}
+
+	if len(s.recording.changes) > 0 {
+		s.undo.push(user, s.recording)
+	}
+	for _, ev := range s.held {
+		s.hub.publish(ev, s.viewers(ev.Task)...)
+	}
+	for _, ev := range s.heldHistory {
+		if err := s.appendEvent(ev); err != nil {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+	}
+	return nil
+}
+
+// rollback reverts the changes of e, newest first. Callers must hold s.mu.
+func (s *TodoServer) rollback(e *undoEntry) error {
+	for i := len(e.changes) - 1; i >= 0; i-- {
+		if err := s.revert(e.changes[i], true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TodoServer) BatchAddTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.BatchAddTasksRequest],
+) (*connect.Response[todov1.BatchAddTasksResponse], error) {
+	requests := req.Msg.Requests
+	tasks := make([]*todov1.Task, len(requests))
+	err := validateBatch(len(requests), func(i int) error {
+		var err error
+		tasks[i], err = s.newTask(ctx, requests[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.applyBatch(userFromContext(ctx), len(tasks), func(i int) error {
+		return s.addTask(tasks[i])
+	}, batchError)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.BatchAddTasksResponse{Tasks: tasks}), nil
+}
+
+func (s *TodoServer) BatchUpdateTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.BatchUpdateTasksRequest],
+) (*connect.Response[todov1.BatchUpdateTasksResponse], error) {
+	requests := req.Msg.Requests
+	err := validateBatch(len(requests), func(i int) error {
+		return s.validateUpdateTask(requests[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*todov1.Task, len(requests))
+	err = s.applyBatch(userFromContext(ctx), len(requests), func(i int) error {
+		var err error
+		tasks[i], err = s.applyUpdateTask(ctx, requests[i])
+		return err
+	}, batchError)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.BatchUpdateTasksResponse{Tasks: tasks}), nil
+}
+
+func (s *TodoServer) BatchDeleteTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.BatchDeleteTasksRequest],
+) (*connect.Response[todov1.BatchDeleteTasksResponse], error) {
+	requests := req.Msg.Requests
+	err := validateBatch(len(requests), func(i int) error {
+		return validateDeleteTask(requests[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.applyBatch(userFromContext(ctx), len(requests), func(i int) error {
+		return s.applyDeleteTask(ctx, requests[i])
+	}, batchError)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.BatchDeleteTasksResponse{}), nil
+}
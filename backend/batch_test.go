@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+// batchItemErrors returns the BatchItemError details of err.
+func batchItemErrors(t *testing.T, err error) []*todov1.BatchItemError {
+	t.Helper()
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		t.Fatalf("error %v is not a connect error", err)
+	}
+	var items []*todov1.BatchItemError
+	for _, d := range cerr.Details() {
+		msg, err := d.Value()
+		if err != nil {
+			t.Fatalf("detail %q: %v", d.Type(), err)
+		}
+		if item, ok := msg.(*todov1.BatchItemError); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func renameRequest(id, text string) *todov1.UpdateTaskRequest {
+	return &todov1.UpdateTaskRequest{
+		Id:         id,
+		Task:       &todov1.Task{Text: text},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"text"}},
+	}
+}
+
+func TestBatch(t *testing.T) {
+	forEachStore(t, testBatch)
+}
+
+func testBatch(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	added, err := server.BatchAddTasks(ctx, connect.NewRequest(&todov1.BatchAddTasksRequest{
+		Requests: []*todov1.AddTaskRequest{{Text: "One"}, {Text: "Two"}, {Text: "Three"}},
+	}))
+	if err != nil {
+		t.Fatalf("BatchAddTasks() error = %v", err)
+	}
+	ids := taskIDs(added.Msg.Tasks)
+	if len(ids) != 3 || len(tasksByID(t, server)) != 3 {
+		t.Fatalf("BatchAddTasks() = %v, want three stored tasks", added.Msg.Tasks)
+	}
+
+	updated, err := server.BatchUpdateTasks(ctx, connect.NewRequest(&todov1.BatchUpdateTasksRequest{
+		Requests: []*todov1.UpdateTaskRequest{renameRequest(ids[0], "First"), renameRequest(ids[1], "Second")},
+	}))
+	if err != nil {
+		t.Fatalf("BatchUpdateTasks() error = %v", err)
+	}
+	if got := updated.Msg.Tasks; len(got) != 2 || got[0].Text != "First" || got[1].Text != "Second" {
+		t.Errorf("BatchUpdateTasks() = %v, want both tasks renamed", got)
+	}
+
+	if _, err := server.BatchDeleteTasks(ctx, connect.NewRequest(&todov1.BatchDeleteTasksRequest{
+		Requests: []*todov1.DeleteTaskRequest{{Id: ids[0]}, {Id: ids[2]}},
+	})); err != nil {
+		t.Fatalf("BatchDeleteTasks() error = %v", err)
+	}
+	if got := tasksByID(t, server); len(got) != 1 || got[ids[1]] == nil {
+		t.Errorf("tasks after BatchDeleteTasks() = %v, want only %q", got, ids[1])
+	}
+
+	// Each batch is undone as a whole.
+	if resp := mustUndo(t, ctx, server); len(resp.Tasks) != 2 {
+		t.Errorf("Undo() of BatchDeleteTasks = %v, want both tasks back", resp.Tasks)
+	}
+	mustUndo(t, ctx, server)
+	if resp := mustUndo(t, ctx, server); !reflect.DeepEqual(resp.RemovedIds, ids) {
+		t.Errorf("Undo() of BatchAddTasks removed %v, want %v", resp.RemovedIds, ids)
+	}
+}
+
+func TestBatchRollback(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	parent := mustAddTask(t, server, "Parent", "")
+	child := mustAddSubtask(t, server, "Child", parent.Id)
+	sub := server.hub.subscribe("")
+	defer server.hub.unsubscribe(sub)
+
+	_, err := server.BatchUpdateTasks(ctx, connect.NewRequest(&todov1.BatchUpdateTasksRequest{
+		Requests: []*todov1.UpdateTaskRequest{renameRequest(parent.Id, "Renamed"), renameRequest("missing", "Nope")},
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("BatchUpdateTasks() with a missing task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	items := batchItemErrors(t, err)
+	if len(items) != 1 || items[0].Index != 1 || items[0].Code != "not_found" {
+		t.Errorf("BatchUpdateTasks() error details = %v, want request 1 not found", items)
+	}
+
+	_, err = server.BatchDeleteTasks(ctx, connect.NewRequest(&todov1.BatchDeleteTasksRequest{
+		Requests: []*todov1.DeleteTaskRequest{
+			{Id: parent.Id, SubtaskPolicy: todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE},
+			{Id: child.Id},
+		},
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("BatchDeleteTasks() of an already deleted subtask error = %v, want code %v", err, connect.CodeNotFound)
+	}
+
+	tasks := tasksByID(t, server)
+	if !proto.Equal(tasks[parent.Id], parent) || tasks[child.Id].GetParentId() != parent.Id {
+		t.Errorf("tasks after failed batches = %v, want them unchanged, versions included", tasks)
+	}
+	if got := trashIDs(t, server); len(got) != 0 {
+		t.Errorf("trash after failed batch = %v, want it empty", got)
+	}
+	if _, err := undo(ctx, server); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	// Failed batches are not logged, so the subtask was the last change.
+	if got := tasksByID(t, server); len(got) != 1 || got[parent.Id] == nil {
+		t.Errorf("tasks after Undo() = %v, want only the parent", got)
+	}
+
+	// Watchers hear nothing of batches that were rolled back.
+	select {
+	case ev := <-sub.events:
+		if ev.Type != todov1.TaskEventType_TASK_EVENT_TYPE_DELETED || ev.Task.Id != child.Id {
+			t.Errorf("first event = %v, want the undo of the subtask", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the undo event")
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	_, err := server.BatchAddTasks(ctx, connect.NewRequest(&todov1.BatchAddTasksRequest{
+		Requests: []*todov1.AddTaskRequest{{Text: " "}, {Text: "Fine"}, {Text: "Bad", DueAt: -1}},
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("BatchAddTasks() with invalid requests error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+	var indexes []int32
+	for _, item := range batchItemErrors(t, err) {
+		indexes = append(indexes, item.Index)
+	}
+	if want := []int32{0, 2}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("error details name requests %v, want %v", indexes, want)
+	}
+	if got := len(tasksByID(t, server)); got != 0 {
+		t.Errorf("got %d tasks after an invalid batch, want 0", got)
+	}
+
+	tooMany := make([]*todov1.DeleteTaskRequest, MaxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = &todov1.DeleteTaskRequest{Id: "x"}
+	}
+	for name, requests := range map[string][]*todov1.DeleteTaskRequest{"empty": nil, "too large": tooMany} {
+		_, err := server.BatchDeleteTasks(ctx, connect.NewRequest(&todov1.BatchDeleteTasksRequest{Requests: requests}))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("BatchDeleteTasks(%s) error = %v, want code %v", name, err, connect.CodeInvalidArgument)
+		}
+	}
+}
+
+func TestBatchErrorDetailsOverHTTP(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	httpServer := authTestServer(t, server)
+
+	_, err := call[todov1.BatchDeleteTasksRequest, todov1.BatchDeleteTasksResponse](t, httpServer, "BatchDeleteTasks", aliceToken,
+		&todov1.BatchDeleteTasksRequest{Requests: []*todov1.DeleteTaskRequest{{Id: "missing"}}})
+	items := batchItemErrors(t, err)
+	if len(items) != 1 || items[0].Index != 0 || items[0].Code != "not_found" {
+		t.Errorf("BatchDeleteTasks() error details = %v, want request 0 not found", items)
+	}
+}
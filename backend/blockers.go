@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+// MaxBlockersPerTask caps how many tasks a single task can wait for.
+const MaxBlockersPerTask = 50
+
+var (
+	ErrInvalidBlockerID = errors.New("invalid blocker ID")
+	ErrBlockerNotFound  = errors.New("blocker task not found")
+	ErrDependencyCycle  = errors.New("a task cannot wait for itself, directly or through other tasks")
+	ErrTooManyBlockers  = errors.New("task has too many blockers")
+)
+
+// waitsFor reports whether the task with ID id is blocked by the task with ID
+// target, directly or through a chain of blockers. Callers must hold s.mu.
+func (s *TodoServer) waitsFor(id, target string) (bool, error) {
+	seen := make(map[string]bool)
+	pending := []string{id}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if id == target {
+			return true, nil
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		task, err := s.store.GetTask(id)
+		if errors.Is(err, ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		pending = append(pending, task.BlockedBy...)
+	}
+	return false, nil
+}
+
+// blockerDone reports whether the task with the given ID no longer holds up
+// the tasks it blocks, because it has been completed or no longer exists.
+// Callers must hold s.mu.
+func (s *TodoServer) blockerDone(id string) bool {
+	task, err := s.store.GetTask(id)
+	return err != nil || task.Completed
+}
+
+// blockerIndex maps each task to the live tasks that wait for it, the
+// reverse of their BlockedBy, so that deleting a task finds the tasks to
+// unblock without a scan. It is not safe for concurrent use; TodoServer
+// guards it with its mutex.
+type blockerIndex struct {
+	waiting map[string]map[string]struct{} // blocker ID -> IDs of tasks waiting for it
+}
+
+func newBlockerIndex(tasks []*todov1.Task) *blockerIndex {
+	x := &blockerIndex{waiting: make(map[string]map[string]struct{})}
+	for _, task := range tasks {
+		x.add(task)
+	}
+	return x
+}
+
+func (x *blockerIndex) add(task *todov1.Task) {
+	for _, id := range task.BlockedBy {
+		ids, ok := x.waiting[id]
+		if !ok {
+			ids = make(map[string]struct{})
+			x.waiting[id] = ids
+		}
+		ids[task.Id] = struct{}{}
+	}
+}
+
+func (x *blockerIndex) remove(task *todov1.Task) {
+	for _, id := range task.BlockedBy {
+		ids := x.waiting[id]
+		delete(ids, task.Id)
+		if len(ids) == 0 {
+			delete(x.waiting, id)
+		}
+	}
+}
+
+// update re-indexes task, whose previous version was old, after its blockers
+// may have changed.
+func (x *blockerIndex) update(old, task *todov1.Task) {
+	if slices.Equal(old.BlockedBy, task.BlockedBy) {
+		return
+	}
+	x.remove(old)
+	x.add(task)
+}
+
+// waiters returns the IDs of the tasks waiting for the task with the given
+// ID, sorted.
+func (x *blockerIndex) waiters(id string) []string {
+	return slices.Sorted(maps.Keys(x.waiting[id]))
+}
+
+// unlinkBlocker removes the task with the given ID from the blockers of every
+// task that waits for it. Callers must hold s.mu.
+func (s *TodoServer) unlinkBlocker(id string) error {
+	for _, waiter := range s.blockers.waiters(id) {
+		current, err := s.store.GetTask(waiter)
+		if err != nil {
+			return err
+		}
+		task := proto.Clone(current).(*todov1.Task)
+		task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(b string) bool { return b == id })
+		if err := s.replaceTask(current, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseBlockerEdit validates the arguments of AddBlocker and RemoveBlocker.
+func parseBlockerEdit(id, blockerID string) error {
+	if strings.TrimSpace(id) == "" {
+		return connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+	if strings.TrimSpace(blockerID) == "" {
+		return connect.NewError(connect.CodeInvalidArgument, ErrInvalidBlockerID)
+	}
+	return nil
+}
+
+func (s *TodoServer) AddBlocker(
+	ctx context.Context,
+	req *connect.Request[todov1.AddBlockerRequest],
+) (*connect.Response[todov1.AddBlockerResponse], error) {
+	if err := parseBlockerEdit(req.Msg.Id, req.Msg.BlockerId); err != nil {
+		return nil, err
+	}
+	blockerID := req.Msg.BlockerId
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		i, found := slices.BinarySearch(task.BlockedBy, blockerID)
+		if found {
+			return nil
+		}
+		if len(task.BlockedBy) >= MaxBlockersPerTask {
+			return connect.NewError(connect.CodeInvalidArgument, ErrTooManyBlockers)
+		}
+		blocker, err := s.store.GetTask(blockerID)
+		if errors.Is(err, ErrTaskNotFound) {
+			return connect.NewError(connect.CodeNotFound, ErrBlockerNotFound)
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+		}
+		if err := s.authorizeTask(ctx, blocker, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+			return err
+		}
+		// The new edge closes a cycle if the blocker already waits for task.
+		cycle, err := s.waitsFor(blockerID, task.Id)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to check blockers: %w", err))
+		}
+		if cycle {
+			return connect.NewError(connect.CodeInvalidArgument, ErrDependencyCycle)
+		}
+		task.BlockedBy = slices.Insert(task.BlockedBy, i, blockerID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.AddBlockerResponse{Task: task}), nil
+}
+
+func (s *TodoServer) RemoveBlocker(
+	ctx context.Context,
+	req *connect.Request[todov1.RemoveBlockerRequest],
+) (*connect.Response[todov1.RemoveBlockerResponse], error) {
+	if err := parseBlockerEdit(req.Msg.Id, req.Msg.BlockerId); err != nil {
+		return nil, err
+	}
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(b string) bool { return b == req.Msg.BlockerId })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.RemoveBlockerResponse{Task: task}), nil
+}
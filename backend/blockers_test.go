@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+func TestBlockers(t *testing.T) {
+	forEachStore(t, testBlockers)
+}
+
+func testBlockers(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	design := mustAddTask(t, server, "Design", "")
+	build := mustAddTask(t, server, "Build", "")
+	ship := mustAddTask(t, server, "Ship", "")
+
+	block := func(id, blockerID string) (*todov1.Task, error) {
+		resp, err := server.AddBlocker(ctx, connect.NewRequest(&todov1.AddBlockerRequest{Id: id, BlockerId: blockerID}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Task, nil
+	}
+	if _, err := block(build.Id, design.Id); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	if _, err := block(ship.Id, build.Id); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	got, err := block(ship.Id, design.Id)
+	if err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	want := []string{build.Id, design.Id}
+	slices.Sort(want)
+	if !reflect.DeepEqual(got.BlockedBy, want) {
+		t.Errorf("AddBlocker() blocked_by = %v, want %v", got.BlockedBy, want)
+	}
+	if again, err := block(ship.Id, design.Id); err != nil || !reflect.DeepEqual(again.BlockedBy, want) {
+		t.Errorf("AddBlocker() repeated = %v, %v; want unchanged blockers", again.GetBlockedBy(), err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		id        string
+		blockerID string
+		want      connect.Code
+	}{
+		{name: "self", id: design.Id, blockerID: design.Id, want: connect.CodeInvalidArgument},
+		{name: "direct cycle", id: design.Id, blockerID: build.Id, want: connect.CodeInvalidArgument},
+		{name: "transitive cycle", id: design.Id, blockerID: ship.Id, want: connect.CodeInvalidArgument},
+		{name: "missing blocker", id: design.Id, blockerID: "missing", want: connect.CodeNotFound},
+		{name: "missing task", id: "missing", blockerID: design.Id, want: connect.CodeNotFound},
+		{name: "empty blocker", id: design.Id, blockerID: "", want: connect.CodeInvalidArgument},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := block(tt.id, tt.blockerID); connect.CodeOf(err) != tt.want {
+				t.Errorf("AddBlocker(%q, %q) error = %v, want code %v", tt.id, tt.blockerID, err, tt.want)
+			}
+		})
+	}
+
+	actionable := func() []string {
+		t.Helper()
+		resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{
+			Actionable: true,
+			OrderBy:    todov1.TaskOrder_TASK_ORDER_POSITION,
+		}))
+		if err != nil {
+			t.Fatalf("GetTasks(actionable) error = %v", err)
+		}
+		return taskIDs(resp.Msg.Tasks)
+	}
+	if got, want := actionable(), []string{design.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("actionable tasks = %v, want %v", got, want)
+	}
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: design.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	if got, want := actionable(), []string{build.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("actionable tasks after completing blocker = %v, want %v", got, want)
+	}
+
+	removed, err := server.RemoveBlocker(ctx, connect.NewRequest(&todov1.RemoveBlockerRequest{Id: ship.Id, BlockerId: design.Id}))
+	if err != nil {
+		t.Fatalf("RemoveBlocker() error = %v", err)
+	}
+	if got, want := removed.Msg.Task.BlockedBy, []string{build.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveBlocker() blocked_by = %v, want %v", got, want)
+	}
+
+	// Deleting a blocker unblocks the tasks that waited for it.
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: build.Id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if got := tasksByID(t, server)[ship.Id].BlockedBy; len(got) != 0 {
+		t.Errorf("blocked_by after deleting blocker = %v, want none", got)
+	}
+	if got, want := actionable(), []string{ship.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("actionable tasks after deleting blocker = %v, want %v", got, want)
+	}
+}
+
+func TestDeleteBlockerEvents(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	blocker := mustAddTask(t, server, "Blocker", "")
+	waiting := mustAddTask(t, server, "Waiting", "")
+	if _, err := server.AddBlocker(ctx, connect.NewRequest(&todov1.AddBlockerRequest{Id: waiting.Id, BlockerId: blocker.Id})); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	sub := server.hub.subscribe("")
+	defer server.hub.unsubscribe(sub)
+
+	mustDeleteTask(t, server, blocker.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	// Watchers see the waiting task unblocked before the blocker goes away.
+	for _, want := range []struct {
+		typ todov1.TaskEventType
+		id  string
+	}{
+		{typ: todov1.TaskEventType_TASK_EVENT_TYPE_UPDATED, id: waiting.Id},
+		{typ: todov1.TaskEventType_TASK_EVENT_TYPE_DELETED, id: blocker.Id},
+	} {
+		ev := <-sub.events
+		if ev.Type != want.typ || ev.Task.Id != want.id {
+			t.Errorf("event = %v for %s, want %v for %s", ev.Type, ev.Task.Id, want.typ, want.id)
+		}
+	}
+	if len(server.blockers.waiting) != 0 {
+		t.Errorf("blocker index after deleting the blocker = %v, want it empty", server.blockers.waiting)
+	}
+
+	// Undoing the delete puts the task back in the index.
+	mustUndo(t, ctx, server)
+	if got, want := server.blockers.waiters(blocker.Id), []string{waiting.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks waiting for %s after undo = %v, want %v", blocker.Id, got, want)
+	}
+}
+
+func TestAddBlockerOtherOwner(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	add := func(ctx context.Context, text string) string {
+		t.Helper()
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: text}))
+		if err != nil {
+			t.Fatalf("AddTask(%q) error = %v", text, err)
+		}
+		return resp.Msg.Task.Id
+	}
+	mine := add(alice, "Mine")
+	theirs := add(bob, "Theirs")
+
+	_, err := server.AddBlocker(alice, connect.NewRequest(&todov1.AddBlockerRequest{Id: mine, BlockerId: theirs}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("AddBlocker() error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	"todo-list/todo/v1"
+)
+
+// commands are the subcommands that talk to a running server instead of
+// starting one, keyed by name.
+var commands = map[string]func(args []string, stdin io.Reader, stdout, stderr io.Writer) error{
+	"export": runExport,
+	"import": runImport,
+}
+
+// formatNames maps the -format values of the commands to task formats.
+var formatNames = map[string]todov1.TaskFormat{
+	"json":     todov1.TaskFormat_TASK_FORMAT_JSON,
+	"csv":      todov1.TaskFormat_TASK_FORMAT_CSV,
+	"markdown": todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+	"md":       todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+	"todotxt":  todov1.TaskFormat_TASK_FORMAT_TODO_TXT,
+	"todo.txt": todov1.TaskFormat_TASK_FORMAT_TODO_TXT,
+}
+
+// taskFormat returns the format named by the -format flag or, if it is
+// empty, the one that the extension of path suggests, JSON if none does.
+func taskFormat(name, path string) (todov1.TaskFormat, error) {
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			return todov1.TaskFormat_TASK_FORMAT_CSV, nil
+		case ".md", ".markdown":
+			return todov1.TaskFormat_TASK_FORMAT_MARKDOWN, nil
+		case ".txt":
+			return todov1.TaskFormat_TASK_FORMAT_TODO_TXT, nil
+		}
+		return todov1.TaskFormat_TASK_FORMAT_JSON, nil
+	}
+	format, ok := formatNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown format %q: want json, csv, markdown or todotxt", name)
+	}
+	return format, nil
+}
+
+// clientFlags are the flags every command uses to reach the server.
+type clientFlags struct {
+	server    string
+	token     string
+	workspace string
+}
+
+func (c *clientFlags) register(fs *flag.FlagSet) {
+	server := os.Getenv("TODO_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	fs.StringVar(&c.server, "server", server, "base URL of the server (env TODO_SERVER)")
+	fs.StringVar(&c.token, "token", os.Getenv("TODO_TOKEN"), "API key to authenticate with (env TODO_TOKEN)")
+	fs.StringVar(&c.workspace, "workspace", os.Getenv("TODO_WORKSPACE"), "workspace to act on; empty for the default one or the key's (env TODO_WORKSPACE)")
+}
+
+// callServer makes a unary call to procedure on the server.
+func callServer[Req, Res any](c *clientFlags, procedure string, msg *Req) (*Res, error) {
+	client := connect.NewClient[Req, Res](
+		http.DefaultClient,
+		strings.TrimSuffix(c.server, "/")+"/"+todov1.TodoServiceName+"/"+procedure,
+		connect.WithProtoJSON(),
+	)
+	req := connect.NewRequest(msg)
+	if c.token != "" {
+		req.Header().Set("Authorization", "Bearer "+c.token)
+	}
+	if c.workspace != "" {
+		req.Header().Set(WorkspaceHeader, c.workspace)
+	}
+	resp, err := client.CallUnary(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+// runExport writes the caller's tasks to a file or stdout.
+func runExport(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var client clientFlags
+	client.register(fs)
+	formatName := fs.String("format", "", "json, csv, markdown or todotxt (default from the -o extension, else json)")
+	listID := fs.String("list", "", "only export this list's tasks")
+	status := fs.String("status", "", "only export open or completed tasks")
+	output := fs.String("o", "", "file to write; stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	format, err := taskFormat(*formatName, *output)
+	if err != nil {
+		return err
+	}
+	req := &todov1.ExportTasksRequest{Format: format, ListId: *listID}
+	switch *status {
+	case "":
+	case "open":
+		req.Status = todov1.TaskStatus_TASK_STATUS_OPEN
+	case "completed":
+		req.Status = todov1.TaskStatus_TASK_STATUS_COMPLETED
+	default:
+		return fmt.Errorf("unknown status %q: want open or completed", *status)
+	}
+
+	resp, err := callServer[todov1.ExportTasksRequest, todov1.ExportTasksResponse](&client, "ExportTasks", req)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(resp.Data)
+		return err
+	}
+	return os.WriteFile(*output, resp.Data, 0o644)
+}
+
+// runImport adds the tasks of a file, or stdin, and reports every invalid
+// line if the server refuses them.
+func runImport(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var client clientFlags
+	client.register(fs)
+	formatName := fs.String("format", "", "json, csv, markdown or todotxt (default from the file extension, else json)")
+	listID := fs.String("list", "", "list to add the tasks to; the inbox if empty")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: todo-server import [flags] [file]\n\nReads stdin if no file, or -, is given.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args()[1:])
+	}
+	path := fs.Arg(0)
+	format, err := taskFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if path == "" || path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := callServer[todov1.ImportTasksRequest, todov1.ImportTasksResponse](&client, "ImportTasks", &todov1.ImportTasksRequest{
+		Format: format,
+		Data:   data,
+		ListId: *listID,
+	})
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		var invalid bool
+		for _, d := range cerr.Details() {
+			if msg, err := d.Value(); err == nil {
+				if line, ok := msg.(*todov1.ImportLineError); ok {
+					fmt.Fprintf(stderr, "line %d: %s\n", line.Line, line.Message)
+					invalid = true
+				}
+			}
+		}
+		if invalid {
+			return errors.New("nothing imported")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Imported %d tasks\n", len(resp.Tasks))
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportExportCommands(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	httpServer := authTestServer(t, server)
+	dir := t.TempDir()
+	connection := []string{"-server", httpServer.URL, "-token", aliceToken}
+
+	input := filepath.Join(dir, "todo.txt")
+	if err := os.WriteFile(input, []byte("(B) Call the plumber +home\nx Pay rent\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if err := runImport(append(connection, input), nil, &stdout, &stderr); err != nil {
+		t.Fatalf("import error = %v, stderr %q", err, stderr.String())
+	}
+	if got := stdout.String(); got != "Imported 2 tasks\n" {
+		t.Errorf("import output = %q", got)
+	}
+
+	// The format follows from the extension of -o.
+	output := filepath.Join(dir, "tasks.csv")
+	if err := runExport(append(connection, "-o", output, "-status", "open"), nil, &stdout, &stderr); err != nil {
+		t.Fatalf("export error = %v, stderr %q", err, stderr.String())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "text,completed,completed_at,due_at,priority,tags,recurrence\nCall the plumber,false,,,P1,home,\n"; string(data) != want {
+		t.Errorf("exported CSV = %q, want %q", data, want)
+	}
+
+	// Invalid lines are listed and nothing is imported.
+	stdout.Reset()
+	stderr.Reset()
+	err = runImport(append(connection, "-format", "markdown", "-"), strings.NewReader("- [ ] Fine\noops\n"), &stdout, &stderr)
+	if err == nil {
+		t.Fatal("import of an invalid checklist succeeded")
+	}
+	if got := stderr.String(); !strings.HasPrefix(got, "line 2: ") {
+		t.Errorf("import stderr = %q, want line 2 reported", got)
+	}
+	stdout.Reset()
+	if err := runExport(append(connection, "-format", "md"), nil, &stdout, &stderr); err != nil {
+		t.Fatalf("export error = %v", err)
+	}
+	if got, want := stdout.String(), "- [ ] Call the plumber\n- [x] Pay rent\n"; got != want {
+		t.Errorf("tasks after a refused import = %q, want %q", got, want)
+	}
+}
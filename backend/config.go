@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxTaskLengthLimit bounds max_task_length well below the 1 MiB request
+// size limit.
+const maxTaskLengthLimit = 64 << 10
+
+// Config holds the server settings. Each setting comes from, in increasing
+// order of precedence: its default, the config file, its environment
+// variable and its command-line flag.
+type Config struct {
+	ListenAddr        string        `yaml:"listen_addr"`
+	AllowedOrigins    []string      `yaml:"allowed_origins"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	MaxTaskLength     int           `yaml:"max_task_length"`
+	Store             string        `yaml:"store"`
+	DataPath          string        `yaml:"data"`
+	WALSync           string        `yaml:"wal_sync"`
+	WALSyncInterval   time.Duration `yaml:"wal_sync_interval"`
+	SnapshotEvery     int           `yaml:"snapshot_every"`
+	WorkspaceDir      string        `yaml:"workspace_dir"`
+	AuthKeysPath      string        `yaml:"auth_keys"`
+	TrashRetention    time.Duration `yaml:"trash_retention"`
+	HistoryRetention  time.Duration `yaml:"history_retention"`
+	RateLimit         int           `yaml:"rate_limit"`
+	RateBurst         int           `yaml:"rate_burst"`
+	MaxTasksPerOwner  int           `yaml:"max_tasks_per_owner"`
+	MaxTasksPerList   int           `yaml:"max_tasks_per_list"`
+}
+
+// defaultConfig returns the settings used when nothing overrides them.
+func defaultConfig() Config {
+	return Config{
+		ListenAddr:        ":8080",
+		AllowedOrigins:    []string{"http://localhost:3000"},
+		ReadTimeout:       5 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		ShutdownTimeout:   10 * time.Second,
+		MaxTaskLength:     MaxTaskTextLength,
+		Store:             "memory",
+		DataPath:          "tasks.json",
+		WALSync:           SyncAlways,
+		WALSyncInterval:   time.Second,
+		SnapshotEvery:     1000,
+		WorkspaceDir:      "workspaces",
+		TrashRetention:    DefaultTrashRetention,
+		HistoryRetention:  DefaultHistoryRetention,
+		RateLimit:         20,
+		RateBurst:         40,
+	}
+}
+
+// setting describes a Config field that can be set from a flag and an
+// environment variable.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, value string) error
+}
+
+func stringSetting(flag, env, usage string, field func(c *Config) *string) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func durationSetting(flag, env, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return field(c).String() },
+		set: func(c *Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = d
+			return nil
+		},
+	}
+}
+
+func intSetting(flag, env, usage string, field func(c *Config) *int) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+var settings = []setting{
+	stringSetting("listen", "TODO_LISTEN_ADDR", "address to listen on", func(c *Config) *string { return &c.ListenAddr }),
+	{
+		flag:  "allowed-origins",
+		env:   "TODO_ALLOWED_ORIGINS",
+		usage: "comma-separated origins allowed to make cross-origin requests",
+		get:   func(c *Config) string { return strings.Join(c.AllowedOrigins, ",") },
+		set: func(c *Config, value string) error {
+			c.AllowedOrigins = nil
+			for _, origin := range strings.Split(value, ",") {
+				if origin = strings.TrimSpace(origin); origin != "" {
+					c.AllowedOrigins = append(c.AllowedOrigins, origin)
+				}
+			}
+			return nil
+		},
+	},
+	durationSetting("read-timeout", "TODO_READ_TIMEOUT", "maximum time to read a request", func(c *Config) *time.Duration { return &c.ReadTimeout }),
+	durationSetting("read-header-timeout", "TODO_READ_HEADER_TIMEOUT", "maximum time to read request headers", func(c *Config) *time.Duration { return &c.ReadHeaderTimeout }),
+	durationSetting("write-timeout", "TODO_WRITE_TIMEOUT", "maximum time to write a unary response", func(c *Config) *time.Duration { return &c.WriteTimeout }),
+	durationSetting("idle-timeout", "TODO_IDLE_TIMEOUT", "how long idle keep-alive connections stay open", func(c *Config) *time.Duration { return &c.IdleTimeout }),
+	durationSetting("shutdown-timeout", "TODO_SHUTDOWN_TIMEOUT", "how long to wait for requests to finish on shutdown", func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+	intSetting("max-task-length", "TODO_MAX_TASK_LENGTH", "maximum length of task text in bytes", func(c *Config) *int { return &c.MaxTaskLength }),
+	stringSetting("store", "TODO_STORE", "task storage backend: memory, file or wal", func(c *Config) *string { return &c.Store }),
+	stringSetting("data", "TODO_DATA", "path of the task file used by the file store, or of the snapshot of the wal store", func(c *Config) *string { return &c.DataPath }),
+	stringSetting("wal-sync", "TODO_WAL_SYNC", "when the wal store syncs its log to disk: always, interval or never", func(c *Config) *string { return &c.WALSync }),
+	durationSetting("wal-sync-interval", "TODO_WAL_SYNC_INTERVAL", "how often the wal store syncs its log with -wal-sync=interval", func(c *Config) *time.Duration { return &c.WALSyncInterval }),
+	intSetting("snapshot-every", "TODO_SNAPSHOT_EVERY", "number of log records after which the wal store writes a snapshot", func(c *Config) *int { return &c.SnapshotEvery }),
+	stringSetting("workspace-dir", "TODO_WORKSPACE_DIR", "directory holding the stores of the workspaces other than the default one, with the file or wal store", func(c *Config) *string { return &c.WorkspaceDir }),
+	stringSetting("auth-keys", "TODO_AUTH_KEYS", "path of the JSON file of API keys; empty disables authentication", func(c *Config) *string { return &c.AuthKeysPath }),
+	durationSetting("trash-retention", "TODO_TRASH_RETENTION", "how long deleted tasks stay in the trash before they are purged", func(c *Config) *time.Duration { return &c.TrashRetention }),
+	durationSetting("history-retention", "TODO_HISTORY_RETENTION", "how long events stay in the task history before they are dropped", func(c *Config) *time.Duration { return &c.HistoryRetention }),
+	intSetting("rate-limit", "TODO_RATE_LIMIT", "calls a second each client may make on average; 0 disables rate limiting", func(c *Config) *int { return &c.RateLimit }),
+	intSetting("rate-burst", "TODO_RATE_BURST", "calls each client may make at once before the rate limit applies", func(c *Config) *int { return &c.RateBurst }),
+	intSetting("max-tasks-per-owner", "TODO_MAX_TASKS_PER_OWNER", "tasks each user may have, trashed ones included; 0 for no limit", func(c *Config) *int { return &c.MaxTasksPerOwner }),
+	intSetting("max-tasks-per-list", "TODO_MAX_TASKS_PER_LIST", "tasks each list may hold, trashed ones included; 0 for no limit", func(c *Config) *int { return &c.MaxTasksPerList }),
+}
+
+// loadConfig builds the configuration from args (without the program name),
+// the environment looked up with getenv and the config file named by the
+// -config flag or TODO_CONFIG. It reports whether -print-config was given.
+func loadConfig(args []string, getenv func(string) string, output io.Writer) (Config, bool, error) {
+	fs := flag.NewFlagSet("todo-server", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configPath := fs.String("config", getenv("TODO_CONFIG"), "path of an optional YAML config file (env TODO_CONFIG)")
+	printConfig := fs.Bool("print-config", false, "print the effective configuration as YAML and exit")
+
+	defaults := defaultConfig()
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		name := s.flag
+		usage := fmt.Sprintf("%s (env %s, default %q)", s.usage, s.env, s.get(&defaults))
+		fs.Func(name, usage, func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, false, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	cfg := defaults
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return Config{}, false, err
+		}
+	}
+	for _, s := range settings {
+		if value := getenv(s.env); value != "" {
+			if err := s.set(&cfg, value); err != nil {
+				return Config{}, false, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, ok := flagValues[s.flag]; ok {
+			if err := s.set(&cfg, value); err != nil {
+				return Config{}, false, fmt.Errorf("invalid -%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return Config{}, false, err
+	}
+	return cfg, *printConfig, nil
+}
+
+// loadFile overrides c with the settings present in the YAML file at path.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// validate reports the first setting that the server could not run with.
+func (c *Config) validate() error {
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", c.ListenAddr, err)
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid allowed origin %q: want scheme://host[:port] or *", origin)
+		}
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read_timeout", c.ReadTimeout},
+		{"read_header_timeout", c.ReadHeaderTimeout},
+		{"write_timeout", c.WriteTimeout},
+		{"idle_timeout", c.IdleTimeout},
+		{"shutdown_timeout", c.ShutdownTimeout},
+		{"trash_retention", c.TrashRetention},
+		{"history_retention", c.HistoryRetention},
+		{"wal_sync_interval", c.WALSyncInterval},
+	}
+	for _, t := range timeouts {
+		if t.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", t.name, t.value)
+		}
+	}
+	if c.MaxTaskLength < MinTaskTextLength || c.MaxTaskLength > maxTaskLengthLimit {
+		return fmt.Errorf("max_task_length must be between %d and %d, got %d", MinTaskTextLength, maxTaskLengthLimit, c.MaxTaskLength)
+	}
+	switch c.Store {
+	case "memory":
+	case "file", "wal":
+		if c.DataPath == "" {
+			return fmt.Errorf("data must name a file when store is %s", c.Store)
+		}
+		if c.WorkspaceDir == "" {
+			return fmt.Errorf("workspace_dir must name a directory when store is %s", c.Store)
+		}
+	default:
+		return fmt.Errorf("unknown store %q (want memory, file or wal)", c.Store)
+	}
+	switch c.WALSync {
+	case SyncAlways, SyncInterval, SyncNever:
+	default:
+		return fmt.Errorf("unknown wal_sync %q (want %s, %s or %s)", c.WALSync, SyncAlways, SyncInterval, SyncNever)
+	}
+	if c.SnapshotEvery < 1 {
+		return fmt.Errorf("snapshot_every must be at least 1, got %d", c.SnapshotEvery)
+	}
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"rate_limit", c.RateLimit},
+		{"max_tasks_per_owner", c.MaxTasksPerOwner},
+		{"max_tasks_per_list", c.MaxTasksPerList},
+	}
+	for _, l := range limits {
+		if l.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", l.name, l.value)
+		}
+	}
+	if c.RateLimit > 0 && c.RateBurst < 1 {
+		return fmt.Errorf("rate_burst must be at least 1 with a rate limit, got %d", c.RateBurst)
+	}
+	return nil
+}
+
+// yaml returns c in the config file format.
+func (c *Config) yaml() ([]byte, error) {
+	return yaml.Marshal(c)
+}
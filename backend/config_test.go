@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+// envMap returns a getenv function backed by env.
+func envMap(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, printConfig, err := loadConfig(nil, envMap(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if printConfig {
+		t.Error("loadConfig() printConfig = true, want false")
+	}
+	if want := defaultConfig(); !reflect.DeepEqual(cfg, want) {
+		t.Errorf("loadConfig() = %+v, want defaults %+v", cfg, want)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+listen_addr: ":7000"
+write_timeout: 20s
+store: file
+data: from-file.json
+allowed_origins: ["https://todo.example.com"]
+`)
+	env := map[string]string{
+		"TODO_CONFIG":        path,
+		"TODO_WRITE_TIMEOUT": "30s",
+		"TODO_DATA":          "from-env.json",
+	}
+	args := []string{"-data", "from-flag.json", "--print-config"}
+
+	cfg, printConfig, err := loadConfig(args, envMap(env), io.Discard)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if !printConfig {
+		t.Error("loadConfig() printConfig = false, want true")
+	}
+	if cfg.ListenAddr != ":7000" {
+		t.Errorf("ListenAddr = %q, want the file's value", cfg.ListenAddr)
+	}
+	if cfg.WriteTimeout != 30*time.Second {
+		t.Errorf("WriteTimeout = %s, want the environment's value", cfg.WriteTimeout)
+	}
+	if cfg.DataPath != "from-flag.json" {
+		t.Errorf("DataPath = %q, want the flag's value", cfg.DataPath)
+	}
+	if cfg.ReadTimeout != defaultConfig().ReadTimeout {
+		t.Errorf("ReadTimeout = %s, want the default", cfg.ReadTimeout)
+	}
+	if want := []string{"https://todo.example.com"}; !reflect.DeepEqual(cfg.AllowedOrigins, want) {
+		t.Errorf("AllowedOrigins = %v, want %v", cfg.AllowedOrigins, want)
+	}
+}
+
+func TestLoadConfigListValues(t *testing.T) {
+	env := map[string]string{"TODO_ALLOWED_ORIGINS": " http://a.example , http://b.example:3000,"}
+	cfg, _, err := loadConfig(nil, envMap(env), io.Discard)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if want := []string{"http://a.example", "http://b.example:3000"}; !reflect.DeepEqual(cfg.AllowedOrigins, want) {
+		t.Errorf("AllowedOrigins = %v, want %v", cfg.AllowedOrigins, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{name: "bad listen address", args: []string{"-listen", "8080"}, wantErr: "listen address"},
+		{name: "bad duration flag", args: []string{"-read-timeout", "soon"}, wantErr: "read-timeout"},
+		{name: "bad duration env", env: map[string]string{"TODO_IDLE_TIMEOUT": "forever"}, wantErr: "TODO_IDLE_TIMEOUT"},
+		{name: "zero timeout", args: []string{"-shutdown-timeout", "0s"}, wantErr: "shutdown_timeout"},
+		{name: "zero trash retention", env: map[string]string{"TODO_TRASH_RETENTION": "0s"}, wantErr: "trash_retention"},
+		{name: "max task length too small", args: []string{"-max-task-length", "0"}, wantErr: "max_task_length"},
+		{name: "max task length not a number", env: map[string]string{"TODO_MAX_TASK_LENGTH": "lots"}, wantErr: "TODO_MAX_TASK_LENGTH"},
+		{name: "unknown store", args: []string{"-store", "postgres"}, wantErr: "unknown store"},
+		{name: "file store without path", args: []string{"-store", "file", "-data", ""}, wantErr: "data"},
+		{name: "wal store without path", args: []string{"-store", "wal", "-data", ""}, wantErr: "data"},
+		{name: "file store without workspace dir", args: []string{"-store", "file", "-workspace-dir", ""}, wantErr: "workspace_dir"},
+		{name: "unknown wal sync", env: map[string]string{"TODO_WAL_SYNC": "sometimes"}, wantErr: "wal_sync"},
+		{name: "zero wal sync interval", args: []string{"-wal-sync-interval", "0s"}, wantErr: "wal_sync_interval"},
+		{name: "zero snapshot interval", args: []string{"-snapshot-every", "0"}, wantErr: "snapshot_every"},
+		{name: "negative rate limit", args: []string{"-rate-limit", "-1"}, wantErr: "rate_limit"},
+		{name: "zero rate burst", env: map[string]string{"TODO_RATE_BURST": "0"}, wantErr: "rate_burst"},
+		{name: "negative task cap", args: []string{"-max-tasks-per-list", "-5"}, wantErr: "max_tasks_per_list"},
+		{name: "origin with path", args: []string{"-allowed-origins", "http://example.com/app"}, wantErr: "allowed origin"},
+		{name: "unknown file key", file: "listen: \":80\"\n", wantErr: "listen"},
+		{name: "missing file", env: map[string]string{"TODO_CONFIG": "/does/not/exist.yaml"}, wantErr: "config file"},
+		{name: "unknown flag", args: []string{"-port", "80"}, wantErr: "port"},
+		{name: "extra argument", args: []string{"serve"}, wantErr: "unexpected arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tt.env
+			if tt.file != "" {
+				env = map[string]string{"TODO_CONFIG": writeConfigFile(t, tt.file)}
+			}
+			_, _, err := loadConfig(tt.args, envMap(env), io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadConfig() error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigYAMLRoundTrip(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.AllowedOrigins = []string{"https://todo.example.com", "*"}
+	cfg.IdleTimeout = 90 * time.Second
+
+	out, err := cfg.yaml()
+	if err != nil {
+		t.Fatalf("yaml() error = %v", err)
+	}
+	env := map[string]string{"TODO_CONFIG": writeConfigFile(t, string(out))}
+	got, _, err := loadConfig(nil, envMap(env), io.Discard)
+	if err != nil {
+		t.Fatalf("loadConfig() of printed config error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("loadConfig() of printed config = %+v, want %+v", got, cfg)
+	}
+}
+
+func TestMaxTaskTextLengthOption(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore(), WithMaxTaskTextLength(10))
+	if _, err := server.AddTask(context.Background(), connect.NewRequest(&todov1.AddTaskRequest{Text: "short"})); err != nil {
+		t.Errorf("AddTask() within limit error = %v", err)
+	}
+	_, err := server.AddTask(context.Background(), connect.NewRequest(&todov1.AddTaskRequest{Text: "far too long for it"}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("AddTask() over limit error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+}
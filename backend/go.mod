@@ -7,6 +7,7 @@ require (
 	github.com/rs/cors v1.11.1
 	golang.org/x/net v0.44.0
 	google.golang.org/protobuf v1.36.9
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/text v0.29.0 // indirect
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+// DefaultHistoryRetention is how long events stay in the task history before
+// the janitor drops them.
+const DefaultHistoryRetention = 90 * 24 * time.Hour
+
+var ErrInvalidEventRange = errors.New("until must be later than since")
+
+// WithHistoryRetention replaces DefaultHistoryRetention as the time events
+// are kept in the task history.
+func WithHistoryRetention(d time.Duration) ServerOption {
+	return func(s *TodoServer) {
+		s.historyRetention = d
+	}
+}
+
+// lockAs takes s.mu for a change made by user, whom the task history records
+// as the actor, and returns the function that releases it.
+func (s *TodoServer) lockAs(user string) (unlock func()) {
+	s.mu.Lock()
+	s.actor = user
+	return func() {
+		s.actor = ""
+		s.mu.Unlock()
+	}
+}
+
+// recordEvent makes a change to task, made by s.actor, by calling store with
+// the event that records it, so that the store writes both together. While a
+// batch is applied store is passed nil instead and the event is held back
+// until the batch commits. Callers must hold s.mu.
+func (s *TodoServer) recordEvent(typ todov1.HistoryEventType, task *todov1.Task, store func(ev *todov1.HistoryEvent) error) error {
+	ev := &todov1.HistoryEvent{
+		Type:       typ,
+		Task:       proto.Clone(task).(*todov1.Task),
+		Actor:      s.actor,
+		OccurredAt: s.now().Unix(),
+	}
+	if typ == todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED {
+		ev.Task = purgedTask(task)
+	}
+	if s.holding {
+		if err := store(nil); err != nil {
+			return err
+		}
+		s.heldHistory = append(s.heldHistory, ev)
+		return nil
+	}
+	ev.Seq = s.lastEventSeq + 1
+	if err := store(ev); err != nil {
+		return err
+	}
+	s.lastEventSeq = ev.Seq
+	return nil
+}
+
+// purgedTask returns what the PURGED event of task keeps of it: no more than
+// is needed to tell who may see the event.
+func purgedTask(task *todov1.Task) *todov1.Task {
+	return &todov1.Task{Id: task.Id, OwnerId: task.OwnerId, ListId: task.ListId}
+}
+
+// appendEvent numbers ev and stores it. Callers must hold s.mu.
+func (s *TodoServer) appendEvent(ev *todov1.HistoryEvent) error {
+	ev.Seq = s.lastEventSeq + 1
+	if err := s.store.AppendEvent(ev); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	s.lastEventSeq = ev.Seq
+	return nil
+}
+
+// compactHistory drops the events older than the retention from the history,
+// except those the store keeps so that replaying it still gives the stored
+// tasks.
+func (s *TodoServer) compactHistory() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.store.CompactHistory(s.now().Add(-s.historyRetention).Unix()); err != nil {
+		return fmt.Errorf("failed to compact history: %w", err)
+	}
+	return nil
+}
+
+// replayHistory rebuilds the live and trashed tasks, by ID, from events in
+// the order they were recorded.
+func replayHistory(events []*todov1.HistoryEvent) (tasks, trash map[string]*todov1.Task) {
+	tasks = make(map[string]*todov1.Task)
+	trash = make(map[string]*todov1.Task)
+	for _, ev := range events {
+		id := ev.Task.GetId()
+		switch ev.Type {
+		case todov1.HistoryEventType_HISTORY_EVENT_TYPE_CREATED,
+			todov1.HistoryEventType_HISTORY_EVENT_TYPE_UPDATED,
+			todov1.HistoryEventType_HISTORY_EVENT_TYPE_RESTORED:
+			tasks[id] = ev.Task
+			delete(trash, id)
+		case todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED:
+			delete(tasks, id)
+			trash[id] = ev.Task
+		case todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED:
+			delete(tasks, id)
+			delete(trash, id)
+		}
+	}
+	return tasks, trash
+}
+
+// loadHistory picks up numbering after the stored events and records, with
+// no actor, whatever changes to the stored tasks the history is missing, so
+// that replaying it gives the stored tasks again. That covers data written
+// before the history existed and changes whose event a crash lost.
+func (s *TodoServer) loadHistory() error {
+	events, err := s.store.ListEvents()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	if len(events) > 0 {
+		s.lastEventSeq = events[len(events)-1].Seq
+	}
+	replayedTasks, replayedTrash := replayHistory(events)
+
+	tasks, err := s.store.ListTasks()
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+	trash, err := s.store.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to load trash: %w", err)
+	}
+	byID := func(a, b *todov1.Task) int { return strings.Compare(a.Id, b.Id) }
+	slices.SortFunc(tasks, byID)
+	slices.SortFunc(trash, byID)
+
+	for _, task := range tasks {
+		replayed, ok := replayedTasks[task.Id]
+		delete(replayedTasks, task.Id)
+		typ := todov1.HistoryEventType_HISTORY_EVENT_TYPE_UPDATED
+		switch {
+		case ok && proto.Equal(replayed, task):
+			continue
+		case replayedTrash[task.Id] != nil:
+			typ = todov1.HistoryEventType_HISTORY_EVENT_TYPE_RESTORED
+			delete(replayedTrash, task.Id)
+		case !ok:
+			typ = todov1.HistoryEventType_HISTORY_EVENT_TYPE_CREATED
+		}
+		if err := s.recordEvent(typ, task, s.store.AppendEvent); err != nil {
+			return err
+		}
+	}
+	for _, task := range trash {
+		replayed, ok := replayedTrash[task.Id]
+		delete(replayedTrash, task.Id)
+		if ok && proto.Equal(replayed, task) {
+			continue
+		}
+		delete(replayedTasks, task.Id)
+		if err := s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED, task, s.store.AppendEvent); err != nil {
+			return err
+		}
+	}
+	gone := slices.Concat(slices.Collect(maps.Values(replayedTasks)), slices.Collect(maps.Values(replayedTrash)))
+	slices.SortFunc(gone, byID)
+	for _, task := range gone {
+		if err := s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED, task, s.store.AppendEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TodoServer) GetTaskHistory(
+	ctx context.Context,
+	req *connect.Request[todov1.GetTaskHistoryRequest],
+) (*connect.Response[todov1.GetTaskHistoryResponse], error) {
+	id := req.Msg.TaskId
+	if strings.TrimSpace(id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events, err := s.store.ListEvents()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load history: %w", err))
+	}
+
+	resp := &todov1.GetTaskHistoryResponse{}
+	for _, ev := range events {
+		if ev.Task.GetId() == id {
+			resp.Events = append(resp.Events, ev)
+		}
+	}
+	if len(resp.Events) == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	}
+	if err := s.authorizeTask(ctx, resp.Events[len(resp.Events)-1].Task, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *TodoServer) ListEvents(
+	ctx context.Context,
+	req *connect.Request[todov1.ListEventsRequest],
+) (*connect.Response[todov1.ListEventsResponse], error) {
+	msg := req.Msg
+	if msg.PageSize < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidPageSize)
+	}
+	if msg.Until != 0 && msg.Until <= msg.Since {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidEventRange)
+	}
+	var after int64
+	if msg.PageToken != "" {
+		var err error
+		if after, err = decodeEventToken(msg.PageToken); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	s.mu.RLock()
+	events, err := s.store.ListEvents()
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load history: %w", err))
+	}
+	access, err := s.accessOf(ctx)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+
+	limit := pageLimit(msg.PageSize)
+	resp := &todov1.ListEventsResponse{}
+	for _, ev := range events {
+		if ev.Seq <= after || !access.canView(ev.Task) ||
+			ev.OccurredAt < msg.Since || (msg.Until != 0 && ev.OccurredAt >= msg.Until) {
+			continue
+		}
+		if len(resp.Events) == limit {
+			resp.NextPageToken = encodeEventToken(resp.Events[limit-1].Seq)
+			break
+		}
+		resp.Events = append(resp.Events, ev)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// encodeEventToken returns the opaque token that resumes ListEvents after the
+// event numbered seq.
+func encodeEventToken(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+func decodeEventToken(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	seq, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return seq, nil
+}
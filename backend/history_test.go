@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"todo-list/todo/v1"
+)
+
+func historyTypes(events []*todov1.HistoryEvent) []todov1.HistoryEventType {
+	var types []todov1.HistoryEventType
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+func mustGetTaskHistory(t *testing.T, ctx context.Context, server *TodoServer, id string) []*todov1.HistoryEvent {
+	t.Helper()
+	resp, err := server.GetTaskHistory(ctx, connect.NewRequest(&todov1.GetTaskHistoryRequest{TaskId: id}))
+	if err != nil {
+		t.Fatalf("GetTaskHistory(%s) error = %v", id, err)
+	}
+	return resp.Msg.Events
+}
+
+// checkReplay checks that replaying the stored history gives the stored
+// tasks and trash.
+func checkReplay(t *testing.T, store TaskStore) {
+	t.Helper()
+	events, err := store.ListEvents()
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	want := readStoreState(t, store)
+	tasks, trash := replayHistory(events)
+	checkStoreState(t, storeState{tasks: tasks, trash: trash, lists: want.lists}, want)
+}
+
+func TestTaskHistory(t *testing.T) {
+	for _, ts := range testStores {
+		t.Run(ts.name, func(t *testing.T) {
+			store := ts.open(t)
+			defer store.Close()
+			testTaskHistory(t, store)
+		})
+	}
+}
+
+func testTaskHistory(t *testing.T, store TaskStore) {
+	clock := newFakeClock()
+	server := mustNewServer(t, store, WithClock(clock.Now))
+	alice := withUser(context.Background(), "alice")
+
+	added, err := server.AddTask(alice, connect.NewRequest(&todov1.AddTaskRequest{Text: "Water plants"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	id := added.Msg.Task.Id
+	clock.Advance(time.Minute)
+	if _, err := server.UpdateTask(alice, connect.NewRequest(renameRequest(id, "Water the plants"))); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	// Completing twice changes the task once.
+	for range 2 {
+		if _, err := server.CompleteTask(alice, connect.NewRequest(&todov1.CompleteTaskRequest{Id: id})); err != nil {
+			t.Fatalf("CompleteTask() error = %v", err)
+		}
+	}
+	if _, err := server.DeleteTask(alice, connect.NewRequest(&todov1.DeleteTaskRequest{Id: id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := server.RestoreTask(alice, connect.NewRequest(&todov1.RestoreTaskRequest{Id: id})); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if _, err := server.DeleteTask(alice, connect.NewRequest(&todov1.DeleteTaskRequest{Id: id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	events := mustGetTaskHistory(t, alice, server, id)
+	wantTypes := []todov1.HistoryEventType{
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_CREATED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_UPDATED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_UPDATED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_RESTORED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED,
+	}
+	if got := historyTypes(events); !reflect.DeepEqual(got, wantTypes) {
+		t.Fatalf("GetTaskHistory() types = %v, want %v", got, wantTypes)
+	}
+	for i, ev := range events {
+		if ev.Actor != "alice" {
+			t.Errorf("event %d actor = %q, want %q", i, ev.Actor, "alice")
+		}
+		if i > 0 && ev.Seq <= events[i-1].Seq {
+			t.Errorf("event %d seq = %d, not after %d", i, ev.Seq, events[i-1].Seq)
+		}
+	}
+	if got := events[0]; got.Task.Text != "Water plants" || got.OccurredAt != added.Msg.Task.CreatedAt {
+		t.Errorf("created event = %v, want the task as added", got)
+	}
+	if got := events[1]; got.Task.Text != "Water the plants" || got.OccurredAt != added.Msg.Task.CreatedAt+60 {
+		t.Errorf("update event = %v, want the renamed task a minute later", got)
+	}
+	if got := events[2]; !got.Task.Completed {
+		t.Errorf("completion event = %v, want a completed task", got)
+	}
+
+	// Emptying the trash is the server's doing, and leaves nothing of the
+	// task but the record that it existed.
+	clock.Advance(DefaultTrashRetention)
+	if err := server.purgeExpiredTrash(); err != nil {
+		t.Fatalf("purgeExpiredTrash() error = %v", err)
+	}
+	events = mustGetTaskHistory(t, alice, server, id)
+	if len(events) != 1 || events[0].Type != todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED || events[0].Actor != "" {
+		t.Fatalf("GetTaskHistory() after purge = %v, want one PURGED event without an actor", events)
+	}
+	if got, want := events[0].Task, (&todov1.Task{Id: id, OwnerId: "alice"}); !proto.Equal(got, want) {
+		t.Errorf("purge event task = %v, want only %v", got, want)
+	}
+
+	bob := withUser(context.Background(), "bob")
+	if _, err := server.GetTaskHistory(bob, connect.NewRequest(&todov1.GetTaskHistoryRequest{TaskId: id})); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("GetTaskHistory() of another user's task error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+	if _, err := server.GetTaskHistory(alice, connect.NewRequest(&todov1.GetTaskHistoryRequest{TaskId: "missing"})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("GetTaskHistory() of a missing task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	checkReplay(t, store)
+}
+
+func TestHistoryReplay(t *testing.T) {
+	for _, ts := range testStores {
+		t.Run(ts.name, func(t *testing.T) {
+			store := ts.open(t)
+			defer store.Close()
+
+			fillStore(t, store)
+			server := mustNewServer(t, store)
+			ctx := context.Background()
+			parent := mustAddTask(t, server, "Plan trip", "")
+			mustAddSubtask(t, server, "Book flights", parent.Id)
+			mustUndo(t, ctx, server)
+			imported, err := server.ImportTasks(ctx, connect.NewRequest(&todov1.ImportTasksRequest{
+				Format: todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+				Data:   []byte("- [ ] Pack\n- [x] Renew passport\n"),
+			}))
+			if err != nil {
+				t.Fatalf("ImportTasks() error = %v", err)
+			}
+
+			before, err := store.ListEvents()
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			// A batch that is rolled back leaves no trace.
+			_, err = server.BatchUpdateTasks(ctx, connect.NewRequest(&todov1.BatchUpdateTasksRequest{
+				Requests: []*todov1.UpdateTaskRequest{
+					renameRequest(imported.Msg.Tasks[0].Id, "Pack bags"),
+					renameRequest("missing", "Nothing"),
+				},
+			}))
+			if connect.CodeOf(err) != connect.CodeNotFound {
+				t.Fatalf("BatchUpdateTasks() error = %v, want code %v", err, connect.CodeNotFound)
+			}
+			after, err := store.ListEvents()
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			if len(after) != len(before) {
+				t.Errorf("a rolled back batch added %d events", len(after)-len(before))
+			}
+			checkReplay(t, store)
+		})
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+	start := clock.Now().Unix()
+
+	var ids []string
+	for _, text := range []string{"First", "Second", "Third"} {
+		resp, err := server.AddTask(alice, connect.NewRequest(&todov1.AddTaskRequest{Text: text}))
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+		ids = append(ids, resp.Msg.Task.Id)
+		if _, err := server.AddTask(bob, connect.NewRequest(&todov1.AddTaskRequest{Text: "Bob's " + text})); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+		clock.Advance(time.Hour)
+	}
+
+	listEvents := func(req *todov1.ListEventsRequest) ([]string, string) {
+		t.Helper()
+		resp, err := server.ListEvents(alice, connect.NewRequest(req))
+		if err != nil {
+			t.Fatalf("ListEvents(%v) error = %v", req, err)
+		}
+		var got []string
+		for _, ev := range resp.Msg.Events {
+			got = append(got, ev.Task.Id)
+		}
+		return got, resp.Msg.NextPageToken
+	}
+
+	if got, _ := listEvents(&todov1.ListEventsRequest{}); !reflect.DeepEqual(got, ids) {
+		t.Errorf("ListEvents() = %v, want alice's %v", got, ids)
+	}
+	if got, _ := listEvents(&todov1.ListEventsRequest{Since: start + 3600, Until: start + 7200}); !reflect.DeepEqual(got, ids[1:2]) {
+		t.Errorf("ListEvents() in the second hour = %v, want %v", got, ids[1:2])
+	}
+
+	got, token := listEvents(&todov1.ListEventsRequest{PageSize: 2})
+	if !reflect.DeepEqual(got, ids[:2]) || token == "" {
+		t.Fatalf("ListEvents() first page = %v, %q; want %v and a token", got, token, ids[:2])
+	}
+	got, token = listEvents(&todov1.ListEventsRequest{PageSize: 2, PageToken: token})
+	if !reflect.DeepEqual(got, ids[2:]) || token != "" {
+		t.Errorf("ListEvents() second page = %v, %q; want %v and no token", got, token, ids[2:])
+	}
+
+	for _, req := range []*todov1.ListEventsRequest{
+		{PageSize: -1},
+		{PageToken: "not a token"},
+		{Since: start + 60, Until: start},
+	} {
+		if _, err := server.ListEvents(alice, connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("ListEvents(%v) error = %v, want code %v", req, err, connect.CodeInvalidArgument)
+		}
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	for _, ts := range testStores {
+		t.Run(ts.name, func(t *testing.T) {
+			store := ts.open(t)
+			defer store.Close()
+
+			clock := newFakeClock()
+			server := mustNewServer(t, store, WithClock(clock.Now), WithHistoryRetention(24*time.Hour))
+			ctx := context.Background()
+			kept := mustAddTask(t, server, "Kept", "")
+			if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: kept.Id})); err != nil {
+				t.Fatalf("CompleteTask() error = %v", err)
+			}
+			gone := mustAddTask(t, server, "Gone", "")
+			mustDeleteTask(t, server, gone.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+			if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: gone.Id})); err != nil {
+				t.Fatalf("PurgeTask() error = %v", err)
+			}
+			clock.Advance(24*time.Hour + time.Second)
+			recent := mustAddTask(t, server, "Recent", "")
+
+			if err := server.compactHistory(); err != nil {
+				t.Fatalf("compactHistory() error = %v", err)
+			}
+			events, err := store.ListEvents()
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			var got []string
+			for _, ev := range events {
+				got = append(got, ev.Task.Id+" "+ev.Type.String())
+			}
+			want := []string{
+				kept.Id + " HISTORY_EVENT_TYPE_UPDATED",
+				recent.Id + " HISTORY_EVENT_TYPE_CREATED",
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("history after compaction = %v, want %v", got, want)
+			}
+			checkReplay(t, store)
+		})
+	}
+}
+
+func TestLoadHistoryRecordsExistingTasks(t *testing.T) {
+	store := NewMemoryStore()
+	for _, task := range []*todov1.Task{
+		{Id: "a", Text: "Kept", CreatedAt: 1, Position: "a0"},
+		{Id: "b", Text: "Trashed", CreatedAt: 2, Position: "a1"},
+	} {
+		if err := store.CreateTask(task, nil); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+	if err := store.TrashTask("b", 3, nil); err != nil {
+		t.Fatalf("TrashTask() error = %v", err)
+	}
+
+	mustNewServer(t, store)
+	events, err := store.ListEvents()
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	wantTypes := []todov1.HistoryEventType{
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_CREATED,
+		todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED,
+	}
+	if got := historyTypes(events); !reflect.DeepEqual(got, wantTypes) {
+		t.Errorf("history of existing tasks = %v, want %v", got, wantTypes)
+	}
+	checkReplay(t, store)
+
+	// A history that matches the tasks is left alone.
+	mustNewServer(t, store)
+	if again, _ := store.ListEvents(); len(again) != len(events) {
+		t.Errorf("restart added %d events", len(again)-len(events))
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"todo-list/todo/v1"
+)
+
+// historyLog keeps the task history of a file or WAL store in a file of its
+// own, one JSON event per line, oldest first. New events are appended to it,
+// so recording an event costs the same however long the history is. The file
+// is only rewritten when events are dropped from the middle, as by
+// CompactHistory.
+type historyLog struct {
+	path    string
+	seq     int64 // of the last event in the file
+	size    int64 // of the file up to the end of the last complete line
+	dropped int   // the memoryStore's dropped count the file reflects, or -1
+}
+
+// openHistoryLog reads the history log at path into mem, after any events
+// mem already holds. Events that mem already holds, as read from a store file
+// written before the history log, are skipped. A missing file is treated as
+// an empty log and a torn last line, left by a crash in the middle of an
+// append, is cut off.
+func openHistoryLog(path string, mem *memoryStore) (*historyLog, error) {
+	l := &historyLog{path: path, dropped: -1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if mem.lastEventSeq() == 0 {
+			l.dropped = mem.dropped
+		}
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	last := mem.lastEventSeq()
+	var off int
+	for off < len(data) {
+		end := bytes.IndexByte(data[off:], '\n')
+		if end < 0 {
+			log.Printf("Discarding torn event at the end of %s (%d bytes)", path, len(data)-off)
+			if err := os.Truncate(path, int64(off)); err != nil {
+				return nil, fmt.Errorf("failed to cut off torn event: %w", err)
+			}
+			break
+		}
+		ev := &todov1.HistoryEvent{}
+		if err := protojson.Unmarshal(data[off:off+end], ev); err != nil {
+			return nil, fmt.Errorf("failed to parse event at offset %d of %s: %w", off, path, err)
+		}
+		off += end + 1
+		l.seq = ev.Seq
+		if ev.Seq > last {
+			mem.events = append(mem.events, ev)
+		}
+	}
+	l.size = int64(off)
+	if mem.lastEventSeq() == l.seq {
+		// Otherwise mem holds events the file lacks, so it is rewritten on
+		// the first sync.
+		l.dropped = mem.dropped
+	}
+	return l, nil
+}
+
+// sync brings the file up to date with the history of mem: it appends the
+// events added since the last sync or, if events were dropped since, rewrites
+// the file. A failed append is cut back off the file so that the next sync
+// starts from a complete line.
+func (l *historyLog) sync(mem *memoryStore) error {
+	events, _ := mem.ListEvents()
+	dropped := mem.markHistory().dropped
+	if dropped != l.dropped {
+		return l.rewrite(events, dropped)
+	}
+
+	var buf bytes.Buffer
+	var seq int64
+	for _, ev := range events {
+		if ev.Seq <= l.seq {
+			continue
+		}
+		b, err := protojson.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %d: %w", ev.Seq, err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+		seq = ev.Seq
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	_, err = f.Write(buf.Bytes())
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		if terr := f.Truncate(l.size); terr != nil {
+			// Rewrite the whole file next time.
+			l.dropped = -1
+		}
+		f.Close()
+		return fmt.Errorf("failed to append to history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		l.dropped = -1
+		return fmt.Errorf("failed to close history: %w", err)
+	}
+	l.seq = seq
+	l.size += int64(buf.Len())
+	return nil
+}
+
+// rewrite atomically replaces the file with events, which reflect dropped.
+func (l *historyLog) rewrite(events []*todov1.HistoryEvent, dropped int) error {
+	var buf bytes.Buffer
+	for _, ev := range events {
+		b, err := protojson.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %d: %w", ev.Seq, err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	if err := writeFileAtomic(l.path, buf.Bytes()); err != nil {
+		return err
+	}
+	l.seq = 0
+	if len(events) > 0 {
+		l.seq = events[len(events)-1].Seq
+	}
+	l.size = int64(buf.Len())
+	l.dropped = dropped
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"todo-list/todo/v1"
+)
+
+// watcherBufferSize is how many events may queue for a single watcher before
+// it is considered too slow and disconnected.
+const watcherBufferSize = 64
+
+var (
+	ErrWatcherTooSlow = errors.New("watcher fell too far behind; reconnect and reload tasks")
+	ErrServerStopping = errors.New("server is shutting down")
+)
+
+// taskHub fans task events out to every active watcher. Publishing never
+// blocks: a watcher whose buffer is full is dropped, so one slow client cannot
+// stall mutations or other watchers.
+type taskHub struct {
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+	closed bool
+}
+
+// subscription is a single watcher's view of the hub. Its events channel is
+// closed when the watcher is dropped or the hub shuts down; err then reports
+// why.
+type subscription struct {
+	user   string // only events about tasks this user may see are delivered
+	events chan *todov1.TaskEvent
+	err    error // set under taskHub.mu before events is closed
+}
+
+func newTaskHub() *taskHub {
+	return &taskHub{
+		subs: make(map[*subscription]struct{}),
+	}
+}
+
+// subscribe registers a new watcher for the tasks user may see. Callers must
+// unsubscribe when done.
+func (h *taskHub) subscribe(user string) *subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &subscription{user: user, events: make(chan *todov1.TaskEvent, watcherBufferSize)}
+	if h.closed {
+		sub.err = ErrServerStopping
+		close(sub.events)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe removes sub from the hub. It is safe to call more than once and
+// after the hub has dropped sub.
+func (h *taskHub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.events)
+	}
+}
+
+// publish delivers ev to every watcher of viewers, the users who may see the
+// task, dropping watchers that cannot keep up.
+func (h *taskHub) publish(ev *todov1.TaskEvent, viewers ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if !slices.Contains(viewers, sub.user) {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			delete(h.subs, sub)
+			sub.err = ErrWatcherTooSlow
+			close(sub.events)
+		}
+	}
+}
+
+// close disconnects every watcher and rejects new ones.
+func (h *taskHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		sub.err = ErrServerStopping
+		close(sub.events)
+	}
+}
+
+// reason returns why sub's events channel was closed.
+func (h *taskHub) reason(sub *subscription) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return sub.err
+}
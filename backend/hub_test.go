@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+func TestTaskHubDropsSlowSubscriber(t *testing.T) {
+	hub := newTaskHub()
+	slow := hub.subscribe("")
+	fast := hub.subscribe("")
+	defer hub.unsubscribe(fast)
+
+	for i := 0; i <= watcherBufferSize; i++ {
+		hub.publish(&todov1.TaskEvent{Type: todov1.TaskEventType_TASK_EVENT_TYPE_ADDED}, "")
+		<-fast.events
+	}
+
+	for range slow.events {
+		// Drain what was buffered; the channel must then be closed.
+	}
+	if err := hub.reason(slow); err != ErrWatcherTooSlow {
+		t.Errorf("reason() = %v, want %v", err, ErrWatcherTooSlow)
+	}
+
+	hub.publish(&todov1.TaskEvent{Type: todov1.TaskEventType_TASK_EVENT_TYPE_DELETED}, "")
+	if ev := <-fast.events; ev.Type != todov1.TaskEventType_TASK_EVENT_TYPE_DELETED {
+		t.Errorf("fast subscriber got %v, want a deletion event", ev.Type)
+	}
+	hub.unsubscribe(slow) // must be a no-op for a dropped subscriber
+}
+
+func TestTaskHubClose(t *testing.T) {
+	hub := newTaskHub()
+	sub := hub.subscribe("")
+	hub.close()
+
+	if _, ok := <-sub.events; ok {
+		t.Fatal("events channel still open after close()")
+	}
+	if err := hub.reason(sub); err != ErrServerStopping {
+		t.Errorf("reason() = %v, want %v", err, ErrServerStopping)
+	}
+
+	late := hub.subscribe("")
+	if _, ok := <-late.events; ok {
+		t.Error("subscribe() after close() returned an open channel")
+	}
+}
+
+// waitForWatchers blocks until server has n active watchers.
+func waitForWatchers(t *testing.T, server *TodoServer, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		server.hub.mu.Lock()
+		got := len(server.hub.subs)
+		server.hub.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d watchers", n)
+}
+
+func TestWatchTasks(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	_, handler := todov1.NewTodoServiceHandler(server)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	client := connect.NewClient[todov1.WatchTasksRequest, todov1.WatchTasksResponse](
+		httpServer.Client(),
+		httpServer.URL+"/todo.v1.TodoService/WatchTasks",
+		connect.WithProtoJSON(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.CallServerStream(ctx, connect.NewRequest(&todov1.WatchTasksRequest{}))
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	defer stream.Close()
+	waitForWatchers(t, server, 1)
+
+	addResp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Watch me"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	id := addResp.Msg.Task.Id
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	want := []todov1.TaskEventType{
+		todov1.TaskEventType_TASK_EVENT_TYPE_ADDED,
+		todov1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+		todov1.TaskEventType_TASK_EVENT_TYPE_DELETED,
+	}
+	for i, typ := range want {
+		if !stream.Receive() {
+			t.Fatalf("Receive() #%d failed: %v", i, stream.Err())
+		}
+		ev := stream.Msg().Event
+		if ev.Type != typ || ev.Task.GetId() != id {
+			t.Errorf("event #%d = (%v, %s), want (%v, %s)", i, ev.Type, ev.Task.GetId(), typ, id)
+		}
+	}
+
+	// Closing the server ends the stream with an error the client can see.
+	server.Close()
+	if stream.Receive() {
+		t.Fatalf("Receive() after Close() got %v, want end of stream", stream.Msg())
+	}
+	if code := connect.CodeOf(stream.Err()); code != connect.CodeUnavailable {
+		t.Errorf("stream error = %v, want code %v", stream.Err(), connect.CodeUnavailable)
+	}
+	waitForWatchers(t, server, 0)
+}
+
+func TestWatchTasksClientDisconnect(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	_, handler := todov1.NewTodoServiceHandler(server)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	client := connect.NewClient[todov1.WatchTasksRequest, todov1.WatchTasksResponse](
+		httpServer.Client(),
+		httpServer.URL+"/todo.v1.TodoService/WatchTasks",
+		connect.WithProtoJSON(),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.CallServerStream(ctx, connect.NewRequest(&todov1.WatchTasksRequest{}))
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	waitForWatchers(t, server, 1)
+
+	cancel()
+	stream.Close()
+	waitForWatchers(t, server, 0)
+}
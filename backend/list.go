@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+const MaxListNameLength = 100
+
+var (
+	ErrListNotFound        = errors.New("list not found")
+	ErrInvalidListID       = errors.New("invalid list ID")
+	ErrListNameEmpty       = errors.New("list name cannot be empty")
+	ErrListNameTooLong     = errors.New("list name exceeds maximum length")
+	ErrListNameTaken       = errors.New("a list with this name already exists")
+	ErrListNotEmpty        = errors.New("list still has tasks; choose whether to delete or move them")
+	ErrInvalidDeletePolicy = errors.New("unknown list delete policy")
+	ErrMoveToSameList      = errors.New("cannot move tasks into the list being deleted")
+)
+
+// validateListName trims surrounding whitespace from name and checks that
+// what remains is a usable list name.
+func validateListName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrListNameEmpty
+	}
+	if len(name) > MaxListNameLength {
+		return ErrListNameTooLong
+	}
+	return nil
+}
+
+// checkListName returns ErrListNameTaken if another of owner's lists than the
+// one with the given ID already uses name, ignoring case. Callers must hold
+// s.mu.
+func (s *TodoServer) checkListName(owner, id, name string) error {
+	lists, err := s.store.ListLists()
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+	for _, list := range lists {
+		if list.OwnerId == owner && list.Id != id && strings.EqualFold(list.Name, name) {
+			return connect.NewError(connect.CodeAlreadyExists, ErrListNameTaken)
+		}
+	}
+	return nil
+}
+
+// loadList returns the list with the given ID after checking that the caller
+// has at least role need on it, converting store errors into connect errors.
+// Callers must hold s.mu.
+func (s *TodoServer) loadList(ctx context.Context, id string, need todov1.ListRole) (*todov1.List, error) {
+	list, err := s.store.GetList(id)
+	if errors.Is(err, ErrListNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, ErrListNotFound)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load list: %w", err))
+	}
+	if err := checkRole(listRole(list, userFromContext(ctx)), need); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *TodoServer) CreateList(
+	ctx context.Context,
+	req *connect.Request[todov1.CreateListRequest],
+) (*connect.Response[todov1.CreateListResponse], error) {
+	if err := validateListName(req.Msg.Name); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	name := strings.TrimSpace(req.Msg.Name)
+	owner := userFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkListName(owner, "", name); err != nil {
+		return nil, err
+	}
+	for i := 0; i < 10; i++ {
+		id, err := generateID()
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate list ID: %w", err))
+		}
+		list := &todov1.List{
+			Id:        id,
+			Name:      name,
+			CreatedAt: s.now().Unix(),
+			OwnerId:   owner,
+		}
+		err = s.store.CreateList(list)
+		if errors.Is(err, ErrListExists) {
+			continue
+		}
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store list: %w", err))
+		}
+		return connect.NewResponse(&todov1.CreateListResponse{List: list}), nil
+	}
+	return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate unique list ID"))
+}
+
+func (s *TodoServer) GetLists(
+	ctx context.Context,
+	req *connect.Request[todov1.GetListsRequest],
+) (*connect.Response[todov1.GetListsResponse], error) {
+	s.mu.RLock()
+	all, err := s.store.ListLists()
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+
+	user := userFromContext(ctx)
+	var lists []*todov1.List
+	for _, list := range all {
+		if listRole(list, user) != todov1.ListRole_LIST_ROLE_UNSPECIFIED {
+			lists = append(lists, list)
+		}
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		if lists[i].CreatedAt != lists[j].CreatedAt {
+			return lists[i].CreatedAt < lists[j].CreatedAt
+		}
+		return lists[i].Id < lists[j].Id
+	})
+	return connect.NewResponse(&todov1.GetListsResponse{Lists: lists}), nil
+}
+
+func (s *TodoServer) RenameList(
+	ctx context.Context,
+	req *connect.Request[todov1.RenameListRequest],
+) (*connect.Response[todov1.RenameListResponse], error) {
+	if strings.TrimSpace(req.Msg.Id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidListID)
+	}
+	if err := validateListName(req.Msg.Name); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	name := strings.TrimSpace(req.Msg.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.loadList(ctx, req.Msg.Id, todov1.ListRole_LIST_ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkListName(current.OwnerId, current.Id, name); err != nil {
+		return nil, err
+	}
+	list := proto.Clone(current).(*todov1.List)
+	list.Name = name
+	if err := s.store.UpdateList(list); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store list: %w", err))
+	}
+	return connect.NewResponse(&todov1.RenameListResponse{List: list}), nil
+}
+
+// DeleteList removes a list after dealing with its tasks as the request's
+// policy asks. The tasks are handled one at a time before the list itself is
+// removed, so if the store fails part way the list survives and the call can
+// simply be retried.
+func (s *TodoServer) DeleteList(
+	ctx context.Context,
+	req *connect.Request[todov1.DeleteListRequest],
+) (*connect.Response[todov1.DeleteListResponse], error) {
+	id := req.Msg.Id
+	if strings.TrimSpace(id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidListID)
+	}
+	policy := req.Msg.Policy
+	if _, ok := todov1.ListDeletePolicy_name[int32(policy)]; !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidDeletePolicy)
+	}
+	moveTo := req.Msg.MoveToListId
+	if policy == todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE && moveTo == id {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrMoveToSameList)
+	}
+
+	defer s.lockAs(userFromContext(ctx))()
+
+	if _, err := s.loadList(ctx, id, todov1.ListRole_LIST_ROLE_OWNER); err != nil {
+		return nil, err
+	}
+	if policy == todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE && moveTo != "" {
+		if _, err := s.loadList(ctx, moveTo, todov1.ListRole_LIST_ROLE_EDITOR); err != nil {
+			return nil, err
+		}
+	}
+
+	all, err := s.store.ListTasks()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
+	}
+	var tasks []*todov1.Task
+	for _, task := range all {
+		if task.ListId == id {
+			tasks = append(tasks, task)
+		}
+	}
+
+	switch policy {
+	case todov1.ListDeletePolicy_LIST_DELETE_POLICY_CASCADE:
+		for _, task := range tasks {
+			if err := s.deleteTask(task); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete task: %w", err))
+			}
+		}
+	case todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE:
+		if err := s.checkListRoom(moveTo, len(tasks)); err != nil {
+			return nil, err
+		}
+		for _, current := range tasks {
+			task := proto.Clone(current).(*todov1.Task)
+			task.ListId = moveTo
+			if err := s.replaceTask(current, task); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to move task: %w", err))
+			}
+		}
+	default:
+		if len(tasks) > 0 {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, ErrListNotEmpty)
+		}
+	}
+
+	if err := s.store.DeleteList(id); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete list: %w", err))
+	}
+	return connect.NewResponse(&todov1.DeleteListResponse{Success: true}), nil
+}
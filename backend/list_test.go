@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func mustCreateList(t *testing.T, server *TodoServer, name string) *todov1.List {
+	t.Helper()
+	resp, err := server.CreateList(context.Background(), connect.NewRequest(&todov1.CreateListRequest{Name: name}))
+	if err != nil {
+		t.Fatalf("CreateList(%q) error = %v", name, err)
+	}
+	return resp.Msg.List
+}
+
+func mustAddTask(t *testing.T, server *TodoServer, text, listID string) *todov1.Task {
+	t.Helper()
+	resp, err := server.AddTask(context.Background(), connect.NewRequest(&todov1.AddTaskRequest{Text: text, ListId: listID}))
+	if err != nil {
+		t.Fatalf("AddTask(%q) error = %v", text, err)
+	}
+	return resp.Msg.Task
+}
+
+// listTaskIDs returns the IDs of the tasks in the list with the given ID,
+// oldest first; an empty ID returns every task.
+func listTaskIDs(t *testing.T, server *TodoServer, listID string) []string {
+	t.Helper()
+	resp, err := server.GetTasks(context.Background(), connect.NewRequest(&todov1.GetTasksRequest{
+		ListId:  listID,
+		OrderBy: todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST,
+	}))
+	if err != nil {
+		t.Fatalf("GetTasks(%q) error = %v", listID, err)
+	}
+	return taskIDs(resp.Msg.Tasks)
+}
+
+func TestLists(t *testing.T) {
+	forEachStore(t, testLists)
+}
+
+func testLists(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	work := mustCreateList(t, server, "  Work  ")
+	if work.Name != "Work" || work.Id == "" || work.CreatedAt == 0 {
+		t.Errorf("CreateList() = %v, want trimmed name, ID and creation time", work)
+	}
+	home := mustCreateList(t, server, "Home")
+
+	_, err := server.CreateList(ctx, connect.NewRequest(&todov1.CreateListRequest{Name: "work"}))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists {
+		t.Errorf("CreateList() duplicate name error = %v, want code %v", err, connect.CodeAlreadyExists)
+	}
+
+	renamed, err := server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Id: home.Id, Name: "Household"}))
+	if err != nil {
+		t.Fatalf("RenameList() error = %v", err)
+	}
+	if renamed.Msg.List.Name != "Household" || renamed.Msg.List.CreatedAt != home.CreatedAt {
+		t.Errorf("RenameList() = %v, want new name and original creation time", renamed.Msg.List)
+	}
+	if _, err := server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Id: home.Id, Name: "household"})); err != nil {
+		t.Errorf("RenameList() changing only case error = %v", err)
+	}
+	_, err = server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Id: home.Id, Name: "WORK"}))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists {
+		t.Errorf("RenameList() to taken name error = %v, want code %v", err, connect.CodeAlreadyExists)
+	}
+
+	resp, err := server.GetLists(ctx, connect.NewRequest(&todov1.GetListsRequest{}))
+	if err != nil {
+		t.Fatalf("GetLists() error = %v", err)
+	}
+	// Both lists were probably created in the same second, so only the set
+	// of names is stable.
+	var names []string
+	for _, list := range resp.Msg.Lists {
+		names = append(names, list.Name)
+	}
+	sort.Strings(names)
+	if want := []string{"Work", "household"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetLists() names = %v, want %v", names, want)
+	}
+}
+
+func TestListValidation(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+	list := mustCreateList(t, server, "Work")
+	longName := strings.Repeat("a", MaxListNameLength+1)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode connect.Code
+	}{
+		{
+			name: "create with empty name",
+			call: func() error {
+				_, err := server.CreateList(ctx, connect.NewRequest(&todov1.CreateListRequest{Name: "   "}))
+				return err
+			},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "create with long name",
+			call: func() error {
+				_, err := server.CreateList(ctx, connect.NewRequest(&todov1.CreateListRequest{Name: longName}))
+				return err
+			},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "rename missing list",
+			call: func() error {
+				_, err := server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Id: "missing", Name: "Other"}))
+				return err
+			},
+			wantCode: connect.CodeNotFound,
+		},
+		{
+			name: "rename without ID",
+			call: func() error {
+				_, err := server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Name: "Other"}))
+				return err
+			},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "delete missing list",
+			call: func() error {
+				_, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{Id: "missing"}))
+				return err
+			},
+			wantCode: connect.CodeNotFound,
+		},
+		{
+			name: "delete with unknown policy",
+			call: func() error {
+				_, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{Id: list.Id, Policy: 99}))
+				return err
+			},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "move tasks into the deleted list",
+			call: func() error {
+				_, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+					Id:           list.Id,
+					Policy:       todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+					MoveToListId: list.Id,
+				}))
+				return err
+			},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name: "move tasks to missing list",
+			call: func() error {
+				_, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+					Id:           list.Id,
+					Policy:       todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+					MoveToListId: "missing",
+				}))
+				return err
+			},
+			wantCode: connect.CodeNotFound,
+		},
+		{
+			name: "add task to missing list",
+			call: func() error {
+				_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Lost", ListId: "missing"}))
+				return err
+			},
+			wantCode: connect.CodeNotFound,
+		},
+		{
+			name: "get tasks of missing list",
+			call: func() error {
+				_, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{ListId: "missing"}))
+				return err
+			},
+			wantCode: connect.CodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); connect.CodeOf(err) != tt.wantCode {
+				t.Errorf("error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestTasksScopedToList(t *testing.T) {
+	forEachStore(t, testTasksScopedToList)
+}
+
+func testTasksScopedToList(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	work := mustCreateList(t, server, "Work")
+	home := mustCreateList(t, server, "Home")
+	inbox := mustAddTask(t, server, "Unsorted", "")
+	report := mustAddTask(t, server, "Write report", work.Id)
+	dishes := mustAddTask(t, server, "Do dishes", home.Id)
+
+	if report.ListId != work.Id {
+		t.Errorf("AddTask() list = %q, want %q", report.ListId, work.Id)
+	}
+	if got, want := listTaskIDs(t, server, work.Id), []string{report.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTasks(work) = %v, want %v", got, want)
+	}
+	if got := listTaskIDs(t, server, ""); len(got) != 3 {
+		t.Errorf("GetTasks() without list returned %v, want all 3 tasks", got)
+	}
+
+	_, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: dishes.Id, ListId: work.Id}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("DeleteTask() from wrong list error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: dishes.Id, ListId: home.Id})); err != nil {
+		t.Errorf("DeleteTask() from its list error = %v", err)
+	}
+
+	moved, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         inbox.Id,
+		Task:       &todov1.Task{ListId: home.Id},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"list_id"}},
+	}))
+	if err != nil {
+		t.Fatalf("UpdateTask() moving list error = %v", err)
+	}
+	if moved.Msg.Task.ListId != home.Id || moved.Msg.Task.Text != inbox.Text {
+		t.Errorf("UpdateTask() = %v, want task moved to %q with its text kept", moved.Msg.Task, home.Id)
+	}
+	_, err = server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         inbox.Id,
+		Task:       &todov1.Task{ListId: "missing"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"list_id"}},
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("UpdateTask() to missing list error = %v, want code %v", err, connect.CodeNotFound)
+	}
+}
+
+func TestDeleteList(t *testing.T) {
+	forEachStore(t, testDeleteList)
+}
+
+func testDeleteList(t *testing.T, newServer func() *TodoServer) {
+	ctx := context.Background()
+
+	t.Run("refuses non-empty list by default", func(t *testing.T) {
+		server := newServer()
+		list := mustCreateList(t, server, "Work")
+		task := mustAddTask(t, server, "Write report", list.Id)
+
+		_, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{Id: list.Id}))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("DeleteList() error = %v, want code %v", err, connect.CodeFailedPrecondition)
+		}
+		if got := listTaskIDs(t, server, list.Id); !reflect.DeepEqual(got, []string{task.Id}) {
+			t.Errorf("tasks after refused delete = %v, want %v", got, []string{task.Id})
+		}
+	})
+
+	t.Run("deletes empty list by default", func(t *testing.T) {
+		server := newServer()
+		list := mustCreateList(t, server, "Work")
+		if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{Id: list.Id})); err != nil {
+			t.Fatalf("DeleteList() error = %v", err)
+		}
+		resp, err := server.GetLists(ctx, connect.NewRequest(&todov1.GetListsRequest{}))
+		if err != nil {
+			t.Fatalf("GetLists() error = %v", err)
+		}
+		if len(resp.Msg.Lists) != 0 {
+			t.Errorf("GetLists() after delete = %v, want none", resp.Msg.Lists)
+		}
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		server := newServer()
+		list := mustCreateList(t, server, "Work")
+		mustAddTask(t, server, "Write report", list.Id)
+		keep := mustAddTask(t, server, "Unsorted", "")
+
+		if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+			Id:     list.Id,
+			Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_CASCADE,
+		})); err != nil {
+			t.Fatalf("DeleteList() error = %v", err)
+		}
+		if got := listTaskIDs(t, server, ""); !reflect.DeepEqual(got, []string{keep.Id}) {
+			t.Errorf("tasks after cascade = %v, want %v", got, []string{keep.Id})
+		}
+	})
+
+	t.Run("move to inbox", func(t *testing.T) {
+		server := newServer()
+		list := mustCreateList(t, server, "Work")
+		task := mustAddTask(t, server, "Write report", list.Id)
+
+		if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+			Id:     list.Id,
+			Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+		})); err != nil {
+			t.Fatalf("DeleteList() error = %v", err)
+		}
+		got, err := server.store.GetTask(task.Id)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		if got.ListId != "" {
+			t.Errorf("moved task list = %q, want the inbox", got.ListId)
+		}
+	})
+
+	t.Run("move to another list", func(t *testing.T) {
+		server := newServer()
+		list := mustCreateList(t, server, "Work")
+		archive := mustCreateList(t, server, "Archive")
+		task := mustAddTask(t, server, "Write report", list.Id)
+
+		if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+			Id:           list.Id,
+			Policy:       todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+			MoveToListId: archive.Id,
+		})); err != nil {
+			t.Fatalf("DeleteList() error = %v", err)
+		}
+		if got := listTaskIDs(t, server, archive.Id); !reflect.DeepEqual(got, []string{task.Id}) {
+			t.Errorf("tasks in destination = %v, want %v", got, []string{task.Id})
+		}
+	})
+}
+
+func TestFileStorePersistsLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	list := mustCreateList(t, mustNewServer(t, store), "Work")
+	store.Close()
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after restart error = %v", err)
+	}
+	defer reopened.Close()
+	got, err := reopened.GetList(list.Id)
+	if err != nil {
+		t.Fatalf("GetList() after restart error = %v", err)
+	}
+	if got.Name != list.Name || got.CreatedAt != list.CreatedAt {
+		t.Errorf("GetList() after restart = %v, want %v", got, list)
+	}
+}
+
+func TestOpenFileStoreReadsTaskArray(t *testing.T) {
+	// Files written before lists existed hold a bare array of tasks.
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte(`[{"id":"abc","text":"Old task","createdAt":"1"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	defer store.Close()
+	task, err := store.GetTask("abc")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.Text != "Old task" || task.ListId != "" {
+		t.Errorf("GetTask() = %v, want the old task in the inbox", task)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"todo-list/todo/v1"
+)
+
+// MaxPageSize caps how many tasks a single GetTasks call returns. Larger
+// page sizes are silently reduced to it.
+const MaxPageSize = 1000
+
+var (
+	ErrInvalidPageSize  = errors.New("page size cannot be negative")
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// pageCursor records the sort key of the last task on a page so the next
+// page can resume strictly after it, whatever was added or removed since.
+type pageCursor struct {
+	Order     todov1.TaskOrder `json:"o,omitempty"`
+	CreatedAt int64            `json:"c,omitempty"`
+	ID        string           `json:"i"`
+	Text      string           `json:"t,omitempty"`
+	Position  string           `json:"p,omitempty"`
+	Priority  todov1.Priority  `json:"r,omitempty"`
+}
+
+func cursorAfter(order todov1.TaskOrder, task *todov1.Task) pageCursor {
+	c := pageCursor{Order: order, CreatedAt: task.CreatedAt, ID: task.Id}
+	switch order {
+	case todov1.TaskOrder_TASK_ORDER_ALPHABETICAL:
+		c.Text = task.Text
+	case todov1.TaskOrder_TASK_ORDER_PRIORITY:
+		c.Priority = task.Priority
+		c.Position = task.Position
+	case todov1.TaskOrder_TASK_ORDER_POSITION:
+		c.Position = task.Position
+	}
+	return c
+}
+
+// task returns a stand-in carrying the cursor's sort key, for comparing
+// against real tasks with the query's ordering.
+func (c pageCursor) task() *todov1.Task {
+	return &todov1.Task{Id: c.ID, CreatedAt: c.CreatedAt, Text: c.Text, Position: c.Position, Priority: c.Priority}
+}
+
+// encodePageToken returns the opaque token that resumes a listing at c.
+func encodePageToken(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return c, ErrInvalidPageToken
+	}
+	return c, nil
+}
+
+// pageLimit converts a requested page size into the number of tasks to
+// return. Zero means no limit, for clients that predate pagination.
+func pageLimit(size int32) int {
+	switch {
+	case size == 0:
+		return -1
+	case size > MaxPageSize:
+		return MaxPageSize
+	default:
+		return int(size)
+	}
+}
+
+// paginate returns the page of tasks, already filtered and sorted in q's
+// order, that follows q's cursor, along with the token for the next page.
+func paginate(tasks []*todov1.Task, q *taskQuery) ([]*todov1.Task, string) {
+	start := 0
+	if q.after != nil {
+		after := q.after.task()
+		start = sort.Search(len(tasks), func(i int) bool { return q.less(after, tasks[i]) })
+	}
+	tasks = tasks[start:]
+	if q.limit < 0 || len(tasks) <= q.limit {
+		return tasks, ""
+	}
+	tasks = tasks[:q.limit]
+	return tasks, encodePageToken(cursorAfter(q.order, tasks[len(tasks)-1]))
+}
+
+// taskKey is a task's position in creation order: newest first, ties broken
+// by ID in descending order.
+type taskKey struct {
+	CreatedAt int64
+	ID        string
+}
+
+func keyOf(task *todov1.Task) taskKey {
+	return taskKey{CreatedAt: task.CreatedAt, ID: task.Id}
+}
+
+// before reports whether k sorts ahead of other.
+func (k taskKey) before(other taskKey) bool {
+	if k.CreatedAt == other.CreatedAt {
+		return k.ID > other.ID
+	}
+	return k.CreatedAt > other.CreatedAt
+}
+
+// orderIndex keeps every task's key in creation order so that newest-first
+// and oldest-first pages can be located with a binary search instead of
+// sorting all tasks on each call.
+// It is not safe for concurrent use; TodoServer guards it with its mutex.
+type orderIndex struct {
+	keys []taskKey
+}
+
+func newOrderIndex(tasks []*todov1.Task) *orderIndex {
+	keys := make([]taskKey, 0, len(tasks))
+	for _, task := range tasks {
+		keys = append(keys, keyOf(task))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].before(keys[j]) })
+	return &orderIndex{keys: keys}
+}
+
+// search returns the position of the first key that does not sort ahead of k.
+func (x *orderIndex) search(k taskKey) int {
+	return sort.Search(len(x.keys), func(i int) bool { return !x.keys[i].before(k) })
+}
+
+func (x *orderIndex) insert(task *todov1.Task) {
+	k := keyOf(task)
+	i := x.search(k)
+	x.keys = append(x.keys, taskKey{})
+	copy(x.keys[i+1:], x.keys[i:])
+	x.keys[i] = k
+}
+
+func (x *orderIndex) remove(task *todov1.Task) {
+	k := keyOf(task)
+	if i := x.search(k); i < len(x.keys) && x.keys[i] == k {
+		x.keys = append(x.keys[:i], x.keys[i+1:]...)
+	}
+}
+
+// page walks the index in q's order (which must be newest or oldest first)
+// from just after q's cursor, loading tasks from store and keeping those
+// accepted by q until the page is full.
+func (x *orderIndex) page(store TaskStore, q *taskQuery) ([]*todov1.Task, string, error) {
+	oldestFirst := q.order == todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST
+
+	// Index positions are visited from i towards the end (or the start, for
+	// oldest first).
+	i, step := 0, 1
+	if oldestFirst {
+		i, step = len(x.keys)-1, -1
+	}
+	if q.after != nil {
+		k := taskKey{CreatedAt: q.after.CreatedAt, ID: q.after.ID}
+		at := x.search(k)
+		switch {
+		case oldestFirst:
+			i = at - 1
+		case at < len(x.keys) && x.keys[at] == k:
+			i = at + 1
+		default:
+			i = at
+		}
+	}
+
+	var tasks []*todov1.Task
+	for ; i >= 0 && i < len(x.keys); i += step {
+		task, err := store.GetTask(x.keys[i].ID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !q.match(task) {
+			continue
+		}
+		if q.limit >= 0 && len(tasks) == q.limit {
+			return tasks, encodePageToken(cursorAfter(q.order, tasks[len(tasks)-1])), nil
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, "", nil
+}
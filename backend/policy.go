@@ -0,0 +1,389 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrInsufficientRole = errors.New("your role on the list does not allow this")
+	ErrInvalidRole      = errors.New("unknown list role")
+	ErrInvalidUserID    = errors.New("invalid user ID")
+	ErrShareWithCreator = errors.New("the list's creator always owns it")
+	ErrNotListMember    = errors.New("the list is not shared with this user")
+)
+
+// listRole returns the role user has on list: owner if they created it, and
+// otherwise the one the list was shared with them under, if any.
+func listRole(list *todov1.List, user string) todov1.ListRole {
+	if list.OwnerId == user {
+		return todov1.ListRole_LIST_ROLE_OWNER
+	}
+	for _, m := range list.Members {
+		if m.UserId == user {
+			return m.Role
+		}
+	}
+	return todov1.ListRole_LIST_ROLE_UNSPECIFIED
+}
+
+// checkRole returns a permission-denied error unless have allows what needs
+// need. A caller with no role at all is told the resource is someone else's.
+func checkRole(have, need todov1.ListRole) error {
+	switch {
+	case have >= need:
+		return nil
+	case have == todov1.ListRole_LIST_ROLE_UNSPECIFIED:
+		return connect.NewError(connect.CodePermissionDenied, ErrPermissionDenied)
+	default:
+		return connect.NewError(connect.CodePermissionDenied, ErrInsufficientRole)
+	}
+}
+
+// taskRole returns the role user has on task: their role on the task's list
+// if it is in one, so that taking away a role on a list also takes it away
+// on the tasks the user created there, and otherwise owner of the tasks they
+// created. A task whose list is gone, as in the trash, is its creator's
+// again. Callers must hold s.mu.
+func (s *TodoServer) taskRole(user string, task *todov1.Task) (todov1.ListRole, error) {
+	if task.ListId != "" {
+		list, err := s.store.GetList(task.ListId)
+		if err == nil {
+			return listRole(list, user), nil
+		}
+		if !errors.Is(err, ErrListNotFound) {
+			return 0, err
+		}
+	}
+	if task.OwnerId == user {
+		return todov1.ListRole_LIST_ROLE_OWNER, nil
+	}
+	return todov1.ListRole_LIST_ROLE_UNSPECIFIED, nil
+}
+
+// authorizeTask returns a permission-denied error unless the caller has at
+// least role need on task. Callers must hold s.mu.
+func (s *TodoServer) authorizeTask(ctx context.Context, task *todov1.Task, need todov1.ListRole) error {
+	have, err := s.taskRole(userFromContext(ctx), task)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load list: %w", err))
+	}
+	return checkRole(have, need)
+}
+
+// checkPlacement returns a permission-denied error unless user may add tasks
+// where task goes: under its parent, if it has one, and otherwise in its
+// list. A missing parent or list is left for setParent and insertTask to
+// report. Callers must hold s.mu.
+func (s *TodoServer) checkPlacement(user string, task *todov1.Task) error {
+	if task.ParentId != "" {
+		parent, err := s.store.GetTask(task.ParentId)
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load parent task: %w", err))
+		}
+		have, err := s.taskRole(user, parent)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load list: %w", err))
+		}
+		return checkRole(have, todov1.ListRole_LIST_ROLE_EDITOR)
+	}
+	if task.ListId == "" {
+		return nil
+	}
+	list, err := s.store.GetList(task.ListId)
+	if errors.Is(err, ErrListNotFound) {
+		return nil
+	}
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load list: %w", err))
+	}
+	return checkRole(listRole(list, user), todov1.ListRole_LIST_ROLE_EDITOR)
+}
+
+// access is what a user may see: the tasks in the lists they have a role on
+// and the tasks they created outside any list, by the rules of taskRole.
+type access struct {
+	user  string
+	lists map[string]todov1.ListRole // of every list, by ID; unspecified for none
+}
+
+// accessOf returns the access of the caller of a call with ctx. Callers must
+// hold s.mu.
+func (s *TodoServer) accessOf(ctx context.Context) (access, error) {
+	a := access{user: userFromContext(ctx), lists: make(map[string]todov1.ListRole)}
+	lists, err := s.store.ListLists()
+	if err != nil {
+		return access{}, err
+	}
+	for _, list := range lists {
+		a.lists[list.Id] = listRole(list, a.user)
+	}
+	return a, nil
+}
+
+// canView reports whether the user may see task.
+func (a access) canView(task *todov1.Task) bool {
+	if role, ok := a.lists[task.ListId]; ok && task.ListId != "" {
+		return role != todov1.ListRole_LIST_ROLE_UNSPECIFIED
+	}
+	return task.OwnerId == a.user
+}
+
+// viewers returns the users who may see task: the owner and members of its
+// list or, outside any list, its creator. Callers must hold s.mu.
+func (s *TodoServer) viewers(task *todov1.Task) []string {
+	if task.GetListId() == "" {
+		return []string{task.GetOwnerId()}
+	}
+	list, err := s.store.GetList(task.ListId)
+	if err != nil {
+		return []string{task.GetOwnerId()}
+	}
+	users := []string{list.OwnerId}
+	for _, m := range list.Members {
+		users = append(users, m.UserId)
+	}
+	return users
+}
+
+func validateRole(role todov1.ListRole) error {
+	if role == todov1.ListRole_LIST_ROLE_UNSPECIFIED {
+		return ErrInvalidRole
+	}
+	if _, ok := todov1.ListRole_name[int32(role)]; !ok {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+func (s *TodoServer) ShareList(
+	ctx context.Context,
+	req *connect.Request[todov1.ShareListRequest],
+) (*connect.Response[todov1.ShareListResponse], error) {
+	if strings.TrimSpace(req.Msg.ListId) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidListID)
+	}
+	user := strings.TrimSpace(req.Msg.UserId)
+	if user == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidUserID)
+	}
+	if err := validateRole(req.Msg.Role); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.loadList(ctx, req.Msg.ListId, todov1.ListRole_LIST_ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+	if user == current.OwnerId {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrShareWithCreator)
+	}
+	list := proto.Clone(current).(*todov1.List)
+	i, found := slices.BinarySearchFunc(list.Members, user, func(m *todov1.ListMember, user string) int {
+		return strings.Compare(m.UserId, user)
+	})
+	if found {
+		list.Members[i].Role = req.Msg.Role
+	} else {
+		list.Members = slices.Insert(list.Members, i, &todov1.ListMember{UserId: user, Role: req.Msg.Role})
+	}
+	if err := s.store.UpdateList(list); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store list: %w", err))
+	}
+	return connect.NewResponse(&todov1.ShareListResponse{List: list}), nil
+}
+
+func (s *TodoServer) RevokeListAccess(
+	ctx context.Context,
+	req *connect.Request[todov1.RevokeListAccessRequest],
+) (*connect.Response[todov1.RevokeListAccessResponse], error) {
+	if strings.TrimSpace(req.Msg.ListId) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidListID)
+	}
+	user := strings.TrimSpace(req.Msg.UserId)
+	if user == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidUserID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Members may leave a list on their own.
+	need := todov1.ListRole_LIST_ROLE_OWNER
+	if user == userFromContext(ctx) {
+		need = todov1.ListRole_LIST_ROLE_VIEWER
+	}
+	current, err := s.loadList(ctx, req.Msg.ListId, need)
+	if err != nil {
+		return nil, err
+	}
+	if user == current.OwnerId {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrShareWithCreator)
+	}
+	i := slices.IndexFunc(current.Members, func(m *todov1.ListMember) bool { return m.UserId == user })
+	if i < 0 {
+		return nil, connect.NewError(connect.CodeNotFound, ErrNotListMember)
+	}
+	list := proto.Clone(current).(*todov1.List)
+	list.Members = slices.Delete(list.Members, i, i+1)
+	if err := s.store.UpdateList(list); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store list: %w", err))
+	}
+	return connect.NewResponse(&todov1.RevokeListAccessResponse{List: list}), nil
+}
+
+// listRoles looks up callers' roles for the policy interceptor.
+type listRoles interface {
+	// callerRole returns the role the caller of a call with ctx and header
+	// has on the list with the given ID, or false if there is no such list.
+	callerRole(ctx context.Context, header http.Header, listID string) (todov1.ListRole, bool, error)
+}
+
+func (s *TodoServer) callerRole(ctx context.Context, _ http.Header, listID string) (todov1.ListRole, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, err := s.store.GetList(listID)
+	if errors.Is(err, ErrListNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load list: %w", err))
+	}
+	return listRole(list, userFromContext(ctx)), true, nil
+}
+
+func (r *workspaceRouter) callerRole(ctx context.Context, header http.Header, listID string) (todov1.ListRole, bool, error) {
+	ws, err := r.enter(ctx, header)
+	if err != nil {
+		return 0, false, err
+	}
+	defer ws.calls.RUnlock()
+	return ws.server.callerRole(ctx, header, listID)
+}
+
+// listAccess is a list a call acts on and the role it needs there.
+type listAccess struct {
+	listID string
+	role   todov1.ListRole
+}
+
+// listPolicies gives, for each procedure whose request names lists, the
+// access a call from user with request msg needs. Calls that name tasks are
+// checked by the handlers, which load the tasks anyway.
+var listPolicies = map[string]func(msg any, user string) []listAccess{
+	"/todo.v1.TodoService/AddTask": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.AddTaskRequest).ListId, todov1.ListRole_LIST_ROLE_EDITOR}}
+	},
+	"/todo.v1.TodoService/BatchAddTasks": func(msg any, _ string) []listAccess {
+		var needs []listAccess
+		for _, req := range msg.(*todov1.BatchAddTasksRequest).Requests {
+			needs = append(needs, listAccess{req.GetListId(), todov1.ListRole_LIST_ROLE_EDITOR})
+		}
+		return needs
+	},
+	"/todo.v1.TodoService/ImportTasks": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.ImportTasksRequest).ListId, todov1.ListRole_LIST_ROLE_EDITOR}}
+	},
+	"/todo.v1.TodoService/GetTasks": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.GetTasksRequest).ListId, todov1.ListRole_LIST_ROLE_VIEWER}}
+	},
+	"/todo.v1.TodoService/ExportTasks": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.ExportTasksRequest).ListId, todov1.ListRole_LIST_ROLE_VIEWER}}
+	},
+	"/todo.v1.TodoService/ListTags": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.ListTagsRequest).ListId, todov1.ListRole_LIST_ROLE_VIEWER}}
+	},
+	"/todo.v1.TodoService/RenameList": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.RenameListRequest).Id, todov1.ListRole_LIST_ROLE_OWNER}}
+	},
+	"/todo.v1.TodoService/DeleteList": func(msg any, _ string) []listAccess {
+		req := msg.(*todov1.DeleteListRequest)
+		needs := []listAccess{{req.Id, todov1.ListRole_LIST_ROLE_OWNER}}
+		if req.Policy == todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE {
+			needs = append(needs, listAccess{req.MoveToListId, todov1.ListRole_LIST_ROLE_EDITOR})
+		}
+		return needs
+	},
+	"/todo.v1.TodoService/ShareList": func(msg any, _ string) []listAccess {
+		return []listAccess{{msg.(*todov1.ShareListRequest).ListId, todov1.ListRole_LIST_ROLE_OWNER}}
+	},
+	"/todo.v1.TodoService/RevokeListAccess": func(msg any, user string) []listAccess {
+		req := msg.(*todov1.RevokeListAccessRequest)
+		if req.UserId == user {
+			return []listAccess{{req.ListId, todov1.ListRole_LIST_ROLE_VIEWER}}
+		}
+		return []listAccess{{req.ListId, todov1.ListRole_LIST_ROLE_OWNER}}
+	},
+}
+
+// policyInterceptor refuses calls that act on a list the caller lacks the
+// role for before they reach the handler. Lists that do not exist are left
+// for the handler to report. It must run after the auth interceptor, which
+// identifies the caller.
+type policyInterceptor struct {
+	roles listRoles
+}
+
+func newPolicyInterceptor(roles listRoles) *policyInterceptor {
+	return &policyInterceptor{roles: roles}
+}
+
+// authorize checks a call to procedure with request msg and header.
+func (p *policyInterceptor) authorize(ctx context.Context, procedure string, msg any, header http.Header) error {
+	policy, ok := listPolicies[procedure]
+	if !ok {
+		return nil
+	}
+	for _, need := range policy(msg, userFromContext(ctx)) {
+		if need.listID == "" {
+			continue
+		}
+		have, found, err := p.roles.callerRole(ctx, header, need.listID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		if err := checkRole(have, need.role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *policyInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		spec, _ := todov1.SpecFromContext(ctx)
+		if err := p.authorize(ctx, spec.Procedure, req.Any(), req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (p *policyInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler passes streams through: WatchTasks names no list and
+// only delivers events the caller may see.
+func (p *policyInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
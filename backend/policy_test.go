@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+func mustShareList(t *testing.T, ctx context.Context, server *TodoServer, listID, user string, role todov1.ListRole) {
+	t.Helper()
+	req := &todov1.ShareListRequest{ListId: listID, UserId: user, Role: role}
+	if _, err := server.ShareList(ctx, connect.NewRequest(req)); err != nil {
+		t.Fatalf("ShareList(%v) error = %v", req, err)
+	}
+}
+
+func TestListSharing(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	created, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Team"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	list := created.Msg.List
+	added, err := server.AddTask(alice, connect.NewRequest(&todov1.AddTaskRequest{Text: "Plan sprint", ListId: list.Id}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	task := added.Msg.Task
+
+	getTasks := func(ctx context.Context) ([]*todov1.Task, error) {
+		resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{ListId: list.Id}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Tasks, nil
+	}
+	complete := func(ctx context.Context) error {
+		_, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id}))
+		return err
+	}
+	addTask := func(ctx context.Context) error {
+		_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Review", ListId: list.Id}))
+		return err
+	}
+
+	if _, err := getTasks(bob); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("GetTasks() before sharing error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+
+	// A viewer sees the list and its tasks but cannot change them.
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_VIEWER)
+	if tasks, err := getTasks(bob); err != nil || len(tasks) != 1 || tasks[0].Id != task.Id {
+		t.Errorf("GetTasks() as viewer = %v, %v; want alice's task", tasks, err)
+	}
+	all, err := server.GetTasks(bob, connect.NewRequest(&todov1.GetTasksRequest{}))
+	if err != nil || len(all.Msg.Tasks) != 1 {
+		t.Errorf("GetTasks() of every list as viewer = %v, %v; want alice's task", all, err)
+	}
+	lists, err := server.GetLists(bob, connect.NewRequest(&todov1.GetListsRequest{}))
+	if err != nil || len(lists.Msg.Lists) != 1 || len(lists.Msg.Lists[0].Members) != 1 {
+		t.Errorf("GetLists() as viewer = %v, %v; want the shared list with its member", lists, err)
+	}
+	if err := complete(bob); connect.CodeOf(err) != connect.CodePermissionDenied || !errors.Is(err, ErrInsufficientRole) {
+		t.Errorf("CompleteTask() as viewer error = %v, want %v", err, ErrInsufficientRole)
+	}
+	if err := addTask(bob); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("AddTask() as viewer error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+
+	// An editor changes tasks but not the list or its members.
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_EDITOR)
+	if err := complete(bob); err != nil {
+		t.Errorf("CompleteTask() as editor error = %v", err)
+	}
+	if err := addTask(bob); err != nil {
+		t.Errorf("AddTask() as editor error = %v", err)
+	}
+	if _, err := server.DeleteTask(bob, connect.NewRequest(&todov1.DeleteTaskRequest{Id: task.Id})); err != nil {
+		t.Errorf("DeleteTask() as editor error = %v", err)
+	}
+	denied := map[string]func() error{
+		"PurgeTask": func() error {
+			_, err := server.PurgeTask(bob, connect.NewRequest(&todov1.PurgeTaskRequest{Id: task.Id}))
+			return err
+		},
+		"RenameList": func() error {
+			_, err := server.RenameList(bob, connect.NewRequest(&todov1.RenameListRequest{Id: list.Id, Name: "Mine"}))
+			return err
+		},
+		"ShareList": func() error {
+			_, err := server.ShareList(bob, connect.NewRequest(&todov1.ShareListRequest{ListId: list.Id, UserId: "carol", Role: todov1.ListRole_LIST_ROLE_VIEWER}))
+			return err
+		},
+		"DeleteList": func() error {
+			_, err := server.DeleteList(bob, connect.NewRequest(&todov1.DeleteListRequest{Id: list.Id, Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_CASCADE}))
+			return err
+		},
+	}
+	for name, call := range denied {
+		if err := call(); connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Errorf("%s() as editor error = %v, want code %v", name, err, connect.CodePermissionDenied)
+		}
+	}
+	if _, err := server.RestoreTask(bob, connect.NewRequest(&todov1.RestoreTaskRequest{Id: task.Id})); err != nil {
+		t.Errorf("RestoreTask() as editor error = %v", err)
+	}
+
+	// Members may leave on their own.
+	revoked, err := server.RevokeListAccess(bob, connect.NewRequest(&todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "bob"}))
+	if err != nil {
+		t.Fatalf("RevokeListAccess() of own access error = %v", err)
+	}
+	if len(revoked.Msg.List.Members) != 0 {
+		t.Errorf("RevokeListAccess() members = %v, want none", revoked.Msg.List.Members)
+	}
+	if _, err := getTasks(bob); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("GetTasks() after revoking error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+}
+
+func TestRevokeListAccessCoversOwnTasks(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+	created, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Team"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	list := created.Msg.List
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_EDITOR)
+	added, err := server.AddTask(bob, connect.NewRequest(&todov1.AddTaskRequest{Text: "Bob's part", ListId: list.Id}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	own := added.Msg.Task
+	added, err = server.AddTask(bob, connect.NewRequest(&todov1.AddTaskRequest{Text: "Bob's errand"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	inbox := added.Msg.Task
+
+	// Creating a task in a list gives no more than the role on the list.
+	if _, err := server.DeleteTask(bob, connect.NewRequest(&todov1.DeleteTaskRequest{Id: own.Id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := server.PurgeTask(bob, connect.NewRequest(&todov1.PurgeTaskRequest{Id: own.Id})); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("PurgeTask() of own task as editor error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+	if _, err := server.RestoreTask(bob, connect.NewRequest(&todov1.RestoreTaskRequest{Id: own.Id})); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+
+	if _, err := server.RevokeListAccess(alice, connect.NewRequest(&todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "bob"})); err != nil {
+		t.Fatalf("RevokeListAccess() error = %v", err)
+	}
+	calls := map[string]func() error{
+		"GetTaskHistory": func() error {
+			_, err := server.GetTaskHistory(bob, connect.NewRequest(&todov1.GetTaskHistoryRequest{TaskId: own.Id}))
+			return err
+		},
+		"CompleteTask": func() error {
+			_, err := server.CompleteTask(bob, connect.NewRequest(&todov1.CompleteTaskRequest{Id: own.Id}))
+			return err
+		},
+		"UpdateTask": func() error {
+			_, err := server.UpdateTask(bob, connect.NewRequest(renameRequest(own.Id, "Mine now")))
+			return err
+		},
+		"DeleteTask": func() error {
+			_, err := server.DeleteTask(bob, connect.NewRequest(&todov1.DeleteTaskRequest{Id: own.Id}))
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Errorf("%s() of own task after revoking error = %v, want code %v", name, err, connect.CodePermissionDenied)
+		}
+	}
+	// The tasks in the inbox stay their creator's.
+	all, err := server.GetTasks(bob, connect.NewRequest(&todov1.GetTasksRequest{}))
+	if err != nil || len(all.Msg.Tasks) != 1 || all.Msg.Tasks[0].Id != inbox.Id {
+		t.Errorf("GetTasks() after revoking = %v, %v; want only bob's inbox task", all, err)
+	}
+	if _, err := server.CompleteTask(bob, connect.NewRequest(&todov1.CompleteTaskRequest{Id: inbox.Id})); err != nil {
+		t.Errorf("CompleteTask() of own inbox task error = %v", err)
+	}
+}
+
+func TestListSharingErrors(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	created, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Team"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	list := created.Msg.List
+
+	for _, tt := range []struct {
+		name string
+		req  *todov1.ShareListRequest
+		want connect.Code
+	}{
+		{"no list", &todov1.ShareListRequest{UserId: "bob", Role: todov1.ListRole_LIST_ROLE_VIEWER}, connect.CodeInvalidArgument},
+		{"no user", &todov1.ShareListRequest{ListId: list.Id, Role: todov1.ListRole_LIST_ROLE_VIEWER}, connect.CodeInvalidArgument},
+		{"no role", &todov1.ShareListRequest{ListId: list.Id, UserId: "bob"}, connect.CodeInvalidArgument},
+		{"unknown role", &todov1.ShareListRequest{ListId: list.Id, UserId: "bob", Role: 42}, connect.CodeInvalidArgument},
+		{"creator", &todov1.ShareListRequest{ListId: list.Id, UserId: "alice", Role: todov1.ListRole_LIST_ROLE_VIEWER}, connect.CodeInvalidArgument},
+		{"missing list", &todov1.ShareListRequest{ListId: "missing", UserId: "bob", Role: todov1.ListRole_LIST_ROLE_VIEWER}, connect.CodeNotFound},
+	} {
+		if _, err := server.ShareList(alice, connect.NewRequest(tt.req)); connect.CodeOf(err) != tt.want {
+			t.Errorf("ShareList() with %s error = %v, want code %v", tt.name, err, tt.want)
+		}
+	}
+
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_EDITOR)
+	bob := withUser(context.Background(), "bob")
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		req  *todov1.RevokeListAccessRequest
+		want connect.Code
+	}{
+		{"non-member", alice, &todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "carol"}, connect.CodeNotFound},
+		{"creator", alice, &todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "alice"}, connect.CodeInvalidArgument},
+		{"creator by member", bob, &todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "alice"}, connect.CodePermissionDenied},
+		{"outsider leaving", withUser(context.Background(), "carol"), &todov1.RevokeListAccessRequest{ListId: list.Id, UserId: "carol"}, connect.CodePermissionDenied},
+	} {
+		if _, err := server.RevokeListAccess(tt.ctx, connect.NewRequest(tt.req)); connect.CodeOf(err) != tt.want {
+			t.Errorf("RevokeListAccess() of %s error = %v, want code %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSharedListEvents(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	created, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Team"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	list := created.Msg.List
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_VIEWER)
+
+	bob := server.hub.subscribe("bob")
+	defer server.hub.unsubscribe(bob)
+	carol := server.hub.subscribe("carol")
+	defer server.hub.unsubscribe(carol)
+
+	if _, err := server.AddTask(alice, connect.NewRequest(&todov1.AddTaskRequest{Text: "Plan sprint", ListId: list.Id})); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if ev := <-bob.events; ev.Task.Text != "Plan sprint" {
+		t.Errorf("member got %v, want the added task", ev)
+	}
+	select {
+	case ev := <-carol.events:
+		t.Errorf("outsider got %v, want nothing", ev)
+	default:
+	}
+
+	events, err := server.ListEvents(withUser(context.Background(), "bob"), connect.NewRequest(&todov1.ListEventsRequest{}))
+	if err != nil || len(events.Msg.Events) != 1 {
+		t.Errorf("ListEvents() as member = %v, %v; want the task's creation", events, err)
+	}
+}
+
+// fakeListRoles gives the caller a fixed role on each list by ID.
+type fakeListRoles map[string]todov1.ListRole
+
+func (f fakeListRoles) callerRole(_ context.Context, _ http.Header, listID string) (todov1.ListRole, bool, error) {
+	role, ok := f[listID]
+	return role, ok, nil
+}
+
+func TestPolicyInterceptorAuthorize(t *testing.T) {
+	p := newPolicyInterceptor(fakeListRoles{
+		"viewed": todov1.ListRole_LIST_ROLE_VIEWER,
+		"edited": todov1.ListRole_LIST_ROLE_EDITOR,
+		"owned":  todov1.ListRole_LIST_ROLE_OWNER,
+		"other":  todov1.ListRole_LIST_ROLE_UNSPECIFIED,
+	})
+	ctx := withUser(context.Background(), "bob")
+	const service = "/todo.v1.TodoService/"
+
+	for _, tt := range []struct {
+		procedure string
+		msg       any
+		want      connect.Code // zero when the call is let through
+	}{
+		{"GetTasks", &todov1.GetTasksRequest{ListId: "viewed"}, 0},
+		{"GetTasks", &todov1.GetTasksRequest{ListId: "other"}, connect.CodePermissionDenied},
+		{"GetTasks", &todov1.GetTasksRequest{}, 0},
+		{"AddTask", &todov1.AddTaskRequest{ListId: "viewed"}, connect.CodePermissionDenied},
+		{"AddTask", &todov1.AddTaskRequest{ListId: "edited"}, 0},
+		{"AddTask", &todov1.AddTaskRequest{ListId: "missing"}, 0},
+		{"BatchAddTasks", &todov1.BatchAddTasksRequest{Requests: []*todov1.AddTaskRequest{{ListId: "edited"}, {ListId: "viewed"}}}, connect.CodePermissionDenied},
+		{"RenameList", &todov1.RenameListRequest{Id: "edited"}, connect.CodePermissionDenied},
+		{"RenameList", &todov1.RenameListRequest{Id: "owned"}, 0},
+		{"DeleteList", &todov1.DeleteListRequest{Id: "owned", Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE, MoveToListId: "viewed"}, connect.CodePermissionDenied},
+		{"ShareList", &todov1.ShareListRequest{ListId: "edited"}, connect.CodePermissionDenied},
+		{"RevokeListAccess", &todov1.RevokeListAccessRequest{ListId: "viewed", UserId: "bob"}, 0},
+		{"RevokeListAccess", &todov1.RevokeListAccessRequest{ListId: "viewed", UserId: "carol"}, connect.CodePermissionDenied},
+		{"CompleteTask", &todov1.CompleteTaskRequest{Id: "anything"}, 0},
+	} {
+		err := p.authorize(ctx, service+tt.procedure, tt.msg, http.Header{})
+		if got := connect.CodeOf(err); (err == nil) != (tt.want == 0) || (err != nil && got != tt.want) {
+			t.Errorf("authorize(%s, %v) error = %v, want code %v", tt.procedure, tt.msg, err, tt.want)
+		}
+	}
+}
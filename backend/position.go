@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrInvalidPriority = errors.New("unknown priority")
+	ErrInvalidMove     = errors.New("exactly one of before_id and after_id must be set")
+	ErrMoveToSelf      = errors.New("cannot move a task next to itself")
+	ErrTargetNotFound  = errors.New("target task not found")
+)
+
+// validatePriority checks that p is one of the defined priorities.
+func validatePriority(p todov1.Priority) error {
+	if _, ok := todov1.Priority_name[int32(p)]; !ok {
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// priorityRank maps p to its place in TASK_ORDER_PRIORITY, where tasks
+// without a priority come after P3.
+func priorityRank(p todov1.Priority) int {
+	if p == todov1.Priority_PRIORITY_UNSPECIFIED {
+		return len(todov1.Priority_name)
+	}
+	return int(p)
+}
+
+// assignPositions gives every task in tasks that has no position one after
+// all existing positions, oldest task first, and saves it to store. Tasks
+// stored before positions existed thus keep their creation order. It returns
+// the highest position in use.
+func assignPositions(store TaskStore, tasks []*todov1.Task) (string, error) {
+	last := ""
+	var unranked []int
+	for i, task := range tasks {
+		if task.Position == "" {
+			unranked = append(unranked, i)
+		} else if task.Position > last {
+			last = task.Position
+		}
+	}
+	sort.Slice(unranked, func(i, j int) bool {
+		a, b := tasks[unranked[i]], tasks[unranked[j]]
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+		return a.Id < b.Id
+	})
+	for _, i := range unranked {
+		pos, err := rankBetween(last, "")
+		if err != nil {
+			return "", err
+		}
+		task := proto.Clone(tasks[i]).(*todov1.Task)
+		task.Position = pos
+		task.Version++
+		if err := store.UpdateTask(task, nil); err != nil {
+			return "", err
+		}
+		tasks[i] = task
+		last = pos
+	}
+	return last, nil
+}
+
+// nextPosition returns a position after every task's, for a new task.
+// Callers must hold s.mu and pass the position to usePosition once the task
+// is stored.
+func (s *TodoServer) nextPosition() (string, error) {
+	return rankBetween(s.lastPosition, "")
+}
+
+// usePosition records that a task now holds pos. Callers must hold s.mu.
+func (s *TodoServer) usePosition(pos string) {
+	if pos > s.lastPosition {
+		s.lastPosition = pos
+	}
+}
+
+// MoveTask places a task directly before or after another task of the same
+// owner. Only the moved task gets a new position, computed between the
+// target and its neighbour, so concurrent moves never disturb other tasks'
+// positions; moves are serialized by s.mu, so each one sees the positions
+// left by the previous.
+func (s *TodoServer) MoveTask(
+	ctx context.Context,
+	req *connect.Request[todov1.MoveTaskRequest],
+) (*connect.Response[todov1.MoveTaskResponse], error) {
+	if strings.TrimSpace(req.Msg.Id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+	before := req.Msg.BeforeId != ""
+	targetID := req.Msg.AfterId
+	if before {
+		targetID = req.Msg.BeforeId
+	}
+	if before == (req.Msg.AfterId != "") {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidMove)
+	}
+	if targetID == req.Msg.Id {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrMoveToSelf)
+	}
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		target, err := s.store.GetTask(targetID)
+		if errors.Is(err, ErrTaskNotFound) {
+			return connect.NewError(connect.CodeNotFound, ErrTargetNotFound)
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+		}
+		if err := s.authorizeTask(ctx, target, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+			return err
+		}
+		access, err := s.accessOf(ctx)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+		}
+		lo, hi, err := s.neighbours(task, target, before, access)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
+		}
+		pos, err := rankBetween(lo, hi)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to compute position: %w", err))
+		}
+		task.Position = pos
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.MoveTaskResponse{Task: task}), nil
+}
+
+// neighbours returns the positions that bound the gap next to target where
+// moved should go: target's position and that of the closest task the
+// caller, whose access is given, sees on the requested side, or "" if there
+// is none. moved itself is ignored. Callers must hold s.mu.
+func (s *TodoServer) neighbours(moved, target *todov1.Task, before bool, access access) (string, string, error) {
+	tasks, err := s.store.ListTasks()
+	if err != nil {
+		return "", "", err
+	}
+	closest := ""
+	for _, task := range tasks {
+		if task.Id == moved.Id || !access.canView(task) {
+			continue
+		}
+		pos := task.Position
+		if before {
+			if pos < target.Position && pos > closest {
+				closest = pos
+			}
+		} else if pos > target.Position && (closest == "" || pos < closest) {
+			closest = pos
+		}
+	}
+	if before {
+		return closest, target.Position, nil
+	}
+	return target.Position, closest, nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+// tasksInOrder returns the texts of all tasks in the given order.
+func tasksInOrder(t *testing.T, server *TodoServer, order todov1.TaskOrder) []string {
+	t.Helper()
+	resp, err := server.GetTasks(context.Background(), connect.NewRequest(&todov1.GetTasksRequest{OrderBy: order}))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	texts := make([]string, 0, len(resp.Msg.Tasks))
+	for _, task := range resp.Msg.Tasks {
+		texts = append(texts, task.Text)
+	}
+	return texts
+}
+
+func TestMoveTask(t *testing.T) {
+	forEachStore(t, testMoveTask)
+}
+
+func testMoveTask(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+	ids := make(map[string]string)
+	for _, text := range []string{"a", "b", "c", "d"} {
+		ids[text] = mustAddTask(t, server, text, "").Id
+	}
+	position := todov1.TaskOrder_TASK_ORDER_POSITION
+	if got, want := tasksInOrder(t, server, position), []string{"a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("initial order = %v, want %v", got, want)
+	}
+
+	move := func(req *todov1.MoveTaskRequest) (*todov1.Task, error) {
+		resp, err := server.MoveTask(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Task, nil
+	}
+
+	before := tasksByID(t, server)
+	moved, err := move(&todov1.MoveTaskRequest{Id: ids["d"], BeforeId: ids["b"]})
+	if err != nil {
+		t.Fatalf("MoveTask(d before b) error = %v", err)
+	}
+	if got, want := tasksInOrder(t, server, position), []string{"a", "d", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after moving d before b = %v, want %v", got, want)
+	}
+	// Only the moved task's position changes.
+	for id, task := range tasksByID(t, server) {
+		if id != moved.Id && task.Position != before[id].Position {
+			t.Errorf("position of %q changed from %q to %q", task.Text, before[id].Position, task.Position)
+		}
+	}
+
+	if _, err := move(&todov1.MoveTaskRequest{Id: ids["a"], AfterId: ids["c"]}); err != nil {
+		t.Fatalf("MoveTask(a after c) error = %v", err)
+	}
+	if _, err := move(&todov1.MoveTaskRequest{Id: ids["c"], AfterId: ids["d"]}); err != nil {
+		t.Fatalf("MoveTask(c after d) error = %v", err)
+	}
+	if got, want := tasksInOrder(t, server, position), []string{"d", "c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after further moves = %v, want %v", got, want)
+	}
+
+	// New tasks go last.
+	mustAddTask(t, server, "e", "")
+	if got, want := tasksInOrder(t, server, position), []string{"d", "c", "b", "a", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after adding e = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name     string
+		req      *todov1.MoveTaskRequest
+		wantCode connect.Code
+		wantErr  error
+	}{
+		{name: "no target", req: &todov1.MoveTaskRequest{Id: ids["a"]}, wantCode: connect.CodeInvalidArgument, wantErr: ErrInvalidMove},
+		{name: "both targets", req: &todov1.MoveTaskRequest{Id: ids["a"], BeforeId: ids["b"], AfterId: ids["c"]}, wantCode: connect.CodeInvalidArgument, wantErr: ErrInvalidMove},
+		{name: "next to itself", req: &todov1.MoveTaskRequest{Id: ids["a"], BeforeId: ids["a"]}, wantCode: connect.CodeInvalidArgument, wantErr: ErrMoveToSelf},
+		{name: "missing ID", req: &todov1.MoveTaskRequest{AfterId: ids["a"]}, wantCode: connect.CodeInvalidArgument, wantErr: ErrInvalidTaskID},
+		{name: "unknown task", req: &todov1.MoveTaskRequest{Id: "nope", AfterId: ids["a"]}, wantCode: connect.CodeNotFound, wantErr: ErrTaskNotFound},
+		{name: "unknown target", req: &todov1.MoveTaskRequest{Id: ids["a"], AfterId: "nope"}, wantCode: connect.CodeNotFound, wantErr: ErrTargetNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := move(tt.req)
+			if connect.CodeOf(err) != tt.wantCode || !errors.Is(err, tt.wantErr) {
+				t.Errorf("MoveTask() error = %v, want code %v and %v", err, tt.wantCode, tt.wantErr)
+			}
+		})
+	}
+}
+
+// tasksByID returns every task in server keyed by ID.
+func tasksByID(t *testing.T, server *TodoServer) map[string]*todov1.Task {
+	t.Helper()
+	resp, err := server.GetTasks(context.Background(), connect.NewRequest(&todov1.GetTasksRequest{}))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	tasks := make(map[string]*todov1.Task)
+	for _, task := range resp.Msg.Tasks {
+		tasks[task.Id] = task
+	}
+	return tasks
+}
+
+func TestMoveTaskOtherOwner(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	add := func(ctx context.Context, text string) string {
+		t.Helper()
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: text}))
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+		return resp.Msg.Task.Id
+	}
+	alicesTask := add(alice, "Alice's task")
+	bobsTask := add(bob, "Bob's task")
+
+	_, err := server.MoveTask(bob, connect.NewRequest(&todov1.MoveTaskRequest{Id: bobsTask, BeforeId: alicesTask}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("MoveTask() next to another user's task error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+}
+
+func TestGetTasksPriorityOrder(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+	for _, tt := range []struct {
+		text     string
+		priority todov1.Priority
+	}{
+		{"none", todov1.Priority_PRIORITY_UNSPECIFIED},
+		{"p2", todov1.Priority_PRIORITY_P2},
+		{"p0", todov1.Priority_PRIORITY_P0},
+		{"p2 later", todov1.Priority_PRIORITY_P2},
+		{"p3", todov1.Priority_PRIORITY_P3},
+	} {
+		if _, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: tt.text, Priority: tt.priority})); err != nil {
+			t.Fatalf("AddTask(%q) error = %v", tt.text, err)
+		}
+	}
+	byPriority := todov1.TaskOrder_TASK_ORDER_PRIORITY
+	if got, want := tasksInOrder(t, server, byPriority), []string{"p0", "p2", "p2 later", "p3", "none"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("priority order = %v, want %v", got, want)
+	}
+
+	// Raising a priority and moving within a priority both take effect.
+	tasks := make(map[string]string)
+	for _, task := range tasksByID(t, server) {
+		tasks[task.Text] = task.Id
+	}
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         tasks["none"],
+		Task:       &todov1.Task{Priority: todov1.Priority_PRIORITY_P1},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"priority"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := server.MoveTask(ctx, connect.NewRequest(&todov1.MoveTaskRequest{Id: tasks["p2 later"], BeforeId: tasks["p2"]})); err != nil {
+		t.Fatalf("MoveTask() error = %v", err)
+	}
+	if got, want := tasksInOrder(t, server, byPriority), []string{"p0", "none", "p2 later", "p2", "p3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("priority order after changes = %v, want %v", got, want)
+	}
+
+	_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "bad", Priority: todov1.Priority(9)}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("AddTask() with unknown priority error = %v, want %v", err, ErrInvalidPriority)
+	}
+}
+
+func TestConcurrentMoves(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+	var ids []string
+	for i := 0; i < 20; i++ {
+		ids = append(ids, mustAddTask(t, server, "task", "").Id)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 100; i++ {
+				id, target := ids[rng.Intn(len(ids))], ids[rng.Intn(len(ids))]
+				if id == target {
+					continue
+				}
+				req := &todov1.MoveTaskRequest{Id: id, AfterId: target}
+				if rng.Intn(2) == 0 {
+					req = &todov1.MoveTaskRequest{Id: id, BeforeId: target}
+				}
+				if _, err := server.MoveTask(ctx, connect.NewRequest(req)); err != nil {
+					t.Errorf("MoveTask() error = %v", err)
+					return
+				}
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, task := range tasksByID(t, server) {
+		if seen[task.Position] {
+			t.Errorf("position %q is used twice", task.Position)
+		}
+		seen[task.Position] = true
+		if err := validateRank(task.Position); err != nil {
+			t.Errorf("position %q is invalid: %v", task.Position, err)
+		}
+	}
+	if len(seen) != len(ids) {
+		t.Errorf("got %d positions, want %d", len(seen), len(ids))
+	}
+}
+
+func TestAssignPositionsToExistingTasks(t *testing.T) {
+	seed := []*todov1.Task{
+		{Id: "c", Text: "third", CreatedAt: 300},
+		{Id: "a", Text: "first", CreatedAt: 100},
+		{Id: "b", Text: "second", CreatedAt: 200},
+	}
+	server := newSeededServer(t, NewMemoryStore(), seed)
+	if got, want := tasksInOrder(t, server, todov1.TaskOrder_TASK_ORDER_POSITION), []string{"first", "second", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("position order = %v, want creation order %v", got, want)
+	}
+	mustAddTask(t, server, "fourth", "")
+	if got, want := tasksInOrder(t, server, todov1.TaskOrder_TASK_ORDER_POSITION), []string{"first", "second", "third", "fourth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("position order = %v, want %v", got, want)
+	}
+}
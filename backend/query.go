@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrInvalidTimeRange = errors.New("created_after must not be later than created_before")
+	ErrPageTokenOrder   = errors.New("page token was issued for a different sort order")
+	ErrInvalidOrder     = errors.New("unknown sort order")
+	ErrInvalidDueFilter = errors.New("unknown due filter")
+	ErrInvalidDueWithin = errors.New("due_within_seconds must be positive with DUE_FILTER_DUE_WITHIN and unset otherwise")
+	ErrInvalidTimeZone  = errors.New("unknown time zone")
+)
+
+// taskQuery is the validated form of a GetTasksRequest.
+type taskQuery struct {
+	access        access // only tasks the user may see
+	status        todov1.TaskStatus
+	listID        string   // only tasks in this list; empty matches every list
+	terms         []string // tokenized search query; empty matches all text
+	anyTags       []string // normalized; a task needs one of them, if set
+	allTags       []string // normalized; a task needs all of them
+	createdAfter  int64    // inclusive lower bound on CreatedAt; zero means none
+	createdBefore int64    // exclusive upper bound on CreatedAt; zero means none
+	dueFilter     todov1.DueFilter
+	dueFrom       int64 // inclusive lower bound on DueAt when dueFilter is set
+	dueTo         int64 // exclusive upper bound on DueAt when dueFilter is set
+	actionable    bool  // only open tasks whose blockers are all done
+	order         todov1.TaskOrder
+	view          todov1.TaskView
+	after         *pageCursor
+	limit         int // negative means unlimited
+
+	// blockerDone reports whether a blocker has been resolved; the server
+	// sets it for actionable queries.
+	blockerDone func(id string) bool
+}
+
+// parseTaskQuery validates req and converts it into a taskQuery. Due date
+// filters are resolved relative to now.
+func parseTaskQuery(req *todov1.GetTasksRequest, now time.Time) (*taskQuery, error) {
+	if req.PageSize < 0 {
+		return nil, ErrInvalidPageSize
+	}
+	if req.CreatedAfter != 0 && req.CreatedBefore != 0 && req.CreatedAfter > req.CreatedBefore {
+		return nil, ErrInvalidTimeRange
+	}
+
+	q := &taskQuery{
+		status:        req.Status,
+		listID:        req.ListId,
+		terms:         tokenize(req.Query),
+		createdAfter:  req.CreatedAfter,
+		createdBefore: req.CreatedBefore,
+		order:         req.OrderBy,
+		view:          req.View,
+		actionable:    req.Actionable,
+		limit:         pageLimit(req.PageSize),
+	}
+	if q.order == todov1.TaskOrder_TASK_ORDER_UNSPECIFIED {
+		q.order = todov1.TaskOrder_TASK_ORDER_NEWEST_FIRST
+	}
+	if _, ok := todov1.TaskOrder_name[int32(q.order)]; !ok {
+		return nil, ErrInvalidOrder
+	}
+	if _, ok := todov1.TaskView_name[int32(q.view)]; !ok {
+		return nil, ErrInvalidView
+	}
+	if err := q.setDueWindow(req, now); err != nil {
+		return nil, err
+	}
+	var err error
+	if q.anyTags, err = normalizeTags(req.AnyTags); err != nil {
+		return nil, err
+	}
+	if q.allTags, err = normalizeTags(req.AllTags); err != nil {
+		return nil, err
+	}
+	if req.PageToken != "" {
+		c, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if c.Order != q.order {
+			return nil, ErrPageTokenOrder
+		}
+		q.after = &c
+	}
+	return q, nil
+}
+
+// setDueWindow resolves the request's due filter into the range of due times
+// it accepts.
+func (q *taskQuery) setDueWindow(req *todov1.GetTasksRequest, now time.Time) error {
+	q.dueFilter = req.DueFilter
+	within := req.DueFilter == todov1.DueFilter_DUE_FILTER_DUE_WITHIN
+	if within != (req.DueWithinSeconds > 0) || req.DueWithinSeconds < 0 {
+		return ErrInvalidDueWithin
+	}
+
+	switch req.DueFilter {
+	case todov1.DueFilter_DUE_FILTER_UNSPECIFIED:
+	case todov1.DueFilter_DUE_FILTER_OVERDUE:
+		q.dueTo = now.Unix()
+	case todov1.DueFilter_DUE_FILTER_DUE_TODAY:
+		loc := time.Local
+		if req.TimeZone != "" {
+			var err error
+			if loc, err = time.LoadLocation(req.TimeZone); err != nil {
+				return fmt.Errorf("%w %q", ErrInvalidTimeZone, req.TimeZone)
+			}
+		}
+		y, m, d := now.In(loc).Date()
+		start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		q.dueFrom, q.dueTo = start.Unix(), start.AddDate(0, 0, 1).Unix()
+	case todov1.DueFilter_DUE_FILTER_DUE_WITHIN:
+		q.dueFrom, q.dueTo = now.Unix(), now.Unix()+req.DueWithinSeconds+1
+	default:
+		return ErrInvalidDueFilter
+	}
+	return nil
+}
+
+// match reports whether task passes the query's filters. Search terms are
+// resolved through the search index and are not checked here.
+func (q *taskQuery) match(task *todov1.Task) bool {
+	if !q.access.canView(task) {
+		return false
+	}
+	if !matchesStatus(task, q.status) {
+		return false
+	}
+	if q.listID != "" && task.ListId != q.listID {
+		return false
+	}
+	if q.createdAfter != 0 && task.CreatedAt < q.createdAfter {
+		return false
+	}
+	if q.createdBefore != 0 && task.CreatedAt >= q.createdBefore {
+		return false
+	}
+	if q.dueFilter != todov1.DueFilter_DUE_FILTER_UNSPECIFIED {
+		if task.DueAt == 0 || task.DueAt < q.dueFrom || task.DueAt >= q.dueTo {
+			return false
+		}
+		if q.dueFilter == todov1.DueFilter_DUE_FILTER_OVERDUE && task.Completed {
+			return false
+		}
+	}
+	if len(q.anyTags) > 0 && !slices.ContainsFunc(q.anyTags, func(tag string) bool { return hasTag(task, tag) }) {
+		return false
+	}
+	for _, tag := range q.allTags {
+		if !hasTag(task, tag) {
+			return false
+		}
+	}
+	if q.actionable {
+		blocked := slices.ContainsFunc(task.BlockedBy, func(id string) bool { return !q.blockerDone(id) })
+		if task.Completed || blocked {
+			return false
+		}
+	}
+	return true
+}
+
+// usesOrderIndex reports whether the query can be answered by walking the
+// creation-order index rather than sorting candidates.
+func (q *taskQuery) usesOrderIndex() bool {
+	if len(q.terms) > 0 {
+		return false
+	}
+	return q.order == todov1.TaskOrder_TASK_ORDER_NEWEST_FIRST ||
+		q.order == todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST
+}
+
+// less reports whether a sorts ahead of b in the query's order. Every order
+// falls back to the task ID so that the ordering is total and cursors are
+// stable.
+func (q *taskQuery) less(a, b *todov1.Task) bool {
+	switch q.order {
+	case todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST:
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+		return a.Id < b.Id
+	case todov1.TaskOrder_TASK_ORDER_ALPHABETICAL:
+		if at, bt := strings.ToLower(a.Text), strings.ToLower(b.Text); at != bt {
+			return at < bt
+		}
+		return a.Id < b.Id
+	case todov1.TaskOrder_TASK_ORDER_ID:
+		return a.Id < b.Id
+	case todov1.TaskOrder_TASK_ORDER_PRIORITY:
+		if ap, bp := priorityRank(a.Priority), priorityRank(b.Priority); ap != bp {
+			return ap < bp
+		}
+		fallthrough
+	case todov1.TaskOrder_TASK_ORDER_POSITION:
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.Id < b.Id
+	default:
+		return keyOf(a).before(keyOf(b))
+	}
+}
+
+// sort orders tasks in place by the query's order.
+func (q *taskQuery) sort(tasks []*todov1.Task) {
+	sort.Slice(tasks, func(i, j int) bool { return q.less(tasks[i], tasks[j]) })
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+// newSeededServer returns a server over a store holding tasks, which keep
+// their IDs and creation times.
+func newSeededServer(t *testing.T, store TaskStore, tasks []*todov1.Task, opts ...ServerOption) *TodoServer {
+	t.Helper()
+	for _, task := range tasks {
+		if err := store.CreateTask(task, nil); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+	return mustNewServer(t, store, opts...)
+}
+
+func taskIDs(tasks []*todov1.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
+
+var querySeed = []*todov1.Task{
+	{Id: "a", Text: "Weekly report", CreatedAt: 100},
+	{Id: "b", Text: "buy milk", CreatedAt: 200},
+	{Id: "c", Text: "Report bug to vendor", CreatedAt: 300},
+	{Id: "d", Text: "Call mom", CreatedAt: 300},
+}
+
+func TestGetTasksQuery(t *testing.T) {
+	for _, ts := range testStores {
+		t.Run(ts.name, func(t *testing.T) {
+			server := newSeededServer(t, ts.open(t), querySeed)
+			ctx := context.Background()
+
+			tests := []struct {
+				name    string
+				req     *todov1.GetTasksRequest
+				wantIDs []string
+			}{
+				{name: "default order", req: &todov1.GetTasksRequest{}, wantIDs: []string{"d", "c", "b", "a"}},
+				{name: "oldest first", req: &todov1.GetTasksRequest{OrderBy: todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST}, wantIDs: []string{"a", "b", "c", "d"}},
+				{name: "alphabetical", req: &todov1.GetTasksRequest{OrderBy: todov1.TaskOrder_TASK_ORDER_ALPHABETICAL}, wantIDs: []string{"b", "d", "c", "a"}},
+				{name: "by ID", req: &todov1.GetTasksRequest{OrderBy: todov1.TaskOrder_TASK_ORDER_ID}, wantIDs: []string{"a", "b", "c", "d"}},
+				{name: "search is case-insensitive", req: &todov1.GetTasksRequest{Query: "REPORT"}, wantIDs: []string{"c", "a"}},
+				{name: "search matches word prefixes", req: &todov1.GetTasksRequest{Query: "rep"}, wantIDs: []string{"c", "a"}},
+				{name: "search requires every word", req: &todov1.GetTasksRequest{Query: "report bug"}, wantIDs: []string{"c"}},
+				{name: "search without matches", req: &todov1.GetTasksRequest{Query: "groceries"}, wantIDs: []string{}},
+				{name: "search sorted alphabetically", req: &todov1.GetTasksRequest{Query: "report", OrderBy: todov1.TaskOrder_TASK_ORDER_ALPHABETICAL}, wantIDs: []string{"c", "a"}},
+				{name: "created range", req: &todov1.GetTasksRequest{CreatedAfter: 200, CreatedBefore: 300}, wantIDs: []string{"b"}},
+				{name: "created after only", req: &todov1.GetTasksRequest{CreatedAfter: 300}, wantIDs: []string{"d", "c"}},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp, err := server.GetTasks(ctx, connect.NewRequest(tt.req))
+					if err != nil {
+						t.Fatalf("GetTasks() error = %v", err)
+					}
+					if got := taskIDs(resp.Msg.Tasks); !reflect.DeepEqual(got, tt.wantIDs) {
+						t.Errorf("GetTasks() = %v, want %v", got, tt.wantIDs)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestGetTasksPagesInEveryOrder(t *testing.T) {
+	server := newSeededServer(t, NewMemoryStore(), querySeed)
+	ctx := context.Background()
+
+	for name, order := range todov1.TaskOrder_value {
+		t.Run(name, func(t *testing.T) {
+			all, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{OrderBy: todov1.TaskOrder(order)}))
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+
+			var got []string
+			token := ""
+			for {
+				resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{
+					OrderBy:   todov1.TaskOrder(order),
+					PageSize:  1,
+					PageToken: token,
+				}))
+				if err != nil {
+					t.Fatalf("GetTasks() error = %v", err)
+				}
+				got = append(got, taskIDs(resp.Msg.Tasks)...)
+				if token = resp.Msg.NextPageToken; token == "" {
+					break
+				}
+			}
+			if want := taskIDs(all.Msg.Tasks); !reflect.DeepEqual(got, want) {
+				t.Errorf("paged result = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGetTasksSearchFollowsUpdates(t *testing.T) {
+	server := newSeededServer(t, NewMemoryStore(), querySeed)
+	ctx := context.Background()
+
+	search := func(query string) []string {
+		t.Helper()
+		resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{Query: query}))
+		if err != nil {
+			t.Fatalf("GetTasks() error = %v", err)
+		}
+		return taskIDs(resp.Msg.Tasks)
+	}
+
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         "b",
+		Task:       &todov1.Task{Text: "Buy oat milk"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"text"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := search("oat"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("search after update = %v, want [b]", got)
+	}
+
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: "b"})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if got := search("milk"); len(got) != 0 {
+		t.Errorf("search after delete = %v, want none", got)
+	}
+}
+
+func TestGetTasksInvalidQuery(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	oldestToken := encodePageToken(pageCursor{Order: todov1.TaskOrder_TASK_ORDER_OLDEST_FIRST, ID: "a"})
+	tests := []struct {
+		name string
+		req  *todov1.GetTasksRequest
+	}{
+		{name: "inverted time range", req: &todov1.GetTasksRequest{CreatedAfter: 300, CreatedBefore: 100}},
+		{name: "unknown order", req: &todov1.GetTasksRequest{OrderBy: todov1.TaskOrder(99)}},
+		{name: "token from another order", req: &todov1.GetTasksRequest{PageSize: 1, PageToken: oldestToken}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.GetTasks(ctx, connect.NewRequest(tt.req))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("GetTasks() error = %v, want code %v", err, connect.CodeInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("  Fix the BUG, then fix-it again!  ")
+	want := []string{"fix", "the", "bug", "then", "it", "again"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestGetTasksDueFilters(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	due := func(d time.Duration) int64 { return now.Add(d).Unix() }
+	seed := []*todov1.Task{
+		{Id: "late", Text: "Late", CreatedAt: 100, DueAt: due(-time.Hour)},
+		{Id: "done", Text: "Done", CreatedAt: 200, DueAt: due(-time.Hour), Completed: true},
+		{Id: "soon", Text: "Soon", CreatedAt: 300, DueAt: due(time.Hour)},
+		{Id: "tonight", Text: "Tonight", CreatedAt: 400, DueAt: due(8 * time.Hour)},
+		{Id: "later", Text: "Later", CreatedAt: 500, DueAt: due(72 * time.Hour)},
+		{Id: "undated", Text: "Undated", CreatedAt: 600},
+	}
+	server := newSeededServer(t, NewMemoryStore(), seed, WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     *todov1.GetTasksRequest
+		wantIDs []string
+		wantErr error
+	}{
+		{name: "overdue skips completed", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_OVERDUE}, wantIDs: []string{"late"}},
+		{name: "due today in UTC", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_TODAY, TimeZone: "UTC"}, wantIDs: []string{"tonight", "soon", "done", "late"}},
+		{name: "due today in Tokyo", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_TODAY, TimeZone: "Asia/Tokyo"}, wantIDs: []string{"tonight", "soon"}},
+		{name: "due within an hour", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_WITHIN, DueWithinSeconds: 3600}, wantIDs: []string{"soon"}},
+		{name: "due within a day", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_WITHIN, DueWithinSeconds: 86400}, wantIDs: []string{"tonight", "soon"}},
+		{name: "within without seconds", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_WITHIN}, wantErr: ErrInvalidDueWithin},
+		{name: "seconds without within", req: &todov1.GetTasksRequest{DueWithinSeconds: 60}, wantErr: ErrInvalidDueWithin},
+		{name: "negative seconds", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_WITHIN, DueWithinSeconds: -1}, wantErr: ErrInvalidDueWithin},
+		{name: "unknown filter", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter(99)}, wantErr: ErrInvalidDueFilter},
+		{name: "unknown time zone", req: &todov1.GetTasksRequest{DueFilter: todov1.DueFilter_DUE_FILTER_DUE_TODAY, TimeZone: "Mars/Olympus_Mons"}, wantErr: ErrInvalidTimeZone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.GetTasks(ctx, connect.NewRequest(tt.req))
+			if tt.wantErr != nil {
+				if connect.CodeOf(err) != connect.CodeInvalidArgument || !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetTasks() error = %v, want invalid argument %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+			if got := taskIDs(resp.Msg.Tasks); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetTasks() = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
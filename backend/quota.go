@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrTaskQuotaExceeded      = errors.New("workspace task limit reached")
+	ErrOwnerTaskQuotaExceeded = errors.New("you have as many tasks as you may; purge some from the trash first")
+	ErrListTaskQuotaExceeded  = errors.New("the list has as many tasks as it may; purge some from the trash first")
+)
+
+// WithMaxTasks limits the number of tasks the server holds, trashed ones
+// included, to n. Adding a task past the limit fails with
+// CodeResourceExhausted until some are purged; the next instance of a
+// recurring task is added regardless. Zero means no limit.
+func WithMaxTasks(n int) ServerOption {
+	return func(s *TodoServer) {
+		s.maxTasks = n
+	}
+}
+
+// WithMaxTasksPerOwner is WithMaxTasks for the tasks each user has created.
+func WithMaxTasksPerOwner(n int) ServerOption {
+	return func(s *TodoServer) {
+		s.maxTasksPerOwner = n
+	}
+}
+
+// WithMaxTasksPerList is WithMaxTasks for the tasks in each list. The inbox
+// has no limit.
+func WithMaxTasksPerList(n int) ServerOption {
+	return func(s *TodoServer) {
+		s.maxTasksPerList = n
+	}
+}
+
+// exhausted returns a resource-exhausted error for err with a RetryInfo
+// detail naming limit. A positive retryAfter is also sent as the Retry-After
+// header, rounded up to whole seconds.
+func exhausted(err error, limit string, retryAfter time.Duration) error {
+	cerr := connect.NewError(connect.CodeResourceExhausted, err)
+	detail, derr := connect.NewErrorDetail(&todov1.RetryInfo{
+		Limit:        limit,
+		RetryAfterMs: retryAfter.Milliseconds(),
+	})
+	if derr != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to describe limit: %w", derr))
+	}
+	cerr.AddDetail(detail)
+	if retryAfter > 0 {
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		cerr.Meta().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	return cerr
+}
+
+// taskCounts counts the live and trashed tasks a TodoServer holds, in all
+// and by owner and list, so that checking the limits on them takes no scan.
+// It is not safe for concurrent use; TodoServer guards it with its mutex.
+type taskCounts struct {
+	total  int
+	owners map[string]int // owner ID -> tasks
+	lists  map[string]int // list ID -> tasks; the inbox is not counted
+}
+
+func newTaskCounts(tasks, trash []*todov1.Task) *taskCounts {
+	c := &taskCounts{
+		owners: make(map[string]int),
+		lists:  make(map[string]int),
+	}
+	for _, task := range slices.Concat(tasks, trash) {
+		c.add(task, 1)
+	}
+	return c
+}
+
+// add counts task delta more times, -1 to stop counting it.
+func (c *taskCounts) add(task *todov1.Task, delta int) {
+	c.total += delta
+	count(c.owners, task.OwnerId, delta)
+	if task.ListId != "" {
+		count(c.lists, task.ListId, delta)
+	}
+}
+
+// update counts task in place of old, its previous version, after its owner
+// or list may have changed.
+func (c *taskCounts) update(old, task *todov1.Task) {
+	if old.OwnerId != task.OwnerId || old.ListId != task.ListId {
+		c.add(old, -1)
+		c.add(task, 1)
+	}
+}
+
+// count adds delta to m[key], deleting the key when the count drops to zero.
+func count(m map[string]int, key string, delta int) {
+	if n := m[key] + delta; n > 0 {
+		m[key] = n
+	} else {
+		delete(m, key)
+	}
+}
+
+// checkTaskQuota returns a resource-exhausted error if adding task would take
+// the server, its creator or its list past their limits. Callers must hold
+// s.mu.
+func (s *TodoServer) checkTaskQuota(task *todov1.Task) error {
+	if s.maxTasks != 0 && s.counts.total >= s.maxTasks {
+		return exhausted(ErrTaskQuotaExceeded, "tasks", 0)
+	}
+	if s.maxTasksPerOwner != 0 && s.counts.owners[task.OwnerId] >= s.maxTasksPerOwner {
+		return exhausted(ErrOwnerTaskQuotaExceeded, "tasks_per_owner", 0)
+	}
+	if s.maxTasksPerList == 0 {
+		return nil
+	}
+	listID := task.ListId
+	if task.ParentId != "" {
+		// A subtask goes into its parent's list.
+		if parent, err := s.store.GetTask(task.ParentId); err == nil {
+			listID = parent.ListId
+		}
+	}
+	return s.checkListRoom(listID, 1)
+}
+
+// checkListRoom returns a resource-exhausted error if n more tasks, added to
+// or moved into the list with the given ID, would take it past its limit.
+// Callers must hold s.mu.
+func (s *TodoServer) checkListRoom(listID string, n int) error {
+	if s.maxTasksPerList != 0 && listID != "" && s.counts.lists[listID]+n > s.maxTasksPerList {
+		return exhausted(ErrListTaskQuotaExceeded, "tasks_per_list", 0)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func TestTaskCaps(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore(), WithMaxTasksPerOwner(3), WithMaxTasksPerList(2))
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+	add := func(ctx context.Context, listID, parentID string) error {
+		_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Task", ListId: listID, ParentId: parentID}))
+		return err
+	}
+	created, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Team"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	list := created.Msg.List
+	mustShareList(t, alice, server, list.Id, "bob", todov1.ListRole_LIST_ROLE_EDITOR)
+
+	if err := add(alice, list.Id, ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if err := add(bob, list.Id, ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	err = add(alice, list.Id, "")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("AddTask() to a full list error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	if info := retryInfo(t, err); info.Limit != "tasks_per_list" || info.RetryAfterMs != 0 {
+		t.Errorf("RetryInfo = %v, want the list cap and no wait", info)
+	}
+
+	// Trashed tasks count until they are purged.
+	tasks, err := server.GetTasks(alice, connect.NewRequest(&todov1.GetTasksRequest{ListId: list.Id}))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	parent := tasks.Msg.Tasks[0]
+	if _, err := server.DeleteTask(alice, connect.NewRequest(&todov1.DeleteTaskRequest{Id: tasks.Msg.Tasks[1].Id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	// A subtask counts against its parent's list.
+	if err := add(alice, "", parent.Id); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("AddTask() of a subtask in a full list error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+
+	for range 2 {
+		if err := add(alice, "", ""); err != nil {
+			t.Fatalf("AddTask() to the inbox error = %v", err)
+		}
+	}
+	err = add(alice, "", "")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("AddTask() past the owner cap error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	if info := retryInfo(t, err); info.Limit != "tasks_per_owner" {
+		t.Errorf("RetryInfo = %v, want the owner cap", info)
+	}
+	if err := add(bob, "", ""); err != nil {
+		t.Errorf("AddTask() by another user error = %v", err)
+	}
+}
+
+func TestListCapCoversMoves(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore(), WithMaxTasksPerList(3))
+	ctx := context.Background()
+	full := mustCreateList(t, server, "Full")
+	other := mustCreateList(t, server, "Other")
+	for range 2 {
+		mustAddTask(t, server, "Task", full.Id)
+	}
+	parent := mustAddTask(t, server, "Parent", other.Id)
+	if _, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Subtask", ParentId: parent.Id})); err != nil {
+		t.Fatalf("AddTask() of a subtask error = %v", err)
+	}
+	checkFull := func(op string, err error) {
+		t.Helper()
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("%s error = %v, want code %v", op, err, connect.CodeResourceExhausted)
+		}
+		if info := retryInfo(t, err); info.Limit != "tasks_per_list" || info.RetryAfterMs != 0 {
+			t.Errorf("%s RetryInfo = %v, want the list cap and no wait", op, info)
+		}
+	}
+
+	// The parent fits but its subtask, which moves with it, does not.
+	_, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         parent.Id,
+		Task:       &todov1.Task{ListId: full.Id},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"list_id"}},
+	}))
+	checkFull("UpdateTask() moving a subtree into a full list", err)
+	tasks, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{ListId: other.Id}))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if len(tasks.Msg.Tasks) != 2 {
+		t.Errorf("GetTasks() of the source list = %v, want the parent and subtask left in place", tasks.Msg.Tasks)
+	}
+
+	_, err = server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+		Id:           other.Id,
+		Policy:       todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+		MoveToListId: full.Id,
+	}))
+	checkFull("DeleteList() moving tasks into a full list", err)
+	if _, err := server.store.GetList(other.Id); err != nil {
+		t.Errorf("GetList() of the list that failed to delete error = %v", err)
+	}
+
+	// A lone task still fits.
+	mustDeleteTask(t, server, parent.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_PROMOTE)
+	if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: parent.Id})); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+		Id:           other.Id,
+		Policy:       todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+		MoveToListId: full.Id,
+	})); err != nil {
+		t.Errorf("DeleteList() moving a task into a list with room error = %v", err)
+	}
+}
+
+func TestTaskCountsFollowChanges(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+	check := func(step string) {
+		t.Helper()
+		tasks, err := server.store.ListTasks()
+		if err != nil {
+			t.Fatalf("ListTasks() error = %v", err)
+		}
+		trash, err := server.store.ListTrash()
+		if err != nil {
+			t.Fatalf("ListTrash() error = %v", err)
+		}
+		if want := newTaskCounts(tasks, trash); !reflect.DeepEqual(server.counts, want) {
+			t.Errorf("counts after %s = %+v, want %+v", step, server.counts, want)
+		}
+	}
+
+	home := mustCreateList(t, server, "Home")
+	work := mustCreateList(t, server, "Work")
+	task := mustAddTask(t, server, "Task", home.Id)
+	mustAddTask(t, server, "Other", work.Id)
+	check("adding")
+
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         task.Id,
+		Task:       &todov1.Task{ListId: work.Id},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"list_id"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	check("moving")
+
+	mustDeleteTask(t, server, task.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+		Id:     work.Id,
+		Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+	})); err != nil {
+		t.Fatalf("DeleteList() error = %v", err)
+	}
+	check("deleting")
+
+	// The restored task moves to the inbox, its list being gone.
+	if _, err := server.RestoreTask(ctx, connect.NewRequest(&todov1.RestoreTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	check("restoring")
+
+	mustDeleteTask(t, server, task.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	check("purging")
+
+	mustAddTask(t, server, "Undone", "")
+	mustUndo(t, ctx, server)
+	check("undoing")
+}
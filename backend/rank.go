@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Task positions are fractional ranks: strings that sort in the task's place
+// in the manual order. A rank can always be made between any two others, so
+// moving a task rewrites that task alone and never renumbers its neighbours.
+//
+// A rank is an integer part followed by an optional fraction, in base 62.
+// The integer part's first character encodes its length ('a' is one digit,
+// 'b' two, ...; 'Z' is one digit below zero, 'Y' two, ...), which keeps
+// integers in numeric order when compared as strings and lets appends grow
+// ranks logarithmically. Fractions never end in '0', so there is always room
+// below them.
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	errInvalidRank   = errors.New("invalid rank")
+	errRankExhausted = errors.New("rank space exhausted")
+)
+
+// rankBetween returns a rank that sorts strictly after a and strictly before
+// b. An empty a means no lower bound and an empty b no upper bound.
+func rankBetween(a, b string) (string, error) {
+	if a != "" {
+		if err := validateRank(a); err != nil {
+			return "", err
+		}
+	}
+	if b != "" {
+		if err := validateRank(b); err != nil {
+			return "", err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return "", errInvalidRank
+	}
+
+	switch {
+	case a == "" && b == "":
+		return "a0", nil
+	case a == "":
+		ib := rankInteger(b)
+		fb := b[len(ib):]
+		if ib == smallestRankInteger {
+			return ib + rankMidpoint("", fb), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		return decrementRankInteger(ib)
+	case b == "":
+		ia := rankInteger(a)
+		fa := a[len(ia):]
+		i, err := incrementRankInteger(ia)
+		if errors.Is(err, errRankExhausted) {
+			return ia + rankMidpoint(fa, ""), nil
+		}
+		return i, err
+	}
+
+	ia, ib := rankInteger(a), rankInteger(b)
+	fa, fb := a[len(ia):], b[len(ib):]
+	if ia == ib {
+		return ia + rankMidpoint(fa, fb), nil
+	}
+	i, err := incrementRankInteger(ia)
+	if err != nil {
+		return "", err
+	}
+	if i < b {
+		return i, nil
+	}
+	return ia + rankMidpoint(fa, ""), nil
+}
+
+// smallestRankInteger is the lowest integer part; ranks below it are made by
+// adding fractions.
+var smallestRankInteger = "A" + strings.Repeat("0", 26)
+
+// rankIntegerLength returns the length of the integer part, head included,
+// that starts with head.
+func rankIntegerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	default:
+		return 0, false
+	}
+}
+
+func rankInteger(rank string) string {
+	n, _ := rankIntegerLength(rank[0])
+	return rank[:n]
+}
+
+func validateRank(rank string) error {
+	if rank == "" || rank == smallestRankInteger {
+		return errInvalidRank
+	}
+	n, ok := rankIntegerLength(rank[0])
+	if !ok || len(rank) < n {
+		return errInvalidRank
+	}
+	for i := 1; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return errInvalidRank
+		}
+	}
+	if len(rank) > n && rank[len(rank)-1] == rankDigits[0] {
+		return errInvalidRank
+	}
+	return nil
+}
+
+// rankMidpoint returns a fraction strictly between the fractions a and b,
+// where an empty b means no upper bound. Neither may end in '0'.
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		// Copy the common prefix, treating a as padded with zeros.
+		n := 0
+		for n < len(b) && fractionDigit(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankMidpoint(suffix(a, n), b[n:])
+		}
+	}
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(rankDigits, a[0])
+	}
+	db := len(rankDigits)
+	if b != "" {
+		db = strings.IndexByte(rankDigits, b[0])
+	}
+	if db-da > 1 {
+		return string(rankDigits[(da+db+1)/2])
+	}
+	// The first digits are adjacent.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(rankDigits[da]) + rankMidpoint(suffix(a, 1), "")
+}
+
+func fractionDigit(f string, i int) byte {
+	if i < len(f) {
+		return f[i]
+	}
+	return rankDigits[0]
+}
+
+func suffix(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+// incrementRankInteger returns the integer part that follows x, or
+// errRankExhausted if x is the largest one.
+func incrementRankInteger(x string) (string, error) {
+	head, digits := x[0], []byte(x[1:])
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := strings.IndexByte(rankDigits, digits[i]) + 1
+		if d < len(rankDigits) {
+			digits[i] = rankDigits[d]
+			return string(head) + string(digits), nil
+		}
+		digits[i] = rankDigits[0]
+	}
+	// Every digit carried over; move to the next length.
+	switch head {
+	case 'Z':
+		return "a0", nil
+	case 'z':
+		return "", errRankExhausted
+	}
+	head++
+	if head > 'a' {
+		digits = append(digits, rankDigits[0])
+	} else {
+		digits = digits[1:]
+	}
+	return string(head) + string(digits), nil
+}
+
+// decrementRankInteger returns the integer part that precedes x, or
+// errRankExhausted if x is the smallest one.
+func decrementRankInteger(x string) (string, error) {
+	head, digits := x[0], []byte(x[1:])
+	last := rankDigits[len(rankDigits)-1]
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := strings.IndexByte(rankDigits, digits[i]) - 1
+		if d >= 0 {
+			digits[i] = rankDigits[d]
+			return string(head) + string(digits), nil
+		}
+		digits[i] = last
+	}
+	switch head {
+	case 'a':
+		return "Z" + string(last), nil
+	case 'A':
+		return "", errRankExhausted
+	}
+	head--
+	if head < 'Z' {
+		digits = append(digits, last)
+	} else {
+		digits = digits[1:]
+	}
+	return string(head) + string(digits), nil
+}
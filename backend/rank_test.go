@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "a0"},
+		{"a0", "", "a1"},
+		{"a1", "", "a2"},
+		{"", "a0", "Zz"},
+		{"a0", "a1", "a0V"},
+		{"a1", "a2", "a1V"},
+		{"a0V", "a1", "a0l"},
+		{"Zz", "a0", "ZzV"},
+		{"az", "", "b00"},
+		{"b00", "", "b01"},
+		{"a0", "a0V", "a0G"},
+		{"a0", "a0G", "a08"},
+		{"a0", "a00V", "a00G"},
+	}
+	for _, tt := range tests {
+		got, err := rankBetween(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("rankBetween(%q, %q) error = %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("rankBetween(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRankBetweenInvalid(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"a1", "a0"},
+		{"a0", "a0"},
+		{"a", ""},
+		{"a00", ""},
+		{"!0", ""},
+		{"a0", "a1-"},
+	}
+	for _, tt := range tests {
+		if got, err := rankBetween(tt.a, tt.b); err == nil {
+			t.Errorf("rankBetween(%q, %q) = %q, want an error", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestRankBetweenKeepsOrder(t *testing.T) {
+	// Insert at random gaps and check that the ranks stay sorted and
+	// distinct, as if each were placed into a sorted list.
+	rng := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 2000; i++ {
+		at := rng.Intn(len(ranks) + 1)
+		lo, hi := "", ""
+		if at > 0 {
+			lo = ranks[at-1]
+		}
+		if at < len(ranks) {
+			hi = ranks[at]
+		}
+		r, err := rankBetween(lo, hi)
+		if err != nil {
+			t.Fatalf("rankBetween(%q, %q) error = %v", lo, hi, err)
+		}
+		if (lo != "" && r <= lo) || (hi != "" && r >= hi) {
+			t.Fatalf("rankBetween(%q, %q) = %q, not strictly between", lo, hi, r)
+		}
+		ranks = append(ranks, "")
+		copy(ranks[at+1:], ranks[at:])
+		ranks[at] = r
+	}
+	if !sort.StringsAreSorted(ranks) {
+		t.Error("ranks are not sorted")
+	}
+}
+
+func TestRankBetweenAppendsStayShort(t *testing.T) {
+	last, first := "", ""
+	for i := 0; i < 100000; i++ {
+		var err error
+		if last, err = rankBetween(last, ""); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+		if first, err = rankBetween("", first); err != nil {
+			t.Fatalf("prepend %d: %v", i, err)
+		}
+	}
+	if len(last) > 4 || len(first) > 4 {
+		t.Errorf("after 100000 appends and prepends ranks are %q and %q, want at most 4 characters", last, first)
+	}
+}
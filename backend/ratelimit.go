@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"todo-list/todo/v1"
+)
+
+// rateLimitSweepInterval is how often the limiter forgets clients whose
+// buckets have refilled.
+const rateLimitSweepInterval = time.Minute
+
+var ErrRateLimited = errors.New("too many requests; slow down")
+
+// tokenBucket is one client's allowance.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // when tokens was last brought up to date
+}
+
+// rateLimiter keeps a token bucket per client. A bucket holds up to burst
+// tokens and refills at rate tokens a second; every call takes one.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter returns a limiter allowing each client rate calls a second
+// on average and burst at once.
+func newRateLimiter(rate, burst int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:      float64(rate),
+		burst:     float64(burst),
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+// take takes a token from the bucket of the client identified by key. If the
+// bucket is empty it reports false and how long until it holds a token.
+func (l *rateLimiter) take(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return l.untilToken(b), false
+}
+
+// wait reports how long until the bucket of the client identified by key
+// holds a token, or zero if it does, without taking one.
+func (l *rateLimiter) wait(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b := l.refill(key); b.tokens < 1 {
+		return l.untilToken(b)
+	}
+	return 0
+}
+
+// refill returns the bucket of the client identified by key, brought up to
+// date. Callers must hold l.mu.
+func (l *rateLimiter) refill(key string) *tokenBucket {
+	now := l.now()
+	l.sweep(now)
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	return b
+}
+
+// untilToken returns how long until b, which holds less than a token, holds
+// one.
+func (l *rateLimiter) untilToken(b *tokenBucket) time.Duration {
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep forgets the buckets that have had time to refill, which are no
+// different from new ones, at most once every rateLimitSweepInterval.
+// Callers must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the caller of a call with ctx for rate limiting: by
+// user when authentication is enabled, and otherwise by IP address.
+func clientKey(ctx context.Context) string {
+	if key, ok := apiKeyFromContext(ctx); ok {
+		return "user:" + key.user
+	}
+	return peerKey(ctx)
+}
+
+// peerKey identifies the caller of a call with ctx by IP address.
+func peerKey(ctx context.Context) string {
+	peer, _ := todov1.PeerFromContext(ctx)
+	host, _, err := net.SplitHostPort(peer.Addr)
+	if err != nil {
+		host = peer.Addr
+	}
+	return "ip:" + host
+}
+
+// rateLimitInterceptor refuses calls from clients that exceed their rate
+// with a resource-exhausted error saying when to retry. Opening a stream
+// counts as one call. It must run after the auth interceptor so that
+// authenticated clients are told apart by user rather than address.
+type rateLimitInterceptor struct {
+	limiter *rateLimiter
+}
+
+func newRateLimitInterceptor(limiter *rateLimiter) *rateLimitInterceptor {
+	return &rateLimitInterceptor{limiter: limiter}
+}
+
+func (i *rateLimitInterceptor) check(ctx context.Context) error {
+	if wait, ok := i.limiter.take(clientKey(ctx)); !ok {
+		return exhausted(ErrRateLimited, "requests", wait)
+	}
+	return nil
+}
+
+func (i *rateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.check(ctx); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *rateLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *rateLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(ctx); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// authFailureLimitInterceptor limits the calls that fail authentication by IP
+// address, each of which takes a token. It must run before the auth
+// interceptor: while an address's bucket is empty, its calls are refused
+// before their credentials are looked at, so that keys cannot be guessed
+// faster than the limit allows. Calls that authenticate take no token here;
+// the rateLimitInterceptor after the auth one limits them by user.
+type authFailureLimitInterceptor struct {
+	limiter *rateLimiter
+}
+
+func newAuthFailureLimitInterceptor(limiter *rateLimiter) *authFailureLimitInterceptor {
+	return &authFailureLimitInterceptor{limiter: limiter}
+}
+
+func (i *authFailureLimitInterceptor) check(ctx context.Context) error {
+	if wait := i.limiter.wait(peerKey(ctx)); wait > 0 {
+		return exhausted(ErrRateLimited, "requests", wait)
+	}
+	return nil
+}
+
+// record takes a token from the caller's bucket if err is an authentication
+// failure.
+func (i *authFailureLimitInterceptor) record(ctx context.Context, err error) {
+	if connect.CodeOf(err) == connect.CodeUnauthenticated {
+		i.limiter.take(peerKey(ctx))
+	}
+}
+
+func (i *authFailureLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.check(ctx); err != nil {
+			return nil, err
+		}
+		res, err := next(ctx, req)
+		i.record(ctx, err)
+		return res, err
+	}
+}
+
+func (i *authFailureLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *authFailureLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(ctx); err != nil {
+			return err
+		}
+		err := next(ctx, conn)
+		i.record(ctx, err)
+		return err
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clock := newFakeClock()
+	l := newRateLimiter(2, 3, clock.Now)
+
+	for i := range 3 {
+		if _, ok := l.take("a"); !ok {
+			t.Fatalf("take() %d within the burst = false, want true", i)
+		}
+	}
+	wait, ok := l.take("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("take() past the burst = %v, %v; want false after 500ms", wait, ok)
+	}
+	if _, ok := l.take("b"); !ok {
+		t.Error("take() for another client = false, want true")
+	}
+
+	clock.Advance(250 * time.Millisecond)
+	if wait, ok := l.take("a"); ok || wait != 250*time.Millisecond {
+		t.Errorf("take() halfway to a token = %v, %v; want false after 250ms", wait, ok)
+	}
+	clock.Advance(250 * time.Millisecond)
+	if _, ok := l.take("a"); !ok {
+		t.Error("take() once a token has refilled = false, want true")
+	}
+
+	// Buckets that have refilled are forgotten.
+	clock.Advance(rateLimitSweepInterval)
+	l.take("c")
+	if _, ok := l.buckets["a"]; ok || len(l.buckets) != 1 {
+		t.Errorf("buckets after a sweep = %v, want only the new client's", l.buckets)
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	auth := newAuthInterceptor(map[string]apiKey{aliceToken: {user: "alice"}, bobToken: {user: "bob"}})
+	limiter := newRateLimiter(1, 2, newFakeClock().Now)
+	_, handler := todov1.NewTodoServiceHandler(server, todov1.WithInterceptors(auth, newRateLimitInterceptor(limiter)))
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	getTasks := func(token string) error {
+		_, err := call[todov1.GetTasksRequest, todov1.GetTasksResponse](t, httpServer, "GetTasks", token, &todov1.GetTasksRequest{})
+		return err
+	}
+	for range 2 {
+		if err := getTasks(aliceToken); err != nil {
+			t.Fatalf("GetTasks() within the burst error = %v", err)
+		}
+	}
+	err := getTasks(aliceToken)
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("GetTasks() past the burst error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	info := retryInfo(t, err)
+	if info.Limit != "requests" || info.RetryAfterMs != 1000 {
+		t.Errorf("RetryInfo = %v, want the request limit and a second's wait", info)
+	}
+	var cerr *connect.Error
+	if errors.As(err, &cerr) && cerr.Meta().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %q, want 1", cerr.Meta().Get("Retry-After"))
+	}
+
+	// Clients are told apart by user.
+	if err := getTasks(bobToken); err != nil {
+		t.Errorf("GetTasks() as another user error = %v", err)
+	}
+}
+
+func TestAuthFailureLimitInterceptor(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	clock := newFakeClock()
+	auth := newAuthInterceptor(map[string]apiKey{aliceToken: {user: "alice"}})
+	failures := newAuthFailureLimitInterceptor(newRateLimiter(1, 2, clock.Now))
+	_, handler := todov1.NewTodoServiceHandler(server, todov1.WithInterceptors(failures, auth))
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	getTasks := func(token string) error {
+		_, err := call[todov1.GetTasksRequest, todov1.GetTasksResponse](t, httpServer, "GetTasks", token, &todov1.GetTasksRequest{})
+		return err
+	}
+	// Calls that authenticate take no tokens.
+	for range 3 {
+		if err := getTasks(aliceToken); err != nil {
+			t.Fatalf("GetTasks() with a valid key error = %v", err)
+		}
+	}
+	for _, token := range []string{"wrong", ""} {
+		if err := getTasks(token); connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("GetTasks() with key %q error = %v, want code %v", token, err, connect.CodeUnauthenticated)
+		}
+	}
+	err := getTasks("wrong")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("GetTasks() past the failure burst error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	if info := retryInfo(t, err); info.Limit != "requests" || info.RetryAfterMs != 1000 {
+		t.Errorf("RetryInfo = %v, want the request limit and a second's wait", info)
+	}
+	// The key is not looked at until the address has a token again.
+	if err := getTasks(aliceToken); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("GetTasks() with a valid key while limited error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	clock.Advance(time.Second)
+	if err := getTasks(aliceToken); err != nil {
+		t.Errorf("GetTasks() with a valid key once a token has refilled error = %v", err)
+	}
+}
+
+// retryInfo returns the RetryInfo detail of err.
+func retryInfo(t *testing.T, err error) *todov1.RetryInfo {
+	t.Helper()
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		t.Fatalf("error %v is not a connect error", err)
+	}
+	for _, d := range cerr.Details() {
+		if v, derr := d.Value(); derr == nil {
+			if info, ok := v.(*todov1.RetryInfo); ok {
+				return info
+			}
+		}
+	}
+	t.Fatalf("error %v has no RetryInfo detail", err)
+	return nil
+}
+
+func TestClientKey(t *testing.T) {
+	if got := clientKey(withAPIKey(context.Background(), apiKey{user: "alice"})); got != "user:alice" {
+		t.Errorf("clientKey() with a key = %q, want user:alice", got)
+	}
+	if got := clientKey(context.Background()); got != "ip:" {
+		t.Errorf("clientKey() without a peer = %q, want ip:", got)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+// MaxRecurrenceInterval caps the INTERVAL of a recurrence rule.
+const MaxRecurrenceInterval = 1000
+
+var (
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrRecurrenceNeedsDue = errors.New("a recurring task needs a due time")
+)
+
+// recurrenceRule is the parsed form of the RFC 5545 RRULE subset tasks
+// support: FREQ=DAILY, WEEKLY or MONTHLY, an optional INTERVAL and, for
+// weekly rules, an optional BYDAY list of weekdays.
+type recurrenceRule struct {
+	freq     string
+	interval int
+	byDay    []time.Weekday // sorted Monday first, as weeks start on Monday
+}
+
+// rruleWeekdays lists the RRULE weekday codes, indexed by weekdayIndex.
+var rruleWeekdays = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// weekdayIndex returns the position of day in a week that starts on Monday.
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// parseRecurrence parses an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+// Parts may come in any order and are case-insensitive; an "RRULE:" prefix is
+// accepted. Every error wraps ErrInvalidRecurrence.
+func parseRecurrence(s string) (recurrenceRule, error) {
+	r := recurrenceRule{interval: 1}
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return recurrenceRule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+		if seen[name] {
+			return recurrenceRule{}, fmt.Errorf("%w: %s given twice", ErrInvalidRecurrence, name)
+		}
+		seen[name] = true
+		switch name {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return recurrenceRule{}, fmt.Errorf("%w: unsupported FREQ %q (want DAILY, WEEKLY or MONTHLY)", ErrInvalidRecurrence, value)
+			}
+			r.freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > MaxRecurrenceInterval {
+				return recurrenceRule{}, fmt.Errorf("%w: INTERVAL must be between 1 and %d", ErrInvalidRecurrence, MaxRecurrenceInterval)
+			}
+			r.interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				i := slices.Index(rruleWeekdays, code)
+				if i < 0 {
+					return recurrenceRule{}, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRecurrence, code)
+				}
+				if day := time.Weekday((i + 1) % 7); !slices.Contains(r.byDay, day) {
+					r.byDay = append(r.byDay, day)
+				}
+			}
+			slices.SortFunc(r.byDay, func(a, b time.Weekday) int { return weekdayIndex(a) - weekdayIndex(b) })
+		default:
+			return recurrenceRule{}, fmt.Errorf("%w: unsupported part %q", ErrInvalidRecurrence, name)
+		}
+	}
+	if r.freq == "" {
+		return recurrenceRule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRecurrence)
+	}
+	if len(r.byDay) > 0 && r.freq != "WEEKLY" {
+		return recurrenceRule{}, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRecurrence)
+	}
+	return r, nil
+}
+
+// String returns the rule in canonical form: FREQ first, INTERVAL only when
+// it is not 1 and weekdays Monday first.
+func (r recurrenceRule) String() string {
+	s := "FREQ=" + r.freq
+	if r.interval != 1 {
+		s += ";INTERVAL=" + strconv.Itoa(r.interval)
+	}
+	if len(r.byDay) > 0 {
+		codes := make([]string, len(r.byDay))
+		for i, day := range r.byDay {
+			codes[i] = rruleWeekdays[weekdayIndex(day)]
+		}
+		s += ";BYDAY=" + strings.Join(codes, ",")
+	}
+	return s
+}
+
+// normalizeRecurrence returns rule in canonical form; an empty rule stays
+// empty.
+func normalizeRecurrence(rule string) (string, error) {
+	if strings.TrimSpace(rule) == "" {
+		return "", nil
+	}
+	r, err := parseRecurrence(rule)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+// after returns the first occurrence of the rule after t, counting from t as
+// an occurrence itself. Occurrences keep t's time of day in t's location.
+// Monthly rules skip months that lack t's day, so a task due on the 31st
+// comes back on the 31st.
+func (r recurrenceRule) after(t time.Time) time.Time {
+	switch r.freq {
+	case "DAILY":
+		return t.AddDate(0, 0, r.interval)
+	case "WEEKLY":
+		if len(r.byDay) == 0 {
+			return t.AddDate(0, 0, 7*r.interval)
+		}
+		// Later in the same week, or the first chosen day of the week
+		// interval weeks on.
+		today := weekdayIndex(t.Weekday())
+		for _, day := range r.byDay {
+			if i := weekdayIndex(day); i > today {
+				return t.AddDate(0, 0, i-today)
+			}
+		}
+		return t.AddDate(0, 0, 7*r.interval-today+weekdayIndex(r.byDay[0]))
+	default: // MONTHLY
+		for k := 1; ; k++ {
+			next := time.Date(t.Year(), t.Month()+time.Month(k*r.interval), t.Day(),
+				t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			if next.Day() == t.Day() {
+				return next
+			}
+		}
+	}
+}
+
+// nextDue returns the due time of the instance that follows one due at dueAt,
+// skipping occurrences that are not after now.
+func (r recurrenceRule) nextDue(dueAt, now int64) int64 {
+	t := time.Unix(dueAt, 0)
+	for {
+		t = r.after(t)
+		if t.Unix() > now {
+			return t.Unix()
+		}
+	}
+}
+
+// recurrenceDue reports whether the recurring task has been completed or has
+// come due by now, so that its next instance should be created.
+func recurrenceDue(task *todov1.Task, now int64) bool {
+	return task.Recurrence != "" && (task.Completed || task.DueAt <= now)
+}
+
+// takeRecurrence checks task, which is about to be stored, and if it has
+// been completed or has come due moves its recurrence rule onto a new,
+// not yet stored instance that it returns. Otherwise it returns nil.
+// Occurrences are computed in the server's time zone.
+func (s *TodoServer) takeRecurrence(task *todov1.Task) *todov1.Task {
+	now := s.now().Unix()
+	if !recurrenceDue(task, now) {
+		return nil
+	}
+	r, err := parseRecurrence(task.Recurrence)
+	if err != nil {
+		// Rules are validated on the way in, so this is a damaged store.
+		log.Printf("Task %s has an invalid recurrence rule: %v", task.Id, err)
+		return nil
+	}
+	next := &todov1.Task{
+		Text:       task.Text,
+		ListId:     task.ListId,
+		OwnerId:    task.OwnerId,
+		DueAt:      r.nextDue(task.DueAt, now),
+		Priority:   task.Priority,
+		Tags:       slices.Clone(task.Tags),
+		ParentId:   task.ParentId,
+		Recurrence: task.Recurrence,
+	}
+	task.Recurrence = ""
+	return next
+}
+
+// addInstance stores next, an instance returned by takeRecurrence, under a
+// fresh ID. Callers must hold s.mu.
+func (s *TodoServer) addInstance(next *todov1.Task) error {
+	for i := 0; i < 10; i++ {
+		id, err := generateID()
+		if err != nil {
+			return err
+		}
+		next.Id = id
+		next.CreatedAt = s.now().Unix()
+		created, err := s.insertTask(next)
+		if err != nil {
+			return err
+		}
+		if created {
+			return nil
+		}
+	}
+	return errors.New("failed to generate unique task ID")
+}
+
+// advanceRecurrences rolls over the recurring tasks that were completed or
+// came due while the server was down.
+func (s *TodoServer) advanceRecurrences(tasks []*todov1.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().Unix()
+	for _, task := range tasks {
+		if !recurrenceDue(task, now) {
+			continue
+		}
+		if err := s.replaceTask(task, proto.Clone(task).(*todov1.Task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	for _, tt := range []struct {
+		rule string
+		want string // canonical form; empty if the rule is invalid
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: "rrule:freq=weekly;interval=1", want: "FREQ=WEEKLY"},
+		{rule: "BYDAY=TH,MO,TH;FREQ=WEEKLY;INTERVAL=2", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"},
+		{rule: "FREQ=WEEKLY;BYDAY=SU,MO", want: "FREQ=WEEKLY;BYDAY=MO,SU"},
+		{rule: "FREQ=MONTHLY;INTERVAL=3", want: "FREQ=MONTHLY;INTERVAL=3"},
+		{rule: "FREQ=YEARLY"},
+		{rule: "INTERVAL=2"},
+		{rule: "FREQ=DAILY;INTERVAL=0"},
+		{rule: "FREQ=DAILY;INTERVAL=x"},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY"},
+		{rule: "FREQ=DAILY;BYDAY=MO"},
+		{rule: "FREQ=WEEKLY;BYDAY=XX"},
+		{rule: "FREQ=WEEKLY;COUNT=3"},
+		{rule: "FREQ=WEEKLY;"},
+	} {
+		got, err := normalizeRecurrence(tt.rule)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("normalizeRecurrence(%q) = %q, %v; want ErrInvalidRecurrence", tt.rule, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeRecurrence(%q) = %q, %v; want %q", tt.rule, got, err, tt.want)
+		}
+	}
+}
+
+func TestRecurrenceAfter(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 9, 30, 0, 0, time.UTC)
+	}
+	// March 10, 2026 is a Tuesday.
+	for _, tt := range []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{rule: "FREQ=DAILY", from: day(3, 10), want: day(3, 11)},
+		{rule: "FREQ=DAILY;INTERVAL=3", from: day(3, 30), want: day(4, 2)},
+		{rule: "FREQ=WEEKLY", from: day(3, 10), want: day(3, 17)},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,TH", from: day(3, 10), want: day(3, 12)},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,TH", from: day(3, 12), want: day(3, 16)},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", from: day(3, 12), want: day(3, 23)},
+		{rule: "FREQ=WEEKLY;BYDAY=TU", from: day(3, 10), want: day(3, 17)},
+		{rule: "FREQ=MONTHLY", from: day(3, 10), want: day(4, 10)},
+		{rule: "FREQ=MONTHLY", from: day(1, 31), want: day(3, 31)},
+		{rule: "FREQ=MONTHLY;INTERVAL=2", from: day(11, 15), want: time.Date(2027, 1, 15, 9, 30, 0, 0, time.UTC)},
+	} {
+		r, err := parseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("parseRecurrence(%q) error = %v", tt.rule, err)
+		}
+		if got := r.after(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s after %v = %v, want %v", tt.rule, tt.from, got, tt.want)
+		}
+	}
+}
+
+// addRecurring adds a task due at dueAt that repeats according to rule.
+func addRecurring(t *testing.T, server *TodoServer, text, rule string, dueAt int64) *todov1.Task {
+	t.Helper()
+	resp, err := server.AddTask(context.Background(), connect.NewRequest(&todov1.AddTaskRequest{
+		Text:       text,
+		DueAt:      dueAt,
+		Priority:   todov1.Priority_PRIORITY_P1,
+		Tags:       []string{"chores"},
+		Recurrence: rule,
+	}))
+	if err != nil {
+		t.Fatalf("AddTask(%q) error = %v", text, err)
+	}
+	return resp.Msg.Task
+}
+
+// otherTasks returns the tasks of the server other than the ones with the
+// given IDs.
+func otherTasks(t *testing.T, server *TodoServer, ids ...string) []*todov1.Task {
+	t.Helper()
+	var out []*todov1.Task
+	for id, task := range tasksByID(t, server) {
+		if !slices.Contains(ids, id) {
+			out = append(out, task)
+		}
+	}
+	return out
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	ctx := context.Background()
+
+	dueAt := clock.Now().Add(time.Hour).Unix()
+	task := addRecurring(t, server, "Standup notes", "freq=daily", dueAt)
+	if task.Recurrence != "FREQ=DAILY" {
+		t.Errorf("AddTask() recurrence = %q, want the canonical form", task.Recurrence)
+	}
+
+	resp, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id}))
+	if err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	if got := resp.Msg.Task.Recurrence; got != "" {
+		t.Errorf("completed task recurrence = %q, want it moved to the next instance", got)
+	}
+	next := otherTasks(t, server, task.Id)
+	if len(next) != 1 {
+		t.Fatalf("got %d new tasks after completing, want 1", len(next))
+	}
+	want := time.Unix(dueAt, 0).AddDate(0, 0, 1).Unix()
+	if n := next[0]; n.DueAt != want || n.Completed || n.Text != task.Text ||
+		n.Recurrence != "FREQ=DAILY" || n.Priority != task.Priority || len(n.Tags) != 1 {
+		t.Errorf("next instance = %v, want an open copy due at %d", n, want)
+	}
+
+	// The rule has moved on, so completing the old task again adds nothing.
+	if _, err := server.ReopenTask(ctx, connect.NewRequest(&todov1.ReopenTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	if got := len(tasksByID(t, server)); got != 2 {
+		t.Errorf("got %d tasks after completing the old instance again, want 2", got)
+	}
+}
+
+func TestRecurringTaskComesDue(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	sub := server.hub.subscribe("")
+	defer server.hub.unsubscribe(sub)
+
+	dueAt := clock.Now().Add(time.Minute).Unix()
+	task := addRecurring(t, server, "Weekly report", "FREQ=WEEKLY", dueAt)
+	<-sub.events // ADDED
+
+	clock.Advance(2 * time.Minute)
+	server.reminders.poke()
+	var types []todov1.TaskEventType
+	var added *todov1.Task
+	for len(types) < 3 {
+		select {
+		case ev := <-sub.events:
+			types = append(types, ev.Type)
+			if ev.Type == todov1.TaskEventType_TASK_EVENT_TYPE_ADDED {
+				added = ev.Task
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", types)
+		}
+	}
+	wantTypes := []todov1.TaskEventType{
+		todov1.TaskEventType_TASK_EVENT_TYPE_DUE,
+		todov1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+		todov1.TaskEventType_TASK_EVENT_TYPE_ADDED,
+	}
+	for i := range wantTypes {
+		if types[i] != wantTypes[i] {
+			t.Fatalf("events = %v, want %v", types, wantTypes)
+		}
+	}
+	if want := time.Unix(dueAt, 0).AddDate(0, 0, 7).Unix(); added.DueAt != want {
+		t.Errorf("next instance due at %d, want %d", added.DueAt, want)
+	}
+	if got := tasksByID(t, server)[task.Id]; got.Completed || got.Recurrence != "" {
+		t.Errorf("passed task = %v, want it left open without its rule", got)
+	}
+}
+
+func TestRecurringTaskMissedWhileDown(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryStore()
+	// Due almost five days ago on a daily rule: the missed occurrences are
+	// skipped and a single instance is due an hour from now.
+	dueAt := clock.Now().Add(-5*24*time.Hour + time.Hour).Unix()
+	if err := store.CreateTask(&todov1.Task{Id: "old", Text: "Water plants", DueAt: dueAt, Recurrence: "FREQ=DAILY"}, nil); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	server := mustNewServer(t, store, WithClock(clock.Now))
+
+	next := otherTasks(t, server, "old")
+	if len(next) != 1 {
+		t.Fatalf("got %d new tasks after restart, want 1", len(next))
+	}
+	want := time.Unix(dueAt, 0).AddDate(0, 0, 5).Unix()
+	if next[0].DueAt != want {
+		t.Errorf("next instance due at %d, want %d, the first occurrence after now", next[0].DueAt, want)
+	}
+}
+
+func TestRecurrenceValidation(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	ctx := context.Background()
+	dueAt := clock.Now().Add(time.Hour).Unix()
+
+	for _, req := range []*todov1.AddTaskRequest{
+		{Text: "No due date", Recurrence: "FREQ=DAILY"},
+		{Text: "Bad rule", DueAt: dueAt, Recurrence: "FREQ=HOURLY"},
+	} {
+		if _, err := server.AddTask(ctx, connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("AddTask(%q) error = %v, want code %v", req.Text, err, connect.CodeInvalidArgument)
+		}
+	}
+
+	task := addRecurring(t, server, "Pay rent", "FREQ=MONTHLY", dueAt)
+	for _, tt := range []struct {
+		name  string
+		src   *todov1.Task
+		paths []string
+	}{
+		{name: "clear due date", src: &todov1.Task{}, paths: []string{"due_at"}},
+		{name: "bad rule", src: &todov1.Task{Recurrence: "FREQ=DAILY;BYDAY=MO"}, paths: []string{"recurrence"}},
+	} {
+		_, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:         task.Id,
+			Task:       tt.src,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths},
+		}))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("UpdateTask(%s) error = %v, want code %v", tt.name, err, connect.CodeInvalidArgument)
+		}
+	}
+
+	resp, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         task.Id,
+		Task:       &todov1.Task{Recurrence: "byday=fr;freq=weekly"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"recurrence"}},
+	}))
+	if err != nil {
+		t.Fatalf("UpdateTask(recurrence) error = %v", err)
+	}
+	if got := resp.Msg.Task.Recurrence; got != "FREQ=WEEKLY;BYDAY=FR" {
+		t.Errorf("UpdateTask(recurrence) = %q, want %q", got, "FREQ=WEEKLY;BYDAY=FR")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// reminder is a task's due time waiting to be announced.
+type reminder struct {
+	dueAt int64
+	id    string
+}
+
+// reminderQueue is a min-heap of reminders ordered by due time.
+type reminderQueue []reminder
+
+func (q reminderQueue) Len() int           { return len(q) }
+func (q reminderQueue) Less(i, j int) bool { return q[i].dueAt < q[j].dueAt }
+func (q reminderQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *reminderQueue) Push(x any)        { *q = append(*q, x.(reminder)) }
+
+func (q *reminderQueue) Pop() any {
+	old := *q
+	r := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return r
+}
+
+func (q reminderQueue) peek() (reminder, bool) {
+	if len(q) == 0 {
+		return reminder{}, false
+	}
+	return q[0], true
+}
+
+// reminderScheduler calls fire for each task when its due time arrives.
+// Rescheduling a task replaces its pending reminder; reminders that are
+// already due when scheduled, such as those missed while the server was
+// down, are dropped rather than announced late.
+type reminderScheduler struct {
+	now  func() time.Time
+	fire func(reminder)
+
+	mu      sync.Mutex
+	queue   reminderQueue
+	pending map[string]int64 // task ID -> due time of its live reminder
+
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newReminderScheduler starts a scheduler that reads the time from now and
+// announces reminders with fire. Callers must stop it with close.
+func newReminderScheduler(now func() time.Time, fire func(reminder)) *reminderScheduler {
+	r := &reminderScheduler{
+		now:     now,
+		fire:    fire,
+		pending: make(map[string]int64),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// schedule arranges for the task with the given ID to be announced at dueAt,
+// replacing any earlier reminder for it. A dueAt that is zero or has passed
+// cancels the reminder.
+func (r *reminderScheduler) schedule(id string, dueAt int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if dueAt <= r.now().Unix() {
+		delete(r.pending, id)
+		return
+	}
+	if r.pending[id] == dueAt {
+		return
+	}
+	r.pending[id] = dueAt
+	heap.Push(&r.queue, reminder{dueAt: dueAt, id: id})
+	r.poke()
+}
+
+// poke makes the scheduler re-examine its queue, for instance after an
+// earlier reminder was added or the clock was moved in a test.
+func (r *reminderScheduler) poke() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// takeDue removes and returns the reminders that are due, along with the
+// due time of the next one, if any.
+func (r *reminderScheduler) takeDue() ([]reminder, int64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().Unix()
+	var due []reminder
+	for {
+		next, ok := r.queue.peek()
+		if !ok {
+			return due, 0, false
+		}
+		if next.dueAt > now {
+			return due, next.dueAt, true
+		}
+		heap.Pop(&r.queue)
+		// Entries replaced by a later schedule call are skipped.
+		if r.pending[next.id] == next.dueAt {
+			delete(r.pending, next.id)
+			due = append(due, next)
+		}
+	}
+}
+
+func (r *reminderScheduler) run() {
+	defer close(r.done)
+
+	for {
+		due, next, ok := r.takeDue()
+		for _, rem := range due {
+			r.fire(rem)
+		}
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if ok {
+			timer = time.NewTimer(time.Unix(next, 0).Sub(r.now()))
+			expired = timer.C
+		}
+		select {
+		case <-r.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-r.wake:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// close stops the scheduler and waits for it to exit. It is safe to call
+// more than once.
+func (r *reminderScheduler) close() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+}
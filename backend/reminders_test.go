@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+// fakeClock is a manually advanced clock for WithClock and the reminder
+// scheduler.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestReminderScheduler(t *testing.T) {
+	clock := newFakeClock()
+	fired := make(chan reminder, 10)
+	r := newReminderScheduler(clock.Now, func(rem reminder) { fired <- rem })
+	defer r.close()
+
+	start := clock.Now().Unix()
+	r.schedule("a", start+10)
+	r.schedule("b", start+20)
+	r.schedule("a", start+30) // replaces a's first reminder
+	r.schedule("c", start+15)
+	r.schedule("c", 0) // cancels c
+	r.schedule("d", start-5)
+
+	expect := func(want ...string) {
+		t.Helper()
+		for _, id := range want {
+			select {
+			case rem := <-fired:
+				if rem.id != id {
+					t.Fatalf("fired %q, want %q", rem.id, id)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %q", id)
+			}
+		}
+		select {
+		case rem := <-fired:
+			t.Fatalf("fired unexpected reminder %q", rem.id)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	expect()
+	clock.Advance(25 * time.Second)
+	r.poke()
+	expect("b")
+	clock.Advance(10 * time.Second)
+	r.poke()
+	expect("a")
+
+	r.close()
+	r.close() // must be a no-op
+}
+
+func TestDueReminderEvents(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	ctx := context.Background()
+	sub := server.hub.subscribe("")
+	defer server.hub.unsubscribe(sub)
+
+	add := func(text string, dueIn time.Duration) *todov1.Task {
+		t.Helper()
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{
+			Text:  text,
+			DueAt: clock.Now().Add(dueIn).Unix(),
+		}))
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+		<-sub.events // ADDED
+		return resp.Msg.Task
+	}
+	open := add("Open", time.Minute)
+	done := add("Done", time.Minute)
+	moved := add("Moved", time.Minute)
+
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: done.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	<-sub.events // UPDATED
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         moved.Id,
+		Task:       &todov1.Task{DueAt: clock.Now().Add(time.Hour).Unix()},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"due_at"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	<-sub.events // UPDATED
+
+	clock.Advance(2 * time.Minute)
+	server.reminders.poke()
+	select {
+	case ev := <-sub.events:
+		if ev.Type != todov1.TaskEventType_TASK_EVENT_TYPE_DUE || ev.Task.Id != open.Id {
+			t.Fatalf("got %v for %q, want a due event for %q", ev.Type, ev.Task.Id, open.Id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a due event")
+	}
+	select {
+	case ev := <-sub.events:
+		t.Fatalf("got unexpected %v event for %q", ev.Type, ev.Task.Id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Bad", DueAt: -1}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("AddTask() with negative due time error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"todo-list/todo/v1"
+)
+
+// tokenize splits text into lower-cased words, dropping punctuation and
+// duplicates. It is used both for indexing task text and for parsing queries
+// so that the two always agree.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	terms := fields[:0]
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// searchIndex is an inverted index from words in task text to task IDs.
+// A query matches a task when every query term is a prefix of some word in
+// the task's text, so "rep" finds "Weekly report".
+// It is not safe for concurrent use; TodoServer guards it with its mutex.
+type searchIndex struct {
+	postings map[string]map[string]struct{} // term -> IDs of tasks containing it
+	terms    []string                       // sorted keys of postings
+	docs     map[string][]string            // task ID -> its indexed terms
+}
+
+func newSearchIndex(tasks []*todov1.Task) *searchIndex {
+	x := &searchIndex{
+		postings: make(map[string]map[string]struct{}),
+		docs:     make(map[string][]string),
+	}
+	for _, task := range tasks {
+		x.add(task)
+	}
+	return x
+}
+
+func (x *searchIndex) add(task *todov1.Task) {
+	terms := tokenize(task.Text)
+	x.docs[task.Id] = terms
+	for _, term := range terms {
+		ids, ok := x.postings[term]
+		if !ok {
+			ids = make(map[string]struct{})
+			x.postings[term] = ids
+			i := sort.SearchStrings(x.terms, term)
+			x.terms = append(x.terms, "")
+			copy(x.terms[i+1:], x.terms[i:])
+			x.terms[i] = term
+		}
+		ids[task.Id] = struct{}{}
+	}
+}
+
+func (x *searchIndex) remove(id string) {
+	for _, term := range x.docs[id] {
+		ids := x.postings[term]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(x.postings, term)
+			i := sort.SearchStrings(x.terms, term)
+			x.terms = append(x.terms[:i], x.terms[i+1:]...)
+		}
+	}
+	delete(x.docs, id)
+}
+
+// update re-indexes task after its text may have changed.
+func (x *searchIndex) update(task *todov1.Task) {
+	x.remove(task.Id)
+	x.add(task)
+}
+
+// lookup returns the IDs of tasks matching every term.
+func (x *searchIndex) lookup(terms []string) map[string]struct{} {
+	var result map[string]struct{}
+	for _, term := range terms {
+		matched := make(map[string]struct{})
+		for i := sort.SearchStrings(x.terms, term); i < len(x.terms) && strings.HasPrefix(x.terms[i], term); i++ {
+			for id := range x.postings[x.terms[i]] {
+				if result == nil {
+					matched[id] = struct{}{}
+				} else if _, ok := result[id]; ok {
+					matched[id] = struct{}{}
+				}
+			}
+		}
+		result = matched
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
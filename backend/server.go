@@ -6,13 +6,14 @@ import (
 	"context"
 	"crypto/rand"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
 	"net/http"
 	"os"
 	"os/signal"
-	"sort"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -20,48 +21,175 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/rs/cors"
-	"golang.org/x/net/http2/h2c"
 	http2 "golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/proto"
 
 	"todo-list/todo/v1"
 )
 
 const (
+	// MaxTaskTextLength is the default limit on task text, in bytes.
 	MaxTaskTextLength = 500
 	MinTaskTextLength = 1
 )
 
 var (
-	ErrTaskTextEmpty    = errors.New("task text cannot be empty")
-	ErrTaskTextTooLong  = errors.New("task text exceeds maximum length")
-	ErrTaskNotFound     = errors.New("task not found")
-	ErrInvalidTaskID    = errors.New("invalid task ID")
+	ErrTaskTextEmpty   = errors.New("task text cannot be empty")
+	ErrTaskTextTooLong = errors.New("task text exceeds maximum length")
+	ErrTaskNotFound    = errors.New("task not found")
+	ErrInvalidTaskID   = errors.New("invalid task ID")
+	ErrEmptyUpdateMask = errors.New("update mask must name at least one field")
+	ErrInvalidUpdate   = errors.New("field cannot be updated")
+	ErrInvalidDueAt    = errors.New("due time cannot be negative")
+	ErrVersionMismatch = errors.New("task has changed since the expected version")
 )
 
 type TodoServer struct {
-	mu     sync.RWMutex
-	tasks  map[string]*todov1.Task
+	// mu serializes mutations so events are published in store order, and
+	// keeps the order index consistent with the store for readers.
+	mu       sync.RWMutex
+	store    TaskStore
+	order    *orderIndex
+	search   *searchIndex
+	blockers *blockerIndex
+	counts   *taskCounts
+	hub      *taskHub
+
+	undo      *undoLog
+	recording *undoEntry // changes of the operation being recorded, if any
+
+	actor        string // user making the current change, for the history
+	lastEventSeq int64  // of the last history event stored
+
+	// While a batch is applied, publish and recordEvent hold its events back
+	// in held and heldHistory so that a batch that is rolled back is never
+	// seen by watchers or recorded.
+	holding     bool
+	held        []*todov1.TaskEvent
+	heldHistory []*todov1.HistoryEvent
+
+	reminders        *reminderScheduler
+	janitor          *janitor
+	lastPosition     string // highest task position handed out
+	now              func() time.Time
+	maxTextLength    int
+	maxTasks         int // live and trashed; 0 for no limit
+	trashRetention   time.Duration
+	historyRetention time.Duration
+
+	// Caps on the live and trashed tasks of each user and list; 0 for no
+	// limit.
+	maxTasksPerOwner int
+	maxTasksPerList  int
+}
+
+// ServerOption configures a TodoServer.
+type ServerOption func(*TodoServer)
+
+// WithMaxTaskTextLength replaces MaxTaskTextLength as the limit on the length
+// of task text.
+func WithMaxTaskTextLength(n int) ServerOption {
+	return func(s *TodoServer) {
+		s.maxTextLength = n
+	}
+}
+
+// WithClock makes the server read the current time from now instead of
+// time.Now.
+func WithClock(now func() time.Time) ServerOption {
+	return func(s *TodoServer) {
+		s.now = now
+	}
+}
+
+// NewTodoServer returns a TodoServer that keeps its tasks in store, indexing
+// the tasks already present, and starts announcing when tasks come due and
+// purging the trash.
+// The server does not take ownership of the store; callers close the server
+// and then the store once the server has stopped serving requests.
+func NewTodoServer(store TaskStore, opts ...ServerOption) (*TodoServer, error) {
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	trash, err := store.ListTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trash: %w", err)
+	}
+	lastPosition, err := assignPositions(store, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign task positions: %w", err)
+	}
+	s := &TodoServer{
+		store:            store,
+		lastPosition:     lastPosition,
+		order:            newOrderIndex(tasks),
+		search:           newSearchIndex(tasks),
+		blockers:         newBlockerIndex(tasks),
+		counts:           newTaskCounts(tasks, trash),
+		hub:              newTaskHub(),
+		undo:             newUndoLog(),
+		now:              time.Now,
+		maxTextLength:    MaxTaskTextLength,
+		trashRetention:   DefaultTrashRetention,
+		historyRetention: DefaultHistoryRetention,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.loadHistory(); err != nil {
+		s.hub.close()
+		return nil, err
+	}
+	s.reminders = newReminderScheduler(s.now, s.remind)
+	for _, task := range tasks {
+		s.scheduleReminder(task)
+	}
+	if err := s.advanceRecurrences(tasks); err != nil {
+		s.hub.close()
+		s.reminders.close()
+		return nil, fmt.Errorf("failed to add recurring task instances: %w", err)
+	}
+	s.janitor = startJanitor(min(s.trashRetention, s.historyRetention, trashSweepInterval), s.sweep)
+	return s, nil
 }
 
-// NewTodoServer returns a pointer to a TodoServer with its tasks map initialized.
-// The returned server is ready for use; its zero-value sync.RWMutex is valid for guarding access to the tasks map.
-func NewTodoServer() *TodoServer {
-	return &TodoServer{
-		tasks: make(map[string]*todov1.Task),
+// Close ends every WatchTasks stream, makes new ones fail immediately and
+// stops the reminder scheduler and the janitor, waiting for them to
+// exit. Unary RPCs keep working. It is safe to call more than once.
+func (s *TodoServer) Close() {
+	s.hub.close()
+	s.reminders.close()
+	s.janitor.close()
+}
+
+// publish notifies watchers of a change to task. Callers must hold s.mu so
+// that events are delivered in the order the changes were stored.
+func (s *TodoServer) publish(typ todov1.TaskEventType, task *todov1.Task) {
+	ev := &todov1.TaskEvent{
+		Type:       typ,
+		Task:       task,
+		OccurredAt: s.now().Unix(),
 	}
+	if s.holding {
+		s.held = append(s.held, ev)
+		return
+	}
+	s.hub.publish(ev, s.viewers(task)...)
 }
 
 // validateTaskText trims leading and trailing whitespace and checks that the
 // remaining text length is within allowed bounds.
 //
 // It returns ErrTaskTextEmpty if the trimmed text is shorter than MinTaskTextLength,
-// ErrTaskTextTooLong if it exceeds MaxTaskTextLength, or nil if the text is valid.
-func validateTaskText(text string) error {
+// ErrTaskTextTooLong if it exceeds maxLength, or nil if the text is valid.
+func validateTaskText(text string, maxLength int) error {
 	text = strings.TrimSpace(text)
 	if len(text) < MinTaskTextLength {
 		return ErrTaskTextEmpty
 	}
-	if len(text) > MaxTaskTextLength {
+	if len(text) > maxLength {
 		return ErrTaskTextTooLong
 	}
 	return nil
@@ -71,82 +199,671 @@ func (s *TodoServer) AddTask(
 	ctx context.Context,
 	req *connect.Request[todov1.AddTaskRequest],
 ) (*connect.Response[todov1.AddTaskResponse], error) {
-	if err := validateTaskText(req.Msg.Text); err != nil {
+	task, err := s.newTask(ctx, req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.lockAs(task.OwnerId)()
+
+	err = s.recordUndo(task.OwnerId, func() error {
+		return s.addTask(task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.AddTaskResponse{Task: task}), nil
+}
+
+// newTask validates req and returns the task it asks the caller to add,
+// without an ID yet.
+func (s *TodoServer) newTask(ctx context.Context, req *todov1.AddTaskRequest) (*todov1.Task, error) {
+	if err := validateTaskText(req.Text, s.maxTextLength); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if req.DueAt < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidDueAt)
+	}
+	if err := validatePriority(req.Priority); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	tags, err := validateTaskTags(req.Tags)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	recurrence, err := normalizeRecurrence(req.Recurrence)
+	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	if recurrence != "" && req.DueAt == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrRecurrenceNeedsDue)
+	}
+	return &todov1.Task{
+		Text:       strings.TrimSpace(req.Text),
+		CreatedAt:  s.now().Unix(),
+		ListId:     req.ListId,
+		OwnerId:    userFromContext(ctx),
+		DueAt:      req.DueAt,
+		Priority:   req.Priority,
+		Tags:       tags,
+		ParentId:   req.ParentId,
+		Recurrence: recurrence,
+	}, nil
+}
 
-	trimmed := strings.TrimSpace(req.Msg.Text)
+// addTask gives task a fresh ID and stores it with insertTask, unless the
+// server holds as many tasks as it may or the task's creator may not add
+// tasks where it goes. Callers must hold s.mu.
+func (s *TodoServer) addTask(task *todov1.Task) error {
+	if err := s.checkTaskQuota(task); err != nil {
+		return err
+	}
+	if err := s.checkPlacement(task.OwnerId, task); err != nil {
+		return err
+	}
 	// Try to generate a unique ID (retry on collision)
 	for i := 0; i < 10; i++ {
 		id, err := generateID()
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate task ID: %w", err))
-		}
-		now := time.Now().Unix()
-		s.mu.Lock()
-		if _, exists := s.tasks[id]; !exists {
-			task := &todov1.Task{
-				Id:        id,
-				Text:      trimmed,
-				CreatedAt: now,
-			}
-			s.tasks[id] = task
-			s.mu.Unlock()
-			return connect.NewResponse(&todov1.AddTaskResponse{Task: task}), nil
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate task ID: %w", err))
+		}
+		task.Id = id
+		created, err := s.insertTask(task)
+		if errors.Is(err, ErrListNotFound) || errors.Is(err, ErrParentNotFound) {
+			return connect.NewError(connect.CodeNotFound, err)
+		}
+		if errors.Is(err, ErrSubtaskList) {
+			return connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store task: %w", err))
+		}
+		if created {
+			return nil
 		}
-		s.mu.Unlock()
 	}
-	
+
 	// If we get here, we couldn't generate a unique ID after 10 attempts
-	return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate unique task ID"))
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to generate unique task ID"))
+}
+
+// insertTask stores task and announces it to watchers, placing it in its
+// parent's list if it is a subtask. It reports false without error if the
+// task's ID is already taken. It returns ErrListNotFound if the task names a
+// list that does not exist and the errors of setParent. Permissions are the
+// caller's to check. A recurring task that is already due is stored together
+// with its next instance. Callers must hold s.mu.
+func (s *TodoServer) insertTask(task *todov1.Task) (bool, error) {
+	if err := s.setParent(task, task.ListId != ""); err != nil {
+		return false, err
+	}
+	if task.ListId != "" {
+		if _, err := s.store.GetList(task.ListId); err != nil {
+			return false, err
+		}
+	}
+	pos, err := s.nextPosition()
+	if err != nil {
+		return false, err
+	}
+	task.Position = pos
+	task.Version = 1
+	next := s.takeRecurrence(task)
+	err = s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_CREATED, task, func(ev *todov1.HistoryEvent) error {
+		return s.store.CreateTask(task, ev)
+	})
+	if errors.Is(err, ErrTaskExists) {
+		// Put the rule back for the caller's retry under another ID.
+		if next != nil {
+			task.Recurrence = next.Recurrence
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	s.usePosition(task.Position)
+	s.order.insert(task)
+	s.search.add(task)
+	s.blockers.add(task)
+	s.counts.add(task, 1)
+	s.scheduleReminder(task)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_ADDED, task)
+	s.recordChange(nil, task)
+	if next != nil {
+		return true, s.addInstance(next)
+	}
+	return true, nil
 }
 
 func (s *TodoServer) GetTasks(
 	ctx context.Context,
 	req *connect.Request[todov1.GetTasksRequest],
 ) (*connect.Response[todov1.GetTasksResponse], error) {
+	q, err := parseTaskQuery(req.Msg, s.now())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if q.actionable {
+		q.blockerDone = s.blockerDone
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var tasks []*todov1.Task
-	for _, task := range s.tasks {
-		tasks = append(tasks, task)
+	if q.listID != "" {
+		if _, err := s.loadList(ctx, q.listID, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+			return nil, err
+		}
 	}
-
-	// Sort tasks by creation time (newest first)
-	sort.Slice(tasks, func(i, j int) bool {
-		if tasks[i].CreatedAt == tasks[j].CreatedAt {
-			return tasks[i].Id > tasks[j].Id // newest first; then lexicographically by ID desc
+	if q.access, err = s.accessOf(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+	if q.view == todov1.TaskView_TASK_VIEW_TREE {
+		tree, next, err := s.queryTree(q)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
 		}
-		return tasks[i].CreatedAt > tasks[j].CreatedAt // newest first
-	})
+		return connect.NewResponse(&todov1.GetTasksResponse{
+			Tree:          tree,
+			NextPageToken: next,
+		}), nil
+	}
+	tasks, next, err := s.queryTasks(q)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
+	}
 
 	return connect.NewResponse(&todov1.GetTasksResponse{
-		Tasks: tasks,
+		Tasks:         tasks,
+		NextPageToken: next,
 	}), nil
 }
 
+// queryTasks returns the page of tasks selected by q and the token for the
+// page after it. Callers must hold s.mu.
+func (s *TodoServer) queryTasks(q *taskQuery) ([]*todov1.Task, string, error) {
+	if q.usesOrderIndex() {
+		return s.order.page(s.store, q)
+	}
+
+	var candidates []*todov1.Task
+	if len(q.terms) > 0 {
+		for id := range s.search.lookup(q.terms) {
+			task, err := s.store.GetTask(id)
+			if err != nil {
+				return nil, "", err
+			}
+			candidates = append(candidates, task)
+		}
+	} else {
+		all, err := s.store.ListTasks()
+		if err != nil {
+			return nil, "", err
+		}
+		candidates = all
+	}
+
+	tasks := candidates[:0]
+	for _, task := range candidates {
+		if q.match(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	q.sort(tasks)
+	page, next := paginate(tasks, q)
+	return page, next, nil
+}
+
+// matchesStatus reports whether task is in the given state. An unspecified
+// status matches every task.
+func matchesStatus(task *todov1.Task, status todov1.TaskStatus) bool {
+	switch status {
+	case todov1.TaskStatus_TASK_STATUS_OPEN:
+		return !task.Completed
+	case todov1.TaskStatus_TASK_STATUS_COMPLETED:
+		return task.Completed
+	default:
+		return true
+	}
+}
+
 func (s *TodoServer) DeleteTask(
 	ctx context.Context,
 	req *connect.Request[todov1.DeleteTaskRequest],
 ) (*connect.Response[todov1.DeleteTaskResponse], error) {
+	if err := validateDeleteTask(req.Msg); err != nil {
+		return nil, err
+	}
+
+	defer s.lockAs(userFromContext(ctx))()
+
+	err := s.recordUndo(userFromContext(ctx), func() error {
+		return s.applyDeleteTask(ctx, req.Msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.DeleteTaskResponse{
+		Success: true,
+	}), nil
+}
+
+// validateDeleteTask checks the parts of req that do not depend on the
+// stored tasks.
+func validateDeleteTask(req *todov1.DeleteTaskRequest) error {
+	if strings.TrimSpace(req.Id) == "" {
+		return connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+	if _, ok := todov1.SubtaskDeletePolicy_name[int32(req.SubtaskPolicy)]; !ok {
+		return connect.NewError(connect.CodeInvalidArgument, ErrInvalidSubtaskPolicy)
+	}
+	return nil
+}
+
+// applyDeleteTask carries out a DeleteTask request that has passed
+// validateDeleteTask. Callers must hold s.mu.
+func (s *TodoServer) applyDeleteTask(ctx context.Context, req *todov1.DeleteTaskRequest) error {
+	task, err := s.store.GetTask(req.Id)
+	if errors.Is(err, ErrTaskNotFound) {
+		return connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	}
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+	}
+	if err := s.authorizeTask(ctx, task, todov1.ListRole_LIST_ROLE_EDITOR); err != nil {
+		return err
+	}
+	// A task outside the requested list is reported as missing rather than
+	// deleted from under a client that is looking at another list.
+	if req.ListId != "" && task.ListId != req.ListId {
+		return connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	}
+	if err := checkVersion(task, req.ExpectedVersion); err != nil {
+		return err
+	}
+	if err := s.deleteSubtasks(task, req.SubtaskPolicy); err != nil {
+		return err
+	}
+	if err := s.deleteTask(task); err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete task: %w", err))
+	}
+	return nil
+}
+
+// deleteTask moves task to the trash, removes it from the indexes, drops it
+// from the blockers of the tasks waiting for it and announces its deletion.
+// Callers must hold s.mu.
+func (s *TodoServer) deleteTask(task *todov1.Task) error {
+	if err := s.unlinkBlocker(task.Id); err != nil {
+		return err
+	}
+	deletedAt := s.now().Unix()
+	trashed := proto.Clone(task).(*todov1.Task)
+	trashed.DeletedAt = deletedAt
+	err := s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_DELETED, trashed, func(ev *todov1.HistoryEvent) error {
+		return s.store.TrashTask(task.Id, deletedAt, ev)
+	})
+	if err != nil {
+		return err
+	}
+	s.recordChange(task, trashed)
+	task = trashed
+	s.order.remove(task)
+	s.search.remove(task.Id)
+	s.blockers.remove(task)
+	s.reminders.schedule(task.Id, 0)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_DELETED, task)
+	return nil
+}
+
+func (s *TodoServer) UpdateTask(
+	ctx context.Context,
+	req *connect.Request[todov1.UpdateTaskRequest],
+) (*connect.Response[todov1.UpdateTaskResponse], error) {
+	if err := s.validateUpdateTask(req.Msg); err != nil {
+		return nil, err
+	}
+
+	defer s.lockAs(userFromContext(ctx))()
+
+	var task *todov1.Task
+	err := s.recordUndo(userFromContext(ctx), func() error {
+		var err error
+		task, err = s.applyUpdateTask(ctx, req.Msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.UpdateTaskResponse{Task: task}), nil
+}
+
+// validateUpdateTask checks the parts of req that do not depend on the
+// stored tasks.
+func (s *TodoServer) validateUpdateTask(req *todov1.UpdateTaskRequest) error {
+	if strings.TrimSpace(req.Id) == "" {
+		return connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, ErrEmptyUpdateMask)
+	}
+	src := req.GetTask()
+	if src == nil {
+		src = &todov1.Task{}
+	}
+	if err := validateTaskUpdate(src, paths, s.maxTextLength); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return nil
+}
+
+// applyUpdateTask carries out an UpdateTask request that has passed
+// validateUpdateTask and returns the updated task. Callers must hold s.mu.
+func (s *TodoServer) applyUpdateTask(ctx context.Context, req *todov1.UpdateTaskRequest) (*todov1.Task, error) {
+	paths := req.GetUpdateMask().GetPaths()
+	src := req.GetTask()
+	if src == nil {
+		src = &todov1.Task{}
+	}
+	listGiven := slices.Contains(paths, "list_id")
+	return s.editTask(ctx, req.Id, req.ExpectedVersion, func(task *todov1.Task) error {
+		listID, parentID := task.ListId, task.ParentId
+		applyTaskUpdate(task, src, paths)
+		if task.Recurrence != "" && task.DueAt == 0 {
+			return connect.NewError(connect.CodeInvalidArgument, ErrRecurrenceNeedsDue)
+		}
+		if listGiven || slices.Contains(paths, "parent_id") {
+			if err := s.setParent(task, listGiven); err != nil {
+				return parentError(err)
+			}
+		}
+		if task.ListId != listID || task.ParentId != parentID {
+			if err := s.checkPlacement(userFromContext(ctx), task); err != nil {
+				return err
+			}
+		}
+		if task.ListId != listID && task.ListId != "" {
+			if _, err := s.loadList(ctx, task.ListId, todov1.ListRole_LIST_ROLE_EDITOR); err != nil {
+				return err
+			}
+		}
+		if task.ListId != listID {
+			subtasks, err := s.descendants(task.Id)
+			if err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list subtasks: %w", err))
+			}
+			if err := s.checkListRoom(task.ListId, 1+len(subtasks)); err != nil {
+				return err
+			}
+			if err := s.moveSubtree(task, subtasks); err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to move subtasks: %w", err))
+			}
+		}
+		return nil
+	})
+}
+
+func (s *TodoServer) CompleteTask(
+	ctx context.Context,
+	req *connect.Request[todov1.CompleteTaskRequest],
+) (*connect.Response[todov1.CompleteTaskResponse], error) {
+	if strings.TrimSpace(req.Msg.Id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		// Completing a done task again keeps its original completion time.
+		if !task.Completed {
+			task.Completed = true
+			task.CompletedAt = s.now().Unix()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.CompleteTaskResponse{Task: task}), nil
+}
+
+func (s *TodoServer) ReopenTask(
+	ctx context.Context,
+	req *connect.Request[todov1.ReopenTaskRequest],
+) (*connect.Response[todov1.ReopenTaskResponse], error) {
 	if strings.TrimSpace(req.Msg.Id) == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
 	}
 
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		task.Completed = false
+		task.CompletedAt = 0
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.ReopenTaskResponse{Task: task}), nil
+}
+
+// modifyTask loads the task with the given ID, checks that the caller may
+// edit it and that it is at the given version, unless version is zero, lets
+// fn change a copy of it and stores the result. Errors from fn are returned unchanged;
+// store failures are wrapped in connect errors.
+func (s *TodoServer) modifyTask(ctx context.Context, id string, version int64, fn func(task *todov1.Task) error) (*todov1.Task, error) {
+	defer s.lockAs(userFromContext(ctx))()
+
+	var task *todov1.Task
+	err := s.recordUndo(userFromContext(ctx), func() error {
+		var err error
+		task, err = s.editTask(ctx, id, version, fn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// editTask is modifyTask for callers that hold s.mu.
+func (s *TodoServer) editTask(ctx context.Context, id string, version int64, fn func(task *todov1.Task) error) (*todov1.Task, error) {
+	current, err := s.store.GetTask(id)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+	}
+	if err := s.authorizeTask(ctx, current, todov1.ListRole_LIST_ROLE_EDITOR); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(current, version); err != nil {
+		return nil, err
+	}
+
+	task := proto.Clone(current).(*todov1.Task)
+	if err := fn(task); err != nil {
+		return nil, err
+	}
+	if err := s.replaceTask(current, task); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store task: %w", err))
+	}
+	return task, nil
+}
+
+// checkVersion reports ErrVersionMismatch as an Aborted error unless task is
+// at the expected version. An expected version of zero matches any task.
+func checkVersion(task *todov1.Task, expected int64) error {
+	if expected != 0 && task.Version != expected {
+		return connect.NewError(connect.CodeAborted, ErrVersionMismatch)
+	}
+	return nil
+}
+
+// replaceTask stores task in place of current, its previous version, keeps
+// the indexes up to date and announces the change. The task's version goes
+// up if it differs from current, unless the caller has set the version
+// itself. If task is recurring and has been completed or come due, its next
+// instance is added as well. Callers must hold s.mu.
+func (s *TodoServer) replaceTask(current, task *todov1.Task) error {
+	next := s.takeRecurrence(task)
+	if err := s.putTask(current, task); err != nil {
+		return err
+	}
+	if next != nil {
+		return s.addInstance(next)
+	}
+	return nil
+}
+
+// putTask is replaceTask without the roll-over of recurring tasks, for undo,
+// which brings back an earlier state as it was. Callers must hold s.mu.
+func (s *TodoServer) putTask(current, task *todov1.Task) error {
+	if task.Version == current.Version && !proto.Equal(current, task) {
+		task.Version = current.Version + 1
+	}
+	store := func(ev *todov1.HistoryEvent) error {
+		return s.store.UpdateTask(task, ev)
+	}
+	var err error
+	if proto.Equal(current, task) {
+		err = store(nil)
+	} else {
+		err = s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_UPDATED, task, store)
+	}
+	if err != nil {
+		return err
+	}
+	if task.Text != current.Text {
+		s.search.update(task)
+	}
+	s.blockers.update(current, task)
+	s.counts.update(current, task)
+	s.usePosition(task.Position)
+	s.scheduleReminder(task)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_UPDATED, task)
+	s.recordChange(current, task)
+	return nil
+}
+
+// scheduleReminder arranges for watchers to be told when task comes due, as
+// long as it is still open by then.
+func (s *TodoServer) scheduleReminder(task *todov1.Task) {
+	if task.Completed {
+		s.reminders.schedule(task.Id, 0)
+		return
+	}
+	s.reminders.schedule(task.Id, task.DueAt)
+}
+
+// remind announces that a task has come due, unless it has since been
+// deleted, completed or given another due time, and adds the next instance
+// of a recurring task.
+func (s *TodoServer) remind(r reminder) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.tasks[req.Msg.Id]; !exists {
-		return nil, connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	task, err := s.store.GetTask(r.id)
+	if err != nil || task.Completed || task.DueAt != r.dueAt {
+		return
+	}
+	log.Printf("Reminder: task %s (%q) is due", task.Id, task.Text)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_DUE, task)
+	if task.Recurrence != "" {
+		if err := s.replaceTask(task, proto.Clone(task).(*todov1.Task)); err != nil {
+			log.Printf("Failed to add the next instance of task %s: %v", task.Id, err)
+		}
 	}
+}
 
-	delete(s.tasks, req.Msg.Id)
-	return connect.NewResponse(&todov1.DeleteTaskResponse{
-		Success: true,
-	}), nil
+func (s *TodoServer) WatchTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.WatchTasksRequest],
+	stream *todov1.ServerStream[todov1.WatchTasksResponse],
+) error {
+	sub := s.hub.subscribe(userFromContext(ctx))
+	defer s.hub.unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The client went away; there is nobody left to report to.
+			return nil
+		case ev, ok := <-sub.events:
+			if !ok {
+				err := s.hub.reason(sub)
+				if errors.Is(err, ErrWatcherTooSlow) {
+					return connect.NewError(connect.CodeResourceExhausted, err)
+				}
+				return connect.NewError(connect.CodeUnavailable, err)
+			}
+			if err := stream.Send(&todov1.WatchTasksResponse{Event: ev}); err != nil {
+				return err
+			}
+		}
+	}
 }
 
+// validateTaskUpdate checks that every path names an updatable Task field and
+// that the corresponding value in src is acceptable.
+func validateTaskUpdate(src *todov1.Task, paths []string, maxTextLength int) error {
+	for _, path := range paths {
+		switch path {
+		case "text":
+			if err := validateTaskText(src.Text, maxTextLength); err != nil {
+				return err
+			}
+		case "list_id":
+			// Checked against the stored lists when the update is applied.
+		case "due_at":
+			if src.DueAt < 0 {
+				return ErrInvalidDueAt
+			}
+		case "priority":
+			if err := validatePriority(src.Priority); err != nil {
+				return err
+			}
+		case "tags":
+			if _, err := validateTaskTags(src.Tags); err != nil {
+				return err
+			}
+		case "parent_id":
+			// Checked against the stored tasks when the update is applied.
+		case "recurrence":
+			if _, err := normalizeRecurrence(src.Recurrence); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidUpdate, path)
+		}
+	}
+	return nil
+}
+
+// applyTaskUpdate copies the fields named by paths from src into task.
+// The paths must already have been checked by validateTaskUpdate.
+func applyTaskUpdate(task, src *todov1.Task, paths []string) {
+	for _, path := range paths {
+		switch path {
+		case "text":
+			task.Text = strings.TrimSpace(src.Text)
+		case "list_id":
+			task.ListId = src.ListId
+		case "due_at":
+			task.DueAt = src.DueAt
+		case "priority":
+			task.Priority = src.Priority
+		case "tags":
+			// Already validated, so normalizing cannot fail.
+			task.Tags, _ = validateTaskTags(src.Tags)
+		case "parent_id":
+			task.ParentId = src.ParentId
+		case "recurrence":
+			// Already validated, so normalizing cannot fail.
+			task.Recurrence, _ = normalizeRecurrence(src.Recurrence)
+		}
+	}
+}
 
 func generateID() (string, error) {
 	const (
@@ -165,29 +882,143 @@ func generateID() (string, error) {
 	return string(b), nil
 }
 
+// openStore returns the TaskStore selected by cfg.Store: "memory" keeps tasks
+// only for the lifetime of the process, "file" persists them to cfg.DataPath
+// and "wal" to a snapshot there and a write-ahead log next to it.
+func openStore(cfg *Config) (TaskStore, error) {
+	switch cfg.Store {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "file":
+		return OpenFileStore(cfg.DataPath)
+	case "wal":
+		return OpenWALStore(cfg.DataPath, WALOptions{
+			Sync:          cfg.WALSync,
+			SyncInterval:  cfg.WALSyncInterval,
+			SnapshotEvery: cfg.SnapshotEvery,
+		})
+	default:
+		return nil, fmt.Errorf("unknown store %q (want memory, file or wal)", cfg.Store)
+	}
+}
+
+// openWorkspaceStorage returns where the workspaces other than the default
+// one are kept: with the memory store in memory too, otherwise each in a
+// store of the kind selected by cfg.Store under cfg.WorkspaceDir.
+func openWorkspaceStorage(cfg *Config) workspaceStorage {
+	if cfg.Store == "memory" {
+		return memoryWorkspaces{}
+	}
+	return &dirWorkspaces{
+		dir: cfg.WorkspaceDir,
+		openStore: func(path string) (TaskStore, error) {
+			c := *cfg
+			c.DataPath = path
+			return openStore(&c)
+		},
+	}
+}
+
 func main() {
-	todoServer := NewTodoServer()
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			err := command(os.Args[2:], os.Stdin, os.Stdout, os.Stderr)
+			if err != nil && !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	cfg, printConfig, err := loadConfig(os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if printConfig {
+		out, err := cfg.yaml()
+		if err != nil {
+			log.Fatalf("Failed to encode configuration: %v", err)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	var handlerOpts []todov1.HandlerOption
+	if cfg.AuthKeysPath != "" {
+		keys, err := loadAPIKeys(cfg.AuthKeysPath)
+		if err != nil {
+			log.Fatalf("Failed to load API keys: %v", err)
+		}
+		if cfg.RateLimit > 0 {
+			// Before authentication, so that failed attempts are limited by
+			// address.
+			failures := newRateLimiter(cfg.RateLimit, cfg.RateBurst, time.Now)
+			handlerOpts = append(handlerOpts, todov1.WithInterceptors(newAuthFailureLimitInterceptor(failures)))
+		}
+		handlerOpts = append(handlerOpts, todov1.WithInterceptors(newAuthInterceptor(keys)))
+	} else {
+		log.Println("Authentication disabled: every client shares the same tasks (set -auth-keys to enable it)")
+	}
+	if cfg.RateLimit > 0 {
+		// After authentication, so that clients are told apart by user.
+		limiter := newRateLimiter(cfg.RateLimit, cfg.RateBurst, time.Now)
+		handlerOpts = append(handlerOpts, todov1.WithInterceptors(newRateLimitInterceptor(limiter)))
+	}
+
+	store, err := openStore(&cfg)
+	if err != nil {
+		log.Fatalf("Failed to open %s store: %v", cfg.Store, err)
+	}
+	defer store.Close()
+
+	serverOpts := []ServerOption{
+		WithMaxTaskTextLength(cfg.MaxTaskLength),
+		WithTrashRetention(cfg.TrashRetention),
+		WithHistoryRetention(cfg.HistoryRetention),
+		WithMaxTasksPerOwner(cfg.MaxTasksPerOwner),
+		WithMaxTasksPerList(cfg.MaxTasksPerList),
+	}
+	todoServer, err := NewTodoServer(store, serverOpts...)
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+	router, err := newWorkspaceRouter(todoServer, openWorkspaceStorage(&cfg), serverOpts...)
+	if err != nil {
+		log.Fatalf("Failed to open workspaces: %v", err)
+	}
 	mux := http.NewServeMux()
-	_, handler := todov1.NewTodoServiceHandler(todoServer)
+	// The policy interceptor comes after the auth one, which identifies the
+	// caller.
+	policy := todov1.WithInterceptors(newPolicyInterceptor(router))
+	_, handler := todov1.NewTodoServiceHandler(router, append(handlerOpts, policy)...)
 	mux.Handle("/", handler)
+	workspacePath, workspaceHandler := todov1.NewWorkspaceServiceHandler(router, handlerOpts...)
+	mux.Handle(workspacePath, workspaceHandler)
 
 	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000"},
+		AllowedOrigins:   cfg.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Connect-Protocol-Version"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "X-Api-Key", "X-Workspace", "Content-Type", "Content-Length", "Connect-Protocol-Version", "Connect-Timeout-Ms"},
 		AllowCredentials: true,
 	})
 
 	finalHandler := corsHandler.Handler(h2c.NewHandler(mux, &http2.Server{}))
 
 	server := &http.Server{
-		Addr:    ":8080",
-		Handler: finalHandler,
-		ReadTimeout:  5 * time.Second,
-		ReadHeaderTimeout: 2 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Addr:              cfg.ListenAddr,
+		Handler:           finalHandler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
+	// Shutdown waits for connections to go idle, which open WatchTasks streams
+	// never do on their own.
+	server.RegisterOnShutdown(router.Close)
 
 	// Channel to listen for interrupt signals
 	stop := make(chan os.Signal, 1)
@@ -195,7 +1026,7 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		fmt.Println("Server starting on :8080...")
+		fmt.Printf("Server starting on %s...\n", cfg.ListenAddr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server error: %v", err)
 		}
@@ -206,13 +1037,16 @@ func main() {
 	fmt.Println("\nShutting down server...")
 
 	// Create a context with timeout for graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
 	// Shutdown the server
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("Server shutdown error: %v", err)
 	}
+	// Stop the reminder schedulers and close the workspaces' stores before
+	// the deferred store.Close.
+	router.Close()
 
 	fmt.Println("Server gracefully stopped")
-}
\ No newline at end of file
+}
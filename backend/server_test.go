@@ -1,18 +1,81 @@
 package main
 
 import (
-	"fmt"
 	"context"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"todo-list/todo/v1"
 )
 
+// testStores lists every TaskStore implementation the server tests run against.
+var testStores = []struct {
+	name string
+	open func(t *testing.T) TaskStore
+}{
+	{
+		name: "memory",
+		open: func(t *testing.T) TaskStore { return NewMemoryStore() },
+	},
+	{
+		name: "file",
+		open: func(t *testing.T) TaskStore {
+			store, err := OpenFileStore(filepath.Join(t.TempDir(), "tasks.json"))
+			if err != nil {
+				t.Fatalf("OpenFileStore() error = %v", err)
+			}
+			return store
+		},
+	},
+	{
+		name: "wal",
+		open: func(t *testing.T) TaskStore {
+			// Snapshot often so that the tests cross snapshots too.
+			store, err := OpenWALStore(filepath.Join(t.TempDir(), "tasks.json"), WALOptions{Sync: SyncNever, SnapshotEvery: 5})
+			if err != nil {
+				t.Fatalf("OpenWALStore() error = %v", err)
+			}
+			return store
+		},
+	},
+}
+
+// forEachStore runs fn once per TaskStore implementation. newServer returns a
+// TodoServer backed by a fresh, empty store that is closed when the test ends.
+func forEachStore(t *testing.T, fn func(t *testing.T, newServer func() *TodoServer)) {
+	for _, ts := range testStores {
+		t.Run(ts.name, func(t *testing.T) {
+			fn(t, func() *TodoServer {
+				store := ts.open(t)
+				t.Cleanup(func() { store.Close() })
+				return mustNewServer(t, store)
+			})
+		})
+	}
+}
+
+// mustNewServer returns a TodoServer backed by store or fails the test.
+func mustNewServer(tb testing.TB, store TaskStore, opts ...ServerOption) *TodoServer {
+	tb.Helper()
+	server, err := NewTodoServer(store, opts...)
+	if err != nil {
+		tb.Fatalf("NewTodoServer() error = %v", err)
+	}
+	tb.Cleanup(server.Close)
+	return server
+}
+
 func TestAddTask(t *testing.T) {
-	server := NewTodoServer()
+	forEachStore(t, testAddTask)
+}
+
+func testAddTask(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
 
 	tests := []struct {
 		name    string
@@ -89,7 +152,11 @@ func TestAddTask(t *testing.T) {
 }
 
 func TestGetTasks(t *testing.T) {
-	server := NewTodoServer()
+	forEachStore(t, testGetTasks)
+}
+
+func testGetTasks(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
 
 	// Add some tasks first
 	ctx := context.Background()
@@ -101,7 +168,7 @@ func TestGetTasks(t *testing.T) {
 		t.Fatalf("AddTask() error = %v", err)
 	}
 
-	time.Sleep( 3 * time.Millisecond) // Ensure different seconds if CreatedAt uses Unix seconds
+	time.Sleep(3 * time.Millisecond) // Ensure different seconds if CreatedAt uses Unix seconds
 
 	_, err = server.AddTask(ctx, task2)
 	if err != nil {
@@ -132,7 +199,11 @@ func TestGetTasks(t *testing.T) {
 }
 
 func TestGetTasksEmpty(t *testing.T) {
-	server := NewTodoServer()
+	forEachStore(t, testGetTasksEmpty)
+}
+
+func testGetTasksEmpty(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
 
 	ctx := context.Background()
 	req := connect.NewRequest(&todov1.GetTasksRequest{})
@@ -148,45 +219,49 @@ func TestGetTasksEmpty(t *testing.T) {
 }
 
 func TestDeleteTask(t *testing.T) {
+	forEachStore(t, testDeleteTask)
+}
+
+func testDeleteTask(t *testing.T, newServer func() *TodoServer) {
 	tests := []struct {
-		name       string
-		taskID     string
-		setupTasks bool
-		wantErr    bool
+		name        string
+		taskID      string
+		setupTasks  bool
+		wantErr     bool
 		wantSuccess bool
 	}{
 		{
-			name:       "existing task",
-			setupTasks: true,
-			wantErr:    false,
+			name:        "existing task",
+			setupTasks:  true,
+			wantErr:     false,
 			wantSuccess: true,
 		},
 		{
-			name:       "non-existing task",
-			taskID:     "nonexistent",
-			setupTasks: false,
-			wantErr:    true,
+			name:        "non-existing task",
+			taskID:      "nonexistent",
+			setupTasks:  false,
+			wantErr:     true,
 			wantSuccess: false,
 		},
 		{
-			name:       "empty task ID",
-			taskID:     "",
-			setupTasks: false,
-			wantErr:    true,
+			name:        "empty task ID",
+			taskID:      "",
+			setupTasks:  false,
+			wantErr:     true,
 			wantSuccess: false,
 		},
 		{
-			name:       "whitespace task ID",
-			taskID:     "   ",
-			setupTasks: false,
-			wantErr:    true,
+			name:        "whitespace task ID",
+			taskID:      "   ",
+			setupTasks:  false,
+			wantErr:     true,
 			wantSuccess: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := NewTodoServer()
+			server := newServer()
 			ctx := context.Background()
 
 			var taskID string
@@ -220,7 +295,11 @@ func TestDeleteTask(t *testing.T) {
 }
 
 func TestAddTaskIntegration(t *testing.T) {
-	server := NewTodoServer()
+	forEachStore(t, testAddTaskIntegration)
+}
+
+func testAddTaskIntegration(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
 	ctx := context.Background()
 
 	// Add a task
@@ -273,6 +352,371 @@ func TestAddTaskIntegration(t *testing.T) {
 	}
 }
 
+func TestUpdateTask(t *testing.T) {
+	forEachStore(t, testUpdateTask)
+}
+
+func testUpdateTask(t *testing.T, newServer func() *TodoServer) {
+	tests := []struct {
+		name     string
+		taskID   string // empty means the ID of the task created during setup
+		text     string
+		paths    []string
+		wantCode connect.Code // zero means success
+	}{
+		{
+			name:  "update text",
+			text:  "Fixed typo",
+			paths: []string{"text"},
+		},
+		{
+			name:  "text is trimmed",
+			text:  "  Fixed typo  ",
+			paths: []string{"text"},
+		},
+		{
+			name:     "empty text",
+			text:     "   ",
+			paths:    []string{"text"},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "text exceeding max length",
+			text:     strings.Repeat("a", MaxTaskTextLength+1),
+			paths:    []string{"text"},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "empty update mask",
+			text:     "Fixed typo",
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "immutable field",
+			text:     "Fixed typo",
+			paths:    []string{"created_at"},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "unknown task",
+			taskID:   "nonexistent",
+			text:     "Fixed typo",
+			paths:    []string{"text"},
+			wantCode: connect.CodeNotFound,
+		},
+		{
+			name:     "whitespace task ID",
+			taskID:   "   ",
+			text:     "Fixed typo",
+			paths:    []string{"text"},
+			wantCode: connect.CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer()
+			ctx := context.Background()
+
+			addResp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Fix tpyo"}))
+			if err != nil {
+				t.Fatalf("Setup AddTask() error = %v", err)
+			}
+			original := addResp.Msg.Task
+			taskID := tt.taskID
+			if taskID == "" {
+				taskID = original.Id
+			}
+
+			resp, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+				Id:         taskID,
+				Task:       &todov1.Task{Text: tt.text},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths},
+			}))
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("UpdateTask() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateTask() error = %v", err)
+			}
+
+			got := resp.Msg.Task
+			if want := strings.TrimSpace(tt.text); got.Text != want {
+				t.Errorf("UpdateTask() text = %q, want %q", got.Text, want)
+			}
+			if got.Id != original.Id || got.CreatedAt != original.CreatedAt {
+				t.Errorf("UpdateTask() changed identity: got (%s, %d), want (%s, %d)", got.Id, got.CreatedAt, original.Id, original.CreatedAt)
+			}
+
+			getResp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{}))
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+			if len(getResp.Msg.Tasks) != 1 || getResp.Msg.Tasks[0].Text != got.Text {
+				t.Errorf("GetTasks() after update = %v, want one task with text %q", getResp.Msg.Tasks, got.Text)
+			}
+		})
+	}
+}
+
+func TestCompleteAndReopenTask(t *testing.T) {
+	forEachStore(t, testCompleteAndReopenTask)
+}
+
+func testCompleteAndReopenTask(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	addResp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Write report"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	id := addResp.Msg.Task.Id
+	if addResp.Msg.Task.Completed {
+		t.Fatal("AddTask() returned a completed task")
+	}
+
+	completeResp, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: id}))
+	if err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	done := completeResp.Msg.Task
+	if !done.Completed || done.CompletedAt == 0 {
+		t.Fatalf("CompleteTask() = %v, want completed with CompletedAt set", done)
+	}
+
+	again, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: id}))
+	if err != nil {
+		t.Fatalf("CompleteTask() second call error = %v", err)
+	}
+	if again.Msg.Task.CompletedAt != done.CompletedAt {
+		t.Errorf("CompleteTask() second call CompletedAt = %d, want unchanged %d", again.Msg.Task.CompletedAt, done.CompletedAt)
+	}
+
+	reopenResp, err := server.ReopenTask(ctx, connect.NewRequest(&todov1.ReopenTaskRequest{Id: id}))
+	if err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if reopenResp.Msg.Task.Completed || reopenResp.Msg.Task.CompletedAt != 0 {
+		t.Errorf("ReopenTask() = %v, want open with CompletedAt cleared", reopenResp.Msg.Task)
+	}
+
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: "nonexistent"})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("CompleteTask() unknown ID error = %v, want %v", err, connect.CodeNotFound)
+	}
+	if _, err := server.ReopenTask(ctx, connect.NewRequest(&todov1.ReopenTaskRequest{Id: " "})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("ReopenTask() blank ID error = %v, want %v", err, connect.CodeInvalidArgument)
+	}
+}
+
+func TestTaskVersion(t *testing.T) {
+	forEachStore(t, testTaskVersion)
+}
+
+func testTaskVersion(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	task := mustAddTask(t, server, "Write report", "")
+	if task.Version != 1 {
+		t.Fatalf("AddTask() version = %d, want 1", task.Version)
+	}
+	complete := func(version int64) (*todov1.Task, error) {
+		resp, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id, ExpectedVersion: version}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Task, nil
+	}
+	done, err := complete(1)
+	if err != nil || done.Version != 2 {
+		t.Fatalf("CompleteTask() = %v, %v; want version 2", done, err)
+	}
+	// Completing it again changes nothing, so the version stays.
+	if again, err := complete(0); err != nil || again.Version != 2 {
+		t.Errorf("CompleteTask() again = %v, %v; want version 2", again, err)
+	}
+	if _, err := complete(1); connect.CodeOf(err) != connect.CodeAborted {
+		t.Errorf("CompleteTask() at a stale version error = %v, want code %v", err, connect.CodeAborted)
+	}
+
+	// Undoing gives the task a new version rather than an old one back.
+	mustUndo(t, ctx, server)
+	if got := tasksByID(t, server)[task.Id]; got.Completed || got.Version != 3 {
+		t.Errorf("task after undoing CompleteTask = %v, want it open at version 3", got)
+	}
+
+	_, err = server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: task.Id, ExpectedVersion: 2}))
+	if connect.CodeOf(err) != connect.CodeAborted {
+		t.Fatalf("DeleteTask() at a stale version error = %v, want code %v", err, connect.CodeAborted)
+	}
+	if _, ok := tasksByID(t, server)[task.Id]; !ok {
+		t.Fatal("DeleteTask() at a stale version deleted the task")
+	}
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: task.Id, ExpectedVersion: 3})); err != nil {
+		t.Fatalf("DeleteTask() at the current version error = %v", err)
+	}
+	if restored, err := restoreTask(server, task.Id); err != nil || restored.Version != 4 {
+		t.Errorf("RestoreTask() = %v, %v; want version 4", restored, err)
+	}
+}
+
+func TestGetTasksStatusFilter(t *testing.T) {
+	forEachStore(t, testGetTasksStatusFilter)
+}
+
+func testGetTasksStatusFilter(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	var ids []string
+	for _, text := range []string{"Open task", "Done task"} {
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: text}))
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+		ids = append(ids, resp.Msg.Task.Id)
+	}
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: ids[1]})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		status  todov1.TaskStatus
+		wantIDs []string
+	}{
+		{name: "unspecified", status: todov1.TaskStatus_TASK_STATUS_UNSPECIFIED, wantIDs: ids},
+		{name: "open", status: todov1.TaskStatus_TASK_STATUS_OPEN, wantIDs: ids[:1]},
+		{name: "completed", status: todov1.TaskStatus_TASK_STATUS_COMPLETED, wantIDs: ids[1:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{Status: tt.status}))
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+			got := map[string]bool{}
+			for _, task := range resp.Msg.Tasks {
+				got[task.Id] = true
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("GetTasks() returned %d tasks, want %d", len(got), len(tt.wantIDs))
+			}
+			for _, id := range tt.wantIDs {
+				if !got[id] {
+					t.Errorf("GetTasks() missing task %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTasksPagination(t *testing.T) {
+	forEachStore(t, testGetTasksPagination)
+}
+
+func testGetTasksPagination(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		if _, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: fmt.Sprintf("Task %d", i)})); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	all, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{}))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if all.Msg.NextPageToken != "" {
+		t.Errorf("GetTasks() without page size returned token %q", all.Msg.NextPageToken)
+	}
+
+	// Walk the pages, inserting a task after the first one. Whether or not the
+	// new task lands behind the cursor, the remaining pages must neither
+	// repeat nor skip the tasks that already existed.
+	var got []string
+	late := ""
+	token := ""
+	for page := 0; ; page++ {
+		resp, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{PageSize: 3, PageToken: token}))
+		if err != nil {
+			t.Fatalf("GetTasks() page %d error = %v", page, err)
+		}
+		if len(resp.Msg.Tasks) > 3 {
+			t.Fatalf("GetTasks() page %d returned %d tasks, want at most 3", page, len(resp.Msg.Tasks))
+		}
+		for _, task := range resp.Msg.Tasks {
+			if task.Id != late {
+				got = append(got, task.Id)
+			}
+		}
+		if page == 0 {
+			resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Late arrival"}))
+			if err != nil {
+				t.Fatalf("AddTask() error = %v", err)
+			}
+			late = resp.Msg.Task.Id
+		}
+		token = resp.Msg.NextPageToken
+		if token == "" {
+			break
+		}
+	}
+
+	if len(got) != len(all.Msg.Tasks) {
+		t.Fatalf("paged through %d tasks, want %d", len(got), len(all.Msg.Tasks))
+	}
+	for i, task := range all.Msg.Tasks {
+		if got[i] != task.Id {
+			t.Errorf("task %d = %s, want %s", i, got[i], task.Id)
+		}
+	}
+}
+
+func TestGetTasksInvalidPaging(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  *todov1.GetTasksRequest
+	}{
+		{name: "negative page size", req: &todov1.GetTasksRequest{PageSize: -1}},
+		{name: "malformed token", req: &todov1.GetTasksRequest{PageSize: 10, PageToken: "not a token"}},
+		{name: "token without ID", req: &todov1.GetTasksRequest{PageSize: 10, PageToken: encodePageToken(pageCursor{CreatedAt: 1})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.GetTasks(ctx, connect.NewRequest(tt.req))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("GetTasks() error = %v, want code %v", err, connect.CodeInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		size int32
+		want int
+	}{
+		{size: 0, want: -1},
+		{size: 1, want: 1},
+		{size: MaxPageSize, want: MaxPageSize},
+		{size: MaxPageSize + 1, want: MaxPageSize},
+	}
+	for _, tt := range tests {
+		if got := pageLimit(tt.size); got != tt.want {
+			t.Errorf("pageLimit(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
 func TestValidateTaskText(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -308,7 +752,7 @@ func TestValidateTaskText(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateTaskText(tt.text)
+			err := validateTaskText(tt.text, MaxTaskTextLength)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validateTaskText() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -327,7 +771,7 @@ func BenchmarkAddTask(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// Create a new server for each iteration to avoid state carry-over
 		b.StopTimer()
-		server := NewTodoServer()
+		server := mustNewServer(b, NewMemoryStore())
 		b.StartTimer()
 		_, err := server.AddTask(ctx, req)
 		if err != nil {
@@ -348,7 +792,7 @@ func BenchmarkGetTasks(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// Create a new server and add tasks for each benchmark iteration
 		b.StopTimer()
-		server := NewTodoServer()
+		server := mustNewServer(b, NewMemoryStore())
 		// Add some tasks using the public API for realistic benchmarking
 		for j := 0; j < taskCount; j++ {
 			taskReq := connect.NewRequest(&todov1.AddTaskRequest{
@@ -360,10 +804,10 @@ func BenchmarkGetTasks(b *testing.B) {
 			}
 		}
 		b.StartTimer()
-		
+
 		_, err := server.GetTasks(ctx, req)
 		if err != nil {
 			b.Fatalf("GetTasks() error = %v", err)
 		}
 	}
-}
\ No newline at end of file
+}
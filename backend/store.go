@@ -0,0 +1,880 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrTaskExists = errors.New("task already exists")
+	ErrListExists = errors.New("list already exists")
+)
+
+// TaskStore persists the tasks served by a TodoServer, the lists that group
+// them, the trash of deleted tasks and the history of changes to tasks.
+// Implementations must be safe for concurrent use.
+//
+// Trashed tasks are kept apart from live ones: the task methods do not see
+// them, but their IDs stay taken until they are purged.
+//
+// Tasks and lists handed to a store are copied, and those returned from it
+// are shared snapshots that callers must treat as read-only.
+//
+// The methods that change tasks take the history event recording the change,
+// if any, and store both in the same write, so that neither is kept without
+// the other. Events are appended with AppendEvent instead when they are
+// recorded after the change, as for a batch.
+type TaskStore interface {
+	// CreateTask stores a new task, returning ErrTaskExists if its ID is taken
+	// by a live or trashed task.
+	CreateTask(task *todov1.Task, ev *todov1.HistoryEvent) error
+	// GetTask returns the task with the given ID or ErrTaskNotFound.
+	GetTask(id string) (*todov1.Task, error)
+	// UpdateTask replaces the stored task that has the same ID, returning
+	// ErrTaskNotFound if there is none.
+	UpdateTask(task *todov1.Task, ev *todov1.HistoryEvent) error
+	// ListTasks returns every stored task in no particular order.
+	ListTasks() ([]*todov1.Task, error)
+	// DeleteTask removes the task with the given ID or returns ErrTaskNotFound.
+	DeleteTask(id string, ev *todov1.HistoryEvent) error
+
+	// TrashTask moves the task with the given ID to the trash, setting its
+	// DeletedAt to deletedAt, or returns ErrTaskNotFound.
+	TrashTask(id string, deletedAt int64, ev *todov1.HistoryEvent) error
+	// GetTrashedTask returns the trashed task with the given ID or
+	// ErrTaskNotFound.
+	GetTrashedTask(id string) (*todov1.Task, error)
+	// ListTrash returns every trashed task in no particular order.
+	ListTrash() ([]*todov1.Task, error)
+	// RestoreTask replaces the trashed task that has the same ID with task,
+	// which becomes live again, returning ErrTaskNotFound if there is none.
+	RestoreTask(task *todov1.Task, ev *todov1.HistoryEvent) error
+	// PurgeTask permanently removes the trashed task with the given ID or
+	// returns ErrTaskNotFound.
+	PurgeTask(id string, ev *todov1.HistoryEvent) error
+
+	// CreateList stores a new list, returning ErrListExists if its ID is taken.
+	CreateList(list *todov1.List) error
+	// GetList returns the list with the given ID or ErrListNotFound.
+	GetList(id string) (*todov1.List, error)
+	// UpdateList replaces the stored list that has the same ID, returning
+	// ErrListNotFound if there is none.
+	UpdateList(list *todov1.List) error
+	// ListLists returns every stored list in no particular order.
+	ListLists() ([]*todov1.List, error)
+	// DeleteList removes the list with the given ID or returns
+	// ErrListNotFound. It does not touch the tasks in the list.
+	DeleteList(id string) error
+
+	// AppendEvent adds ev to the end of the task history.
+	AppendEvent(ev *todov1.HistoryEvent) error
+	// ListEvents returns the task history in the order it was appended.
+	ListEvents() ([]*todov1.HistoryEvent, error)
+	// CompactHistory drops the events that occurred before the Unix time
+	// before, except the newest event and the last event of each task that
+	// is still stored, so that replaying the history still gives the stored
+	// tasks.
+	CompactHistory(before int64) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// memoryStore is a TaskStore that keeps tasks and lists in maps. Its contents
+// are lost when the process exits.
+type memoryStore struct {
+	mu     sync.RWMutex
+	tasks  map[string]*todov1.Task
+	lists  map[string]*todov1.List
+	trash  map[string]*todov1.Task
+	events []*todov1.HistoryEvent
+	// dropped counts the times events were taken out of the history rather
+	// than appended to it, which a historyLog must then rewrite.
+	dropped int
+}
+
+// NewMemoryStore returns an empty in-memory TaskStore.
+func NewMemoryStore() TaskStore {
+	return newMemoryStore()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		tasks: make(map[string]*todov1.Task),
+		lists: make(map[string]*todov1.List),
+		trash: make(map[string]*todov1.Task),
+	}
+}
+
+func (m *memoryStore) CreateTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tasks[task.Id]; exists {
+		return ErrTaskExists
+	}
+	if _, exists := m.trash[task.Id]; exists {
+		return ErrTaskExists
+	}
+	m.tasks[task.Id] = proto.Clone(task).(*todov1.Task)
+	m.appendEvent(ev)
+	return nil
+}
+
+func (m *memoryStore) GetTask(id string) (*todov1.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (m *memoryStore) UpdateTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tasks[task.Id]; !exists {
+		return ErrTaskNotFound
+	}
+	m.tasks[task.Id] = proto.Clone(task).(*todov1.Task)
+	m.appendEvent(ev)
+	return nil
+}
+
+func (m *memoryStore) ListTasks() ([]*todov1.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tasks := make([]*todov1.Task, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (m *memoryStore) DeleteTask(id string, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tasks[id]; !exists {
+		return ErrTaskNotFound
+	}
+	delete(m.tasks, id)
+	m.appendEvent(ev)
+	return nil
+}
+
+func (m *memoryStore) TrashTask(id string, deletedAt int64, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, exists := m.tasks[id]
+	if !exists {
+		return ErrTaskNotFound
+	}
+	trashed := proto.Clone(task).(*todov1.Task)
+	trashed.DeletedAt = deletedAt
+	delete(m.tasks, id)
+	m.trash[id] = trashed
+	m.appendEvent(ev)
+	return nil
+}
+
+func (m *memoryStore) GetTrashedTask(id string) (*todov1.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, exists := m.trash[id]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (m *memoryStore) ListTrash() ([]*todov1.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tasks := make([]*todov1.Task, 0, len(m.trash))
+	for _, task := range m.trash {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (m *memoryStore) RestoreTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.trash[task.Id]; !exists {
+		return ErrTaskNotFound
+	}
+	delete(m.trash, task.Id)
+	m.tasks[task.Id] = proto.Clone(task).(*todov1.Task)
+	m.appendEvent(ev)
+	return nil
+}
+
+func (m *memoryStore) PurgeTask(id string, ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.trash[id]; !exists {
+		return ErrTaskNotFound
+	}
+	delete(m.trash, id)
+	m.appendEvent(ev)
+	return nil
+}
+
+// putTrash puts task into the trash as it is, taking it out of the live
+// tasks. The file store uses it to roll back failed writes.
+func (m *memoryStore) putTrash(task *todov1.Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tasks, task.Id)
+	m.trash[task.Id] = task
+}
+
+func (m *memoryStore) CreateList(list *todov1.List) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.lists[list.Id]; exists {
+		return ErrListExists
+	}
+	m.lists[list.Id] = proto.Clone(list).(*todov1.List)
+	return nil
+}
+
+func (m *memoryStore) GetList(id string) (*todov1.List, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list, exists := m.lists[id]
+	if !exists {
+		return nil, ErrListNotFound
+	}
+	return list, nil
+}
+
+func (m *memoryStore) UpdateList(list *todov1.List) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.lists[list.Id]; !exists {
+		return ErrListNotFound
+	}
+	m.lists[list.Id] = proto.Clone(list).(*todov1.List)
+	return nil
+}
+
+func (m *memoryStore) ListLists() ([]*todov1.List, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lists := make([]*todov1.List, 0, len(m.lists))
+	for _, list := range m.lists {
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+func (m *memoryStore) DeleteList(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.lists[id]; !exists {
+		return ErrListNotFound
+	}
+	delete(m.lists, id)
+	return nil
+}
+
+func (m *memoryStore) AppendEvent(ev *todov1.HistoryEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.appendEvent(ev)
+	return nil
+}
+
+// appendEvent adds a copy of ev, if not nil, to the history. A PURGED event
+// replaces the earlier events of its task, so that none of the task's
+// contents outlive it. Callers must hold m.mu.
+func (m *memoryStore) appendEvent(ev *todov1.HistoryEvent) {
+	if ev == nil {
+		return
+	}
+	if ev.Type == todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED {
+		id := ev.Task.GetId()
+		kept := make([]*todov1.HistoryEvent, 0, len(m.events))
+		for _, old := range m.events {
+			if old.Task.GetId() != id {
+				kept = append(kept, old)
+			}
+		}
+		if len(kept) < len(m.events) {
+			m.events = kept
+			m.dropped++
+		}
+	}
+	m.events = append(m.events, proto.Clone(ev).(*todov1.HistoryEvent))
+}
+
+func (m *memoryStore) ListEvents() ([]*todov1.HistoryEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.events), nil
+}
+
+func (m *memoryStore) CompactHistory(before int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	last := make(map[string]int) // task ID -> index of its last event
+	for i, ev := range m.events {
+		last[ev.Task.GetId()] = i
+	}
+	kept := make([]*todov1.HistoryEvent, 0, len(m.events))
+	for i, ev := range m.events {
+		id := ev.Task.GetId()
+		_, live := m.tasks[id]
+		_, trashed := m.trash[id]
+		// The newest event stays too, so that Seq keeps counting up from it
+		// after a restart.
+		if ev.OccurredAt >= before || i == len(m.events)-1 || (last[id] == i && (live || trashed)) {
+			kept = append(kept, ev)
+		}
+	}
+	if len(kept) < len(m.events) {
+		m.events = kept
+		m.dropped++
+	}
+	return nil
+}
+
+// lastEventSeq returns the Seq of the last event in the history, or zero if
+// it is empty.
+func (m *memoryStore) lastEventSeq() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.events) == 0 {
+		return 0
+	}
+	return m.events[len(m.events)-1].Seq
+}
+
+// historyMark is the state of a memoryStore's history at some point, which
+// resetHistory goes back to.
+type historyMark struct {
+	events  []*todov1.HistoryEvent
+	dropped int
+}
+
+// markHistory returns the current state of the history. The file store uses
+// it to roll back failed writes.
+func (m *memoryStore) markHistory() historyMark {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return historyMark{events: m.events, dropped: m.dropped}
+}
+
+// resetHistory puts the history back in the state of mark. Events are only
+// ever appended to the slice or dropped into a new one, so mark.events still
+// holds the events of that state.
+func (m *memoryStore) resetHistory(mark historyMark) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dropped == mark.dropped && len(m.events) == len(mark.events) {
+		return
+	}
+	m.events = mark.events
+	// Whatever a historyLog appended since is no longer wanted.
+	m.dropped++
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
+
+// fileStore is a TaskStore that keeps its working set in memory and hands
+// every mutation to a storeWriter, which makes it durable, so tasks and lists
+// survive a restart. A mutation that cannot be written is undone in memory.
+type fileStore struct {
+	mu  sync.Mutex // serializes mutations and the writes that follow them
+	mem *memoryStore
+	w   storeWriter
+}
+
+// storeWriter makes the mutations of a fileStore durable. Its methods are
+// called with the store's mu held.
+type storeWriter interface {
+	// write persists m, which has just been applied to the store's memory.
+	write(m *mutation) error
+	// close flushes anything still buffered and releases the writer.
+	close() error
+}
+
+// OpenFileStore opens the file-backed TaskStore at path, loading any tasks
+// and lists saved by a previous run. A missing file is treated as an empty
+// store. The whole file is rewritten after every change to tasks or lists,
+// while the history is appended to the log at path+".history".
+//
+// Each write goes to a temporary file that is synced and then renamed over
+// the previous one, so a crash leaves either the old or the new contents.
+func OpenFileStore(path string) (TaskStore, error) {
+	mem := newMemoryStore()
+	if _, err := readStoreFile(path, mem); err != nil {
+		return nil, err
+	}
+	legacy := len(mem.events) > 0
+	history, err := openHistoryLog(path+".history", mem)
+	if err != nil {
+		return nil, err
+	}
+	if legacy {
+		// Move the history out of the store file, where files written before
+		// the history log kept it.
+		if err := history.sync(mem); err != nil {
+			return nil, err
+		}
+		if err := writeStoreFile(path, mem, 0); err != nil {
+			return nil, err
+		}
+	}
+	return &fileStore{mem: mem, w: &jsonWriter{path: path, mem: mem, history: history}}, nil
+}
+
+// jsonWriter is the storeWriter of OpenFileStore, which rewrites the store
+// file with the tasks and lists of mem and brings the history log up to date.
+type jsonWriter struct {
+	path    string
+	mem     *memoryStore
+	history *historyLog
+}
+
+func (j *jsonWriter) write(m *mutation) error {
+	if m.historyOnly() {
+		return j.history.sync(j.mem)
+	}
+	if err := writeStoreFile(j.path, j.mem, 0); err != nil {
+		return err
+	}
+	if err := j.history.sync(j.mem); err != nil {
+		// The change itself is stored. Its event is written with the next
+		// one, or recorded again on restart should the server stop first.
+		log.Printf("Failed to write history to %s: %v", j.history.path, err)
+	}
+	return nil
+}
+
+func (j *jsonWriter) close() error {
+	return nil
+}
+
+// storeFile is the layout of the file written by fileStore. Files written
+// before lists existed hold only the bare array of tasks.
+type storeFile struct {
+	Tasks []json.RawMessage `json:"tasks"`
+	Lists []json.RawMessage `json:"lists"`
+	Trash []json.RawMessage `json:"trash"`
+	// Events is the task history, oldest first, in files written before it
+	// moved to a historyLog of its own. It is only read.
+	Events []json.RawMessage `json:"events,omitempty"`
+	// Seq is the sequence number of the last write-ahead log record the
+	// file includes, if it is a snapshot of the WAL store.
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// readStoreFile loads the store file at path into mem and returns its Seq.
+// A missing file leaves mem empty.
+func readStoreFile(path string, mem *memoryStore) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read task file: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file.Tasks); err != nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return 0, fmt.Errorf("failed to parse task file %s: %w", path, err)
+		}
+	}
+	for i, r := range file.Tasks {
+		task := &todov1.Task{}
+		if err := protojson.Unmarshal(r, task); err != nil {
+			return 0, fmt.Errorf("failed to parse task %d in %s: %w", i, path, err)
+		}
+		mem.tasks[task.Id] = task
+	}
+	for i, r := range file.Lists {
+		list := &todov1.List{}
+		if err := protojson.Unmarshal(r, list); err != nil {
+			return 0, fmt.Errorf("failed to parse list %d in %s: %w", i, path, err)
+		}
+		mem.lists[list.Id] = list
+	}
+	for i, r := range file.Trash {
+		task := &todov1.Task{}
+		if err := protojson.Unmarshal(r, task); err != nil {
+			return 0, fmt.Errorf("failed to parse trashed task %d in %s: %w", i, path, err)
+		}
+		mem.trash[task.Id] = task
+	}
+	for i, r := range file.Events {
+		ev := &todov1.HistoryEvent{}
+		if err := protojson.Unmarshal(r, ev); err != nil {
+			return 0, fmt.Errorf("failed to parse event %d in %s: %w", i, path, err)
+		}
+		// Older versions kept the history of purged tasks.
+		if ev.Type == todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED {
+			ev.Task = purgedTask(ev.Task)
+		}
+		mem.appendEvent(ev)
+	}
+	return file.Seq, nil
+}
+
+// writeStoreFile atomically replaces the store file at path with the tasks
+// and lists of mem, recording seq as its Seq. The history is left to a
+// historyLog.
+func writeStoreFile(path string, mem *memoryStore, seq uint64) error {
+	tasks, _ := mem.ListTasks()
+	lists, _ := mem.ListLists()
+	trash, _ := mem.ListTrash()
+	file := storeFile{
+		Tasks: make([]json.RawMessage, 0, len(tasks)),
+		Lists: make([]json.RawMessage, 0, len(lists)),
+		Trash: make([]json.RawMessage, 0, len(trash)),
+		Seq:   seq,
+	}
+	for _, task := range tasks {
+		b, err := protojson.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("failed to encode task %s: %w", task.Id, err)
+		}
+		file.Tasks = append(file.Tasks, b)
+	}
+	for _, list := range lists {
+		b, err := protojson.Marshal(list)
+		if err != nil {
+			return fmt.Errorf("failed to encode list %s: %w", list.Id, err)
+		}
+		file.Lists = append(file.Lists, b)
+	}
+	for _, task := range trash {
+		b, err := protojson.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("failed to encode trashed task %s: %w", task.Id, err)
+		}
+		file.Trash = append(file.Trash, b)
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode tasks: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+func (s *fileStore) CreateTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.mem.markHistory()
+	if err := s.mem.CreateTask(task, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opCreateTask, task: task, event: ev}); err != nil {
+		s.mem.DeleteTask(task.Id, nil)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) GetTask(id string) (*todov1.Task, error) {
+	return s.mem.GetTask(id)
+}
+
+func (s *fileStore) UpdateTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetTask(task.Id)
+	if err != nil {
+		return err
+	}
+	history := s.mem.markHistory()
+	if err := s.mem.UpdateTask(task, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opUpdateTask, task: task, event: ev}); err != nil {
+		s.mem.UpdateTask(old, nil)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) ListTasks() ([]*todov1.Task, error) {
+	return s.mem.ListTasks()
+}
+
+func (s *fileStore) DeleteTask(id string, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetTask(id)
+	if err != nil {
+		return err
+	}
+	history := s.mem.markHistory()
+	if err := s.mem.DeleteTask(id, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opDeleteTask, id: id, event: ev}); err != nil {
+		s.mem.CreateTask(old, nil)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) TrashTask(id string, deletedAt int64, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetTask(id)
+	if err != nil {
+		return err
+	}
+	history := s.mem.markHistory()
+	if err := s.mem.TrashTask(id, deletedAt, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opTrashTask, id: id, deletedAt: deletedAt, event: ev}); err != nil {
+		s.mem.RestoreTask(old, nil)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) GetTrashedTask(id string) (*todov1.Task, error) {
+	return s.mem.GetTrashedTask(id)
+}
+
+func (s *fileStore) ListTrash() ([]*todov1.Task, error) {
+	return s.mem.ListTrash()
+}
+
+func (s *fileStore) RestoreTask(task *todov1.Task, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetTrashedTask(task.Id)
+	if err != nil {
+		return err
+	}
+	history := s.mem.markHistory()
+	if err := s.mem.RestoreTask(task, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opRestoreTask, task: task, event: ev}); err != nil {
+		s.mem.putTrash(old)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) PurgeTask(id string, ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetTrashedTask(id)
+	if err != nil {
+		return err
+	}
+	history := s.mem.markHistory()
+	if err := s.mem.PurgeTask(id, ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opPurgeTask, id: id, event: ev}); err != nil {
+		s.mem.putTrash(old)
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) CreateList(list *todov1.List) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.mem.CreateList(list); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opCreateList, list: list}); err != nil {
+		s.mem.DeleteList(list.Id)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) GetList(id string) (*todov1.List, error) {
+	return s.mem.GetList(id)
+}
+
+func (s *fileStore) UpdateList(list *todov1.List) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetList(list.Id)
+	if err != nil {
+		return err
+	}
+	if err := s.mem.UpdateList(list); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opUpdateList, list: list}); err != nil {
+		s.mem.UpdateList(old)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) ListLists() ([]*todov1.List, error) {
+	return s.mem.ListLists()
+}
+
+func (s *fileStore) DeleteList(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.mem.GetList(id)
+	if err != nil {
+		return err
+	}
+	if err := s.mem.DeleteList(id); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opDeleteList, id: id}); err != nil {
+		s.mem.CreateList(old)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) AppendEvent(ev *todov1.HistoryEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.mem.markHistory()
+	if err := s.mem.AppendEvent(ev); err != nil {
+		return err
+	}
+	if err := s.w.write(&mutation{op: opAppendEvent, event: ev}); err != nil {
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) ListEvents() ([]*todov1.HistoryEvent, error) {
+	return s.mem.ListEvents()
+}
+
+func (s *fileStore) CompactHistory(before int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.mem.markHistory()
+	if err := s.mem.CompactHistory(before); err != nil {
+		return err
+	}
+	if s.mem.markHistory().dropped == history.dropped {
+		// Nothing was old enough to drop.
+		return nil
+	}
+	if err := s.w.write(&mutation{op: opCompactHistory, before: before}); err != nil {
+		s.mem.resetHistory(history)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.close()
+}
+
+// writeFileAtomic replaces the file at path with data by writing to a
+// temporary file in the same directory, syncing it and renaming it into place.
+// The directory is synced too, so that the rename survives a power loss before
+// anything that relies on it, such as emptying the WAL store's log.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to sync %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to close %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir makes the entries of directory dir, such as a file renamed into it,
+// durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return fmt.Errorf("failed to sync %s: %w", dir, err)
+	}
+	return d.Close()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+func TestFileStorePersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	ctx := context.Background()
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	server := mustNewServer(t, store)
+
+	keep, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Keep me"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	drop, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Drop me"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: drop.Msg.Task.Id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after restart error = %v", err)
+	}
+	defer reopened.Close()
+
+	tasks, err := reopened.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("ListTasks() returned %d tasks after restart, want 1", len(tasks))
+	}
+	got := tasks[0]
+	want := keep.Msg.Task
+	if got.Id != want.Id || got.Text != want.Text || got.CreatedAt != want.CreatedAt {
+		t.Errorf("ListTasks() after restart = %v, want %v", got, want)
+	}
+
+	trash, err := reopened.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	if len(trash) != 1 || trash[0].Id != drop.Msg.Task.Id {
+		t.Errorf("ListTrash() after restart = %v, want the deleted task", trash)
+	}
+}
+
+func TestFileStoreAppendsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	fillStore(t, store)
+	before, err := os.ReadFile(path + ".history")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustAddTask(t, mustNewServer(t, store), "One more", "")
+	after, err := os.ReadFile(path + ".history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(after, before) || bytes.Count(after[len(before):], []byte("\n")) != 1 {
+		t.Errorf("history file after AddTask = %q, want %q followed by one event", after, before)
+	}
+	if data, err := os.ReadFile(path); err != nil || bytes.Contains(data, []byte(`"events"`)) {
+		t.Errorf("task file = %s, %v; want it without the history", data, err)
+	}
+
+	history, err := store.ListEvents()
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	store.Close()
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after restart error = %v", err)
+	}
+	defer reopened.Close()
+	checkHistory(t, reopened, history)
+}
+
+func TestFileStorePurgeDropsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	defer store.Close()
+	server := mustNewServer(t, store)
+	ctx := context.Background()
+
+	secret := mustAddTask(t, server, "Secret plans", "")
+	mustDeleteTask(t, server, secret.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: secret.Id})); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	data, err := os.ReadFile(path + ".history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("Secret plans")) || !bytes.Contains(data, []byte(secret.Id)) {
+		t.Errorf("history file after purge = %s, want the purge recorded without the task's text", data)
+	}
+}
+
+func TestOpenStoreMovesLegacyHistory(t *testing.T) {
+	const legacy = `{
+		"tasks": [{"id": "a", "text": "Kept", "createdAt": "1", "position": "a0", "version": "1"}],
+		"lists": [],
+		"trash": [],
+		"events": [{"seq": "1", "type": "HISTORY_EVENT_TYPE_CREATED", "task": {"id": "a", "text": "Kept", "createdAt": "1", "position": "a0", "version": "1"}}]
+	}`
+	for name, open := range map[string]func(path string) (TaskStore, error){
+		"file": OpenFileStore,
+		"wal": func(path string) (TaskStore, error) {
+			return OpenWALStore(path, WALOptions{Sync: SyncAlways, SnapshotEvery: 1000})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tasks.json")
+			if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			// Opening twice reads the moved history back without repeating it.
+			for range 2 {
+				store, err := open(path)
+				if err != nil {
+					t.Fatalf("open error = %v", err)
+				}
+				events, err := store.ListEvents()
+				if err != nil {
+					t.Fatalf("ListEvents() error = %v", err)
+				}
+				if len(events) != 1 || events[0].Seq != 1 || events[0].Task.GetId() != "a" {
+					t.Errorf("ListEvents() = %v, want the event of the old file", events)
+				}
+				store.Close()
+			}
+			if data, err := os.ReadFile(path); err != nil || bytes.Contains(data, []byte(`"events"`)) {
+				t.Errorf("task file = %s, %v; want it without the history", data, err)
+			}
+		})
+	}
+}
+
+func TestOpenFileStoreRejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenFileStore(path); err == nil {
+		t.Error("OpenFileStore() error = nil, want error for corrupt file")
+	}
+}
+
+func TestMemoryStoreCreateTaskDuplicateID(t *testing.T) {
+	store := NewMemoryStore()
+	task := &todov1.Task{Id: "abc", Text: "First", CreatedAt: 1}
+
+	if err := store.CreateTask(task, nil); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	if err := store.CreateTask(task, nil); err != ErrTaskExists {
+		t.Errorf("CreateTask() duplicate error = %v, want %v", err, ErrTaskExists)
+	}
+}
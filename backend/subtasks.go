@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+var (
+	ErrParentNotFound       = errors.New("parent task not found")
+	ErrParentCycle          = errors.New("a task cannot be nested under itself or one of its subtasks")
+	ErrSubtaskList          = errors.New("a subtask must be in its parent's list")
+	ErrHasSubtasks          = errors.New("task still has subtasks; choose whether to delete or promote them")
+	ErrInvalidSubtaskPolicy = errors.New("unknown subtask delete policy")
+	ErrInvalidView          = errors.New("unknown task view")
+)
+
+// setParent checks that task can be nested under the task named by its
+// ParentId and moves it into the parent's list. listGiven reports whether the
+// caller chose task.ListId explicitly, in which case it must already be the
+// parent's list. Top-level tasks are left alone. Callers must hold s.mu.
+//
+// It returns ErrParentNotFound if the parent does not exist, ErrParentCycle if
+// it is task itself or one of its subtasks and ErrSubtaskList if the lists
+// differ. Whether the caller may nest tasks under the parent is checked by
+// checkPlacement.
+func (s *TodoServer) setParent(task *todov1.Task, listGiven bool) error {
+	if task.ParentId == "" {
+		return nil
+	}
+	parent, err := s.store.GetTask(task.ParentId)
+	if errors.Is(err, ErrTaskNotFound) {
+		return ErrParentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	for ancestor := parent; ; {
+		if ancestor.Id == task.Id {
+			return ErrParentCycle
+		}
+		if ancestor.ParentId == "" {
+			break
+		}
+		if ancestor, err = s.store.GetTask(ancestor.ParentId); err != nil {
+			return err
+		}
+	}
+	if listGiven && task.ListId != parent.ListId {
+		return ErrSubtaskList
+	}
+	task.ListId = parent.ListId
+	return nil
+}
+
+// parentError converts an error from setParent into a connect error.
+func parentError(err error) error {
+	switch {
+	case errors.Is(err, ErrParentNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, ErrParentCycle), errors.Is(err, ErrSubtaskList):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load parent task: %w", err))
+	}
+}
+
+// subtasksByParent groups tasks by the ID of their parent. Top-level tasks
+// are left out.
+func subtasksByParent(tasks []*todov1.Task) map[string][]*todov1.Task {
+	children := make(map[string][]*todov1.Task)
+	for _, task := range tasks {
+		if task.ParentId != "" {
+			children[task.ParentId] = append(children[task.ParentId], task)
+		}
+	}
+	return children
+}
+
+// descendants returns every subtask of the task with the given ID, however
+// deeply nested, each one listed after its own subtasks so that deleting
+// them in order never leaves a subtask without its parent. Callers must hold
+// s.mu.
+func (s *TodoServer) descendants(id string) ([]*todov1.Task, error) {
+	tasks, err := s.store.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	children := subtasksByParent(tasks)
+	var out []*todov1.Task
+	var walk func(id string)
+	walk = func(id string) {
+		for _, child := range children[id] {
+			walk(child.Id)
+			out = append(out, child)
+		}
+	}
+	walk(id)
+	return out, nil
+}
+
+// moveSubtree puts subtasks, the descendants of task, into task's list, so
+// that subtasks follow their parent when it changes lists. Callers must hold
+// s.mu.
+func (s *TodoServer) moveSubtree(task *todov1.Task, subtasks []*todov1.Task) error {
+	for _, current := range subtasks {
+		if current.ListId == task.ListId {
+			continue
+		}
+		moved := proto.Clone(current).(*todov1.Task)
+		moved.ListId = task.ListId
+		if err := s.replaceTask(current, moved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSubtasks deals with the subtasks of task, which is about to be
+// deleted, as policy asks. Subtasks are handled one at a time, deepest
+// first, so if the store fails part way every remaining subtask still has
+// its parent and the call can be retried. Callers must hold s.mu.
+func (s *TodoServer) deleteSubtasks(task *todov1.Task, policy todov1.SubtaskDeletePolicy) error {
+	subtasks, err := s.descendants(task.Id)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list subtasks: %w", err))
+	}
+	if len(subtasks) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE:
+		for _, subtask := range subtasks {
+			if err := s.deleteTask(subtask); err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete subtask: %w", err))
+			}
+		}
+	case todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_PROMOTE:
+		for _, current := range subtasks {
+			if current.ParentId != task.Id {
+				continue
+			}
+			promoted := proto.Clone(current).(*todov1.Task)
+			promoted.ParentId = task.ParentId
+			if err := s.replaceTask(current, promoted); err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to promote subtask: %w", err))
+			}
+		}
+	default:
+		return connect.NewError(connect.CodeFailedPrecondition, ErrHasSubtasks)
+	}
+	return nil
+}
+
+// queryTree returns the tasks selected by q nested under their parents,
+// paginated by top-level node, and the token for the page after it. Callers
+// must hold s.mu.
+func (s *TodoServer) queryTree(q *taskQuery) ([]*todov1.TaskNode, string, error) {
+	all := *q
+	all.after, all.limit = nil, -1
+	tasks, _, err := s.queryTasks(&all)
+	if err != nil {
+		return nil, "", err
+	}
+	q.sort(tasks)
+	nodes := make(map[string]*todov1.TaskNode, len(tasks))
+	for _, task := range tasks {
+		nodes[task.Id] = &todov1.TaskNode{Task: task}
+	}
+	// Subtasks whose parent was filtered out surface at the top level.
+	var roots []*todov1.Task
+	for _, task := range tasks {
+		if parent, ok := nodes[task.ParentId]; ok {
+			parent.Subtasks = append(parent.Subtasks, nodes[task.Id])
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	page, next := paginate(roots, q)
+	tree := make([]*todov1.TaskNode, 0, len(page))
+	for _, task := range page {
+		tree = append(tree, nodes[task.Id])
+	}
+	return tree, next, nil
+}
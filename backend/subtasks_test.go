@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func mustAddSubtask(t *testing.T, server *TodoServer, text, parentID string) *todov1.Task {
+	t.Helper()
+	resp, err := server.AddTask(context.Background(), connect.NewRequest(&todov1.AddTaskRequest{Text: text, ParentId: parentID}))
+	if err != nil {
+		t.Fatalf("AddTask(%q) error = %v", text, err)
+	}
+	return resp.Msg.Task
+}
+
+// treeShape renders nodes as task texts, each followed by its subtasks in
+// brackets, so trees can be compared at a glance.
+func treeShape(nodes []*todov1.TaskNode) []any {
+	shape := []any{}
+	for _, node := range nodes {
+		shape = append(shape, node.Task.Text)
+		if len(node.Subtasks) > 0 {
+			shape = append(shape, treeShape(node.Subtasks))
+		}
+	}
+	return shape
+}
+
+func getTree(t *testing.T, server *TodoServer, req *todov1.GetTasksRequest) *todov1.GetTasksResponse {
+	t.Helper()
+	req.View = todov1.TaskView_TASK_VIEW_TREE
+	if req.OrderBy == todov1.TaskOrder_TASK_ORDER_UNSPECIFIED {
+		req.OrderBy = todov1.TaskOrder_TASK_ORDER_POSITION
+	}
+	resp, err := server.GetTasks(context.Background(), connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if len(resp.Msg.Tasks) != 0 {
+		t.Errorf("GetTasks() tree view returned %d flat tasks, want none", len(resp.Msg.Tasks))
+	}
+	return resp.Msg
+}
+
+func TestSubtasks(t *testing.T) {
+	forEachStore(t, testSubtasks)
+}
+
+func testSubtasks(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	work := mustCreateList(t, server, "Work")
+	launch := mustAddTask(t, server, "Launch", work.Id)
+	docs := mustAddSubtask(t, server, "Docs", launch.Id)
+	api := mustAddSubtask(t, server, "API docs", docs.Id)
+	mustAddSubtask(t, server, "Release notes", launch.Id)
+	mustAddTask(t, server, "Groceries", "")
+
+	if docs.ParentId != launch.Id || docs.ListId != work.Id {
+		t.Errorf("AddTask() subtask = %v, want parent %s in list %s", docs, launch.Id, work.Id)
+	}
+
+	want := []any{"Launch", []any{"Docs", []any{"API docs"}, "Release notes"}, "Groceries"}
+	if got := treeShape(getTree(t, server, &todov1.GetTasksRequest{}).Tree); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTasks() tree = %v, want %v", got, want)
+	}
+	if got := listTaskIDs(t, server, ""); len(got) != 5 {
+		t.Errorf("GetTasks() flat view returned %d tasks, want 5", len(got))
+	}
+
+	update := func(id string, task *todov1.Task, paths ...string) (*todov1.Task, error) {
+		resp, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:         id,
+			Task:       task,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Task, nil
+	}
+
+	for _, tt := range []struct {
+		name  string
+		id    string
+		task  *todov1.Task
+		paths []string
+		want  connect.Code
+	}{
+		{name: "under itself", id: docs.Id, task: &todov1.Task{ParentId: docs.Id}, paths: []string{"parent_id"}, want: connect.CodeInvalidArgument},
+		{name: "under own subtask", id: launch.Id, task: &todov1.Task{ParentId: api.Id}, paths: []string{"parent_id"}, want: connect.CodeInvalidArgument},
+		{name: "missing parent", id: docs.Id, task: &todov1.Task{ParentId: "missing"}, paths: []string{"parent_id"}, want: connect.CodeNotFound},
+		{name: "subtask out of parent's list", id: docs.Id, task: &todov1.Task{}, paths: []string{"list_id"}, want: connect.CodeInvalidArgument},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := update(tt.id, tt.task, tt.paths...); connect.CodeOf(err) != tt.want {
+				t.Errorf("UpdateTask() error = %v, want code %v", err, tt.want)
+			}
+		})
+	}
+
+	// Moving a top-level task to another list takes its subtasks along.
+	home := mustCreateList(t, server, "Home")
+	if _, err := update(launch.Id, &todov1.Task{ListId: home.Id}, "list_id"); err != nil {
+		t.Fatalf("UpdateTask(list_id) error = %v", err)
+	}
+	if got := listTaskIDs(t, server, home.Id); len(got) != 4 {
+		t.Errorf("tasks in new list = %v, want the task and its 3 subtasks", got)
+	}
+
+	// Re-parenting moves the task, with its subtasks, into the new parent's list.
+	groceries := mustAddTask(t, server, "Errands", "")
+	moved, err := update(docs.Id, &todov1.Task{ParentId: groceries.Id}, "parent_id")
+	if err != nil {
+		t.Fatalf("UpdateTask(parent_id) error = %v", err)
+	}
+	if moved.ParentId != groceries.Id || moved.ListId != "" {
+		t.Errorf("UpdateTask(parent_id) = %v, want parent %s in the inbox", moved, groceries.Id)
+	}
+	if got := tasksByID(t, server)[api.Id].ListId; got != "" {
+		t.Errorf("nested subtask list = %q, want the inbox", got)
+	}
+
+	promoted, err := update(docs.Id, &todov1.Task{}, "parent_id")
+	if err != nil {
+		t.Fatalf("UpdateTask(parent_id) error = %v", err)
+	}
+	if promoted.ParentId != "" {
+		t.Errorf("UpdateTask(parent_id) parent = %q, want none", promoted.ParentId)
+	}
+}
+
+func TestAddSubtaskValidation(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	add := func(ctx context.Context, req *todov1.AddTaskRequest) (*todov1.Task, error) {
+		resp, err := server.AddTask(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Task, nil
+	}
+	parent, err := add(alice, &todov1.AddTaskRequest{Text: "Parent"})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	list, err := server.CreateList(alice, connect.NewRequest(&todov1.CreateListRequest{Name: "Work"}))
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *todov1.AddTaskRequest
+		want connect.Code
+	}{
+		{name: "missing parent", ctx: alice, req: &todov1.AddTaskRequest{Text: "Child", ParentId: "missing"}, want: connect.CodeNotFound},
+		{name: "other user's parent", ctx: bob, req: &todov1.AddTaskRequest{Text: "Child", ParentId: parent.Id}, want: connect.CodePermissionDenied},
+		{name: "list differs from parent's", ctx: alice, req: &todov1.AddTaskRequest{Text: "Child", ParentId: parent.Id, ListId: list.Msg.List.Id}, want: connect.CodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := add(tt.ctx, tt.req); connect.CodeOf(err) != tt.want {
+				t.Errorf("AddTask() error = %v, want code %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTaskWithSubtasks(t *testing.T) {
+	forEachStore(t, testDeleteTaskWithSubtasks)
+}
+
+func testDeleteTaskWithSubtasks(t *testing.T, newServer func() *TodoServer) {
+	ctx := context.Background()
+
+	// Each case starts from Project > Phase > (Step 1 > Detail, Step 2).
+	setup := func(t *testing.T) (*TodoServer, *todov1.Task) {
+		server := newServer()
+		project := mustAddTask(t, server, "Project", "")
+		phase := mustAddSubtask(t, server, "Phase", project.Id)
+		step := mustAddSubtask(t, server, "Step 1", phase.Id)
+		mustAddSubtask(t, server, "Detail", step.Id)
+		mustAddSubtask(t, server, "Step 2", phase.Id)
+		return server, phase
+	}
+	del := func(server *TodoServer, id string, policy todov1.SubtaskDeletePolicy) error {
+		_, err := server.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: id, SubtaskPolicy: policy}))
+		return err
+	}
+
+	t.Run("refuses by default", func(t *testing.T) {
+		server, phase := setup(t)
+		if err := del(server, phase.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("DeleteTask() error = %v, want code %v", err, connect.CodeFailedPrecondition)
+		}
+		if got := len(tasksByID(t, server)); got != 5 {
+			t.Errorf("tasks after refused delete = %d, want 5", got)
+		}
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		server, phase := setup(t)
+		if err := del(server, phase.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE); err != nil {
+			t.Fatalf("DeleteTask() error = %v", err)
+		}
+		want := []any{"Project"}
+		if got := treeShape(getTree(t, server, &todov1.GetTasksRequest{}).Tree); !reflect.DeepEqual(got, want) {
+			t.Errorf("tree after cascade = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("promote", func(t *testing.T) {
+		server, phase := setup(t)
+		if err := del(server, phase.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_PROMOTE); err != nil {
+			t.Fatalf("DeleteTask() error = %v", err)
+		}
+		want := []any{"Project", []any{"Step 1", []any{"Detail"}, "Step 2"}}
+		if got := treeShape(getTree(t, server, &todov1.GetTasksRequest{}).Tree); !reflect.DeepEqual(got, want) {
+			t.Errorf("tree after promote = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("leaf needs no policy", func(t *testing.T) {
+		server, _ := setup(t)
+		leaf := mustAddTask(t, server, "Leaf", "")
+		if err := del(server, leaf.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED); err != nil {
+			t.Fatalf("DeleteTask() error = %v", err)
+		}
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		server, phase := setup(t)
+		if err := del(server, phase.Id, todov1.SubtaskDeletePolicy(99)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("DeleteTask() error = %v, want code %v", err, connect.CodeInvalidArgument)
+		}
+	})
+}
+
+func TestGetTasksTreeView(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	a := mustAddTask(t, server, "A", "")
+	a1 := mustAddSubtask(t, server, "A1", a.Id)
+	mustAddSubtask(t, server, "A1a", a1.Id)
+	b := mustAddTask(t, server, "B", "")
+	mustAddSubtask(t, server, "B1", b.Id)
+	mustAddTask(t, server, "C", "")
+
+	// A subtask whose parent is filtered out is returned at the top level.
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: a.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	want := []any{"A1", []any{"A1a"}, "B", []any{"B1"}, "C"}
+	open := getTree(t, server, &todov1.GetTasksRequest{Status: todov1.TaskStatus_TASK_STATUS_OPEN})
+	if got := treeShape(open.Tree); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTasks(open) tree = %v, want %v", got, want)
+	}
+
+	// Pages hold whole top-level nodes.
+	var pages [][]any
+	token := ""
+	for {
+		resp := getTree(t, server, &todov1.GetTasksRequest{PageSize: 2, PageToken: token})
+		pages = append(pages, treeShape(resp.Tree))
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	wantPages := [][]any{
+		{"A", []any{"A1", []any{"A1a"}}, "B", []any{"B1"}},
+		{"C"},
+	}
+	if !reflect.DeepEqual(pages, wantPages) {
+		t.Errorf("GetTasks() tree pages = %v, want %v", pages, wantPages)
+	}
+
+	_, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{View: todov1.TaskView(99)}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("GetTasks(unknown view) error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+}
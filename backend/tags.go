@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"connectrpc.com/connect"
+
+	"todo-list/todo/v1"
+)
+
+const (
+	MaxTagLength   = 32
+	MaxTagsPerTask = 20
+)
+
+var (
+	ErrTagEmpty    = errors.New("tag cannot be empty")
+	ErrTagTooLong  = errors.New("tag exceeds maximum length")
+	ErrInvalidTag  = errors.New("tag may only contain letters, digits, '-', '_' and '/'")
+	ErrTooManyTags = errors.New("task has too many tags")
+	ErrNoTagsGiven = errors.New("at least one tag is required")
+)
+
+// normalizeTag returns tag in its canonical form: trimmed, lower-cased and
+// without a leading '#', so "#Work" and "work" are the same tag.
+//
+// It returns ErrTagEmpty if nothing is left, ErrTagTooLong if the result
+// exceeds MaxTagLength bytes, or ErrInvalidTag if it contains anything but
+// letters, digits, '-', '_' and '/'.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimPrefix(tag, "#")
+	if tag == "" {
+		return "", ErrTagEmpty
+	}
+	if len(tag) > MaxTagLength {
+		return "", ErrTagTooLong
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '/' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes every tag and returns them sorted without
+// duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	uniq := out[:0]
+	for i, t := range out {
+		if i == 0 || t != out[i-1] {
+			uniq = append(uniq, t)
+		}
+	}
+	return uniq, nil
+}
+
+// validateTaskTags normalizes the tags for a task and checks that there are
+// not too many of them.
+func validateTaskTags(tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > MaxTagsPerTask {
+		return nil, ErrTooManyTags
+	}
+	return tags, nil
+}
+
+// hasTag reports whether task carries tag. Task tags are kept sorted.
+func hasTag(task *todov1.Task, tag string) bool {
+	i := sort.SearchStrings(task.Tags, tag)
+	return i < len(task.Tags) && task.Tags[i] == tag
+}
+
+func (s *TodoServer) AddTags(
+	ctx context.Context,
+	req *connect.Request[todov1.AddTagsRequest],
+) (*connect.Response[todov1.AddTagsResponse], error) {
+	tags, err := parseTagEdit(req.Msg.Id, req.Msg.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		merged, err := validateTaskTags(append(task.Tags, tags...))
+		if err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		task.Tags = merged
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.AddTagsResponse{Task: task}), nil
+}
+
+func (s *TodoServer) RemoveTags(
+	ctx context.Context,
+	req *connect.Request[todov1.RemoveTagsRequest],
+) (*connect.Response[todov1.RemoveTagsResponse], error) {
+	tags, err := parseTagEdit(req.Msg.Id, req.Msg.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.modifyTask(ctx, req.Msg.Id, req.Msg.ExpectedVersion, func(task *todov1.Task) error {
+		kept := make([]string, 0, len(task.Tags))
+		for _, tag := range task.Tags {
+			if i := sort.SearchStrings(tags, tag); i == len(tags) || tags[i] != tag {
+				kept = append(kept, tag)
+			}
+		}
+		task.Tags = kept
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.RemoveTagsResponse{Task: task}), nil
+}
+
+// parseTagEdit validates the arguments of AddTags and RemoveTags and returns
+// the normalized tags.
+func parseTagEdit(id string, tags []string) ([]string, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+	if len(tags) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrNoTagsGiven)
+	}
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return tags, nil
+}
+
+func (s *TodoServer) ListTags(
+	ctx context.Context,
+	req *connect.Request[todov1.ListTagsRequest],
+) (*connect.Response[todov1.ListTagsResponse], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if req.Msg.ListId != "" {
+		if _, err := s.loadList(ctx, req.Msg.ListId, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+			return nil, err
+		}
+	}
+	access, err := s.accessOf(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+	tasks, err := s.store.ListTasks()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
+	}
+
+	counts := make(map[string]int32)
+	for _, task := range tasks {
+		if !access.canView(task) || (req.Msg.ListId != "" && task.ListId != req.Msg.ListId) {
+			continue
+		}
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+	resp := &todov1.ListTagsResponse{Tags: make([]*todov1.TagCount, 0, len(counts))}
+	for name, count := range counts {
+		resp.Tags = append(resp.Tags, &todov1.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(resp.Tags, func(i, j int) bool { return resp.Tags[i].Name < resp.Tags[j].Name })
+	return connect.NewResponse(resp), nil
+}
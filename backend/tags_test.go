@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr error
+	}{
+		{name: "plain", tag: "work", want: "work"},
+		{name: "trimmed and lower-cased", tag: "  Work ", want: "work"},
+		{name: "hash prefix", tag: "#Home", want: "home"},
+		{name: "punctuation allowed", tag: "team/q3-plans_v2", want: "team/q3-plans_v2"},
+		{name: "non-ASCII letters", tag: "Café", want: "café"},
+		{name: "empty", tag: "   ", wantErr: ErrTagEmpty},
+		{name: "only hash", tag: "#", wantErr: ErrTagEmpty},
+		{name: "at max length", tag: strings.Repeat("a", MaxTagLength), want: strings.Repeat("a", MaxTagLength)},
+		{name: "too long", tag: strings.Repeat("a", MaxTagLength+1), wantErr: ErrTagTooLong},
+		{name: "inner space", tag: "two words", wantErr: ErrInvalidTag},
+		{name: "comma", tag: "a,b", wantErr: ErrInvalidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTag(tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeTag(%q) error = %v, want %v", tt.tag, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	forEachStore(t, testTags)
+}
+
+func testTags(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	add := func(text string, tags ...string) string {
+		t.Helper()
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: text, Tags: tags}))
+		if err != nil {
+			t.Fatalf("AddTask(%q) error = %v", text, err)
+		}
+		return resp.Msg.Task.Id
+	}
+	report := add("Report", "Work", "#urgent", "work")
+	milk := add("Milk", "home")
+	call := add("Call", "home", "urgent")
+	add("Untagged")
+
+	tasks := tasksByID(t, server)
+	if got, want := tasks[report].Tags, []string{"urgent", "work"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AddTask() tags = %v, want %v", got, want)
+	}
+
+	added, err := server.AddTags(ctx, connect.NewRequest(&todov1.AddTagsRequest{Id: milk, Tags: []string{"Errands", "home"}}))
+	if err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+	if got, want := added.Msg.Task.Tags, []string{"errands", "home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AddTags() tags = %v, want %v", got, want)
+	}
+	removed, err := server.RemoveTags(ctx, connect.NewRequest(&todov1.RemoveTagsRequest{Id: call, Tags: []string{"URGENT", "missing"}}))
+	if err != nil {
+		t.Fatalf("RemoveTags() error = %v", err)
+	}
+	if got, want := removed.Msg.Task.Tags, []string{"home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveTags() tags = %v, want %v", got, want)
+	}
+
+	listed, err := server.ListTags(ctx, connect.NewRequest(&todov1.ListTagsRequest{}))
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	var counts []string
+	for _, tc := range listed.Msg.Tags {
+		counts = append(counts, fmt.Sprintf("%s=%d", tc.Name, tc.Count))
+	}
+	if want := []string{"errands=1", "home=2", "urgent=1", "work=1"}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("ListTags() = %v, want %v", counts, want)
+	}
+
+	filters := []struct {
+		name    string
+		req     *todov1.GetTasksRequest
+		wantIDs []string
+	}{
+		{name: "any of", req: &todov1.GetTasksRequest{AnyTags: []string{"work", "errands"}}, wantIDs: []string{milk, report}},
+		{name: "all of", req: &todov1.GetTasksRequest{AllTags: []string{"home", "errands"}}, wantIDs: []string{milk}},
+		{name: "all of without match", req: &todov1.GetTasksRequest{AllTags: []string{"home", "work"}}, wantIDs: []string{}},
+		{name: "filters are normalized", req: &todov1.GetTasksRequest{AnyTags: []string{"#HOME"}}, wantIDs: []string{call, milk}},
+		{name: "any and all combined", req: &todov1.GetTasksRequest{AnyTags: []string{"home", "work"}, AllTags: []string{"errands"}}, wantIDs: []string{milk}},
+	}
+	for _, tt := range filters {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.OrderBy = todov1.TaskOrder_TASK_ORDER_ID
+			resp, err := server.GetTasks(ctx, connect.NewRequest(tt.req))
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+			got := taskIDs(resp.Msg.Tasks)
+			want := slices.Clone(tt.wantIDs)
+			slices.Sort(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetTasks() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Replacing every tag through UpdateTask.
+	updated, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         report,
+		Task:       &todov1.Task{Tags: []string{"Done", "done"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tags"}},
+	}))
+	if err != nil {
+		t.Fatalf("UpdateTask(tags) error = %v", err)
+	}
+	if got, want := updated.Msg.Task.Tags, []string{"done"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UpdateTask(tags) = %v, want %v", got, want)
+	}
+
+	many := make([]string, MaxTagsPerTask+1)
+	for i := range many {
+		many[i] = "tag" + strings.Repeat("x", i)
+	}
+	invalid := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"AddTask with bad tag", func() error {
+			_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "x", Tags: []string{"no spaces"}}))
+			return err
+		}, ErrInvalidTag},
+		{"AddTask with too many tags", func() error {
+			_, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "x", Tags: many}))
+			return err
+		}, ErrTooManyTags},
+		{"AddTags over the limit", func() error {
+			_, err := server.AddTags(ctx, connect.NewRequest(&todov1.AddTagsRequest{Id: report, Tags: many[:MaxTagsPerTask]}))
+			return err
+		}, ErrTooManyTags},
+		{"AddTags without tags", func() error {
+			_, err := server.AddTags(ctx, connect.NewRequest(&todov1.AddTagsRequest{Id: report}))
+			return err
+		}, ErrNoTagsGiven},
+		{"RemoveTags with empty tag", func() error {
+			_, err := server.RemoveTags(ctx, connect.NewRequest(&todov1.RemoveTagsRequest{Id: report, Tags: []string{" "}}))
+			return err
+		}, ErrTagEmpty},
+		{"GetTasks with bad filter", func() error {
+			_, err := server.GetTasks(ctx, connect.NewRequest(&todov1.GetTasksRequest{AllTags: []string{"a b"}}))
+			return err
+		}, ErrInvalidTag},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); connect.CodeOf(err) != connect.CodeInvalidArgument || !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want invalid argument %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,72 +2,252 @@
 package todov1
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"connectrpc.com/connect"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type TodoServiceHandler interface {
 	AddTask(context.Context, *connect.Request[AddTaskRequest]) (*connect.Response[AddTaskResponse], error)
 	GetTasks(context.Context, *connect.Request[GetTasksRequest]) (*connect.Response[GetTasksResponse], error)
 	DeleteTask(context.Context, *connect.Request[DeleteTaskRequest]) (*connect.Response[DeleteTaskResponse], error)
+	UpdateTask(context.Context, *connect.Request[UpdateTaskRequest]) (*connect.Response[UpdateTaskResponse], error)
+	CompleteTask(context.Context, *connect.Request[CompleteTaskRequest]) (*connect.Response[CompleteTaskResponse], error)
+	ReopenTask(context.Context, *connect.Request[ReopenTaskRequest]) (*connect.Response[ReopenTaskResponse], error)
+	WatchTasks(context.Context, *connect.Request[WatchTasksRequest], *ServerStream[WatchTasksResponse]) error
+	CreateList(context.Context, *connect.Request[CreateListRequest]) (*connect.Response[CreateListResponse], error)
+	GetLists(context.Context, *connect.Request[GetListsRequest]) (*connect.Response[GetListsResponse], error)
+	RenameList(context.Context, *connect.Request[RenameListRequest]) (*connect.Response[RenameListResponse], error)
+	DeleteList(context.Context, *connect.Request[DeleteListRequest]) (*connect.Response[DeleteListResponse], error)
+	ShareList(context.Context, *connect.Request[ShareListRequest]) (*connect.Response[ShareListResponse], error)
+	RevokeListAccess(context.Context, *connect.Request[RevokeListAccessRequest]) (*connect.Response[RevokeListAccessResponse], error)
+	MoveTask(context.Context, *connect.Request[MoveTaskRequest]) (*connect.Response[MoveTaskResponse], error)
+	AddTags(context.Context, *connect.Request[AddTagsRequest]) (*connect.Response[AddTagsResponse], error)
+	RemoveTags(context.Context, *connect.Request[RemoveTagsRequest]) (*connect.Response[RemoveTagsResponse], error)
+	ListTags(context.Context, *connect.Request[ListTagsRequest]) (*connect.Response[ListTagsResponse], error)
+	AddBlocker(context.Context, *connect.Request[AddBlockerRequest]) (*connect.Response[AddBlockerResponse], error)
+	RemoveBlocker(context.Context, *connect.Request[RemoveBlockerRequest]) (*connect.Response[RemoveBlockerResponse], error)
+	ListTrash(context.Context, *connect.Request[ListTrashRequest]) (*connect.Response[ListTrashResponse], error)
+	RestoreTask(context.Context, *connect.Request[RestoreTaskRequest]) (*connect.Response[RestoreTaskResponse], error)
+	PurgeTask(context.Context, *connect.Request[PurgeTaskRequest]) (*connect.Response[PurgeTaskResponse], error)
+	Undo(context.Context, *connect.Request[UndoRequest]) (*connect.Response[UndoResponse], error)
+	BatchAddTasks(context.Context, *connect.Request[BatchAddTasksRequest]) (*connect.Response[BatchAddTasksResponse], error)
+	BatchUpdateTasks(context.Context, *connect.Request[BatchUpdateTasksRequest]) (*connect.Response[BatchUpdateTasksResponse], error)
+	BatchDeleteTasks(context.Context, *connect.Request[BatchDeleteTasksRequest]) (*connect.Response[BatchDeleteTasksResponse], error)
+	ExportTasks(context.Context, *connect.Request[ExportTasksRequest]) (*connect.Response[ExportTasksResponse], error)
+	ImportTasks(context.Context, *connect.Request[ImportTasksRequest]) (*connect.Response[ImportTasksResponse], error)
+	GetTaskHistory(context.Context, *connect.Request[GetTaskHistoryRequest]) (*connect.Response[GetTaskHistoryResponse], error)
+	ListEvents(context.Context, *connect.Request[ListEventsRequest]) (*connect.Response[ListEventsResponse], error)
 }
 
 const TodoServiceName = "todo.v1.TodoService"
 
-func NewTodoServiceHandler(svc TodoServiceHandler) (string, http.Handler) {
-	return "/" + TodoServiceName + "/", &todoServiceHandler{
-		svc: svc,
-		pjm: protojson.MarshalOptions{},
-		pju: protojson.UnmarshalOptions{},
+type WorkspaceServiceHandler interface {
+	CreateWorkspace(context.Context, *connect.Request[CreateWorkspaceRequest]) (*connect.Response[CreateWorkspaceResponse], error)
+	ListWorkspaces(context.Context, *connect.Request[ListWorkspacesRequest]) (*connect.Response[ListWorkspacesResponse], error)
+	DeleteWorkspace(context.Context, *connect.Request[DeleteWorkspaceRequest]) (*connect.Response[DeleteWorkspaceResponse], error)
+}
+
+const WorkspaceServiceName = "todo.v1.WorkspaceService"
+
+// HandlerOption configures the handlers returned by NewTodoServiceHandler and
+// NewWorkspaceServiceHandler.
+type HandlerOption func(*todoServiceHandler)
+
+// WithInterceptors runs every RPC through interceptors. As with connect-go,
+// the first interceptor is the outermost one.
+//
+// Unary requests seen by interceptors carry no Spec or Peer; use
+// SpecFromContext and PeerFromContext instead.
+func WithInterceptors(interceptors ...connect.Interceptor) HandlerOption {
+	return func(h *todoServiceHandler) {
+		h.interceptors = append(h.interceptors, interceptors...)
 	}
 }
 
+func NewTodoServiceHandler(svc TodoServiceHandler, opts ...HandlerOption) (string, http.Handler) {
+	h := &todoServiceHandler{
+		base: "/" + TodoServiceName + "/",
+		pjm:  protojson.MarshalOptions{},
+		pju:  protojson.UnmarshalOptions{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.routes = map[string]http.HandlerFunc{
+		"AddTask":          func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.AddTask) },
+		"GetTasks":         func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.GetTasks) },
+		"DeleteTask":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.DeleteTask) },
+		"UpdateTask":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.UpdateTask) },
+		"CompleteTask":     func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.CompleteTask) },
+		"ReopenTask":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ReopenTask) },
+		"WatchTasks":       func(w http.ResponseWriter, r *http.Request) { serveServerStream(h, w, r, svc.WatchTasks) },
+		"CreateList":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.CreateList) },
+		"GetLists":         func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.GetLists) },
+		"RenameList":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.RenameList) },
+		"DeleteList":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.DeleteList) },
+		"ShareList":        func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ShareList) },
+		"RevokeListAccess": func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.RevokeListAccess) },
+		"MoveTask":         func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.MoveTask) },
+		"AddTags":          func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.AddTags) },
+		"RemoveTags":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.RemoveTags) },
+		"ListTags":         func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ListTags) },
+		"AddBlocker":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.AddBlocker) },
+		"RemoveBlocker":    func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.RemoveBlocker) },
+		"ListTrash":        func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ListTrash) },
+		"RestoreTask":      func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.RestoreTask) },
+		"PurgeTask":        func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.PurgeTask) },
+		"Undo":             func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.Undo) },
+		"BatchAddTasks":    func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.BatchAddTasks) },
+		"BatchUpdateTasks": func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.BatchUpdateTasks) },
+		"BatchDeleteTasks": func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.BatchDeleteTasks) },
+		"ExportTasks":      func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ExportTasks) },
+		"ImportTasks":      func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ImportTasks) },
+		"GetTaskHistory":   func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.GetTaskHistory) },
+		"ListEvents":       func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ListEvents) },
+	}
+	return h.base, h
+}
+
+func NewWorkspaceServiceHandler(svc WorkspaceServiceHandler, opts ...HandlerOption) (string, http.Handler) {
+	h := &todoServiceHandler{
+		base: "/" + WorkspaceServiceName + "/",
+		pjm:  protojson.MarshalOptions{},
+		pju:  protojson.UnmarshalOptions{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.routes = map[string]http.HandlerFunc{
+		"CreateWorkspace": func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.CreateWorkspace) },
+		"ListWorkspaces":  func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.ListWorkspaces) },
+		"DeleteWorkspace": func(w http.ResponseWriter, r *http.Request) { serveUnary(h, w, r, svc.DeleteWorkspace) },
+	}
+	return h.base, h
+}
+
+// todoServiceHandler serves the RPCs of one service, TodoService or
+// WorkspaceService.
 type todoServiceHandler struct {
-	svc  TodoServiceHandler
-	pjm  protojson.MarshalOptions
-	pju  protojson.UnmarshalOptions
+	base         string // URL path prefix of the service's RPCs
+	pjm          protojson.MarshalOptions
+	pju          protojson.UnmarshalOptions
+	routes       map[string]http.HandlerFunc // keyed by RPC method name
+	interceptors []connect.Interceptor
+}
+
+type callInfoKey struct{}
+
+type callInfo struct {
+	spec connect.Spec
+	peer connect.Peer
+}
+
+// withCallInfo records the spec and peer of the RPC being served in ctx.
+func withCallInfo(ctx context.Context, r *http.Request, streamType connect.StreamType) (context.Context, callInfo) {
+	info := callInfo{
+		spec: connect.Spec{StreamType: streamType, Procedure: r.URL.Path},
+		peer: connect.Peer{Addr: r.RemoteAddr, Protocol: connect.ProtocolConnect, Query: r.URL.Query()},
+	}
+	return context.WithValue(ctx, callInfoKey{}, info), info
+}
+
+// SpecFromContext returns the Spec of the RPC being served with ctx.
+func SpecFromContext(ctx context.Context) (connect.Spec, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(callInfo)
+	return info.spec, ok
+}
+
+// PeerFromContext returns the client of the RPC being served with ctx.
+func PeerFromContext(ctx context.Context) (connect.Peer, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(callInfo)
+	return info.peer, ok
 }
 
 func writeConnectError(w http.ResponseWriter, err *connect.Error) {
 	// Ensure protocol version is always present
 	w.Header().Set("Connect-Protocol-Version", "1")
 	w.Header().Set("Content-Type", "application/json")
-	
+
 	// Map ConnectRPC codes to HTTP status codes
 	var statusCode int
 	switch err.Code() {
-	case connect.CodeInvalidArgument:
+	case connect.CodeInvalidArgument, connect.CodeFailedPrecondition:
 		statusCode = http.StatusBadRequest
 	case connect.CodeNotFound:
 		statusCode = http.StatusNotFound
+	case connect.CodeAlreadyExists, connect.CodeAborted:
+		statusCode = http.StatusConflict
+	case connect.CodeUnauthenticated:
+		statusCode = http.StatusUnauthorized
+	case connect.CodePermissionDenied:
+		statusCode = http.StatusForbidden
+	case connect.CodeResourceExhausted:
+		statusCode = http.StatusTooManyRequests
+	case connect.CodeUnavailable:
+		statusCode = http.StatusServiceUnavailable
 	case connect.CodeInternal:
 		statusCode = http.StatusInternalServerError
 	default:
 		statusCode = http.StatusInternalServerError
 	}
-	
+
+	// Metadata such as Retry-After goes out as response headers.
+	for k, vv := range err.Meta() {
+		for _, v := range vv {
+			w.Header().Add(k, v)
+		}
+	}
 	w.WriteHeader(statusCode)
-	
-	// Use simple error response for now - can be enhanced later
-	fmt.Fprintf(w, `{"code":"%s","message":"%s"}`, err.Code(), err.Message())
+	w.Write(marshalConnectError(err))
 }
 
-func propagateHeaders[T any](r *http.Request, connectReq *connect.Request[T]) {
-	if v := r.Header.Get("Authorization"); v != "" {
-		connectReq.Header().Set("Authorization", v)
+// errorDetail is an error detail in the Connect protocol's JSON error format.
+type errorDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// marshalConnectError encodes err in the Connect protocol's JSON error format.
+func marshalConnectError(err *connect.Error) []byte {
+	var details []errorDetail
+	for _, d := range err.Details() {
+		details = append(details, errorDetail{
+			Type:  d.Type(),
+			Value: base64.RawStdEncoding.EncodeToString(d.Bytes()),
+		})
 	}
-	for k, vv := range r.Header {
-		if strings.HasPrefix(http.CanonicalHeaderKey(k), "Connect-") {
-			for _, v := range vv {
-				connectReq.Header().Add(k, v)
-			}
+	data, _ := json.Marshal(struct {
+		Code    string        `json:"code"`
+		Message string        `json:"message,omitempty"`
+		Details []errorDetail `json:"details,omitempty"`
+	}{
+		Code:    err.Code().String(),
+		Message: err.Message(),
+		Details: details,
+	})
+	return data
+}
+
+// propagateHeaders copies the HTTP request headers, which carry credentials
+// and Connect metadata, onto the request handed to the service.
+func propagateHeaders(from http.Header, to http.Header) {
+	for k, vv := range from {
+		for _, v := range vv {
+			to.Add(k, v)
 		}
 	}
 }
@@ -86,8 +266,8 @@ func (h *todoServiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Connect-Protocol-Version", "1")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Connect-Protocol-Version, Connect-Timeout-Ms, Connect-Content-Encoding, Connect-Accept-Encoding, Accept")
-	w.Header().Set("Access-Control-Expose-Headers", "Content-Type, Connect-Protocol-Version, Connect-Content-Encoding, Connect-Accept-Encoding, Connect-Error-Code")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Api-Key, X-Workspace, Connect-Protocol-Version, Connect-Timeout-Ms, Connect-Content-Encoding, Connect-Accept-Encoding, Accept")
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Type, Connect-Protocol-Version, Connect-Content-Encoding, Connect-Accept-Encoding, Connect-Error-Code, Retry-After")
 	w.Header().Add("Vary", "Origin")
 	w.Header().Add("Vary", "Access-Control-Request-Method")
 	w.Header().Add("Vary", "Access-Control-Request-Headers")
@@ -100,39 +280,33 @@ func (h *todoServiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract the method from the URL path
 	// ConnectRPC client sends requests to /todo.v1.TodoService/MethodName
 	path := r.URL.Path
-	base := "/" + TodoServiceName + "/"
-	if !strings.HasPrefix(path, base) {
+	if !strings.HasPrefix(path, h.base) {
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
 	}
 
-	methodName := strings.TrimPrefix(path, base)
+	methodName := strings.TrimPrefix(path, h.base)
 
-	switch methodName {
-	case "AddTask":
-		if r.Method != "POST" {
-			writeConnectError(w, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("method not allowed")))
-			return
-		}
-		h.handleAddTask(w, r)
-	case "GetTasks":
-		if r.Method != "POST" {
-			writeConnectError(w, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("method not allowed")))
-			return
-		}
-		h.handleGetTasks(w, r)
-	case "DeleteTask":
-		if r.Method != "POST" {
-			writeConnectError(w, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("method not allowed")))
-			return
-		}
-		h.handleDeleteTask(w, r)
-	default:
+	serve, ok := h.routes[methodName]
+	if !ok {
 		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != "POST" {
+		writeConnectError(w, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("method not allowed")))
+		return
 	}
+	serve(w, r)
 }
 
-func (h *todoServiceHandler) handleAddTask(w http.ResponseWriter, r *http.Request) {
+// serveUnary decodes a JSON request body into a Req, invokes call and writes
+// the JSON-encoded response. An empty body decodes to the zero message.
+func serveUnary[Req, Res any](
+	h *todoServiceHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+	call func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error),
+) {
 	defer r.Body.Close()
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB limit
 	data, err := io.ReadAll(r.Body)
@@ -141,19 +315,38 @@ func (h *todoServiceHandler) handleAddTask(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var req AddTaskRequest
-	if err := h.pju.Unmarshal(data, &req); err != nil {
-		writeConnectError(w, connect.NewError(connect.CodeInvalidArgument, err))
-		return
+	req := new(Req)
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := h.pju.Unmarshal(data, any(req).(proto.Message)); err != nil {
+			writeConnectError(w, connect.NewError(connect.CodeInvalidArgument, err))
+			return
+		}
 	}
 
-	connectReq := connect.NewRequest(&req)
-	propagateHeaders(r, connectReq)
-	resp, err := h.svc.AddTask(r.Context(), connectReq)
+	connectReq := connect.NewRequest(req)
+	propagateHeaders(r.Header, connectReq.Header())
+
+	next := connect.UnaryFunc(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		typed, ok := req.(*connect.Request[Req])
+		if !ok {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("unexpected request type %T", req))
+		}
+		return call(ctx, typed)
+	})
+	for i := len(h.interceptors) - 1; i >= 0; i-- {
+		next = h.interceptors[i].WrapUnary(next)
+	}
+	ctx, _ := withCallInfo(r.Context(), r, connect.StreamTypeUnary)
+	anyResp, err := next(ctx, connectReq)
 	if err != nil {
 		handleServiceError(w, err)
 		return
 	}
+	resp, ok := anyResp.(*connect.Response[Res])
+	if !ok {
+		handleServiceError(w, fmt.Errorf("unexpected response type %T", anyResp))
+		return
+	}
 
 	// propagate headers from svc
 	for k, vv := range resp.Header() {
@@ -161,7 +354,7 @@ func (h *todoServiceHandler) handleAddTask(w http.ResponseWriter, r *http.Reques
 			w.Header().Add(k, v)
 		}
 	}
-	data, err = h.pjm.Marshal(resp.Msg)
+	data, err = h.pjm.Marshal(any(resp.Msg).(proto.Message))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -172,34 +365,146 @@ func (h *todoServiceHandler) handleAddTask(w http.ResponseWriter, r *http.Reques
 	}
 }
 
-func (h *todoServiceHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
-	req := GetTasksRequest{}
-	connectReq := connect.NewRequest(&req)
-	propagateHeaders(r, connectReq)
-	resp, err := h.svc.GetTasks(r.Context(), connectReq)
+// Envelope flags used by the Connect streaming protocol.
+const (
+	envelopeFlagCompressed = 0x01
+	envelopeFlagEndStream  = 0x02
+)
+
+// ServerStream is the send side of a server-streaming RPC. Messages are
+// written and flushed to the client as soon as Send is called.
+type ServerStream[Res any] struct {
+	conn connect.StreamingHandlerConn
+}
+
+// Send writes msg to the client.
+func (s *ServerStream[Res]) Send(msg *Res) error {
+	return s.conn.Send(msg)
+}
+
+// handlerConn adapts a server-streaming HTTP exchange to
+// connect.StreamingHandlerConn so that interceptors can wrap it.
+type handlerConn struct {
+	info    callInfo
+	request proto.Message // the single, already decoded request message
+	read    bool
+	header  http.Header
+
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	pjm     protojson.MarshalOptions
+	trailer http.Header
+}
+
+func (c *handlerConn) Spec() connect.Spec { return c.info.spec }
+
+func (c *handlerConn) Peer() connect.Peer { return c.info.peer }
+
+func (c *handlerConn) RequestHeader() http.Header { return c.header }
+
+// ResponseHeader returns the response headers. They are sent before the
+// handler runs, so changes made to them have no effect.
+func (c *handlerConn) ResponseHeader() http.Header { return c.w.Header() }
+
+func (c *handlerConn) ResponseTrailer() http.Header { return c.trailer }
+
+func (c *handlerConn) Receive(msg any) error {
+	if c.read {
+		return io.EOF
+	}
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unexpected message type %T", msg))
+	}
+	c.read = true
+	proto.Merge(m, c.request)
+	return nil
+}
+
+func (c *handlerConn) Send(msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unexpected message type %T", msg))
+	}
+	data, err := c.pjm.Marshal(m)
 	if err != nil {
-		handleServiceError(w, err)
-		return
+		return connect.NewError(connect.CodeInternal, err)
 	}
 
-	// propagate headers from svc
-	for k, vv := range resp.Header() {
-		for _, v := range vv {
-			w.Header().Add(k, v)
-		}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := writeEnvelope(c.w, 0, data); err != nil {
+		return err
+	}
+	return c.rc.Flush()
+}
+
+// end writes the end-stream message carrying err, if any, and the trailers.
+func (c *handlerConn) end(err error) {
+	var msg struct {
+		Error    json.RawMessage     `json:"error,omitempty"`
+		Metadata map[string][]string `json:"metadata,omitempty"`
 	}
-	data, err := h.pjm.Marshal(resp.Msg)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		var cerr *connect.Error
+		if !errors.As(err, &cerr) {
+			cerr = connect.NewError(connect.CodeInternal, err)
+		}
+		msg.Error = marshalConnectError(cerr)
 	}
-	if _, err := w.Write(data); err != nil {
-		// can't recover after write starts; optionally log
+	if len(c.trailer) > 0 {
+		msg.Metadata = c.trailer
+	}
+	data, _ := json.Marshal(msg)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := writeEnvelope(c.w, envelopeFlagEndStream, data); err != nil {
 		return
 	}
+	c.rc.Flush()
 }
 
-func (h *todoServiceHandler) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+func writeEnvelope(w io.Writer, flags byte, data []byte) error {
+	var prefix [5]byte
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(data)))
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// readEnvelope extracts the single message of a streaming request body. A
+// body without an envelope is accepted as a bare JSON message.
+func readEnvelope(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] == '{' {
+		return data, nil
+	}
+	if len(data) < 5 {
+		return nil, fmt.Errorf("truncated envelope")
+	}
+	if data[0]&envelopeFlagCompressed != 0 {
+		return nil, fmt.Errorf("compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(data[1:5])
+	if int(size) != len(data)-5 {
+		return nil, fmt.Errorf("envelope length %d does not match body length %d", size, len(data)-5)
+	}
+	return data[5:], nil
+}
+
+// serveServerStream decodes a single request message, invokes call and
+// streams every message it sends using the Connect streaming protocol. The
+// stream ends with an end-stream message carrying call's error, if any.
+func serveServerStream[Req, Res any](
+	h *todoServiceHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+	call func(context.Context, *connect.Request[Req], *ServerStream[Res]) error,
+) {
 	defer r.Body.Close()
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB limit
 	data, err := io.ReadAll(r.Body)
@@ -208,33 +513,45 @@ func (h *todoServiceHandler) handleDeleteTask(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	var req DeleteTaskRequest
-	if err := h.pju.Unmarshal(data, &req); err != nil {
-		writeConnectError(w, connect.NewError(connect.CodeInvalidArgument, err))
-		return
+	req := new(Req)
+	payload, err := readEnvelope(data)
+	if err == nil && len(bytes.TrimSpace(payload)) > 0 {
+		err = h.pju.Unmarshal(payload, any(req).(proto.Message))
 	}
-
-	connectReq := connect.NewRequest(&req)
-	propagateHeaders(r, connectReq)
-	resp, err := h.svc.DeleteTask(r.Context(), connectReq)
 	if err != nil {
-		handleServiceError(w, err)
+		writeConnectError(w, connect.NewError(connect.CodeInvalidArgument, err))
 		return
 	}
 
-	// propagate headers from svc
-	for k, vv := range resp.Header() {
-		for _, v := range vv {
-			w.Header().Add(k, v)
-		}
-	}
-	data, err = h.pjm.Marshal(resp.Msg)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	ctx, info := withCallInfo(r.Context(), r, connect.StreamTypeServer)
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's write timeout; lift it for this response.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/connect+json")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	conn := &handlerConn{
+		info:    info,
+		request: any(req).(proto.Message),
+		header:  r.Header,
+		w:       w,
+		rc:      rc,
+		pjm:     h.pjm,
+		trailer: make(http.Header),
 	}
-	if _, err := w.Write(data); err != nil {
-		// can't recover after write starts; optionally log
-		return
+	next := connect.StreamingHandlerFunc(func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		req := new(Req)
+		if err := conn.Receive(req); err != nil {
+			return err
+		}
+		connectReq := connect.NewRequest(req)
+		propagateHeaders(conn.RequestHeader(), connectReq.Header())
+		return call(ctx, connectReq, &ServerStream[Res]{conn: conn})
+	})
+	for i := len(h.interceptors) - 1; i >= 0; i-- {
+		next = h.interceptors[i].WrapStreamingHandler(next)
 	}
-}
\ No newline at end of file
+	conn.end(next(ctx, conn))
+}
@@ -9,6 +9,7 @@ package todov1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,28 +22,4414 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// TaskFormat is a file format for exporting and importing tasks. Each format
+// carries only some of a task's fields; imported tasks always get new IDs
+// and creation times, and IDs, lists, subtasks and blockers are not carried
+// over.
+type TaskFormat int32
+
+const (
+	// Same as TASK_FORMAT_JSON.
+	TaskFormat_TASK_FORMAT_UNSPECIFIED TaskFormat = 0
+	// A JSON array of Task objects in the protobuf JSON mapping. Text,
+	// completion, due time, priority, tags and recurrence are imported.
+	TaskFormat_TASK_FORMAT_JSON TaskFormat = 1
+	// CSV with a header row. The columns are text, completed (true or false),
+	// completed_at and due_at (RFC 3339, empty for none), priority (P0 to P3,
+	// empty for none), tags (separated by spaces) and recurrence. Only text is
+	// required on import; unknown columns are ignored.
+	TaskFormat_TASK_FORMAT_CSV TaskFormat = 2
+	// A Markdown checklist, one "- [ ] text" or "- [x] text" item per task.
+	// Blank lines and headings are skipped on import.
+	TaskFormat_TASK_FORMAT_MARKDOWN TaskFormat = 3
+	// The todo.txt format: "x" and the completion date for done tasks,
+	// priorities (A) to (D) for P0 to P3, "+tag" for each tag and "due:" with
+	// the due date in the server's time zone. Recurrence is not carried.
+	TaskFormat_TASK_FORMAT_TODO_TXT TaskFormat = 4
+)
+
+// Enum value maps for TaskFormat.
+var (
+	TaskFormat_name = map[int32]string{
+		0: "TASK_FORMAT_UNSPECIFIED",
+		1: "TASK_FORMAT_JSON",
+		2: "TASK_FORMAT_CSV",
+		3: "TASK_FORMAT_MARKDOWN",
+		4: "TASK_FORMAT_TODO_TXT",
+	}
+	TaskFormat_value = map[string]int32{
+		"TASK_FORMAT_UNSPECIFIED": 0,
+		"TASK_FORMAT_JSON":        1,
+		"TASK_FORMAT_CSV":         2,
+		"TASK_FORMAT_MARKDOWN":    3,
+		"TASK_FORMAT_TODO_TXT":    4,
+	}
+)
+
+func (x TaskFormat) Enum() *TaskFormat {
+	p := new(TaskFormat)
+	*p = x
+	return p
+}
+
+func (x TaskFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (TaskFormat) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[0]
+}
+
+func (x TaskFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskFormat.Descriptor instead.
+func (TaskFormat) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{0}
+}
+
+// What a user may do with a list and the tasks in it. Each role allows
+// everything the ones before it do.
+type ListRole int32
+
+const (
+	ListRole_LIST_ROLE_UNSPECIFIED ListRole = 0
+	// May read the list and its tasks.
+	ListRole_LIST_ROLE_VIEWER ListRole = 1
+	// May also add, change, move and delete tasks in the list.
+	ListRole_LIST_ROLE_EDITOR ListRole = 2
+	// May also rename, share and delete the list and purge its tasks.
+	ListRole_LIST_ROLE_OWNER ListRole = 3
+)
+
+// Enum value maps for ListRole.
+var (
+	ListRole_name = map[int32]string{
+		0: "LIST_ROLE_UNSPECIFIED",
+		1: "LIST_ROLE_VIEWER",
+		2: "LIST_ROLE_EDITOR",
+		3: "LIST_ROLE_OWNER",
+	}
+	ListRole_value = map[string]int32{
+		"LIST_ROLE_UNSPECIFIED": 0,
+		"LIST_ROLE_VIEWER":      1,
+		"LIST_ROLE_EDITOR":      2,
+		"LIST_ROLE_OWNER":       3,
+	}
+)
+
+func (x ListRole) Enum() *ListRole {
+	p := new(ListRole)
+	*p = x
+	return p
+}
+
+func (x ListRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (ListRole) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[1]
+}
+
+func (x ListRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListRole.Descriptor instead.
+func (ListRole) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1}
+}
+
+type TaskStatus int32
+
+const (
+	TaskStatus_TASK_STATUS_UNSPECIFIED TaskStatus = 0
+	TaskStatus_TASK_STATUS_OPEN        TaskStatus = 1
+	TaskStatus_TASK_STATUS_COMPLETED   TaskStatus = 2
+)
+
+// Enum value maps for TaskStatus.
+var (
+	TaskStatus_name = map[int32]string{
+		0: "TASK_STATUS_UNSPECIFIED",
+		1: "TASK_STATUS_OPEN",
+		2: "TASK_STATUS_COMPLETED",
+	}
+	TaskStatus_value = map[string]int32{
+		"TASK_STATUS_UNSPECIFIED": 0,
+		"TASK_STATUS_OPEN":        1,
+		"TASK_STATUS_COMPLETED":   2,
+	}
+)
+
+func (x TaskStatus) Enum() *TaskStatus {
+	p := new(TaskStatus)
+	*p = x
+	return p
+}
+
+func (x TaskStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (TaskStatus) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[2]
+}
+
+func (x TaskStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskStatus.Descriptor instead.
+func (TaskStatus) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{2}
+}
+
+type TaskOrder int32
+
+const (
+	TaskOrder_TASK_ORDER_UNSPECIFIED  TaskOrder = 0
+	TaskOrder_TASK_ORDER_NEWEST_FIRST TaskOrder = 1
+	TaskOrder_TASK_ORDER_OLDEST_FIRST TaskOrder = 2
+	// Case-insensitive by text.
+	TaskOrder_TASK_ORDER_ALPHABETICAL TaskOrder = 3
+	TaskOrder_TASK_ORDER_ID           TaskOrder = 4
+	// Manual order, as arranged with MoveTask.
+	TaskOrder_TASK_ORDER_POSITION TaskOrder = 5
+	// Most urgent first, tasks without a priority last; ties keep their
+	// manual order.
+	TaskOrder_TASK_ORDER_PRIORITY TaskOrder = 6
+)
+
+// Enum value maps for TaskOrder.
+var (
+	TaskOrder_name = map[int32]string{
+		0: "TASK_ORDER_UNSPECIFIED",
+		1: "TASK_ORDER_NEWEST_FIRST",
+		2: "TASK_ORDER_OLDEST_FIRST",
+		3: "TASK_ORDER_ALPHABETICAL",
+		4: "TASK_ORDER_ID",
+		5: "TASK_ORDER_POSITION",
+		6: "TASK_ORDER_PRIORITY",
+	}
+	TaskOrder_value = map[string]int32{
+		"TASK_ORDER_UNSPECIFIED":  0,
+		"TASK_ORDER_NEWEST_FIRST": 1,
+		"TASK_ORDER_OLDEST_FIRST": 2,
+		"TASK_ORDER_ALPHABETICAL": 3,
+		"TASK_ORDER_ID":           4,
+		"TASK_ORDER_POSITION":     5,
+		"TASK_ORDER_PRIORITY":     6,
+	}
+)
+
+func (x TaskOrder) Enum() *TaskOrder {
+	p := new(TaskOrder)
+	*p = x
+	return p
+}
+
+func (x TaskOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (TaskOrder) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[3]
+}
+
+func (x TaskOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskOrder.Descriptor instead.
+func (TaskOrder) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{3}
+}
+
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	// Most urgent.
+	Priority_PRIORITY_P0 Priority = 1
+	Priority_PRIORITY_P1 Priority = 2
+	Priority_PRIORITY_P2 Priority = 3
+	Priority_PRIORITY_P3 Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_P0",
+		2: "PRIORITY_P1",
+		3: "PRIORITY_P2",
+		4: "PRIORITY_P3",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_P0":          1,
+		"PRIORITY_P1":          2,
+		"PRIORITY_P2":          3,
+		"PRIORITY_P3":          4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[4].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[4]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{4}
+}
+
+type DueFilter int32
+
+const (
+	DueFilter_DUE_FILTER_UNSPECIFIED DueFilter = 0
+	// Open tasks whose due time has passed.
+	DueFilter_DUE_FILTER_OVERDUE DueFilter = 1
+	// Tasks due at any time today.
+	DueFilter_DUE_FILTER_DUE_TODAY DueFilter = 2
+	// Tasks due from now until due_within_seconds from now.
+	DueFilter_DUE_FILTER_DUE_WITHIN DueFilter = 3
+)
+
+// Enum value maps for DueFilter.
+var (
+	DueFilter_name = map[int32]string{
+		0: "DUE_FILTER_UNSPECIFIED",
+		1: "DUE_FILTER_OVERDUE",
+		2: "DUE_FILTER_DUE_TODAY",
+		3: "DUE_FILTER_DUE_WITHIN",
+	}
+	DueFilter_value = map[string]int32{
+		"DUE_FILTER_UNSPECIFIED": 0,
+		"DUE_FILTER_OVERDUE":     1,
+		"DUE_FILTER_DUE_TODAY":   2,
+		"DUE_FILTER_DUE_WITHIN":  3,
+	}
+)
+
+func (x DueFilter) Enum() *DueFilter {
+	p := new(DueFilter)
+	*p = x
+	return p
+}
+
+func (x DueFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DueFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[5].Descriptor()
+}
+
+func (DueFilter) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[5]
+}
+
+func (x DueFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DueFilter.Descriptor instead.
+func (DueFilter) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{5}
+}
+
+type ListDeletePolicy int32
+
+const (
+	// Refuses to delete a list that still has tasks.
+	ListDeletePolicy_LIST_DELETE_POLICY_UNSPECIFIED ListDeletePolicy = 0
+	// Deletes the list's tasks along with it.
+	ListDeletePolicy_LIST_DELETE_POLICY_CASCADE ListDeletePolicy = 1
+	// Moves the list's tasks to move_to_list_id.
+	ListDeletePolicy_LIST_DELETE_POLICY_MOVE ListDeletePolicy = 2
+)
+
+// Enum value maps for ListDeletePolicy.
+var (
+	ListDeletePolicy_name = map[int32]string{
+		0: "LIST_DELETE_POLICY_UNSPECIFIED",
+		1: "LIST_DELETE_POLICY_CASCADE",
+		2: "LIST_DELETE_POLICY_MOVE",
+	}
+	ListDeletePolicy_value = map[string]int32{
+		"LIST_DELETE_POLICY_UNSPECIFIED": 0,
+		"LIST_DELETE_POLICY_CASCADE":     1,
+		"LIST_DELETE_POLICY_MOVE":        2,
+	}
+)
+
+func (x ListDeletePolicy) Enum() *ListDeletePolicy {
+	p := new(ListDeletePolicy)
+	*p = x
+	return p
+}
+
+func (x ListDeletePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListDeletePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[6].Descriptor()
+}
+
+func (ListDeletePolicy) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[6]
+}
+
+func (x ListDeletePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListDeletePolicy.Descriptor instead.
+func (ListDeletePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{6}
+}
+
+type TaskView int32
+
+const (
+	TaskView_TASK_VIEW_UNSPECIFIED TaskView = 0
+	// Every matching task in GetTasksResponse.tasks.
+	TaskView_TASK_VIEW_FLAT TaskView = 1
+	// Matching tasks nested under their parents in GetTasksResponse.tree.
+	TaskView_TASK_VIEW_TREE TaskView = 2
+)
+
+// Enum value maps for TaskView.
+var (
+	TaskView_name = map[int32]string{
+		0: "TASK_VIEW_UNSPECIFIED",
+		1: "TASK_VIEW_FLAT",
+		2: "TASK_VIEW_TREE",
+	}
+	TaskView_value = map[string]int32{
+		"TASK_VIEW_UNSPECIFIED": 0,
+		"TASK_VIEW_FLAT":        1,
+		"TASK_VIEW_TREE":        2,
+	}
+)
+
+func (x TaskView) Enum() *TaskView {
+	p := new(TaskView)
+	*p = x
+	return p
+}
+
+func (x TaskView) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskView) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[7].Descriptor()
+}
+
+func (TaskView) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[7]
+}
+
+func (x TaskView) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskView.Descriptor instead.
+func (TaskView) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{7}
+}
+
+type SubtaskDeletePolicy int32
+
+const (
+	// Refuses to delete a task that still has subtasks.
+	SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED SubtaskDeletePolicy = 0
+	// Deletes every subtask, however deeply nested, along with the task.
+	SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE SubtaskDeletePolicy = 1
+	// Moves the task's direct subtasks up to the task's own parent.
+	SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_PROMOTE SubtaskDeletePolicy = 2
+)
+
+// Enum value maps for SubtaskDeletePolicy.
+var (
+	SubtaskDeletePolicy_name = map[int32]string{
+		0: "SUBTASK_DELETE_POLICY_UNSPECIFIED",
+		1: "SUBTASK_DELETE_POLICY_CASCADE",
+		2: "SUBTASK_DELETE_POLICY_PROMOTE",
+	}
+	SubtaskDeletePolicy_value = map[string]int32{
+		"SUBTASK_DELETE_POLICY_UNSPECIFIED": 0,
+		"SUBTASK_DELETE_POLICY_CASCADE":     1,
+		"SUBTASK_DELETE_POLICY_PROMOTE":     2,
+	}
+)
+
+func (x SubtaskDeletePolicy) Enum() *SubtaskDeletePolicy {
+	p := new(SubtaskDeletePolicy)
+	*p = x
+	return p
+}
+
+func (x SubtaskDeletePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubtaskDeletePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[8].Descriptor()
+}
+
+func (SubtaskDeletePolicy) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[8]
+}
+
+func (x SubtaskDeletePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubtaskDeletePolicy.Descriptor instead.
+func (SubtaskDeletePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{8}
+}
+
+type TaskEventType int32
+
+const (
+	TaskEventType_TASK_EVENT_TYPE_UNSPECIFIED TaskEventType = 0
+	TaskEventType_TASK_EVENT_TYPE_ADDED       TaskEventType = 1
+	TaskEventType_TASK_EVENT_TYPE_UPDATED     TaskEventType = 2
+	TaskEventType_TASK_EVENT_TYPE_DELETED     TaskEventType = 3
+	// Reminder that an open task has just come due.
+	TaskEventType_TASK_EVENT_TYPE_DUE TaskEventType = 4
+)
+
+// Enum value maps for TaskEventType.
+var (
+	TaskEventType_name = map[int32]string{
+		0: "TASK_EVENT_TYPE_UNSPECIFIED",
+		1: "TASK_EVENT_TYPE_ADDED",
+		2: "TASK_EVENT_TYPE_UPDATED",
+		3: "TASK_EVENT_TYPE_DELETED",
+		4: "TASK_EVENT_TYPE_DUE",
+	}
+	TaskEventType_value = map[string]int32{
+		"TASK_EVENT_TYPE_UNSPECIFIED": 0,
+		"TASK_EVENT_TYPE_ADDED":       1,
+		"TASK_EVENT_TYPE_UPDATED":     2,
+		"TASK_EVENT_TYPE_DELETED":     3,
+		"TASK_EVENT_TYPE_DUE":         4,
+	}
+)
+
+func (x TaskEventType) Enum() *TaskEventType {
+	p := new(TaskEventType)
+	*p = x
+	return p
+}
+
+func (x TaskEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[9].Descriptor()
+}
+
+func (TaskEventType) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[9]
+}
+
+func (x TaskEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskEventType.Descriptor instead.
+func (TaskEventType) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{9}
+}
+
+type HistoryEventType int32
+
+const (
+	HistoryEventType_HISTORY_EVENT_TYPE_UNSPECIFIED HistoryEventType = 0
+	HistoryEventType_HISTORY_EVENT_TYPE_CREATED     HistoryEventType = 1
+	HistoryEventType_HISTORY_EVENT_TYPE_UPDATED     HistoryEventType = 2
+	// Moved to the trash.
+	HistoryEventType_HISTORY_EVENT_TYPE_DELETED HistoryEventType = 3
+	// Brought back from the trash.
+	HistoryEventType_HISTORY_EVENT_TYPE_RESTORED HistoryEventType = 4
+	// Removed for good: purged from the trash, or an undone AddTask.
+	HistoryEventType_HISTORY_EVENT_TYPE_PURGED HistoryEventType = 5
+)
+
+// Enum value maps for HistoryEventType.
+var (
+	HistoryEventType_name = map[int32]string{
+		0: "HISTORY_EVENT_TYPE_UNSPECIFIED",
+		1: "HISTORY_EVENT_TYPE_CREATED",
+		2: "HISTORY_EVENT_TYPE_UPDATED",
+		3: "HISTORY_EVENT_TYPE_DELETED",
+		4: "HISTORY_EVENT_TYPE_RESTORED",
+		5: "HISTORY_EVENT_TYPE_PURGED",
+	}
+	HistoryEventType_value = map[string]int32{
+		"HISTORY_EVENT_TYPE_UNSPECIFIED": 0,
+		"HISTORY_EVENT_TYPE_CREATED":     1,
+		"HISTORY_EVENT_TYPE_UPDATED":     2,
+		"HISTORY_EVENT_TYPE_DELETED":     3,
+		"HISTORY_EVENT_TYPE_RESTORED":    4,
+		"HISTORY_EVENT_TYPE_PURGED":      5,
+	}
+)
+
+func (x HistoryEventType) Enum() *HistoryEventType {
+	p := new(HistoryEventType)
+	*p = x
+	return p
+}
+
+func (x HistoryEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HistoryEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_proto_enumTypes[10].Descriptor()
+}
+
+func (HistoryEventType) Type() protoreflect.EnumType {
+	return &file_todo_proto_enumTypes[10]
+}
+
+func (x HistoryEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryEventType.Descriptor instead.
+func (HistoryEventType) EnumDescriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{10}
+}
+
 type AddTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// List to add the task to; empty adds it to the inbox.
+	ListId string `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// Unix time the task is due; zero for none.
+	DueAt    int64    `protobuf:"varint,3,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Priority Priority `protobuf:"varint,4,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	Tags     []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Task to add this one under as a subtask; empty adds a top-level task.
+	// A subtask always lives in its parent's list, so list_id must be empty
+	// or name that list.
+	ParentId string `protobuf:"bytes,6,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Recurrence rule; see Task.recurrence. Requires due_at.
+	Recurrence    string `protobuf:"bytes,7,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTaskRequest) Reset() {
+	*x = AddTaskRequest{}
+	mi := &file_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTaskRequest) ProtoMessage() {}
+
+func (x *AddTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTaskRequest.ProtoReflect.Descriptor instead.
+func (*AddTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddTaskRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetDueAt() int64 {
+	if x != nil {
+		return x.DueAt
+	}
+	return 0
+}
+
+func (x *AddTaskRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *AddTaskRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddTaskRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *AddTaskRequest) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+type AddTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTaskResponse) Reset() {
+	*x = AddTaskResponse{}
+	mi := &file_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTaskResponse) ProtoMessage() {}
+
+func (x *AddTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTaskResponse.ProtoReflect.Descriptor instead.
+func (*AddTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type GetTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restricts the result to tasks in the given state. Unspecified returns
+	// tasks in any state.
+	Status TaskStatus `protobuf:"varint,1,opt,name=status,proto3,enum=todo.v1.TaskStatus" json:"status,omitempty"`
+	// Maximum number of tasks to return, capped at 1000. Zero returns every
+	// matching task.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response; resumes the listing after the
+	// last task of that page. Must be used with the same filters and order.
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Case-insensitive search over task text. Every word in the query must
+	// match the start of a word in the task, so "rep" finds "Weekly report".
+	Query string `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	// Only tasks created at or after this Unix time, if non-zero.
+	CreatedAfter int64 `protobuf:"varint,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Only tasks created before this Unix time, if non-zero.
+	CreatedBefore int64 `protobuf:"varint,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Defaults to newest first.
+	OrderBy TaskOrder `protobuf:"varint,7,opt,name=order_by,json=orderBy,proto3,enum=todo.v1.TaskOrder" json:"order_by,omitempty"`
+	// Only tasks in this list, if set. Empty returns tasks from every list,
+	// including the inbox.
+	ListId string `protobuf:"bytes,8,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// Only tasks whose due date falls in the given window.
+	DueFilter DueFilter `protobuf:"varint,9,opt,name=due_filter,json=dueFilter,proto3,enum=todo.v1.DueFilter" json:"due_filter,omitempty"`
+	// Length of the window for DUE_FILTER_DUE_WITHIN, in seconds.
+	DueWithinSeconds int64 `protobuf:"varint,10,opt,name=due_within_seconds,json=dueWithinSeconds,proto3" json:"due_within_seconds,omitempty"`
+	// IANA time zone, such as "Europe/Berlin", that decides where today starts
+	// and ends for DUE_FILTER_DUE_TODAY. Defaults to the server's time zone.
+	TimeZone string `protobuf:"bytes,11,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Only tasks with at least one of these tags, if any are given.
+	AnyTags []string `protobuf:"bytes,12,rep,name=any_tags,json=anyTags,proto3" json:"any_tags,omitempty"`
+	// Only tasks with every one of these tags, if any are given.
+	AllTags []string `protobuf:"bytes,13,rep,name=all_tags,json=allTags,proto3" json:"all_tags,omitempty"`
+	// Defaults to a flat list.
+	View TaskView `protobuf:"varint,14,opt,name=view,proto3,enum=todo.v1.TaskView" json:"view,omitempty"`
+	// Only open tasks whose blockers are all completed, if set.
+	Actionable    bool `protobuf:"varint,15,opt,name=actionable,proto3" json:"actionable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTasksRequest) Reset() {
+	*x = GetTasksRequest{}
+	mi := &file_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTasksRequest) ProtoMessage() {}
+
+func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTasksRequest.ProtoReflect.Descriptor instead.
+func (*GetTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTasksRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *GetTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *GetTasksRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *GetTasksRequest) GetOrderBy() TaskOrder {
+	if x != nil {
+		return x.OrderBy
+	}
+	return TaskOrder_TASK_ORDER_UNSPECIFIED
+}
+
+func (x *GetTasksRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetDueFilter() DueFilter {
+	if x != nil {
+		return x.DueFilter
+	}
+	return DueFilter_DUE_FILTER_UNSPECIFIED
+}
+
+func (x *GetTasksRequest) GetDueWithinSeconds() int64 {
+	if x != nil {
+		return x.DueWithinSeconds
+	}
+	return 0
+}
+
+func (x *GetTasksRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetAnyTags() []string {
+	if x != nil {
+		return x.AnyTags
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetAllTags() []string {
+	if x != nil {
+		return x.AllTags
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetView() TaskView {
+	if x != nil {
+		return x.View
+	}
+	return TaskView_TASK_VIEW_UNSPECIFIED
+}
+
+func (x *GetTasksRequest) GetActionable() bool {
+	if x != nil {
+		return x.Actionable
+	}
+	return false
+}
+
+type GetTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matching tasks, in a flat list. Empty for TASK_VIEW_TREE.
+	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Token for the following page; empty when there are no more tasks.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// The matching tasks nested under their parents, for TASK_VIEW_TREE. A
+	// task whose parent does not match the filters is returned at the top
+	// level. Pages count top-level nodes only.
+	Tree          []*TaskNode `protobuf:"bytes,3,rep,name=tree,proto3" json:"tree,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTasksResponse) Reset() {
+	*x = GetTasksResponse{}
+	mi := &file_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTasksResponse) ProtoMessage() {}
+
+func (x *GetTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTasksResponse.ProtoReflect.Descriptor instead.
+func (*GetTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *GetTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetTasksResponse) GetTree() []*TaskNode {
+	if x != nil {
+		return x.Tree
+	}
+	return nil
+}
+
+// A task and its subtasks, in the requested order.
+type TaskNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Subtasks      []*TaskNode            `protobuf:"bytes,2,rep,name=subtasks,proto3" json:"subtasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskNode) Reset() {
+	*x = TaskNode{}
+	mi := &file_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskNode) ProtoMessage() {}
+
+func (x *TaskNode) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskNode.ProtoReflect.Descriptor instead.
+func (*TaskNode) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaskNode) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskNode) GetSubtasks() []*TaskNode {
+	if x != nil {
+		return x.Subtasks
+	}
+	return nil
+}
+
+type DeleteTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// If set, the task is only deleted when it belongs to this list.
+	ListId string `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// What to do with the task's subtasks.
+	SubtaskPolicy SubtaskDeletePolicy `protobuf:"varint,3,opt,name=subtask_policy,json=subtaskPolicy,proto3,enum=todo.v1.SubtaskDeletePolicy" json:"subtask_policy,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteTaskRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *DeleteTaskRequest) GetSubtaskPolicy() SubtaskDeletePolicy {
+	if x != nil {
+		return x.SubtaskPolicy
+	}
+	return SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED
+}
+
+func (x *DeleteTaskRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskResponse) Reset() {
+	*x = DeleteTaskResponse{}
+	mi := &file_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskResponse) ProtoMessage() {}
+
+func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type UpdateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Carries the new values for the fields named in update_mask.
+	Task *Task `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	// Paths of the Task fields to overwrite. Supported paths: "text",
+	// "list_id" (empty moves the task to the inbox), "due_at" (zero clears
+	// the due date), "priority", "tags" (replaces every tag), "parent_id"
+	// (empty makes the task top-level) and "recurrence" (empty stops the task
+	// from repeating). Use MoveTask to change the position.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskResponse) Reset() {
+	*x = UpdateTaskResponse{}
+	mi := &file_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskResponse) ProtoMessage() {}
+
+func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type CompleteTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CompleteTaskRequest) Reset() {
+	*x = CompleteTaskRequest{}
+	mi := &file_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteTaskRequest) ProtoMessage() {}
+
+func (x *CompleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*CompleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CompleteTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CompleteTaskRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type CompleteTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteTaskResponse) Reset() {
+	*x = CompleteTaskResponse{}
+	mi := &file_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteTaskResponse) ProtoMessage() {}
+
+func (x *CompleteTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteTaskResponse.ProtoReflect.Descriptor instead.
+func (*CompleteTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CompleteTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type ReopenTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReopenTaskRequest) Reset() {
+	*x = ReopenTaskRequest{}
+	mi := &file_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReopenTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenTaskRequest) ProtoMessage() {}
+
+func (x *ReopenTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenTaskRequest.ProtoReflect.Descriptor instead.
+func (*ReopenTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReopenTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReopenTaskRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ReopenTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReopenTaskResponse) Reset() {
+	*x = ReopenTaskResponse{}
+	mi := &file_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReopenTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenTaskResponse) ProtoMessage() {}
+
+func (x *ReopenTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenTaskResponse.ProtoReflect.Descriptor instead.
+func (*ReopenTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReopenTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{13}
+}
+
+type WatchTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *TaskEvent             `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksResponse) Reset() {
+	*x = WatchTasksResponse{}
+	mi := &file_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksResponse) ProtoMessage() {}
+
+func (x *WatchTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksResponse.ProtoReflect.Descriptor instead.
+func (*WatchTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchTasksResponse) GetEvent() *TaskEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type TaskEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  TaskEventType          `protobuf:"varint,1,opt,name=type,proto3,enum=todo.v1.TaskEventType" json:"type,omitempty"`
+	// The task after the change. Deletion events carry the task as it was
+	// just before it was removed.
+	Task          *Task `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	OccurredAt    int64 `protobuf:"varint,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TaskEvent) GetType() TaskEventType {
+	if x != nil {
+		return x.Type
+	}
+	return TaskEventType_TASK_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+// HistoryEvent is an immutable record of a change to a task. Replaying every
+// event in seq order rebuilds the live and trashed tasks.
+type HistoryEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Increases by one with every event the server records.
+	Seq  int64            `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type HistoryEventType `protobuf:"varint,2,opt,name=type,proto3,enum=todo.v1.HistoryEventType" json:"type,omitempty"`
+	// The task after the change. Purge events carry only the task's id,
+	// owner_id and list_id, and replace the earlier events of the task.
+	Task *Task `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`
+	// User who made the change; empty for changes the server made on its own,
+	// such as emptying the trash, and when authentication is disabled.
+	Actor         string `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
+	OccurredAt    int64  `protobuf:"varint,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEvent) Reset() {
+	*x = HistoryEvent{}
+	mi := &file_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEvent) ProtoMessage() {}
+
+func (x *HistoryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEvent.ProtoReflect.Descriptor instead.
+func (*HistoryEvent) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HistoryEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *HistoryEvent) GetType() HistoryEventType {
+	if x != nil {
+		return x.Type
+	}
+	return HistoryEventType_HISTORY_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *HistoryEvent) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *HistoryEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+type GetTaskHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskHistoryRequest) Reset() {
+	*x = GetTaskHistoryRequest{}
+	mi := &file_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskHistoryRequest) ProtoMessage() {}
+
+func (x *GetTaskHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetTaskHistoryRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type GetTaskHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*HistoryEvent        `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskHistoryResponse) Reset() {
+	*x = GetTaskHistoryResponse{}
+	mi := &file_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskHistoryResponse) ProtoMessage() {}
+
+func (x *GetTaskHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTaskHistoryResponse) GetEvents() []*HistoryEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ListEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only events that occurred at or after this Unix time, if set.
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	// Only events that occurred before this Unix time, if set.
+	Until int64 `protobuf:"varint,2,opt,name=until,proto3" json:"until,omitempty"`
+	// Maximum number of events to return; zero returns all of them, values
+	// above 1000 are reduced to 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response; resumes the listing after the
+	// last event of that page.
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListEventsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*HistoryEvent        `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Token for the following page; empty when there are no more events.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListEventsResponse) GetEvents() []*HistoryEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Task struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text      string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Completed bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	// Unix time the task was marked done; zero while the task is open.
+	CompletedAt int64 `protobuf:"varint,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// List the task belongs to; empty for the inbox.
+	ListId string `protobuf:"bytes,6,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	// User who created the task. Set by the server; empty when authentication
+	// is disabled.
+	OwnerId string `protobuf:"bytes,7,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// Unix time the task is due; zero for none.
+	DueAt    int64    `protobuf:"varint,8,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Priority Priority `protobuf:"varint,9,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Rank of the task in the owner's manual order. Positions compare as
+	// strings; they are assigned by the server, new tasks going last.
+	Position string `protobuf:"bytes,10,opt,name=position,proto3" json:"position,omitempty"`
+	// Normalized tag names, sorted and without duplicates.
+	Tags []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Task this one is a subtask of; empty for a top-level task.
+	ParentId string `protobuf:"bytes,12,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// IDs of the tasks that must be completed before this one can start,
+	// sorted. Deleting a blocker removes it from the list.
+	BlockedBy []string `protobuf:"bytes,13,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	// RFC 5545 RRULE subset saying how the task repeats, such as
+	// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH": FREQ is DAILY, WEEKLY or MONTHLY,
+	// INTERVAL defaults to 1 and BYDAY is only allowed with WEEKLY. Empty for
+	// a task that does not repeat. Once the task is completed or comes due, the
+	// server adds its next instance, due at the rule's next occurrence in the
+	// server's time zone, and moves the rule onto it. Stored in canonical form.
+	Recurrence string `protobuf:"bytes,14,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// Unix time the task was moved to the trash; zero for a live task.
+	DeletedAt int64 `protobuf:"varint,15,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Starts at 1 and goes up every time the task changes, including when it
+	// is restored from the trash or a change to it is undone. Pass it as the
+	// expected_version of a mutating call to make the call fail if the task
+	// has changed since it was read.
+	Version       int64 `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Task) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Task) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Task) GetCompletedAt() int64 {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return 0
+}
+
+func (x *Task) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *Task) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Task) GetDueAt() int64 {
+	if x != nil {
+		return x.DueAt
+	}
+	return 0
+}
+
+func (x *Task) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Task) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Task) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *Task) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Task) GetDeletedAt() int64 {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return 0
+}
+
+func (x *Task) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type MoveTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Exactly one of before_id and after_id names the task to place this one
+	// next to.
+	BeforeId string `protobuf:"bytes,2,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"`
+	AfterId  string `protobuf:"bytes,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MoveTaskRequest) Reset() {
+	*x = MoveTaskRequest{}
+	mi := &file_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskRequest) ProtoMessage() {}
+
+func (x *MoveTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskRequest.ProtoReflect.Descriptor instead.
+func (*MoveTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MoveTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetBeforeId() string {
+	if x != nil {
+		return x.BeforeId
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *MoveTaskRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type MoveTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTaskResponse) Reset() {
+	*x = MoveTaskResponse{}
+	mi := &file_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTaskResponse) ProtoMessage() {}
+
+func (x *MoveTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTaskResponse.ProtoReflect.Descriptor instead.
+func (*MoveTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MoveTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type AddTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Tags to add; tags the task already has are ignored.
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddTagsRequest) Reset() {
+	*x = AddTagsRequest{}
+	mi := &file_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTagsRequest) ProtoMessage() {}
+
+func (x *AddTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTagsRequest.ProtoReflect.Descriptor instead.
+func (*AddTagsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AddTagsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AddTagsRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AddTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTagsResponse) Reset() {
+	*x = AddTagsResponse{}
+	mi := &file_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTagsResponse) ProtoMessage() {}
+
+func (x *AddTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTagsResponse.ProtoReflect.Descriptor instead.
+func (*AddTagsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AddTagsResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type RemoveTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Tags to remove; tags the task does not have are ignored.
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveTagsRequest) Reset() {
+	*x = RemoveTagsRequest{}
+	mi := &file_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTagsRequest) ProtoMessage() {}
+
+func (x *RemoveTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTagsRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTagsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RemoveTagsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RemoveTagsRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTagsResponse) Reset() {
+	*x = RemoveTagsResponse{}
+	mi := &file_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTagsResponse) ProtoMessage() {}
+
+func (x *RemoveTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTagsResponse.ProtoReflect.Descriptor instead.
+func (*RemoveTagsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RemoveTagsResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type ListTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only count tasks in this list, if set.
+	ListId        string `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListTagsRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+type ListTagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sorted by name.
+	Tags          []*TagCount `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListTagsResponse) GetTags() []*TagCount {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type TagCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of tasks, open or completed, that carry the tag.
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagCount) Reset() {
+	*x = TagCount{}
+	mi := &file_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagCount) ProtoMessage() {}
+
+func (x *TagCount) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagCount.ProtoReflect.Descriptor instead.
+func (*TagCount) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TagCount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TagCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AddBlockerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task that has to wait.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The task it waits for. Adding a blocker the task already has does
+	// nothing; one that would make the task wait on itself is refused.
+	BlockerId string `protobuf:"bytes,2,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddBlockerRequest) Reset() {
+	*x = AddBlockerRequest{}
+	mi := &file_todo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBlockerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBlockerRequest) ProtoMessage() {}
+
+func (x *AddBlockerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBlockerRequest.ProtoReflect.Descriptor instead.
+func (*AddBlockerRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AddBlockerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddBlockerRequest) GetBlockerId() string {
+	if x != nil {
+		return x.BlockerId
+	}
+	return ""
+}
+
+func (x *AddBlockerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AddBlockerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBlockerResponse) Reset() {
+	*x = AddBlockerResponse{}
+	mi := &file_todo_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBlockerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBlockerResponse) ProtoMessage() {}
+
+func (x *AddBlockerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBlockerResponse.ProtoReflect.Descriptor instead.
+func (*AddBlockerResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AddBlockerResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type RemoveBlockerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Removing a blocker the task does not have does nothing.
+	BlockerId string `protobuf:"bytes,2,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	// If non-zero, the call fails with ABORTED unless the task is at this
+	// version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveBlockerRequest) Reset() {
+	*x = RemoveBlockerRequest{}
+	mi := &file_todo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveBlockerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveBlockerRequest) ProtoMessage() {}
+
+func (x *RemoveBlockerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveBlockerRequest.ProtoReflect.Descriptor instead.
+func (*RemoveBlockerRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RemoveBlockerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveBlockerRequest) GetBlockerId() string {
+	if x != nil {
+		return x.BlockerId
+	}
+	return ""
+}
+
+func (x *RemoveBlockerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveBlockerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveBlockerResponse) Reset() {
+	*x = RemoveBlockerResponse{}
+	mi := &file_todo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveBlockerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveBlockerResponse) ProtoMessage() {}
+
+func (x *RemoveBlockerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveBlockerResponse.ProtoReflect.Descriptor instead.
+func (*RemoveBlockerResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RemoveBlockerResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_todo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{35}
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_todo_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListTrashResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type RestoreTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTaskRequest) Reset() {
+	*x = RestoreTaskRequest{}
+	mi := &file_todo_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTaskRequest) ProtoMessage() {}
+
+func (x *RestoreTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTaskRequest.ProtoReflect.Descriptor instead.
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RestoreTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RestoreTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The restored task. If its parent or list is gone it comes back at the
+	// top level or in the inbox, and blockers that are gone are dropped.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTaskResponse) Reset() {
+	*x = RestoreTaskResponse{}
+	mi := &file_todo_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTaskResponse) ProtoMessage() {}
+
+func (x *RestoreTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTaskResponse.ProtoReflect.Descriptor instead.
+func (*RestoreTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RestoreTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type PurgeTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of a trashed task.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeTaskRequest) Reset() {
+	*x = PurgeTaskRequest{}
+	mi := &file_todo_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeTaskRequest) ProtoMessage() {}
+
+func (x *PurgeTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeTaskRequest.ProtoReflect.Descriptor instead.
+func (*PurgeTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PurgeTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PurgeTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeTaskResponse) Reset() {
+	*x = PurgeTaskResponse{}
+	mi := &file_todo_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeTaskResponse) ProtoMessage() {}
+
+func (x *PurgeTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeTaskResponse.ProtoReflect.Descriptor instead.
+func (*PurgeTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{40}
+}
+
+type UndoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoRequest) Reset() {
+	*x = UndoRequest{}
+	mi := &file_todo_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoRequest) ProtoMessage() {}
+
+func (x *UndoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndoRequest.ProtoReflect.Descriptor instead.
+func (*UndoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{41}
+}
+
+type UndoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tasks the undo put back, as they are now.
+	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// IDs of the tasks removed because the undone operation added them.
+	RemovedIds    []string `protobuf:"bytes,2,rep,name=removed_ids,json=removedIds,proto3" json:"removed_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoResponse) Reset() {
+	*x = UndoResponse{}
+	mi := &file_todo_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoResponse) ProtoMessage() {}
+
+func (x *UndoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndoResponse.ProtoReflect.Descriptor instead.
+func (*UndoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *UndoResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *UndoResponse) GetRemovedIds() []string {
+	if x != nil {
+		return x.RemovedIds
+	}
+	return nil
+}
+
+type BatchAddTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 100 requests.
+	Requests      []*AddTaskRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAddTasksRequest) Reset() {
+	*x = BatchAddTasksRequest{}
+	mi := &file_todo_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAddTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAddTasksRequest) ProtoMessage() {}
+
+func (x *BatchAddTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAddTasksRequest.ProtoReflect.Descriptor instead.
+func (*BatchAddTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BatchAddTasksRequest) GetRequests() []*AddTaskRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type BatchAddTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The added tasks, in request order.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAddTasksResponse) Reset() {
+	*x = BatchAddTasksResponse{}
+	mi := &file_todo_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAddTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAddTasksResponse) ProtoMessage() {}
+
+func (x *BatchAddTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAddTasksResponse.ProtoReflect.Descriptor instead.
+func (*BatchAddTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *BatchAddTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type BatchUpdateTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 100 requests. A task updated more than once sees the earlier
+	// updates applied.
+	Requests      []*UpdateTaskRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateTasksRequest) Reset() {
+	*x = BatchUpdateTasksRequest{}
+	mi := &file_todo_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateTasksRequest) ProtoMessage() {}
+
+func (x *BatchUpdateTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateTasksRequest.ProtoReflect.Descriptor instead.
+func (*BatchUpdateTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *BatchUpdateTasksRequest) GetRequests() []*UpdateTaskRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type BatchUpdateTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task each request left behind, in request order.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateTasksResponse) Reset() {
+	*x = BatchUpdateTasksResponse{}
+	mi := &file_todo_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateTasksResponse) ProtoMessage() {}
+
+func (x *BatchUpdateTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateTasksResponse.ProtoReflect.Descriptor instead.
+func (*BatchUpdateTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *BatchUpdateTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type BatchDeleteTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 100 requests.
+	Requests      []*DeleteTaskRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteTasksRequest) Reset() {
+	*x = BatchDeleteTasksRequest{}
+	mi := &file_todo_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteTasksRequest) ProtoMessage() {}
+
+func (x *BatchDeleteTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteTasksRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *BatchDeleteTasksRequest) GetRequests() []*DeleteTaskRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type BatchDeleteTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteTasksResponse) Reset() {
+	*x = BatchDeleteTasksResponse{}
+	mi := &file_todo_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteTasksResponse) ProtoMessage() {}
+
+func (x *BatchDeleteTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteTasksResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{48}
+}
+
+// BatchItemError is the error detail describing a failed request of a batch.
+// Requests that fail validation are all reported; otherwise only the first
+// request that failed is.
+type BatchItemError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the request in the batch, from zero.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// The Connect error code the request would have failed with on its own,
+	// such as "not_found".
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemError) Reset() {
+	*x = BatchItemError{}
+	mi := &file_todo_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemError) ProtoMessage() {}
+
+func (x *BatchItemError) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemError.ProtoReflect.Descriptor instead.
+func (*BatchItemError) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BatchItemError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItemError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BatchItemError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExportTasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format TaskFormat             `protobuf:"varint,1,opt,name=format,proto3,enum=todo.v1.TaskFormat" json:"format,omitempty"`
+	// Only export this list's tasks, if set.
+	ListId        string     `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Status        TaskStatus `protobuf:"varint,3,opt,name=status,proto3,enum=todo.v1.TaskStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTasksRequest) Reset() {
+	*x = ExportTasksRequest{}
+	mi := &file_todo_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTasksRequest) ProtoMessage() {}
+
+func (x *ExportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ExportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ExportTasksRequest) GetFormat() TaskFormat {
+	if x != nil {
+		return x.Format
+	}
+	return TaskFormat_TASK_FORMAT_UNSPECIFIED
+}
+
+func (x *ExportTasksRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *ExportTasksRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+type ExportTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTasksResponse) Reset() {
+	*x = ExportTasksResponse{}
+	mi := &file_todo_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTasksResponse) ProtoMessage() {}
+
+func (x *ExportTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksResponse.ProtoReflect.Descriptor instead.
+func (*ExportTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ExportTasksResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ImportTasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format TaskFormat             `protobuf:"varint,1,opt,name=format,proto3,enum=todo.v1.TaskFormat" json:"format,omitempty"`
+	// At most 1000 tasks.
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// List to add the tasks to; empty for the inbox.
+	ListId        string `protobuf:"bytes,3,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksRequest) Reset() {
+	*x = ImportTasksRequest{}
+	mi := &file_todo_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksRequest) ProtoMessage() {}
+
+func (x *ImportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ImportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ImportTasksRequest) GetFormat() TaskFormat {
+	if x != nil {
+		return x.Format
+	}
+	return TaskFormat_TASK_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportTasksRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ImportTasksRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+type ImportTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The added tasks, in file order.
+	Tasks         []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksResponse) Reset() {
+	*x = ImportTasksResponse{}
+	mi := &file_todo_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksResponse) ProtoMessage() {}
+
+func (x *ImportTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksResponse.ProtoReflect.Descriptor instead.
+func (*ImportTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ImportTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// ImportLineError is the error detail describing an invalid line of an
+// import.
+type ImportLineError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Line number, counting from 1. For JSON it is the position of the task in
+	// the array instead.
+	Line          int32  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportLineError) Reset() {
+	*x = ImportLineError{}
+	mi := &file_todo_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportLineError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportLineError) ProtoMessage() {}
+
+func (x *ImportLineError) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportLineError.ProtoReflect.Descriptor instead.
+func (*ImportLineError) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ImportLineError) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportLineError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// RetryInfo is the error detail of RESOURCE_EXHAUSTED errors, saying which
+// limit the call ran into and when retrying it may succeed.
+type RetryInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The limit that was reached: "requests" for the rate limit, or "tasks",
+	// "tasks_per_owner" or "tasks_per_list" for the caps on stored tasks.
+	Limit string `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// How long to wait before retrying, in milliseconds. Zero when waiting
+	// alone will not help: a task cap only frees up as tasks are purged.
+	RetryAfterMs  int64 `protobuf:"varint,2,opt,name=retry_after_ms,json=retryAfterMs,proto3" json:"retry_after_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryInfo) Reset() {
+	*x = RetryInfo{}
+	mi := &file_todo_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryInfo) ProtoMessage() {}
+
+func (x *RetryInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryInfo.ProtoReflect.Descriptor instead.
+func (*RetryInfo) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RetryInfo) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *RetryInfo) GetRetryAfterMs() int64 {
+	if x != nil {
+		return x.RetryAfterMs
+	}
+	return 0
+}
+
+type CreateListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateListRequest) Reset() {
+	*x = CreateListRequest{}
+	mi := &file_todo_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateListRequest) ProtoMessage() {}
+
+func (x *CreateListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateListRequest.ProtoReflect.Descriptor instead.
+func (*CreateListRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreateListRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          *List                  `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateListResponse) Reset() {
+	*x = CreateListResponse{}
+	mi := &file_todo_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateListResponse) ProtoMessage() {}
+
+func (x *CreateListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateListResponse.ProtoReflect.Descriptor instead.
+func (*CreateListResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CreateListResponse) GetList() *List {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type GetListsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetListsRequest) Reset() {
+	*x = GetListsRequest{}
+	mi := &file_todo_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetListsRequest) ProtoMessage() {}
+
+func (x *GetListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetListsRequest.ProtoReflect.Descriptor instead.
+func (*GetListsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{58}
+}
+
+type GetListsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every list the caller has a role on, oldest first. The inbox is implicit
+	// and not included.
+	Lists         []*List `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetListsResponse) Reset() {
+	*x = GetListsResponse{}
+	mi := &file_todo_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetListsResponse) ProtoMessage() {}
+
+func (x *GetListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetListsResponse.ProtoReflect.Descriptor instead.
+func (*GetListsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetListsResponse) GetLists() []*List {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+type RenameListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameListRequest) Reset() {
+	*x = RenameListRequest{}
+	mi := &file_todo_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameListRequest) ProtoMessage() {}
+
+func (x *RenameListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameListRequest.ProtoReflect.Descriptor instead.
+func (*RenameListRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *RenameListRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameListRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          *List                  `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameListResponse) Reset() {
+	*x = RenameListResponse{}
+	mi := &file_todo_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameListResponse) ProtoMessage() {}
+
+func (x *RenameListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameListResponse.ProtoReflect.Descriptor instead.
+func (*RenameListResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *RenameListResponse) GetList() *List {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type DeleteListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// What to do with the tasks still in the list.
+	Policy ListDeletePolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=todo.v1.ListDeletePolicy" json:"policy,omitempty"`
+	// Destination for the tasks under LIST_DELETE_POLICY_MOVE; empty moves
+	// them to the inbox.
+	MoveToListId  string `protobuf:"bytes,3,opt,name=move_to_list_id,json=moveToListId,proto3" json:"move_to_list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteListRequest) Reset() {
+	*x = DeleteListRequest{}
+	mi := &file_todo_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteListRequest) ProtoMessage() {}
+
+func (x *DeleteListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteListRequest.ProtoReflect.Descriptor instead.
+func (*DeleteListRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DeleteListRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteListRequest) GetPolicy() ListDeletePolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return ListDeletePolicy_LIST_DELETE_POLICY_UNSPECIFIED
+}
+
+func (x *DeleteListRequest) GetMoveToListId() string {
+	if x != nil {
+		return x.MoveToListId
+	}
+	return ""
+}
+
+type DeleteListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteListResponse) Reset() {
+	*x = DeleteListResponse{}
+	mi := &file_todo_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteListResponse) ProtoMessage() {}
+
+func (x *DeleteListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteListResponse.ProtoReflect.Descriptor instead.
+func (*DeleteListResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DeleteListResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ShareListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          ListRole               `protobuf:"varint,3,opt,name=role,proto3,enum=todo.v1.ListRole" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareListRequest) Reset() {
+	*x = ShareListRequest{}
+	mi := &file_todo_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareListRequest) ProtoMessage() {}
+
+func (x *ShareListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareListRequest.ProtoReflect.Descriptor instead.
+func (*ShareListRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ShareListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *ShareListRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ShareListRequest) GetRole() ListRole {
+	if x != nil {
+		return x.Role
+	}
+	return ListRole_LIST_ROLE_UNSPECIFIED
+}
+
+type ShareListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          *List                  `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareListResponse) Reset() {
+	*x = ShareListResponse{}
+	mi := &file_todo_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareListResponse) ProtoMessage() {}
+
+func (x *ShareListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareListResponse.ProtoReflect.Descriptor instead.
+func (*ShareListResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ShareListResponse) GetList() *List {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type RevokeListAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeListAccessRequest) Reset() {
+	*x = RevokeListAccessRequest{}
+	mi := &file_todo_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeListAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeListAccessRequest) ProtoMessage() {}
+
+func (x *RevokeListAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeListAccessRequest.ProtoReflect.Descriptor instead.
+func (*RevokeListAccessRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *RevokeListAccessRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *RevokeListAccessRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RevokeListAccessResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	List          *List                  `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeListAccessResponse) Reset() {
+	*x = RevokeListAccessResponse{}
+	mi := &file_todo_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeListAccessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeListAccessResponse) ProtoMessage() {}
+
+func (x *RevokeListAccessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeListAccessResponse.ProtoReflect.Descriptor instead.
+func (*RevokeListAccessResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *RevokeListAccessResponse) GetList() *List {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+// A named group of tasks, such as a project.
+type List struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unique among the owner's lists, ignoring case.
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// User who created the list. Set by the server. The creator always has
+	// the owner role.
+	OwnerId string `protobuf:"bytes,4,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// Users the list is shared with, by user ID.
+	Members       []*ListMember `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddTaskRequest) Reset() {
-	*x = AddTaskRequest{}
-	mi := &file_todo_proto_msgTypes[0]
+func (x *List) Reset() {
+	*x = List{}
+	mi := &file_todo_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddTaskRequest) String() string {
+func (x *List) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddTaskRequest) ProtoMessage() {}
+func (*List) ProtoMessage() {}
 
-func (x *AddTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[0]
+func (x *List) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -53,40 +4440,70 @@ func (x *AddTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddTaskRequest.ProtoReflect.Descriptor instead.
-func (*AddTaskRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use List.ProtoReflect.Descriptor instead.
+func (*List) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *AddTaskRequest) GetText() string {
+func (x *List) GetId() string {
 	if x != nil {
-		return x.Text
+		return x.Id
 	}
 	return ""
 }
 
-type AddTaskResponse struct {
+func (x *List) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *List) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *List) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *List) GetMembers() []*ListMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// A user's access to a list shared with them.
+type ListMember struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          ListRole               `protobuf:"varint,2,opt,name=role,proto3,enum=todo.v1.ListRole" json:"role,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddTaskResponse) Reset() {
-	*x = AddTaskResponse{}
-	mi := &file_todo_proto_msgTypes[1]
+func (x *ListMember) Reset() {
+	*x = ListMember{}
+	mi := &file_todo_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddTaskResponse) String() string {
+func (x *ListMember) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddTaskResponse) ProtoMessage() {}
+func (*ListMember) ProtoMessage() {}
 
-func (x *AddTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[1]
+func (x *ListMember) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -97,39 +4514,58 @@ func (x *AddTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddTaskResponse.ProtoReflect.Descriptor instead.
-func (*AddTaskResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ListMember.ProtoReflect.Descriptor instead.
+func (*ListMember) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *AddTaskResponse) GetTask() *Task {
+func (x *ListMember) GetUserId() string {
 	if x != nil {
-		return x.Task
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-type GetTasksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *ListMember) GetRole() ListRole {
+	if x != nil {
+		return x.Role
+	}
+	return ListRole_LIST_ROLE_UNSPECIFIED
+}
+
+// A workspace is an isolated set of tasks and lists, such as a team's.
+type Workspace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lower-case letters, digits and dashes, starting with a letter or digit,
+	// at most 63 characters.
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Limit on the length of task text in bytes; zero for the server's.
+	MaxTaskTextLength int32 `protobuf:"varint,4,opt,name=max_task_text_length,json=maxTaskTextLength,proto3" json:"max_task_text_length,omitempty"`
+	// Limit on the tasks in the workspace, trashed ones included; zero for
+	// none. AddTask and the calls that add tasks fail with RESOURCE_EXHAUSTED
+	// once it is reached.
+	MaxTasks      int32 `protobuf:"varint,5,opt,name=max_tasks,json=maxTasks,proto3" json:"max_tasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTasksRequest) Reset() {
-	*x = GetTasksRequest{}
-	mi := &file_todo_proto_msgTypes[2]
+func (x *Workspace) Reset() {
+	*x = Workspace{}
+	mi := &file_todo_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTasksRequest) String() string {
+func (x *Workspace) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTasksRequest) ProtoMessage() {}
+func (*Workspace) ProtoMessage() {}
 
-func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[2]
+func (x *Workspace) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -140,33 +4576,69 @@ func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTasksRequest.ProtoReflect.Descriptor instead.
-func (*GetTasksRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Workspace.ProtoReflect.Descriptor instead.
+func (*Workspace) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{70}
 }
 
-type GetTasksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+func (x *Workspace) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Workspace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workspace) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Workspace) GetMaxTaskTextLength() int32 {
+	if x != nil {
+		return x.MaxTaskTextLength
+	}
+	return 0
+}
+
+func (x *Workspace) GetMaxTasks() int32 {
+	if x != nil {
+		return x.MaxTasks
+	}
+	return 0
+}
+
+type CreateWorkspaceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The new workspace. created_at is set by the server.
+	Workspace     *Workspace `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTasksResponse) Reset() {
-	*x = GetTasksResponse{}
-	mi := &file_todo_proto_msgTypes[3]
+func (x *CreateWorkspaceRequest) Reset() {
+	*x = CreateWorkspaceRequest{}
+	mi := &file_todo_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTasksResponse) String() string {
+func (x *CreateWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTasksResponse) ProtoMessage() {}
+func (*CreateWorkspaceRequest) ProtoMessage() {}
 
-func (x *GetTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[3]
+func (x *CreateWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -177,40 +4649,40 @@ func (x *GetTasksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTasksResponse.ProtoReflect.Descriptor instead.
-func (*GetTasksResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use CreateWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *GetTasksResponse) GetTasks() []*Task {
+func (x *CreateWorkspaceRequest) GetWorkspace() *Workspace {
 	if x != nil {
-		return x.Tasks
+		return x.Workspace
 	}
 	return nil
 }
 
-type DeleteTaskRequest struct {
+type CreateWorkspaceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Workspace     *Workspace             `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTaskRequest) Reset() {
-	*x = DeleteTaskRequest{}
-	mi := &file_todo_proto_msgTypes[4]
+func (x *CreateWorkspaceResponse) Reset() {
+	*x = CreateWorkspaceResponse{}
+	mi := &file_todo_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTaskRequest) String() string {
+func (x *CreateWorkspaceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTaskRequest) ProtoMessage() {}
+func (*CreateWorkspaceResponse) ProtoMessage() {}
 
-func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[4]
+func (x *CreateWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -221,40 +4693,39 @@ func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
-func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use CreateWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *DeleteTaskRequest) GetId() string {
+func (x *CreateWorkspaceResponse) GetWorkspace() *Workspace {
 	if x != nil {
-		return x.Id
+		return x.Workspace
 	}
-	return ""
+	return nil
 }
 
-type DeleteTaskResponse struct {
+type ListWorkspacesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTaskResponse) Reset() {
-	*x = DeleteTaskResponse{}
-	mi := &file_todo_proto_msgTypes[5]
+func (x *ListWorkspacesRequest) Reset() {
+	*x = ListWorkspacesRequest{}
+	mi := &file_todo_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTaskResponse) String() string {
+func (x *ListWorkspacesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTaskResponse) ProtoMessage() {}
+func (*ListWorkspacesRequest) ProtoMessage() {}
 
-func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[5]
+func (x *ListWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,42 +4736,77 @@ func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
-func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *DeleteTaskResponse) GetSuccess() bool {
+type ListWorkspacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspaces    []*Workspace           `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesResponse) Reset() {
+	*x = ListWorkspacesResponse{}
+	mi := &file_todo_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesResponse) ProtoMessage() {}
+
+func (x *ListWorkspacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[74]
 	if x != nil {
-		return x.Success
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-type Task struct {
+// Deprecated: Use ListWorkspacesResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ListWorkspacesResponse) GetWorkspaces() []*Workspace {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+type DeleteWorkspaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Task) Reset() {
-	*x = Task{}
-	mi := &file_todo_proto_msgTypes[6]
+func (x *DeleteWorkspaceRequest) Reset() {
+	*x = DeleteWorkspaceRequest{}
+	mi := &file_todo_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Task) String() string {
+func (x *DeleteWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Task) ProtoMessage() {}
+func (*DeleteWorkspaceRequest) ProtoMessage() {}
 
-func (x *Task) ProtoReflect() protoreflect.Message {
-	mi := &file_todo_proto_msgTypes[6]
+func (x *DeleteWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -311,30 +4817,52 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Task.ProtoReflect.Descriptor instead.
-func (*Task) Descriptor() ([]byte, []int) {
-	return file_todo_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use DeleteWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *Task) GetId() string {
+func (x *DeleteWorkspaceRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *Task) GetText() string {
-	if x != nil {
-		return x.Text
-	}
-	return ""
+type DeleteWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Task) GetCreatedAt() int64 {
+func (x *DeleteWorkspaceResponse) Reset() {
+	*x = DeleteWorkspaceResponse{}
+	mi := &file_todo_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWorkspaceResponse) ProtoMessage() {}
+
+func (x *DeleteWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[76]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{76}
 }
 
 var File_todo_proto protoreflect.FileDescriptor
@@ -342,28 +4870,376 @@ var File_todo_proto protoreflect.FileDescriptor
 const file_todo_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"todo.proto\x12\atodo.v1\"$\n" +
+	"todo.proto\x12\atodo.v1\x1a google/protobuf/field_mask.proto\"\xd4\x01\n" +
 	"\x0eAddTaskRequest\x12\x12\n" +
-	"\x04text\x18\x01 \x01(\tR\x04text\"4\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\tR\x06listId\x12\x15\n" +
+	"\x06due_at\x18\x03 \x01(\x03R\x05dueAt\x12-\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1b\n" +
+	"\tparent_id\x18\x06 \x01(\tR\bparentId\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\a \x01(\tR\n" +
+	"recurrence\"4\n" +
 	"\x0fAddTaskResponse\x12!\n" +
-	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x11\n" +
-	"\x0fGetTasksRequest\"7\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x9f\x04\n" +
+	"\x0fGetTasksRequest\x12+\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x13.todo.v1.TaskStatusR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12#\n" +
+	"\rcreated_after\x18\x05 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x06 \x01(\x03R\rcreatedBefore\x12-\n" +
+	"\border_by\x18\a \x01(\x0e2\x12.todo.v1.TaskOrderR\aorderBy\x12\x17\n" +
+	"\alist_id\x18\b \x01(\tR\x06listId\x121\n" +
+	"\n" +
+	"due_filter\x18\t \x01(\x0e2\x12.todo.v1.DueFilterR\tdueFilter\x12,\n" +
+	"\x12due_within_seconds\x18\n" +
+	" \x01(\x03R\x10dueWithinSeconds\x12\x1b\n" +
+	"\ttime_zone\x18\v \x01(\tR\btimeZone\x12\x19\n" +
+	"\bany_tags\x18\f \x03(\tR\aanyTags\x12\x19\n" +
+	"\ball_tags\x18\r \x03(\tR\aallTags\x12%\n" +
+	"\x04view\x18\x0e \x01(\x0e2\x11.todo.v1.TaskViewR\x04view\x12\x1e\n" +
+	"\n" +
+	"actionable\x18\x0f \x01(\bR\n" +
+	"actionable\"\x86\x01\n" +
 	"\x10GetTasksResponse\x12#\n" +
-	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12%\n" +
+	"\x04tree\x18\x03 \x03(\v2\x11.todo.v1.TaskNodeR\x04tree\"\\\n" +
+	"\bTaskNode\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\x12-\n" +
+	"\bsubtasks\x18\x02 \x03(\v2\x11.todo.v1.TaskNodeR\bsubtasks\"\xac\x01\n" +
 	"\x11DeleteTaskRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\tR\x06listId\x12C\n" +
+	"\x0esubtask_policy\x18\x03 \x01(\x0e2\x1c.todo.v1.SubtaskDeletePolicyR\rsubtaskPolicy\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\".\n" +
 	"\x12DeleteTaskResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"I\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xae\x01\n" +
+	"\x11UpdateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\x04task\x18\x02 \x01(\v2\r.todo.v1.TaskR\x04task\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"7\n" +
+	"\x12UpdateTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"P\n" +
+	"\x13CompleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"9\n" +
+	"\x14CompleteTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"N\n" +
+	"\x11ReopenTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"7\n" +
+	"\x12ReopenTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x13\n" +
+	"\x11WatchTasksRequest\">\n" +
+	"\x12WatchTasksResponse\x12(\n" +
+	"\x05event\x18\x01 \x01(\v2\x12.todo.v1.TaskEventR\x05event\"{\n" +
+	"\tTaskEvent\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.todo.v1.TaskEventTypeR\x04type\x12!\n" +
+	"\x04task\x18\x02 \x01(\v2\r.todo.v1.TaskR\x04task\x12\x1f\n" +
+	"\voccurred_at\x18\x03 \x01(\x03R\n" +
+	"occurredAt\"\xa9\x01\n" +
+	"\fHistoryEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.todo.v1.HistoryEventTypeR\x04type\x12!\n" +
+	"\x04task\x18\x03 \x01(\v2\r.todo.v1.TaskR\x04task\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x12\x1f\n" +
+	"\voccurred_at\x18\x05 \x01(\x03R\n" +
+	"occurredAt\"0\n" +
+	"\x15GetTaskHistoryRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"G\n" +
+	"\x16GetTaskHistoryResponse\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.todo.v1.HistoryEventR\x06events\"{\n" +
+	"\x11ListEventsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x02 \x01(\x03R\x05until\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"k\n" +
+	"\x12ListEventsResponse\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.todo.v1.HistoryEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc9\x03\n" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x03 \x01(\x03R\tcreatedAt2\xd9\x01\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x12!\n" +
+	"\fcompleted_at\x18\x05 \x01(\x03R\vcompletedAt\x12\x17\n" +
+	"\alist_id\x18\x06 \x01(\tR\x06listId\x12\x19\n" +
+	"\bowner_id\x18\a \x01(\tR\aownerId\x12\x15\n" +
+	"\x06due_at\x18\b \x01(\x03R\x05dueAt\x12-\n" +
+	"\bpriority\x18\t \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x1a\n" +
+	"\bposition\x18\n" +
+	" \x01(\tR\bposition\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x1b\n" +
+	"\tparent_id\x18\f \x01(\tR\bparentId\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\r \x03(\tR\tblockedBy\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\x0e \x01(\tR\n" +
+	"recurrence\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x0f \x01(\x03R\tdeletedAt\x12\x18\n" +
+	"\aversion\x18\x10 \x01(\x03R\aversion\"\x84\x01\n" +
+	"\x0fMoveTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tbefore_id\x18\x02 \x01(\tR\bbeforeId\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\tR\aafterId\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"5\n" +
+	"\x10MoveTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"_\n" +
+	"\x0eAddTagsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"4\n" +
+	"\x0fAddTagsResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"b\n" +
+	"\x11RemoveTagsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"7\n" +
+	"\x12RemoveTagsResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"*\n" +
+	"\x0fListTagsRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\"9\n" +
+	"\x10ListTagsResponse\x12%\n" +
+	"\x04tags\x18\x01 \x03(\v2\x11.todo.v1.TagCountR\x04tags\"4\n" +
+	"\bTagCount\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"m\n" +
+	"\x11AddBlockerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x02 \x01(\tR\tblockerId\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"7\n" +
+	"\x12AddBlockerResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"p\n" +
+	"\x14RemoveBlockerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x02 \x01(\tR\tblockerId\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\":\n" +
+	"\x15RemoveBlockerResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\x12\n" +
+	"\x10ListTrashRequest\"8\n" +
+	"\x11ListTrashResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"$\n" +
+	"\x12RestoreTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
+	"\x13RestoreTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"\"\n" +
+	"\x10PurgeTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11PurgeTaskResponse\"\r\n" +
+	"\vUndoRequest\"T\n" +
+	"\fUndoResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12\x1f\n" +
+	"\vremoved_ids\x18\x02 \x03(\tR\n" +
+	"removedIds\"K\n" +
+	"\x14BatchAddTasksRequest\x123\n" +
+	"\brequests\x18\x01 \x03(\v2\x17.todo.v1.AddTaskRequestR\brequests\"<\n" +
+	"\x15BatchAddTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"Q\n" +
+	"\x17BatchUpdateTasksRequest\x126\n" +
+	"\brequests\x18\x01 \x03(\v2\x1a.todo.v1.UpdateTaskRequestR\brequests\"?\n" +
+	"\x18BatchUpdateTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"Q\n" +
+	"\x17BatchDeleteTasksRequest\x126\n" +
+	"\brequests\x18\x01 \x03(\v2\x1a.todo.v1.DeleteTaskRequestR\brequests\"\x1a\n" +
+	"\x18BatchDeleteTasksResponse\"T\n" +
+	"\x0eBatchItemError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x87\x01\n" +
+	"\x12ExportTasksRequest\x12+\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x13.todo.v1.TaskFormatR\x06format\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\tR\x06listId\x12+\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x13.todo.v1.TaskStatusR\x06status\")\n" +
+	"\x13ExportTasksResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"n\n" +
+	"\x12ImportTasksRequest\x12+\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x13.todo.v1.TaskFormatR\x06format\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x17\n" +
+	"\alist_id\x18\x03 \x01(\tR\x06listId\":\n" +
+	"\x13ImportTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"?\n" +
+	"\x0fImportLineError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"G\n" +
+	"\tRetryInfo\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\tR\x05limit\x12$\n" +
+	"\x0eretry_after_ms\x18\x02 \x01(\x03R\fretryAfterMs\"'\n" +
+	"\x11CreateListRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"7\n" +
+	"\x12CreateListResponse\x12!\n" +
+	"\x04list\x18\x01 \x01(\v2\r.todo.v1.ListR\x04list\"\x11\n" +
+	"\x0fGetListsRequest\"7\n" +
+	"\x10GetListsResponse\x12#\n" +
+	"\x05lists\x18\x01 \x03(\v2\r.todo.v1.ListR\x05lists\"7\n" +
+	"\x11RenameListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"7\n" +
+	"\x12RenameListResponse\x12!\n" +
+	"\x04list\x18\x01 \x01(\v2\r.todo.v1.ListR\x04list\"}\n" +
+	"\x11DeleteListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\x06policy\x18\x02 \x01(\x0e2\x19.todo.v1.ListDeletePolicyR\x06policy\x12%\n" +
+	"\x0fmove_to_list_id\x18\x03 \x01(\tR\fmoveToListId\".\n" +
+	"\x12DeleteListResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"k\n" +
+	"\x10ShareListRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12%\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x11.todo.v1.ListRoleR\x04role\"6\n" +
+	"\x11ShareListResponse\x12!\n" +
+	"\x04list\x18\x01 \x01(\v2\r.todo.v1.ListR\x04list\"K\n" +
+	"\x17RevokeListAccessRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"=\n" +
+	"\x18RevokeListAccessResponse\x12!\n" +
+	"\x04list\x18\x01 \x01(\v2\r.todo.v1.ListR\x04list\"\x93\x01\n" +
+	"\x04List\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x19\n" +
+	"\bowner_id\x18\x04 \x01(\tR\aownerId\x12-\n" +
+	"\amembers\x18\x05 \x03(\v2\x13.todo.v1.ListMemberR\amembers\"L\n" +
+	"\n" +
+	"ListMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x11.todo.v1.ListRoleR\x04role\"\x9c\x01\n" +
+	"\tWorkspace\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12/\n" +
+	"\x14max_task_text_length\x18\x04 \x01(\x05R\x11maxTaskTextLength\x12\x1b\n" +
+	"\tmax_tasks\x18\x05 \x01(\x05R\bmaxTasks\"J\n" +
+	"\x16CreateWorkspaceRequest\x120\n" +
+	"\tworkspace\x18\x01 \x01(\v2\x12.todo.v1.WorkspaceR\tworkspace\"K\n" +
+	"\x17CreateWorkspaceResponse\x120\n" +
+	"\tworkspace\x18\x01 \x01(\v2\x12.todo.v1.WorkspaceR\tworkspace\"\x17\n" +
+	"\x15ListWorkspacesRequest\"L\n" +
+	"\x16ListWorkspacesResponse\x122\n" +
+	"\n" +
+	"workspaces\x18\x01 \x03(\v2\x12.todo.v1.WorkspaceR\n" +
+	"workspaces\"(\n" +
+	"\x16DeleteWorkspaceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x19\n" +
+	"\x17DeleteWorkspaceResponse*\x88\x01\n" +
+	"\n" +
+	"TaskFormat\x12\x1b\n" +
+	"\x17TASK_FORMAT_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10TASK_FORMAT_JSON\x10\x01\x12\x13\n" +
+	"\x0fTASK_FORMAT_CSV\x10\x02\x12\x18\n" +
+	"\x14TASK_FORMAT_MARKDOWN\x10\x03\x12\x18\n" +
+	"\x14TASK_FORMAT_TODO_TXT\x10\x04*f\n" +
+	"\bListRole\x12\x19\n" +
+	"\x15LIST_ROLE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10LIST_ROLE_VIEWER\x10\x01\x12\x14\n" +
+	"\x10LIST_ROLE_EDITOR\x10\x02\x12\x13\n" +
+	"\x0fLIST_ROLE_OWNER\x10\x03*Z\n" +
+	"\n" +
+	"TaskStatus\x12\x1b\n" +
+	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10TASK_STATUS_OPEN\x10\x01\x12\x19\n" +
+	"\x15TASK_STATUS_COMPLETED\x10\x02*\xc3\x01\n" +
+	"\tTaskOrder\x12\x1a\n" +
+	"\x16TASK_ORDER_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17TASK_ORDER_NEWEST_FIRST\x10\x01\x12\x1b\n" +
+	"\x17TASK_ORDER_OLDEST_FIRST\x10\x02\x12\x1b\n" +
+	"\x17TASK_ORDER_ALPHABETICAL\x10\x03\x12\x11\n" +
+	"\rTASK_ORDER_ID\x10\x04\x12\x17\n" +
+	"\x13TASK_ORDER_POSITION\x10\x05\x12\x17\n" +
+	"\x13TASK_ORDER_PRIORITY\x10\x06*h\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vPRIORITY_P0\x10\x01\x12\x0f\n" +
+	"\vPRIORITY_P1\x10\x02\x12\x0f\n" +
+	"\vPRIORITY_P2\x10\x03\x12\x0f\n" +
+	"\vPRIORITY_P3\x10\x04*t\n" +
+	"\tDueFilter\x12\x1a\n" +
+	"\x16DUE_FILTER_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12DUE_FILTER_OVERDUE\x10\x01\x12\x18\n" +
+	"\x14DUE_FILTER_DUE_TODAY\x10\x02\x12\x19\n" +
+	"\x15DUE_FILTER_DUE_WITHIN\x10\x03*s\n" +
+	"\x10ListDeletePolicy\x12\"\n" +
+	"\x1eLIST_DELETE_POLICY_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aLIST_DELETE_POLICY_CASCADE\x10\x01\x12\x1b\n" +
+	"\x17LIST_DELETE_POLICY_MOVE\x10\x02*M\n" +
+	"\bTaskView\x12\x19\n" +
+	"\x15TASK_VIEW_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eTASK_VIEW_FLAT\x10\x01\x12\x12\n" +
+	"\x0eTASK_VIEW_TREE\x10\x02*\x82\x01\n" +
+	"\x13SubtaskDeletePolicy\x12%\n" +
+	"!SUBTASK_DELETE_POLICY_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dSUBTASK_DELETE_POLICY_CASCADE\x10\x01\x12!\n" +
+	"\x1dSUBTASK_DELETE_POLICY_PROMOTE\x10\x02*\x9e\x01\n" +
+	"\rTaskEventType\x12\x1f\n" +
+	"\x1bTASK_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15TASK_EVENT_TYPE_ADDED\x10\x01\x12\x1b\n" +
+	"\x17TASK_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17TASK_EVENT_TYPE_DELETED\x10\x03\x12\x17\n" +
+	"\x13TASK_EVENT_TYPE_DUE\x10\x04*\xd6\x01\n" +
+	"\x10HistoryEventType\x12\"\n" +
+	"\x1eHISTORY_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aHISTORY_EVENT_TYPE_CREATED\x10\x01\x12\x1e\n" +
+	"\x1aHISTORY_EVENT_TYPE_UPDATED\x10\x02\x12\x1e\n" +
+	"\x1aHISTORY_EVENT_TYPE_DELETED\x10\x03\x12\x1f\n" +
+	"\x1bHISTORY_EVENT_TYPE_RESTORED\x10\x04\x12\x1d\n" +
+	"\x19HISTORY_EVENT_TYPE_PURGED\x10\x052\xbb\x11\n" +
 	"\vTodoService\x12>\n" +
 	"\aAddTask\x12\x17.todo.v1.AddTaskRequest\x1a\x18.todo.v1.AddTaskResponse\"\x00\x12A\n" +
 	"\bGetTasks\x12\x18.todo.v1.GetTasksRequest\x1a\x19.todo.v1.GetTasksResponse\"\x00\x12G\n" +
 	"\n" +
-	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x1b.todo.v1.DeleteTaskResponse\"\x00B\x1aZ\x18todo-list/todo/v1;todov1b\x06proto3"
+	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x1b.todo.v1.DeleteTaskResponse\"\x00\x12G\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.todo.v1.UpdateTaskRequest\x1a\x1b.todo.v1.UpdateTaskResponse\"\x00\x12M\n" +
+	"\fCompleteTask\x12\x1c.todo.v1.CompleteTaskRequest\x1a\x1d.todo.v1.CompleteTaskResponse\"\x00\x12G\n" +
+	"\n" +
+	"ReopenTask\x12\x1a.todo.v1.ReopenTaskRequest\x1a\x1b.todo.v1.ReopenTaskResponse\"\x00\x12I\n" +
+	"\n" +
+	"WatchTasks\x12\x1a.todo.v1.WatchTasksRequest\x1a\x1b.todo.v1.WatchTasksResponse\"\x000\x01\x12G\n" +
+	"\n" +
+	"CreateList\x12\x1a.todo.v1.CreateListRequest\x1a\x1b.todo.v1.CreateListResponse\"\x00\x12A\n" +
+	"\bGetLists\x12\x18.todo.v1.GetListsRequest\x1a\x19.todo.v1.GetListsResponse\"\x00\x12G\n" +
+	"\n" +
+	"RenameList\x12\x1a.todo.v1.RenameListRequest\x1a\x1b.todo.v1.RenameListResponse\"\x00\x12G\n" +
+	"\n" +
+	"DeleteList\x12\x1a.todo.v1.DeleteListRequest\x1a\x1b.todo.v1.DeleteListResponse\"\x00\x12D\n" +
+	"\tShareList\x12\x19.todo.v1.ShareListRequest\x1a\x1a.todo.v1.ShareListResponse\"\x00\x12Y\n" +
+	"\x10RevokeListAccess\x12 .todo.v1.RevokeListAccessRequest\x1a!.todo.v1.RevokeListAccessResponse\"\x00\x12A\n" +
+	"\bMoveTask\x12\x18.todo.v1.MoveTaskRequest\x1a\x19.todo.v1.MoveTaskResponse\"\x00\x12>\n" +
+	"\aAddTags\x12\x17.todo.v1.AddTagsRequest\x1a\x18.todo.v1.AddTagsResponse\"\x00\x12G\n" +
+	"\n" +
+	"RemoveTags\x12\x1a.todo.v1.RemoveTagsRequest\x1a\x1b.todo.v1.RemoveTagsResponse\"\x00\x12A\n" +
+	"\bListTags\x12\x18.todo.v1.ListTagsRequest\x1a\x19.todo.v1.ListTagsResponse\"\x00\x12G\n" +
+	"\n" +
+	"AddBlocker\x12\x1a.todo.v1.AddBlockerRequest\x1a\x1b.todo.v1.AddBlockerResponse\"\x00\x12P\n" +
+	"\rRemoveBlocker\x12\x1d.todo.v1.RemoveBlockerRequest\x1a\x1e.todo.v1.RemoveBlockerResponse\"\x00\x12D\n" +
+	"\tListTrash\x12\x19.todo.v1.ListTrashRequest\x1a\x1a.todo.v1.ListTrashResponse\"\x00\x12J\n" +
+	"\vRestoreTask\x12\x1b.todo.v1.RestoreTaskRequest\x1a\x1c.todo.v1.RestoreTaskResponse\"\x00\x12D\n" +
+	"\tPurgeTask\x12\x19.todo.v1.PurgeTaskRequest\x1a\x1a.todo.v1.PurgeTaskResponse\"\x00\x125\n" +
+	"\x04Undo\x12\x14.todo.v1.UndoRequest\x1a\x15.todo.v1.UndoResponse\"\x00\x12P\n" +
+	"\rBatchAddTasks\x12\x1d.todo.v1.BatchAddTasksRequest\x1a\x1e.todo.v1.BatchAddTasksResponse\"\x00\x12Y\n" +
+	"\x10BatchUpdateTasks\x12 .todo.v1.BatchUpdateTasksRequest\x1a!.todo.v1.BatchUpdateTasksResponse\"\x00\x12Y\n" +
+	"\x10BatchDeleteTasks\x12 .todo.v1.BatchDeleteTasksRequest\x1a!.todo.v1.BatchDeleteTasksResponse\"\x00\x12J\n" +
+	"\vExportTasks\x12\x1b.todo.v1.ExportTasksRequest\x1a\x1c.todo.v1.ExportTasksResponse\"\x00\x12J\n" +
+	"\vImportTasks\x12\x1b.todo.v1.ImportTasksRequest\x1a\x1c.todo.v1.ImportTasksResponse\"\x00\x12S\n" +
+	"\x0eGetTaskHistory\x12\x1e.todo.v1.GetTaskHistoryRequest\x1a\x1f.todo.v1.GetTaskHistoryResponse\"\x00\x12G\n" +
+	"\n" +
+	"ListEvents\x12\x1a.todo.v1.ListEventsRequest\x1a\x1b.todo.v1.ListEventsResponse\"\x002\x97\x02\n" +
+	"\x10WorkspaceService\x12V\n" +
+	"\x0fCreateWorkspace\x12\x1f.todo.v1.CreateWorkspaceRequest\x1a .todo.v1.CreateWorkspaceResponse\"\x00\x12S\n" +
+	"\x0eListWorkspaces\x12\x1e.todo.v1.ListWorkspacesRequest\x1a\x1f.todo.v1.ListWorkspacesResponse\"\x00\x12V\n" +
+	"\x0fDeleteWorkspace\x12\x1f.todo.v1.DeleteWorkspaceRequest\x1a .todo.v1.DeleteWorkspaceResponse\"\x00B\x1aZ\x18todo-list/todo/v1;todov1b\x06proto3"
 
 var (
 	file_todo_proto_rawDescOnce sync.Once
@@ -377,30 +5253,225 @@ func file_todo_proto_rawDescGZIP() []byte {
 	return file_todo_proto_rawDescData
 }
 
-var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 11)
+var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 77)
 var file_todo_proto_goTypes = []any{
-	(*AddTaskRequest)(nil),     // 0: todo.v1.AddTaskRequest
-	(*AddTaskResponse)(nil),    // 1: todo.v1.AddTaskResponse
-	(*GetTasksRequest)(nil),    // 2: todo.v1.GetTasksRequest
-	(*GetTasksResponse)(nil),   // 3: todo.v1.GetTasksResponse
-	(*DeleteTaskRequest)(nil),  // 4: todo.v1.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil), // 5: todo.v1.DeleteTaskResponse
-	(*Task)(nil),               // 6: todo.v1.Task
+	(TaskFormat)(0),                  // 0: todo.v1.TaskFormat
+	(ListRole)(0),                    // 1: todo.v1.ListRole
+	(TaskStatus)(0),                  // 2: todo.v1.TaskStatus
+	(TaskOrder)(0),                   // 3: todo.v1.TaskOrder
+	(Priority)(0),                    // 4: todo.v1.Priority
+	(DueFilter)(0),                   // 5: todo.v1.DueFilter
+	(ListDeletePolicy)(0),            // 6: todo.v1.ListDeletePolicy
+	(TaskView)(0),                    // 7: todo.v1.TaskView
+	(SubtaskDeletePolicy)(0),         // 8: todo.v1.SubtaskDeletePolicy
+	(TaskEventType)(0),               // 9: todo.v1.TaskEventType
+	(HistoryEventType)(0),            // 10: todo.v1.HistoryEventType
+	(*AddTaskRequest)(nil),           // 11: todo.v1.AddTaskRequest
+	(*AddTaskResponse)(nil),          // 12: todo.v1.AddTaskResponse
+	(*GetTasksRequest)(nil),          // 13: todo.v1.GetTasksRequest
+	(*GetTasksResponse)(nil),         // 14: todo.v1.GetTasksResponse
+	(*TaskNode)(nil),                 // 15: todo.v1.TaskNode
+	(*DeleteTaskRequest)(nil),        // 16: todo.v1.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),       // 17: todo.v1.DeleteTaskResponse
+	(*UpdateTaskRequest)(nil),        // 18: todo.v1.UpdateTaskRequest
+	(*UpdateTaskResponse)(nil),       // 19: todo.v1.UpdateTaskResponse
+	(*CompleteTaskRequest)(nil),      // 20: todo.v1.CompleteTaskRequest
+	(*CompleteTaskResponse)(nil),     // 21: todo.v1.CompleteTaskResponse
+	(*ReopenTaskRequest)(nil),        // 22: todo.v1.ReopenTaskRequest
+	(*ReopenTaskResponse)(nil),       // 23: todo.v1.ReopenTaskResponse
+	(*WatchTasksRequest)(nil),        // 24: todo.v1.WatchTasksRequest
+	(*WatchTasksResponse)(nil),       // 25: todo.v1.WatchTasksResponse
+	(*TaskEvent)(nil),                // 26: todo.v1.TaskEvent
+	(*HistoryEvent)(nil),             // 27: todo.v1.HistoryEvent
+	(*GetTaskHistoryRequest)(nil),    // 28: todo.v1.GetTaskHistoryRequest
+	(*GetTaskHistoryResponse)(nil),   // 29: todo.v1.GetTaskHistoryResponse
+	(*ListEventsRequest)(nil),        // 30: todo.v1.ListEventsRequest
+	(*ListEventsResponse)(nil),       // 31: todo.v1.ListEventsResponse
+	(*Task)(nil),                     // 32: todo.v1.Task
+	(*MoveTaskRequest)(nil),          // 33: todo.v1.MoveTaskRequest
+	(*MoveTaskResponse)(nil),         // 34: todo.v1.MoveTaskResponse
+	(*AddTagsRequest)(nil),           // 35: todo.v1.AddTagsRequest
+	(*AddTagsResponse)(nil),          // 36: todo.v1.AddTagsResponse
+	(*RemoveTagsRequest)(nil),        // 37: todo.v1.RemoveTagsRequest
+	(*RemoveTagsResponse)(nil),       // 38: todo.v1.RemoveTagsResponse
+	(*ListTagsRequest)(nil),          // 39: todo.v1.ListTagsRequest
+	(*ListTagsResponse)(nil),         // 40: todo.v1.ListTagsResponse
+	(*TagCount)(nil),                 // 41: todo.v1.TagCount
+	(*AddBlockerRequest)(nil),        // 42: todo.v1.AddBlockerRequest
+	(*AddBlockerResponse)(nil),       // 43: todo.v1.AddBlockerResponse
+	(*RemoveBlockerRequest)(nil),     // 44: todo.v1.RemoveBlockerRequest
+	(*RemoveBlockerResponse)(nil),    // 45: todo.v1.RemoveBlockerResponse
+	(*ListTrashRequest)(nil),         // 46: todo.v1.ListTrashRequest
+	(*ListTrashResponse)(nil),        // 47: todo.v1.ListTrashResponse
+	(*RestoreTaskRequest)(nil),       // 48: todo.v1.RestoreTaskRequest
+	(*RestoreTaskResponse)(nil),      // 49: todo.v1.RestoreTaskResponse
+	(*PurgeTaskRequest)(nil),         // 50: todo.v1.PurgeTaskRequest
+	(*PurgeTaskResponse)(nil),        // 51: todo.v1.PurgeTaskResponse
+	(*UndoRequest)(nil),              // 52: todo.v1.UndoRequest
+	(*UndoResponse)(nil),             // 53: todo.v1.UndoResponse
+	(*BatchAddTasksRequest)(nil),     // 54: todo.v1.BatchAddTasksRequest
+	(*BatchAddTasksResponse)(nil),    // 55: todo.v1.BatchAddTasksResponse
+	(*BatchUpdateTasksRequest)(nil),  // 56: todo.v1.BatchUpdateTasksRequest
+	(*BatchUpdateTasksResponse)(nil), // 57: todo.v1.BatchUpdateTasksResponse
+	(*BatchDeleteTasksRequest)(nil),  // 58: todo.v1.BatchDeleteTasksRequest
+	(*BatchDeleteTasksResponse)(nil), // 59: todo.v1.BatchDeleteTasksResponse
+	(*BatchItemError)(nil),           // 60: todo.v1.BatchItemError
+	(*ExportTasksRequest)(nil),       // 61: todo.v1.ExportTasksRequest
+	(*ExportTasksResponse)(nil),      // 62: todo.v1.ExportTasksResponse
+	(*ImportTasksRequest)(nil),       // 63: todo.v1.ImportTasksRequest
+	(*ImportTasksResponse)(nil),      // 64: todo.v1.ImportTasksResponse
+	(*ImportLineError)(nil),          // 65: todo.v1.ImportLineError
+	(*RetryInfo)(nil),                // 66: todo.v1.RetryInfo
+	(*CreateListRequest)(nil),        // 67: todo.v1.CreateListRequest
+	(*CreateListResponse)(nil),       // 68: todo.v1.CreateListResponse
+	(*GetListsRequest)(nil),          // 69: todo.v1.GetListsRequest
+	(*GetListsResponse)(nil),         // 70: todo.v1.GetListsResponse
+	(*RenameListRequest)(nil),        // 71: todo.v1.RenameListRequest
+	(*RenameListResponse)(nil),       // 72: todo.v1.RenameListResponse
+	(*DeleteListRequest)(nil),        // 73: todo.v1.DeleteListRequest
+	(*DeleteListResponse)(nil),       // 74: todo.v1.DeleteListResponse
+	(*ShareListRequest)(nil),         // 75: todo.v1.ShareListRequest
+	(*ShareListResponse)(nil),        // 76: todo.v1.ShareListResponse
+	(*RevokeListAccessRequest)(nil),  // 77: todo.v1.RevokeListAccessRequest
+	(*RevokeListAccessResponse)(nil), // 78: todo.v1.RevokeListAccessResponse
+	(*List)(nil),                     // 79: todo.v1.List
+	(*ListMember)(nil),               // 80: todo.v1.ListMember
+	(*Workspace)(nil),                // 81: todo.v1.Workspace
+	(*CreateWorkspaceRequest)(nil),   // 82: todo.v1.CreateWorkspaceRequest
+	(*CreateWorkspaceResponse)(nil),  // 83: todo.v1.CreateWorkspaceResponse
+	(*ListWorkspacesRequest)(nil),    // 84: todo.v1.ListWorkspacesRequest
+	(*ListWorkspacesResponse)(nil),   // 85: todo.v1.ListWorkspacesResponse
+	(*DeleteWorkspaceRequest)(nil),   // 86: todo.v1.DeleteWorkspaceRequest
+	(*DeleteWorkspaceResponse)(nil),  // 87: todo.v1.DeleteWorkspaceResponse
+	(*fieldmaskpb.FieldMask)(nil),    // 88: google.protobuf.FieldMask
 }
 var file_todo_proto_depIdxs = []int32{
-	6, // 0: todo.v1.AddTaskResponse.task:type_name -> todo.v1.Task
-	6, // 1: todo.v1.GetTasksResponse.tasks:type_name -> todo.v1.Task
-	0, // 2: todo.v1.TodoService.AddTask:input_type -> todo.v1.AddTaskRequest
-	2, // 3: todo.v1.TodoService.GetTasks:input_type -> todo.v1.GetTasksRequest
-	4, // 4: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
-	1, // 5: todo.v1.TodoService.AddTask:output_type -> todo.v1.AddTaskResponse
-	3, // 6: todo.v1.TodoService.GetTasks:output_type -> todo.v1.GetTasksResponse
-	5, // 7: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4,  // 0: todo.v1.AddTaskRequest.priority:type_name -> todo.v1.Priority
+	32, // 1: todo.v1.AddTaskResponse.task:type_name -> todo.v1.Task
+	2,  // 2: todo.v1.GetTasksRequest.status:type_name -> todo.v1.TaskStatus
+	3,  // 3: todo.v1.GetTasksRequest.order_by:type_name -> todo.v1.TaskOrder
+	5,  // 4: todo.v1.GetTasksRequest.due_filter:type_name -> todo.v1.DueFilter
+	7,  // 5: todo.v1.GetTasksRequest.view:type_name -> todo.v1.TaskView
+	32, // 6: todo.v1.GetTasksResponse.tasks:type_name -> todo.v1.Task
+	15, // 7: todo.v1.GetTasksResponse.tree:type_name -> todo.v1.TaskNode
+	32, // 8: todo.v1.TaskNode.task:type_name -> todo.v1.Task
+	15, // 9: todo.v1.TaskNode.subtasks:type_name -> todo.v1.TaskNode
+	8,  // 10: todo.v1.DeleteTaskRequest.subtask_policy:type_name -> todo.v1.SubtaskDeletePolicy
+	32, // 11: todo.v1.UpdateTaskRequest.task:type_name -> todo.v1.Task
+	88, // 12: todo.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	32, // 13: todo.v1.UpdateTaskResponse.task:type_name -> todo.v1.Task
+	32, // 14: todo.v1.CompleteTaskResponse.task:type_name -> todo.v1.Task
+	32, // 15: todo.v1.ReopenTaskResponse.task:type_name -> todo.v1.Task
+	26, // 16: todo.v1.WatchTasksResponse.event:type_name -> todo.v1.TaskEvent
+	9,  // 17: todo.v1.TaskEvent.type:type_name -> todo.v1.TaskEventType
+	32, // 18: todo.v1.TaskEvent.task:type_name -> todo.v1.Task
+	10, // 19: todo.v1.HistoryEvent.type:type_name -> todo.v1.HistoryEventType
+	32, // 20: todo.v1.HistoryEvent.task:type_name -> todo.v1.Task
+	27, // 21: todo.v1.GetTaskHistoryResponse.events:type_name -> todo.v1.HistoryEvent
+	27, // 22: todo.v1.ListEventsResponse.events:type_name -> todo.v1.HistoryEvent
+	4,  // 23: todo.v1.Task.priority:type_name -> todo.v1.Priority
+	32, // 24: todo.v1.MoveTaskResponse.task:type_name -> todo.v1.Task
+	32, // 25: todo.v1.AddTagsResponse.task:type_name -> todo.v1.Task
+	32, // 26: todo.v1.RemoveTagsResponse.task:type_name -> todo.v1.Task
+	41, // 27: todo.v1.ListTagsResponse.tags:type_name -> todo.v1.TagCount
+	32, // 28: todo.v1.AddBlockerResponse.task:type_name -> todo.v1.Task
+	32, // 29: todo.v1.RemoveBlockerResponse.task:type_name -> todo.v1.Task
+	32, // 30: todo.v1.ListTrashResponse.tasks:type_name -> todo.v1.Task
+	32, // 31: todo.v1.RestoreTaskResponse.task:type_name -> todo.v1.Task
+	32, // 32: todo.v1.UndoResponse.tasks:type_name -> todo.v1.Task
+	11, // 33: todo.v1.BatchAddTasksRequest.requests:type_name -> todo.v1.AddTaskRequest
+	32, // 34: todo.v1.BatchAddTasksResponse.tasks:type_name -> todo.v1.Task
+	18, // 35: todo.v1.BatchUpdateTasksRequest.requests:type_name -> todo.v1.UpdateTaskRequest
+	32, // 36: todo.v1.BatchUpdateTasksResponse.tasks:type_name -> todo.v1.Task
+	16, // 37: todo.v1.BatchDeleteTasksRequest.requests:type_name -> todo.v1.DeleteTaskRequest
+	0,  // 38: todo.v1.ExportTasksRequest.format:type_name -> todo.v1.TaskFormat
+	2,  // 39: todo.v1.ExportTasksRequest.status:type_name -> todo.v1.TaskStatus
+	0,  // 40: todo.v1.ImportTasksRequest.format:type_name -> todo.v1.TaskFormat
+	32, // 41: todo.v1.ImportTasksResponse.tasks:type_name -> todo.v1.Task
+	79, // 42: todo.v1.CreateListResponse.list:type_name -> todo.v1.List
+	79, // 43: todo.v1.GetListsResponse.lists:type_name -> todo.v1.List
+	79, // 44: todo.v1.RenameListResponse.list:type_name -> todo.v1.List
+	6,  // 45: todo.v1.DeleteListRequest.policy:type_name -> todo.v1.ListDeletePolicy
+	1,  // 46: todo.v1.ShareListRequest.role:type_name -> todo.v1.ListRole
+	79, // 47: todo.v1.ShareListResponse.list:type_name -> todo.v1.List
+	79, // 48: todo.v1.RevokeListAccessResponse.list:type_name -> todo.v1.List
+	80, // 49: todo.v1.List.members:type_name -> todo.v1.ListMember
+	1,  // 50: todo.v1.ListMember.role:type_name -> todo.v1.ListRole
+	81, // 51: todo.v1.CreateWorkspaceRequest.workspace:type_name -> todo.v1.Workspace
+	81, // 52: todo.v1.CreateWorkspaceResponse.workspace:type_name -> todo.v1.Workspace
+	81, // 53: todo.v1.ListWorkspacesResponse.workspaces:type_name -> todo.v1.Workspace
+	11, // 54: todo.v1.TodoService.AddTask:input_type -> todo.v1.AddTaskRequest
+	13, // 55: todo.v1.TodoService.GetTasks:input_type -> todo.v1.GetTasksRequest
+	16, // 56: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
+	18, // 57: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
+	20, // 58: todo.v1.TodoService.CompleteTask:input_type -> todo.v1.CompleteTaskRequest
+	22, // 59: todo.v1.TodoService.ReopenTask:input_type -> todo.v1.ReopenTaskRequest
+	24, // 60: todo.v1.TodoService.WatchTasks:input_type -> todo.v1.WatchTasksRequest
+	67, // 61: todo.v1.TodoService.CreateList:input_type -> todo.v1.CreateListRequest
+	69, // 62: todo.v1.TodoService.GetLists:input_type -> todo.v1.GetListsRequest
+	71, // 63: todo.v1.TodoService.RenameList:input_type -> todo.v1.RenameListRequest
+	73, // 64: todo.v1.TodoService.DeleteList:input_type -> todo.v1.DeleteListRequest
+	75, // 65: todo.v1.TodoService.ShareList:input_type -> todo.v1.ShareListRequest
+	77, // 66: todo.v1.TodoService.RevokeListAccess:input_type -> todo.v1.RevokeListAccessRequest
+	33, // 67: todo.v1.TodoService.MoveTask:input_type -> todo.v1.MoveTaskRequest
+	35, // 68: todo.v1.TodoService.AddTags:input_type -> todo.v1.AddTagsRequest
+	37, // 69: todo.v1.TodoService.RemoveTags:input_type -> todo.v1.RemoveTagsRequest
+	39, // 70: todo.v1.TodoService.ListTags:input_type -> todo.v1.ListTagsRequest
+	42, // 71: todo.v1.TodoService.AddBlocker:input_type -> todo.v1.AddBlockerRequest
+	44, // 72: todo.v1.TodoService.RemoveBlocker:input_type -> todo.v1.RemoveBlockerRequest
+	46, // 73: todo.v1.TodoService.ListTrash:input_type -> todo.v1.ListTrashRequest
+	48, // 74: todo.v1.TodoService.RestoreTask:input_type -> todo.v1.RestoreTaskRequest
+	50, // 75: todo.v1.TodoService.PurgeTask:input_type -> todo.v1.PurgeTaskRequest
+	52, // 76: todo.v1.TodoService.Undo:input_type -> todo.v1.UndoRequest
+	54, // 77: todo.v1.TodoService.BatchAddTasks:input_type -> todo.v1.BatchAddTasksRequest
+	56, // 78: todo.v1.TodoService.BatchUpdateTasks:input_type -> todo.v1.BatchUpdateTasksRequest
+	58, // 79: todo.v1.TodoService.BatchDeleteTasks:input_type -> todo.v1.BatchDeleteTasksRequest
+	61, // 80: todo.v1.TodoService.ExportTasks:input_type -> todo.v1.ExportTasksRequest
+	63, // 81: todo.v1.TodoService.ImportTasks:input_type -> todo.v1.ImportTasksRequest
+	28, // 82: todo.v1.TodoService.GetTaskHistory:input_type -> todo.v1.GetTaskHistoryRequest
+	30, // 83: todo.v1.TodoService.ListEvents:input_type -> todo.v1.ListEventsRequest
+	82, // 84: todo.v1.WorkspaceService.CreateWorkspace:input_type -> todo.v1.CreateWorkspaceRequest
+	84, // 85: todo.v1.WorkspaceService.ListWorkspaces:input_type -> todo.v1.ListWorkspacesRequest
+	86, // 86: todo.v1.WorkspaceService.DeleteWorkspace:input_type -> todo.v1.DeleteWorkspaceRequest
+	12, // 87: todo.v1.TodoService.AddTask:output_type -> todo.v1.AddTaskResponse
+	14, // 88: todo.v1.TodoService.GetTasks:output_type -> todo.v1.GetTasksResponse
+	17, // 89: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
+	19, // 90: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.UpdateTaskResponse
+	21, // 91: todo.v1.TodoService.CompleteTask:output_type -> todo.v1.CompleteTaskResponse
+	23, // 92: todo.v1.TodoService.ReopenTask:output_type -> todo.v1.ReopenTaskResponse
+	25, // 93: todo.v1.TodoService.WatchTasks:output_type -> todo.v1.WatchTasksResponse
+	68, // 94: todo.v1.TodoService.CreateList:output_type -> todo.v1.CreateListResponse
+	70, // 95: todo.v1.TodoService.GetLists:output_type -> todo.v1.GetListsResponse
+	72, // 96: todo.v1.TodoService.RenameList:output_type -> todo.v1.RenameListResponse
+	74, // 97: todo.v1.TodoService.DeleteList:output_type -> todo.v1.DeleteListResponse
+	76, // 98: todo.v1.TodoService.ShareList:output_type -> todo.v1.ShareListResponse
+	78, // 99: todo.v1.TodoService.RevokeListAccess:output_type -> todo.v1.RevokeListAccessResponse
+	34, // 100: todo.v1.TodoService.MoveTask:output_type -> todo.v1.MoveTaskResponse
+	36, // 101: todo.v1.TodoService.AddTags:output_type -> todo.v1.AddTagsResponse
+	38, // 102: todo.v1.TodoService.RemoveTags:output_type -> todo.v1.RemoveTagsResponse
+	40, // 103: todo.v1.TodoService.ListTags:output_type -> todo.v1.ListTagsResponse
+	43, // 104: todo.v1.TodoService.AddBlocker:output_type -> todo.v1.AddBlockerResponse
+	45, // 105: todo.v1.TodoService.RemoveBlocker:output_type -> todo.v1.RemoveBlockerResponse
+	47, // 106: todo.v1.TodoService.ListTrash:output_type -> todo.v1.ListTrashResponse
+	49, // 107: todo.v1.TodoService.RestoreTask:output_type -> todo.v1.RestoreTaskResponse
+	51, // 108: todo.v1.TodoService.PurgeTask:output_type -> todo.v1.PurgeTaskResponse
+	53, // 109: todo.v1.TodoService.Undo:output_type -> todo.v1.UndoResponse
+	55, // 110: todo.v1.TodoService.BatchAddTasks:output_type -> todo.v1.BatchAddTasksResponse
+	57, // 111: todo.v1.TodoService.BatchUpdateTasks:output_type -> todo.v1.BatchUpdateTasksResponse
+	59, // 112: todo.v1.TodoService.BatchDeleteTasks:output_type -> todo.v1.BatchDeleteTasksResponse
+	62, // 113: todo.v1.TodoService.ExportTasks:output_type -> todo.v1.ExportTasksResponse
+	64, // 114: todo.v1.TodoService.ImportTasks:output_type -> todo.v1.ImportTasksResponse
+	29, // 115: todo.v1.TodoService.GetTaskHistory:output_type -> todo.v1.GetTaskHistoryResponse
+	31, // 116: todo.v1.TodoService.ListEvents:output_type -> todo.v1.ListEventsResponse
+	83, // 117: todo.v1.WorkspaceService.CreateWorkspace:output_type -> todo.v1.CreateWorkspaceResponse
+	85, // 118: todo.v1.WorkspaceService.ListWorkspaces:output_type -> todo.v1.ListWorkspacesResponse
+	87, // 119: todo.v1.WorkspaceService.DeleteWorkspace:output_type -> todo.v1.DeleteWorkspaceResponse
+	87, // [87:120] is the sub-list for method output_type
+	54, // [54:87] is the sub-list for method input_type
+	54, // [54:54] is the sub-list for extension type_name
+	54, // [54:54] is the sub-list for extension extendee
+	0,  // [0:54] is the sub-list for field type_name
 }
 
 func init() { file_todo_proto_init() }
@@ -413,13 +5484,14 @@ func file_todo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_proto_rawDesc), len(file_todo_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      11,
+			NumMessages:   77,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_todo_proto_goTypes,
 		DependencyIndexes: file_todo_proto_depIdxs,
+		EnumInfos:         file_todo_proto_enumTypes,
 		MessageInfos:      file_todo_proto_msgTypes,
 	}.Build()
 	File_todo_proto = out.File
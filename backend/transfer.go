@@ -0,0 +1,594 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"todo-list/todo/v1"
+)
+
+// MaxImportTasks is the most tasks ImportTasks adds at once.
+const MaxImportTasks = 1000
+
+var (
+	ErrInvalidFormat     = errors.New("invalid task format")
+	ErrImportTooLarge    = fmt.Errorf("import has more than %d tasks", MaxImportTasks)
+	ErrMissingTextColumn = errors.New(`CSV header has no "text" column`)
+	ErrNotChecklistItem  = errors.New(`not a checklist item such as "- [ ] text"`)
+)
+
+// importedTask is a task read from line of an import file. Only the fields
+// the file can carry are set.
+type importedTask struct {
+	line int
+	task *todov1.Task
+}
+
+// lineError is a problem with one line of an import file.
+type lineError struct {
+	line int
+	err  error
+}
+
+// importError returns an error with the given code that describes the
+// failed lines of an import, first of all, with an ImportLineError detail
+// for each.
+func importError(code connect.Code, failed []lineError) error {
+	msg := fmt.Sprintf("line %d: %s", failed[0].line, errorMessage(failed[0].err))
+	if len(failed) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(failed)-1)
+	}
+	cerr := connect.NewError(code, errors.New(msg))
+	for _, f := range failed {
+		detail, err := connect.NewErrorDetail(&todov1.ImportLineError{
+			Line:    int32(f.line),
+			Message: errorMessage(f.err),
+		})
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to describe import error: %w", err))
+		}
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
+
+// encodeTasks writes tasks in format. Dates without a time of day are
+// written in loc.
+func encodeTasks(format todov1.TaskFormat, tasks []*todov1.Task, loc *time.Location) ([]byte, error) {
+	switch format {
+	case todov1.TaskFormat_TASK_FORMAT_UNSPECIFIED, todov1.TaskFormat_TASK_FORMAT_JSON:
+		return encodeJSONTasks(tasks)
+	case todov1.TaskFormat_TASK_FORMAT_CSV:
+		return encodeCSVTasks(tasks)
+	case todov1.TaskFormat_TASK_FORMAT_MARKDOWN:
+		return encodeMarkdownTasks(tasks), nil
+	case todov1.TaskFormat_TASK_FORMAT_TODO_TXT:
+		return encodeTodoTxtTasks(tasks, loc), nil
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// decodeTasks reads the tasks of an import file in format, along with the
+// lines it could not parse. It fails as a whole only if the file is not in
+// the format at all. Dates without a time of day are read in loc.
+func decodeTasks(format todov1.TaskFormat, data []byte, loc *time.Location) ([]importedTask, []lineError, error) {
+	switch format {
+	case todov1.TaskFormat_TASK_FORMAT_UNSPECIFIED, todov1.TaskFormat_TASK_FORMAT_JSON:
+		return decodeJSONTasks(data)
+	case todov1.TaskFormat_TASK_FORMAT_CSV:
+		return decodeCSVTasks(data)
+	case todov1.TaskFormat_TASK_FORMAT_MARKDOWN:
+		tasks, failed := decodeMarkdownTasks(data)
+		return tasks, failed, nil
+	case todov1.TaskFormat_TASK_FORMAT_TODO_TXT:
+		tasks, failed := decodeTodoTxtTasks(data, loc)
+		return tasks, failed, nil
+	default:
+		return nil, nil, ErrInvalidFormat
+	}
+}
+
+// importable returns the fields of task that imports carry over.
+func importable(task *todov1.Task) *todov1.Task {
+	return &todov1.Task{
+		Text:        task.Text,
+		Completed:   task.Completed,
+		CompletedAt: task.CompletedAt,
+		DueAt:       task.DueAt,
+		Priority:    task.Priority,
+		Tags:        task.Tags,
+		Recurrence:  task.Recurrence,
+	}
+}
+
+func encodeJSONTasks(tasks []*todov1.Task) ([]byte, error) {
+	items := make([]json.RawMessage, len(tasks))
+	for i, task := range tasks {
+		data, err := protojson.Marshal(task)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = data
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// decodeJSONTasks reads a JSON array of tasks. Each task is reported at the
+// line its object starts on.
+func decodeJSONTasks(data []byte) ([]importedTask, []lineError, error) {
+	invalid := func(err error) error {
+		return fmt.Errorf("%w: want a JSON array of tasks: %v", ErrInvalidFormat, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, invalid(err)
+	} else if tok != json.Delim('[') {
+		return nil, nil, invalid(fmt.Errorf("found %v", tok))
+	}
+
+	var tasks []importedTask
+	var failed []lineError
+	line, counted := 1, 0
+	for dec.More() {
+		// The item starts after the separator and space that follow the
+		// previous token.
+		start := int(dec.InputOffset())
+		for start < len(data) && strings.IndexByte(", \t\r\n", data[start]) >= 0 {
+			start++
+		}
+		line += bytes.Count(data[counted:start], []byte("\n"))
+		counted = start
+
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return nil, nil, invalid(err)
+		}
+		task := &todov1.Task{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(item, task); err != nil {
+			failed = append(failed, lineError{line: line, err: err})
+			continue
+		}
+		tasks = append(tasks, importedTask{line: line, task: importable(task)})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, invalid(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, invalid(errors.New("data after the array"))
+	}
+	return tasks, failed, nil
+}
+
+// csvColumns is the header of CSV exports.
+var csvColumns = []string{"text", "completed", "completed_at", "due_at", "priority", "tags", "recurrence"}
+
+func encodeCSVTasks(tasks []*todov1.Task) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvColumns)
+	for _, task := range tasks {
+		w.Write([]string{
+			task.Text,
+			strconv.FormatBool(task.Completed),
+			formatTimestamp(task.CompletedAt),
+			formatTimestamp(task.DueAt),
+			priorityLabel(task.Priority),
+			strings.Join(task.Tags, " "),
+			task.Recurrence,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func decodeCSVTasks(data []byte) ([]importedTask, []lineError, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["text"]; !ok {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFormat, ErrMissingTextColumn)
+	}
+
+	var tasks []importedTask
+	var failed []lineError
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			failed = append(failed, lineError{line: perr.StartLine, err: perr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+		}
+		line, _ := r.FieldPos(0)
+		task, err := parseCSVRecord(record, cols)
+		if err != nil {
+			failed = append(failed, lineError{line: line, err: err})
+			continue
+		}
+		tasks = append(tasks, importedTask{line: line, task: task})
+	}
+	return tasks, failed, nil
+}
+
+// parseCSVRecord reads a task from a CSV record whose columns are named by
+// cols. Missing columns are left at their zero values.
+func parseCSVRecord(record []string, cols map[string]int) (*todov1.Task, error) {
+	field := func(name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	task := &todov1.Task{Text: field("text"), Recurrence: field("recurrence")}
+	var err error
+	if v := field("completed"); v != "" {
+		if task.Completed, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid completed %q: want true or false", v)
+		}
+	}
+	if task.CompletedAt, err = parseTimestamp("completed_at", field("completed_at")); err != nil {
+		return nil, err
+	}
+	if task.DueAt, err = parseTimestamp("due_at", field("due_at")); err != nil {
+		return nil, err
+	}
+	if task.Priority, err = parsePriorityLabel(field("priority")); err != nil {
+		return nil, err
+	}
+	task.Tags = strings.Fields(field("tags"))
+	return task, nil
+}
+
+// formatTimestamp writes Unix seconds as an RFC 3339 time in UTC, or an
+// empty string for zero.
+func formatTimestamp(sec int64) string {
+	if sec == 0 {
+		return ""
+	}
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+}
+
+// parseTimestamp reads the RFC 3339 time in the named column as Unix
+// seconds; an empty value is zero.
+func parseTimestamp(column, v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: want an RFC 3339 time such as 2026-01-02T15:04:05Z", column, v)
+	}
+	return t.Unix(), nil
+}
+
+// priorityLabel names p as P0 to P3, or returns an empty string for no
+// priority.
+func priorityLabel(p todov1.Priority) string {
+	if p == todov1.Priority_PRIORITY_UNSPECIFIED {
+		return ""
+	}
+	return strings.TrimPrefix(p.String(), "PRIORITY_")
+}
+
+// parsePriorityLabel is the inverse of priorityLabel.
+func parsePriorityLabel(v string) (todov1.Priority, error) {
+	if v == "" {
+		return todov1.Priority_PRIORITY_UNSPECIFIED, nil
+	}
+	p, ok := todov1.Priority_value["PRIORITY_"+strings.ToUpper(v)]
+	if !ok {
+		return 0, fmt.Errorf("invalid priority %q: want P0 to P3", v)
+	}
+	return todov1.Priority(p), nil
+}
+
+// singleLine replaces the line breaks in text with spaces, for formats with
+// a task per line.
+func singleLine(text string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+}
+
+// splitLines splits data into lines without their line endings.
+func splitLines(data []byte) []string {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+func encodeMarkdownTasks(tasks []*todov1.Task) []byte {
+	var buf bytes.Buffer
+	for _, task := range tasks {
+		box := " "
+		if task.Completed {
+			box = "x"
+		}
+		fmt.Fprintf(&buf, "- [%s] %s\n", box, singleLine(task.Text))
+	}
+	return buf.Bytes()
+}
+
+var checklistItem = regexp.MustCompile(`^[-*+]\s+\[([ xX])\](?:\s+(.*))?$`)
+
+func decodeMarkdownTasks(data []byte) ([]importedTask, []lineError) {
+	var tasks []importedTask
+	var failed []lineError
+	for i, line := range splitLines(data) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := checklistItem.FindStringSubmatch(line)
+		if m == nil {
+			failed = append(failed, lineError{line: i + 1, err: ErrNotChecklistItem})
+			continue
+		}
+		tasks = append(tasks, importedTask{line: i + 1, task: &todov1.Task{
+			Text:      m[2],
+			Completed: m[1] != " ",
+		}})
+	}
+	return tasks, failed
+}
+
+// todoTxtDate is the layout of todo.txt dates.
+const todoTxtDate = "2006-01-02"
+
+// todoTxtPriority returns the todo.txt letter for p: A for P0 to D for P3,
+// or an empty string for no priority.
+func todoTxtPriority(p todov1.Priority) string {
+	if p == todov1.Priority_PRIORITY_UNSPECIFIED {
+		return ""
+	}
+	return string(rune('A' + p - todov1.Priority_PRIORITY_P0))
+}
+
+// parseTodoTxtPriority is the inverse of todoTxtPriority.
+func parseTodoTxtPriority(letter string) (todov1.Priority, error) {
+	if len(letter) != 1 || letter[0] < 'A' || letter[0] > 'D' {
+		return 0, fmt.Errorf("invalid priority %q: want A to D", letter)
+	}
+	return todov1.Priority_PRIORITY_P0 + todov1.Priority(letter[0]-'A'), nil
+}
+
+func encodeTodoTxtTasks(tasks []*todov1.Task, loc *time.Location) []byte {
+	date := func(sec int64) string {
+		return time.Unix(sec, 0).In(loc).Format(todoTxtDate)
+	}
+	var buf bytes.Buffer
+	for _, task := range tasks {
+		var parts []string
+		priority := todoTxtPriority(task.Priority)
+		if task.Completed {
+			// A creation date is only allowed after a completion date.
+			parts = append(parts, "x")
+			if task.CompletedAt != 0 {
+				parts = append(parts, date(task.CompletedAt), date(task.CreatedAt))
+			}
+		} else {
+			if priority != "" {
+				parts = append(parts, "("+priority+")")
+			}
+			parts = append(parts, date(task.CreatedAt))
+		}
+		parts = append(parts, singleLine(task.Text))
+		for _, tag := range task.Tags {
+			parts = append(parts, "+"+tag)
+		}
+		if task.DueAt != 0 {
+			parts = append(parts, "due:"+date(task.DueAt))
+		}
+		if task.Completed && priority != "" {
+			// Done tasks keep their priority as a tag, by convention.
+			parts = append(parts, "pri:"+priority)
+		}
+		buf.WriteString(strings.Join(parts, " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+var todoTxtPriorityToken = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+func decodeTodoTxtTasks(data []byte, loc *time.Location) ([]importedTask, []lineError) {
+	var tasks []importedTask
+	var failed []lineError
+	for i, line := range splitLines(data) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		task, err := parseTodoTxtLine(fields, loc)
+		if err != nil {
+			failed = append(failed, lineError{line: i + 1, err: err})
+			continue
+		}
+		tasks = append(tasks, importedTask{line: i + 1, task: task})
+	}
+	return tasks, failed
+}
+
+// parseTodoTxtLine reads a task from the fields of a todo.txt line. The
+// creation date is skipped, since imported tasks are new.
+func parseTodoTxtLine(fields []string, loc *time.Location) (*todov1.Task, error) {
+	task := &todov1.Task{}
+	isDate := func() bool {
+		if len(fields) == 0 {
+			return false
+		}
+		_, err := time.ParseInLocation(todoTxtDate, fields[0], loc)
+		return err == nil
+	}
+	if fields[0] == "x" {
+		task.Completed = true
+		fields = fields[1:]
+		if isDate() {
+			completed, _ := time.ParseInLocation(todoTxtDate, fields[0], loc)
+			task.CompletedAt = completed.Unix()
+			fields = fields[1:]
+			if isDate() {
+				fields = fields[1:]
+			}
+		}
+	} else {
+		if m := todoTxtPriorityToken.FindStringSubmatch(fields[0]); m != nil {
+			p, err := parseTodoTxtPriority(m[1])
+			if err != nil {
+				return nil, err
+			}
+			task.Priority = p
+			fields = fields[1:]
+		}
+		if isDate() {
+			fields = fields[1:]
+		}
+	}
+
+	var words []string
+	for _, f := range fields {
+		switch {
+		case strings.HasPrefix(f, "+") && len(f) > 1:
+			task.Tags = append(task.Tags, f[1:])
+		case strings.HasPrefix(f, "due:"):
+			due, err := time.ParseInLocation(todoTxtDate, f[len("due:"):], loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid due date %q: want YYYY-MM-DD", f)
+			}
+			task.DueAt = due.Unix()
+		case strings.HasPrefix(f, "pri:"):
+			p, err := parseTodoTxtPriority(f[len("pri:"):])
+			if err != nil {
+				return nil, err
+			}
+			task.Priority = p
+		default:
+			words = append(words, f)
+		}
+	}
+	task.Text = strings.Join(words, " ")
+	return task, nil
+}
+
+func (s *TodoServer) ExportTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.ExportTasksRequest],
+) (*connect.Response[todov1.ExportTasksResponse], error) {
+	q, err := parseTaskQuery(&todov1.GetTasksRequest{
+		ListId:  req.Msg.ListId,
+		Status:  req.Msg.Status,
+		OrderBy: todov1.TaskOrder_TASK_ORDER_POSITION,
+	}, s.now())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if q.listID != "" {
+		if _, err := s.loadList(ctx, q.listID, todov1.ListRole_LIST_ROLE_VIEWER); err != nil {
+			return nil, err
+		}
+	}
+	if q.access, err = s.accessOf(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+	tasks, _, err := s.queryTasks(q)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list tasks: %w", err))
+	}
+	data, err := encodeTasks(req.Msg.Format, tasks, s.now().Location())
+	if errors.Is(err, ErrInvalidFormat) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to encode tasks: %w", err))
+	}
+	return connect.NewResponse(&todov1.ExportTasksResponse{Data: data}), nil
+}
+
+// ImportTasks validates every task of the file before adding any, so that
+// all invalid lines are reported at once, and then adds them all or none.
+func (s *TodoServer) ImportTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.ImportTasksRequest],
+) (*connect.Response[todov1.ImportTasksResponse], error) {
+	rows, failed, err := decodeTasks(req.Msg.Format, req.Msg.Data, s.now().Location())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if len(rows)+len(failed) > MaxImportTasks {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrImportTooLarge)
+	}
+
+	tasks := make([]*todov1.Task, 0, len(rows))
+	for _, row := range rows {
+		task, err := s.newTask(ctx, &todov1.AddTaskRequest{
+			Text:       row.task.Text,
+			ListId:     req.Msg.ListId,
+			DueAt:      row.task.DueAt,
+			Priority:   row.task.Priority,
+			Tags:       row.task.Tags,
+			Recurrence: row.task.Recurrence,
+		})
+		if err != nil {
+			failed = append(failed, lineError{line: row.line, err: err})
+			continue
+		}
+		if row.task.Completed {
+			task.Completed = true
+			task.CompletedAt = row.task.CompletedAt
+			if task.CompletedAt == 0 {
+				task.CompletedAt = task.CreatedAt
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	if len(failed) > 0 {
+		slices.SortFunc(failed, func(a, b lineError) int { return a.line - b.line })
+		return nil, importError(connect.CodeInvalidArgument, failed)
+	}
+
+	err = s.applyBatch(userFromContext(ctx), len(tasks), func(i int) error {
+		return s.addTask(tasks[i])
+	}, func(code connect.Code, failed []itemError) error {
+		lines := make([]lineError, len(failed))
+		for i, f := range failed {
+			lines[i] = lineError{line: rows[f.index].line, err: f.err}
+		}
+		return importError(code, lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&todov1.ImportTasksResponse{Tasks: tasks}), nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"todo-list/todo/v1"
+)
+
+func TestTaskFormatsRoundTrip(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	midnight := time.Date(2026, 3, 10, 0, 0, 0, 0, loc).Unix()
+	tasks := []*todov1.Task{
+		{
+			Text:      "Buy milk",
+			CreatedAt: midnight - 86400,
+			DueAt:     midnight,
+			Priority:  todov1.Priority_PRIORITY_P1,
+			Tags:      []string{"errands", "home"},
+		},
+		{
+			Text:        "File taxes, finally",
+			CreatedAt:   midnight - 2*86400,
+			Completed:   true,
+			CompletedAt: midnight - 86400,
+			Priority:    todov1.Priority_PRIORITY_P0,
+		},
+	}
+	// What each format carries of the tasks above.
+	for _, tt := range []struct {
+		format todov1.TaskFormat
+		keep   func(task *todov1.Task) *todov1.Task
+	}{
+		{format: todov1.TaskFormat_TASK_FORMAT_JSON, keep: importable},
+		{format: todov1.TaskFormat_TASK_FORMAT_CSV, keep: importable},
+		{format: todov1.TaskFormat_TASK_FORMAT_TODO_TXT, keep: importable},
+		{format: todov1.TaskFormat_TASK_FORMAT_MARKDOWN, keep: func(task *todov1.Task) *todov1.Task {
+			return &todov1.Task{Text: task.Text, Completed: task.Completed}
+		}},
+	} {
+		data, err := encodeTasks(tt.format, tasks, loc)
+		if err != nil {
+			t.Fatalf("encodeTasks(%v) error = %v", tt.format, err)
+		}
+		got, failed, err := decodeTasks(tt.format, data, loc)
+		if err != nil || len(failed) != 0 {
+			t.Fatalf("decodeTasks(%v) = %v, %v; want no errors for\n%s", tt.format, failed, err, data)
+		}
+		if len(got) != len(tasks) {
+			t.Fatalf("decodeTasks(%v) returned %d tasks, want %d", tt.format, len(got), len(tasks))
+		}
+		for i, want := range tasks {
+			if !proto.Equal(got[i].task, tt.keep(want)) {
+				t.Errorf("%v round trip = %v, want %v", tt.format, got[i].task, tt.keep(want))
+			}
+		}
+	}
+}
+
+func TestDecodeTaskErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		format    todov1.TaskFormat
+		data      string
+		wantLines []int // lines reported as invalid
+		wantTasks int
+	}{
+		{
+			name:      "markdown",
+			format:    todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+			data:      "# Groceries\n\n- [ ] Milk\n* [X] Eggs\nBread\n- [?] Butter\n",
+			wantLines: []int{5, 6},
+			wantTasks: 2,
+		},
+		{
+			name:      "todo.txt",
+			format:    todov1.TaskFormat_TASK_FORMAT_TODO_TXT,
+			data:      "(A) 2026-03-01 Call mom +family\n(F) Low priority\nx 2026-03-02 2026-03-01 Done due:2026-03-05\nPay rent due:tomorrow\n",
+			wantLines: []int{2, 4},
+			wantTasks: 2,
+		},
+		{
+			name:      "CSV",
+			format:    todov1.TaskFormat_TASK_FORMAT_CSV,
+			data:      "Text,Priority,Notes\nMilk,P1,skimmed\nEggs,urgent,\nBread\n",
+			wantLines: []int{3},
+			wantTasks: 2,
+		},
+		{
+			name:      "JSON",
+			format:    todov1.TaskFormat_TASK_FORMAT_JSON,
+			data:      "[\n  {\"text\": \"Milk\", \"color\": \"blue\"},\n  {\n    \"text\": 7\n  },\n\n  {\"text\": 8}, {\"text\": \"Eggs\"}\n]\n",
+			wantLines: []int{3, 7},
+			wantTasks: 2,
+		},
+	} {
+		tasks, failed, err := decodeTasks(tt.format, []byte(tt.data), time.UTC)
+		if err != nil {
+			t.Errorf("%s: decodeTasks() error = %v", tt.name, err)
+			continue
+		}
+		var lines []int
+		for _, f := range failed {
+			lines = append(lines, f.line)
+		}
+		if !reflect.DeepEqual(lines, tt.wantLines) || len(tasks) != tt.wantTasks {
+			t.Errorf("%s: decodeTasks() = %d tasks, invalid lines %v; want %d tasks, lines %v", tt.name, len(tasks), lines, tt.wantTasks, tt.wantLines)
+		}
+	}
+
+	for _, tt := range []struct {
+		format todov1.TaskFormat
+		data   string
+	}{
+		{format: todov1.TaskFormat_TASK_FORMAT_JSON, data: `{"text": "not an array"}`},
+		{format: todov1.TaskFormat_TASK_FORMAT_JSON, data: `[{"text": "Milk"}] []`},
+		{format: todov1.TaskFormat_TASK_FORMAT_JSON, data: `[{"text": "Milk"}`},
+		{format: todov1.TaskFormat_TASK_FORMAT_CSV, data: "title,due\nMilk,\n"},
+		{format: todov1.TaskFormat(99), data: ""},
+	} {
+		if _, _, err := decodeTasks(tt.format, []byte(tt.data), time.UTC); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("decodeTasks(%v, %q) error = %v, want ErrInvalidFormat", tt.format, tt.data, err)
+		}
+	}
+}
+
+// importLines returns the lines named by the ImportLineError details of err.
+func importLines(t *testing.T, err error) []int32 {
+	t.Helper()
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		t.Fatalf("error %v is not a connect error", err)
+	}
+	var lines []int32
+	for _, d := range cerr.Details() {
+		msg, err := d.Value()
+		if err != nil {
+			t.Fatalf("detail %q: %v", d.Type(), err)
+		}
+		if line, ok := msg.(*todov1.ImportLineError); ok {
+			lines = append(lines, line.Line)
+		}
+	}
+	return lines
+}
+
+func TestImportTasks(t *testing.T) {
+	forEachStore(t, testImportTasks)
+}
+
+func testImportTasks(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+	list := mustCreateList(t, server, "Groceries")
+	importTasks := func(data string) (*todov1.ImportTasksResponse, error) {
+		resp, err := server.ImportTasks(ctx, connect.NewRequest(&todov1.ImportTasksRequest{
+			Format: todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+			Data:   []byte(data),
+			ListId: list.Id,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	}
+
+	_, err := importTasks("- [ ] Milk\n- [ ]\nEggs\n- [ ] " + strings.Repeat("x", MaxTaskTextLength+1) + "\n")
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("ImportTasks() with invalid lines error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+	if got, want := importLines(t, err), []int32{2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportTasks() error details name lines %v, want %v", got, want)
+	}
+	if got := len(tasksByID(t, server)); got != 0 {
+		t.Fatalf("got %d tasks after a refused import, want 0", got)
+	}
+
+	resp, err := importTasks("- [ ] Milk\n- [x] Eggs\n")
+	if err != nil {
+		t.Fatalf("ImportTasks() error = %v", err)
+	}
+	if len(resp.Tasks) != 2 || resp.Tasks[0].ListId != list.Id || !resp.Tasks[1].Completed || resp.Tasks[1].CompletedAt == 0 {
+		t.Errorf("ImportTasks() = %v, want an open and a completed task in %q", resp.Tasks, list.Id)
+	}
+
+	exported, err := server.ExportTasks(ctx, connect.NewRequest(&todov1.ExportTasksRequest{
+		Format: todov1.TaskFormat_TASK_FORMAT_MARKDOWN,
+		ListId: list.Id,
+		Status: todov1.TaskStatus_TASK_STATUS_OPEN,
+	}))
+	if err != nil {
+		t.Fatalf("ExportTasks() error = %v", err)
+	}
+	if got, want := string(exported.Msg.Data), "- [ ] Milk\n"; got != want {
+		t.Errorf("ExportTasks() = %q, want %q", got, want)
+	}
+
+	// An import is undone as a whole.
+	if undone := mustUndo(t, ctx, server); len(undone.RemovedIds) != 2 {
+		t.Errorf("Undo() of ImportTasks removed %v, want both tasks", undone.RemovedIds)
+	}
+}
+
+func TestImportTasksMissingList(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+
+	_, err := server.ImportTasks(context.Background(), connect.NewRequest(&todov1.ImportTasksRequest{
+		Format: todov1.TaskFormat_TASK_FORMAT_TODO_TXT,
+		Data:   []byte("\nWater plants\nFeed cat\n"),
+		ListId: "missing",
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("ImportTasks() into a missing list error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	if got, want := importLines(t, err), []int32{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportTasks() error details name lines %v, want %v", got, want)
+	}
+}
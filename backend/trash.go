@@ -0,0 +1,309 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+const (
+	// DefaultTrashRetention is how long deleted tasks stay in the trash
+	// before the janitor purges them.
+	DefaultTrashRetention = 30 * 24 * time.Hour
+
+	// trashSweepInterval is how often the janitor looks for expired tasks
+	// and events, unless a retention is shorter still.
+	trashSweepInterval = time.Hour
+)
+
+// WithTrashRetention replaces DefaultTrashRetention as the time deleted tasks
+// are kept in the trash.
+func WithTrashRetention(d time.Duration) ServerOption {
+	return func(s *TodoServer) {
+		s.trashRetention = d
+	}
+}
+
+// janitor calls sweep every interval until it is closed.
+type janitor struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startJanitor starts a janitor. Callers must stop it with close.
+func startJanitor(interval time.Duration, sweep func()) *janitor {
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+	return j
+}
+
+// close stops the janitor and waits for it to exit. It is safe to call more
+// than once.
+func (j *janitor) close() {
+	j.stopOnce.Do(func() { close(j.stop) })
+	<-j.done
+}
+
+// purgeExpiredTrash permanently removes the tasks that have been in the trash
+// for longer than the retention.
+func (s *TodoServer) purgeExpiredTrash() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trash, err := s.store.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+	cutoff := s.now().Add(-s.trashRetention).Unix()
+	for _, task := range trash {
+		if task.DeletedAt > cutoff {
+			continue
+		}
+		if err := s.purgeTask(task); err != nil {
+			return fmt.Errorf("failed to purge task %s: %w", task.Id, err)
+		}
+	}
+	return nil
+}
+
+// sweep is the janitor's sweep, which empties the trash and then the history
+// of what has expired. It has nobody to report errors to.
+func (s *TodoServer) sweep() {
+	if err := s.purgeExpiredTrash(); err != nil {
+		log.Printf("Janitor: %v", err)
+	}
+	if err := s.compactHistory(); err != nil {
+		log.Printf("Janitor: %v", err)
+	}
+}
+
+// loadTrashedTask returns the trashed task with the given ID after checking
+// that the caller has at least role need on it. Callers must hold s.mu.
+func (s *TodoServer) loadTrashedTask(ctx context.Context, id string, need todov1.ListRole) (*todov1.Task, error) {
+	task, err := s.store.GetTrashedTask(id)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, ErrTaskNotFound)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+	}
+	if err := s.authorizeTask(ctx, task, need); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *TodoServer) ListTrash(
+	ctx context.Context,
+	req *connect.Request[todov1.ListTrashRequest],
+) (*connect.Response[todov1.ListTrashResponse], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trash, err := s.store.ListTrash()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list trash: %w", err))
+	}
+	access, err := s.accessOf(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list lists: %w", err))
+	}
+	tasks := make([]*todov1.Task, 0, len(trash))
+	for _, task := range trash {
+		if access.canView(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *todov1.Task) int {
+		if c := cmp.Compare(b.DeletedAt, a.DeletedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return connect.NewResponse(&todov1.ListTrashResponse{Tasks: tasks}), nil
+}
+
+// RestoreTask brings a trashed task back together with the subtasks that were
+// trashed along with it, parents first so that every restored subtask finds
+// its parent in place.
+func (s *TodoServer) RestoreTask(
+	ctx context.Context,
+	req *connect.Request[todov1.RestoreTaskRequest],
+) (*connect.Response[todov1.RestoreTaskResponse], error) {
+	if strings.TrimSpace(req.Msg.Id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+
+	defer s.lockAs(userFromContext(ctx))()
+
+	trashed, err := s.loadTrashedTask(ctx, req.Msg.Id, todov1.ListRole_LIST_ROLE_EDITOR)
+	if err != nil {
+		return nil, err
+	}
+	trash, err := s.store.ListTrash()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list trash: %w", err))
+	}
+	children := subtasksByParent(trash)
+
+	// The store's snapshots are shared, so bump versions on copies.
+	restored := proto.Clone(trashed).(*todov1.Task)
+	restored.Version++
+	task, err := s.restoreTask(restored)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to restore task: %w", err))
+	}
+	pending := children[trashed.Id]
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if current.DeletedAt < trashed.DeletedAt {
+			// Deleted on its own before its parent was.
+			continue
+		}
+		restored := proto.Clone(current).(*todov1.Task)
+		restored.Version++
+		if _, err := s.restoreTask(restored); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to restore subtask: %w", err))
+		}
+		pending = append(pending, children[current.Id]...)
+	}
+	return connect.NewResponse(&todov1.RestoreTaskResponse{Task: task}), nil
+}
+
+// restoreTask makes the trashed task live again at the version it carries,
+// announces it and adds it to the indexes. Whatever it pointed to that is
+// gone by now is let go: it becomes top-level if its parent is gone, moves to
+// the inbox if its list is, and drops blockers that no longer exist. If it is
+// recurring and has been completed or come due, its next instance is added
+// as well. Callers must hold s.mu.
+func (s *TodoServer) restoreTask(trashed *todov1.Task) (*todov1.Task, error) {
+	task, err := s.restoredState(trashed)
+	if err != nil {
+		return nil, err
+	}
+	next := s.takeRecurrence(task)
+	if err := s.putRestored(task); err != nil {
+		return nil, err
+	}
+	if next != nil {
+		if err := s.addInstance(next); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// restoredState returns a copy of the trashed task as restoreTask brings it
+// back. Callers must hold s.mu.
+func (s *TodoServer) restoredState(trashed *todov1.Task) (*todov1.Task, error) {
+	task := proto.Clone(trashed).(*todov1.Task)
+	task.DeletedAt = 0
+	if task.ParentId != "" {
+		parent, err := s.store.GetTask(task.ParentId)
+		switch {
+		case errors.Is(err, ErrTaskNotFound):
+			task.ParentId = ""
+		case err != nil:
+			return nil, err
+		default:
+			task.ListId = parent.ListId
+		}
+	}
+	if task.ParentId == "" && task.ListId != "" {
+		if _, err := s.store.GetList(task.ListId); errors.Is(err, ErrListNotFound) {
+			task.ListId = ""
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(id string) bool {
+		_, err := s.store.GetTask(id)
+		return err != nil
+	})
+	return task, nil
+}
+
+// putRestored stores task, a state returned by restoredState, in place of
+// the trashed task, without rolling over its recurrence. Callers must hold
+// s.mu.
+func (s *TodoServer) putRestored(task *todov1.Task) error {
+	trashed, err := s.store.GetTrashedTask(task.Id)
+	if err != nil {
+		return err
+	}
+	err = s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_RESTORED, task, func(ev *todov1.HistoryEvent) error {
+		return s.store.RestoreTask(task, ev)
+	})
+	if err != nil {
+		return err
+	}
+	s.usePosition(task.Position)
+	s.order.insert(task)
+	s.search.add(task)
+	s.blockers.add(task)
+	s.counts.update(trashed, task)
+	s.scheduleReminder(task)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_ADDED, task)
+	return nil
+}
+
+func (s *TodoServer) PurgeTask(
+	ctx context.Context,
+	req *connect.Request[todov1.PurgeTaskRequest],
+) (*connect.Response[todov1.PurgeTaskResponse], error) {
+	if strings.TrimSpace(req.Msg.Id) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidTaskID)
+	}
+
+	defer s.lockAs(userFromContext(ctx))()
+
+	task, err := s.loadTrashedTask(ctx, req.Msg.Id, todov1.ListRole_LIST_ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.purgeTask(task); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to purge task: %w", err))
+	}
+	return connect.NewResponse(&todov1.PurgeTaskResponse{}), nil
+}
+
+// purgeTask permanently removes task from the trash. Callers must hold s.mu.
+func (s *TodoServer) purgeTask(task *todov1.Task) error {
+	err := s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED, task, func(ev *todov1.HistoryEvent) error {
+		return s.store.PurgeTask(task.Id, ev)
+	})
+	if err != nil {
+		return err
+	}
+	s.counts.add(task, -1)
+	return nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+// trashIDs returns the IDs of the caller's trashed tasks in the order
+// ListTrash returns them.
+func trashIDs(t *testing.T, server *TodoServer) []string {
+	t.Helper()
+	resp, err := server.ListTrash(context.Background(), connect.NewRequest(&todov1.ListTrashRequest{}))
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	return taskIDs(resp.Msg.Tasks)
+}
+
+func mustDeleteTask(t *testing.T, server *TodoServer, id string, policy todov1.SubtaskDeletePolicy) {
+	t.Helper()
+	_, err := server.DeleteTask(context.Background(), connect.NewRequest(&todov1.DeleteTaskRequest{Id: id, SubtaskPolicy: policy}))
+	if err != nil {
+		t.Fatalf("DeleteTask(%q) error = %v", id, err)
+	}
+}
+
+func restoreTask(server *TodoServer, id string) (*todov1.Task, error) {
+	resp, err := server.RestoreTask(context.Background(), connect.NewRequest(&todov1.RestoreTaskRequest{Id: id}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg.Task, nil
+}
+
+func TestTrash(t *testing.T) {
+	forEachStore(t, testTrash)
+}
+
+func testTrash(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	keep := mustAddTask(t, server, "Keep", "")
+	drop := mustAddTask(t, server, "Drop", "")
+	mustDeleteTask(t, server, drop.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+
+	if _, ok := tasksByID(t, server)[drop.Id]; ok {
+		t.Errorf("GetTasks() still returns deleted task %q", drop.Id)
+	}
+	resp, err := server.ListTrash(ctx, connect.NewRequest(&todov1.ListTrashRequest{}))
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	if len(resp.Msg.Tasks) != 1 || resp.Msg.Tasks[0].Id != drop.Id || resp.Msg.Tasks[0].DeletedAt == 0 {
+		t.Fatalf("ListTrash() = %v, want only %q with its deletion time", resp.Msg.Tasks, drop.Id)
+	}
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: drop.Id})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("CompleteTask() on a trashed task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+
+	restored, err := restoreTask(server, drop.Id)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if restored.DeletedAt != 0 || restored.Text != drop.Text {
+		t.Errorf("RestoreTask() = %v, want the live task back", restored)
+	}
+	if _, ok := tasksByID(t, server)[drop.Id]; !ok {
+		t.Errorf("GetTasks() does not return restored task %q", drop.Id)
+	}
+	if got := trashIDs(t, server); len(got) != 0 {
+		t.Errorf("trash after restoring = %v, want it empty", got)
+	}
+	if _, err := restoreTask(server, keep.Id); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("RestoreTask() on a live task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+
+	mustDeleteTask(t, server, drop.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	purge := func(id string) error {
+		_, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: id}))
+		return err
+	}
+	if err := purge(keep.Id); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("PurgeTask() on a live task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	if err := purge(drop.Id); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	if got := trashIDs(t, server); len(got) != 0 {
+		t.Errorf("trash after purging = %v, want it empty", got)
+	}
+	if _, err := restoreTask(server, drop.Id); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("RestoreTask() on a purged task error = %v, want code %v", err, connect.CodeNotFound)
+	}
+}
+
+func TestRestoreTaskLinks(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+	cascade := todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE
+
+	list := mustCreateList(t, server, "Home")
+	parent := mustAddTask(t, server, "Parent", list.Id)
+	child := mustAddSubtask(t, server, "Child", parent.Id)
+	grandchild := mustAddSubtask(t, server, "Grandchild", child.Id)
+	mustDeleteTask(t, server, parent.Id, cascade)
+
+	// Restoring the parent brings back the subtasks deleted with it.
+	if _, err := restoreTask(server, parent.Id); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	tasks := tasksByID(t, server)
+	if tasks[child.Id].GetParentId() != parent.Id || tasks[grandchild.Id].GetParentId() != child.Id {
+		t.Fatalf("restored tasks = %v, want the subtree back in place", tasks)
+	}
+
+	// A subtask whose parent is still in the trash comes back at the top
+	// level, and one whose list is gone comes back in the inbox.
+	mustDeleteTask(t, server, parent.Id, cascade)
+	if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{Id: list.Id})); err != nil {
+		t.Fatalf("DeleteList() error = %v", err)
+	}
+	got, err := restoreTask(server, child.Id)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if got.ParentId != "" || got.ListId != "" {
+		t.Errorf("RestoreTask() = %v, want a top-level task in the inbox", got)
+	}
+	if want := []string{parent.Id}; !reflect.DeepEqual(trashIDs(t, server), want) {
+		t.Errorf("trash = %v, want %v", trashIDs(t, server), want)
+	}
+
+	// Blockers that are gone by the time a task is restored are dropped.
+	waiting := mustAddTask(t, server, "Waiting", "")
+	blocker := mustAddTask(t, server, "Blocker", "")
+	if _, err := server.AddBlocker(ctx, connect.NewRequest(&todov1.AddBlockerRequest{Id: waiting.Id, BlockerId: blocker.Id})); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	mustDeleteTask(t, server, waiting.Id, cascade)
+	mustDeleteTask(t, server, blocker.Id, cascade)
+	if got, err := restoreTask(server, waiting.Id); err != nil || len(got.BlockedBy) != 0 {
+		t.Errorf("RestoreTask() = %v, %v; want no blockers", got, err)
+	}
+}
+
+func TestTrashJanitor(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now), WithTrashRetention(24*time.Hour))
+
+	old := mustAddTask(t, server, "Old", "")
+	mustDeleteTask(t, server, old.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	clock.Advance(12 * time.Hour)
+	recent := mustAddTask(t, server, "Recent", "")
+	mustDeleteTask(t, server, recent.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+
+	clock.Advance(12*time.Hour - time.Second)
+	if err := server.purgeExpiredTrash(); err != nil {
+		t.Fatalf("purgeExpiredTrash() error = %v", err)
+	}
+	if got, want := trashIDs(t, server), []string{recent.Id, old.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("trash before the retention ends = %v, want %v", got, want)
+	}
+
+	clock.Advance(time.Second)
+	if err := server.purgeExpiredTrash(); err != nil {
+		t.Fatalf("purgeExpiredTrash() error = %v", err)
+	}
+	if got, want := trashIDs(t, server), []string{recent.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("trash after the retention ends = %v, want %v", got, want)
+	}
+}
+
+func TestTrashOtherOwner(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	resp, err := server.AddTask(alice, connect.NewRequest(&todov1.AddTaskRequest{Text: "Private"}))
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	id := resp.Msg.Task.Id
+	if _, err := server.DeleteTask(alice, connect.NewRequest(&todov1.DeleteTaskRequest{Id: id})); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	trash, err := server.ListTrash(bob, connect.NewRequest(&todov1.ListTrashRequest{}))
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	if len(trash.Msg.Tasks) != 0 {
+		t.Errorf("ListTrash() for another user = %v, want nothing", trash.Msg.Tasks)
+	}
+	if _, err := server.RestoreTask(bob, connect.NewRequest(&todov1.RestoreTaskRequest{Id: id})); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("RestoreTask() by another user error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+	if _, err := server.PurgeTask(bob, connect.NewRequest(&todov1.PurgeTaskRequest{Id: id})); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("PurgeTask() by another user error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+}
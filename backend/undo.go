@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"todo-list/todo/v1"
+)
+
+// DefaultUndoDepth is how many operations each user can undo.
+const DefaultUndoDepth = 20
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrUndoConflict  = errors.New("the tasks have changed since; the operation can no longer be undone")
+)
+
+// WithUndoDepth replaces DefaultUndoDepth as the number of operations each
+// user can undo.
+func WithUndoDepth(n int) ServerOption {
+	return func(s *TodoServer) {
+		s.undo.depth = n
+	}
+}
+
+// taskChange is one task's state before and after a step of an operation.
+// A nil before means the step created the task; an after with DeletedAt set
+// means it moved the task to the trash.
+type taskChange struct {
+	before, after *todov1.Task
+}
+
+// undoEntry holds the changes made by one operation, in the order they were
+// made.
+type undoEntry struct {
+	changes []taskChange
+}
+
+// undoLog keeps the most recent operations of each user, newest last.
+// It is not safe for concurrent use; TodoServer guards it with its mutex.
+type undoLog struct {
+	depth   int
+	entries map[string][]*undoEntry // user ID -> operations
+}
+
+func newUndoLog() *undoLog {
+	return &undoLog{
+		depth:   DefaultUndoDepth,
+		entries: make(map[string][]*undoEntry),
+	}
+}
+
+// push records e as user's latest operation, forgetting the oldest one if
+// the user is at the limit.
+func (l *undoLog) push(user string, e *undoEntry) {
+	if l.depth <= 0 {
+		return
+	}
+	entries := append(l.entries[user], e)
+	if len(entries) > l.depth {
+		entries = slices.Delete(entries, 0, len(entries)-l.depth)
+	}
+	l.entries[user] = entries
+}
+
+// pop removes and returns user's latest operation, or nil if there is none.
+func (l *undoLog) pop(user string) *undoEntry {
+	entries := l.entries[user]
+	if len(entries) == 0 {
+		return nil
+	}
+	e := entries[len(entries)-1]
+	entries[len(entries)-1] = nil
+	if len(entries) == 1 {
+		delete(l.entries, user)
+	} else {
+		l.entries[user] = entries[:len(entries)-1]
+	}
+	return e
+}
+
+// recordUndo runs fn, which changes tasks on behalf of user, and logs the
+// changes it makes so that user can undo them. Nothing is logged if fn
+// fails. Callers must hold s.mu.
+func (s *TodoServer) recordUndo(user string, fn func() error) error {
+	s.recording = &undoEntry{}
+	err := fn()
+	e := s.recording
+	s.recording = nil
+	if err == nil && len(e.changes) > 0 {
+		s.undo.push(user, e)
+	}
+	return err
+}
+
+// recordChange adds a step to the operation being recorded, if any. Callers
+// must hold s.mu.
+func (s *TodoServer) recordChange(before, after *todov1.Task) {
+	if s.recording == nil || proto.Equal(before, after) {
+		return
+	}
+	change := taskChange{after: proto.Clone(after).(*todov1.Task)}
+	if before != nil {
+		change.before = proto.Clone(before).(*todov1.Task)
+	}
+	s.recording.changes = append(s.recording.changes, change)
+}
+
+// currentState returns the task with the given ID, live or trashed, or nil if
+// it no longer exists. Callers must hold s.mu.
+func (s *TodoServer) currentState(id string) (*todov1.Task, error) {
+	task, err := s.store.GetTask(id)
+	if errors.Is(err, ErrTaskNotFound) {
+		task, err = s.store.GetTrashedTask(id)
+	}
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, nil
+	}
+	return task, err
+}
+
+// checkUndo reports ErrUndoConflict unless every task changed by e is still
+// as e left it, apart from the new versions that undoing later operations
+// gave it, the lists its tasks were in still exist and the tasks it
+// created have not been given subtasks since. Callers must hold s.mu.
+func (s *TodoServer) checkUndo(e *undoEntry) error {
+	final := make(map[string]*todov1.Task)
+	for _, c := range e.changes {
+		final[c.after.Id] = c.after
+	}
+	for id, want := range final {
+		got, err := s.currentState(id)
+		if err != nil {
+			return err
+		}
+		if got == nil {
+			return ErrUndoConflict
+		}
+		got = proto.Clone(got).(*todov1.Task)
+		got.Version = want.Version
+		if !proto.Equal(got, want) {
+			return ErrUndoConflict
+		}
+	}
+
+	for _, c := range e.changes {
+		if c.before == nil || c.before.ListId == "" {
+			continue
+		}
+		if _, err := s.store.GetList(c.before.ListId); errors.Is(err, ErrListNotFound) {
+			return ErrUndoConflict
+		} else if err != nil {
+			return err
+		}
+	}
+
+	tasks, err := s.store.ListTasks()
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if _, ok := final[task.Id]; ok || task.ParentId == "" {
+			continue
+		}
+		for _, c := range e.changes {
+			if c.before == nil && c.after.Id == task.ParentId {
+				return ErrUndoConflict
+			}
+		}
+	}
+	return nil
+}
+
+// revert takes one change back. The task must currently be in the change's
+// after state. If exact is set, as when rolling back changes nobody has seen,
+// the task gets back the version it had before the change; otherwise taking
+// the change back is a change of its own and gets a new version. The task is
+// brought back as it was, so a recurring task that has come due since is not
+// rolled over again: the instance that took its rule is gone with the change
+// that created it. Callers must hold s.mu.
+func (s *TodoServer) revert(c taskChange, exact bool) error {
+	if c.before == nil {
+		return s.eraseTask(c.after)
+	}
+	version := c.before.Version
+	if !exact {
+		current, err := s.currentState(c.after.Id)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrTaskNotFound
+		}
+		version = current.Version + 1
+	}
+	if c.after.DeletedAt != 0 {
+		task, err := s.restoredState(c.after)
+		if err != nil {
+			return err
+		}
+		task.Version = version
+		return s.putRestored(task)
+	}
+	task := proto.Clone(c.before).(*todov1.Task)
+	task.Version = version
+	return s.putTask(c.after, task)
+}
+
+// eraseTask removes task for good, as if it had never been added, and drops
+// it from the blockers of the tasks waiting for it. Callers must hold s.mu.
+func (s *TodoServer) eraseTask(task *todov1.Task) error {
+	if err := s.unlinkBlocker(task.Id); err != nil {
+		return err
+	}
+	err := s.recordEvent(todov1.HistoryEventType_HISTORY_EVENT_TYPE_PURGED, task, func(ev *todov1.HistoryEvent) error {
+		return s.store.DeleteTask(task.Id, ev)
+	})
+	if err != nil {
+		return err
+	}
+	s.order.remove(task)
+	s.search.remove(task.Id)
+	s.blockers.remove(task)
+	s.counts.add(task, -1)
+	s.reminders.schedule(task.Id, 0)
+	s.publish(todov1.TaskEventType_TASK_EVENT_TYPE_DELETED, task)
+	return nil
+}
+
+// Undo takes back the caller's most recent AddTask, DeleteTask, task update
+// or batch. It is refused if any task the operation changed has been changed
+// again since, in which case the operation is forgotten.
+func (s *TodoServer) Undo(
+	ctx context.Context,
+	req *connect.Request[todov1.UndoRequest],
+) (*connect.Response[todov1.UndoResponse], error) {
+	user := userFromContext(ctx)
+
+	defer s.lockAs(user)()
+
+	e := s.undo.pop(user)
+	if e == nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, ErrNothingToUndo)
+	}
+	if err := s.checkUndo(e); errors.Is(err, ErrUndoConflict) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	} else if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to check tasks: %w", err))
+	}
+	// Undoing is editing again, which a member who has lost access since may
+	// not do; the operation is forgotten as after a conflict.
+	for _, c := range e.changes {
+		if err := s.authorizeTask(ctx, c.after, todov1.ListRole_LIST_ROLE_EDITOR); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := len(e.changes) - 1; i >= 0; i-- {
+		if err := s.revert(e.changes[i], false); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to undo: %w", err))
+		}
+	}
+
+	resp := &todov1.UndoResponse{}
+	seen := make(map[string]bool)
+	for _, c := range e.changes {
+		id := c.after.Id
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		// The first change to a task tells whether it existed before.
+		if c.before == nil {
+			resp.RemovedIds = append(resp.RemovedIds, id)
+			continue
+		}
+		task, err := s.store.GetTask(id)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to load task: %w", err))
+		}
+		resp.Tasks = append(resp.Tasks, task)
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"todo-list/todo/v1"
+)
+
+func undo(ctx context.Context, server *TodoServer) (*todov1.UndoResponse, error) {
+	resp, err := server.Undo(ctx, connect.NewRequest(&todov1.UndoRequest{}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+func mustUndo(t *testing.T, ctx context.Context, server *TodoServer) *todov1.UndoResponse {
+	t.Helper()
+	resp, err := undo(ctx, server)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	return resp
+}
+
+func TestUndo(t *testing.T) {
+	forEachStore(t, testUndo)
+}
+
+func testUndo(t *testing.T, newServer func() *TodoServer) {
+	server := newServer()
+	ctx := context.Background()
+
+	task := mustAddTask(t, server, "Draft", "")
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         task.Id,
+		Task:       &todov1.Task{Text: "Final"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"text"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+
+	resp := mustUndo(t, ctx, server)
+	if len(resp.Tasks) != 1 || resp.Tasks[0].Completed || resp.Tasks[0].Text != "Final" {
+		t.Errorf("Undo() of CompleteTask = %v, want the open task", resp.Tasks)
+	}
+	resp = mustUndo(t, ctx, server)
+	if len(resp.Tasks) != 1 || resp.Tasks[0].Text != "Draft" {
+		t.Errorf("Undo() of UpdateTask = %v, want the original text", resp.Tasks)
+	}
+	resp = mustUndo(t, ctx, server)
+	if want := []string{task.Id}; !reflect.DeepEqual(resp.RemovedIds, want) || len(resp.Tasks) != 0 {
+		t.Errorf("Undo() of AddTask = %v, want %v removed", resp, want)
+	}
+	if got := len(tasksByID(t, server)); got != 0 {
+		t.Errorf("got %d tasks after undoing AddTask, want 0", got)
+	}
+	if got := trashIDs(t, server); len(got) != 0 {
+		t.Errorf("trash after undoing AddTask = %v, want it empty", got)
+	}
+
+	if _, err := undo(ctx, server); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("Undo() with nothing left error = %v, want code %v", err, connect.CodeFailedPrecondition)
+	}
+}
+
+func TestUndoDeleteTask(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	parent := mustAddTask(t, server, "Parent", "")
+	child := mustAddSubtask(t, server, "Child", parent.Id)
+	waiting := mustAddTask(t, server, "Waiting", "")
+	if _, err := server.AddBlocker(ctx, connect.NewRequest(&todov1.AddBlockerRequest{Id: waiting.Id, BlockerId: parent.Id})); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	mustDeleteTask(t, server, parent.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_CASCADE)
+
+	mustUndo(t, ctx, server)
+	tasks := tasksByID(t, server)
+	if tasks[child.Id].GetParentId() != parent.Id {
+		t.Errorf("subtask after undo = %v, want it back under %q", tasks[child.Id], parent.Id)
+	}
+	if got, want := tasks[waiting.Id].GetBlockedBy(), []string{parent.Id}; !reflect.DeepEqual(got, want) {
+		t.Errorf("blocked_by after undo = %v, want %v", got, want)
+	}
+	if got := trashIDs(t, server); len(got) != 0 {
+		t.Errorf("trash after undoing DeleteTask = %v, want it empty", got)
+	}
+}
+
+func TestUndoCompleteRecurringTask(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	ctx := context.Background()
+
+	task := addRecurring(t, server, "Standup notes", "FREQ=DAILY", clock.Now().Add(time.Hour).Unix())
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	next := otherTasks(t, server, task.Id)
+	if len(next) != 1 {
+		t.Fatalf("got %d new tasks after completing, want 1", len(next))
+	}
+
+	resp := mustUndo(t, ctx, server)
+	if want := []string{next[0].Id}; !reflect.DeepEqual(resp.RemovedIds, want) {
+		t.Errorf("Undo() removed %v, want the next instance %v", resp.RemovedIds, want)
+	}
+	got := tasksByID(t, server)
+	if len(got) != 1 || got[task.Id].Completed || got[task.Id].Recurrence != "FREQ=DAILY" {
+		t.Errorf("tasks after undo = %v, want only the open original with its rule", got)
+	}
+}
+
+func TestUndoRecurringTaskPastDue(t *testing.T) {
+	clock := newFakeClock()
+	server := mustNewServer(t, NewMemoryStore(), WithClock(clock.Now))
+	ctx := context.Background()
+
+	completed := addRecurring(t, server, "Standup notes", "FREQ=DAILY", clock.Now().Add(time.Hour).Unix())
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: completed.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	clock.Advance(2 * time.Hour)
+	mustUndo(t, ctx, server)
+	got := tasksByID(t, server)
+	if len(got) != 1 || got[completed.Id].Completed || got[completed.Id].Recurrence != "FREQ=DAILY" {
+		t.Errorf("tasks after undoing CompleteTask past the due time = %v, want only the open original with its rule", got)
+	}
+
+	deleted := addRecurring(t, server, "Water plants", "FREQ=DAILY", clock.Now().Add(time.Hour).Unix())
+	mustDeleteTask(t, server, deleted.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	clock.Advance(2 * time.Hour)
+	mustUndo(t, ctx, server)
+	got = tasksByID(t, server)
+	if len(got) != 2 || got[deleted.Id].Recurrence != "FREQ=DAILY" {
+		t.Errorf("tasks after undoing DeleteTask past the due time = %v, want the restored task with its rule and no new instance", got)
+	}
+}
+
+func TestUndoConflict(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore())
+	ctx := context.Background()
+
+	list := mustCreateList(t, server, "Errands")
+	task := mustAddTask(t, server, "Buy milk", list.Id)
+	if _, err := server.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+		Id:         task.Id,
+		Task:       &todov1.Task{Priority: todov1.Priority_PRIORITY_P0},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"priority"}},
+	})); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	// Deleting the list moves the task to the inbox, which is not undoable.
+	if _, err := server.DeleteList(ctx, connect.NewRequest(&todov1.DeleteListRequest{
+		Id:     list.Id,
+		Policy: todov1.ListDeletePolicy_LIST_DELETE_POLICY_MOVE,
+	})); err != nil {
+		t.Fatalf("DeleteList() error = %v", err)
+	}
+
+	if _, err := undo(ctx, server); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("Undo() after a later change error = %v, want code %v", err, connect.CodeFailedPrecondition)
+	}
+	if got := tasksByID(t, server)[task.Id]; got.Priority != todov1.Priority_PRIORITY_P0 || got.ListId != "" {
+		t.Errorf("task after refused undo = %v, want it unchanged", got)
+	}
+}
+
+func TestUndoPerUser(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore(), WithUndoDepth(2))
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+
+	add := func(ctx context.Context, text string) string {
+		t.Helper()
+		resp, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: text}))
+		if err != nil {
+			t.Fatalf("AddTask(%q) error = %v", text, err)
+		}
+		return resp.Msg.Task.Id
+	}
+	add(alice, "First")
+	second := add(alice, "Second")
+	third := add(alice, "Third")
+	bobs := add(bob, "Bob's")
+
+	for _, want := range []string{third, second} {
+		if resp := mustUndo(t, alice, server); !reflect.DeepEqual(resp.RemovedIds, []string{want}) {
+			t.Errorf("Undo() removed %v, want [%s]", resp.RemovedIds, want)
+		}
+	}
+	// Only the last two operations are kept.
+	if _, err := undo(alice, server); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("Undo() past the depth error = %v, want code %v", err, connect.CodeFailedPrecondition)
+	}
+	if resp := mustUndo(t, bob, server); !reflect.DeepEqual(resp.RemovedIds, []string{bobs}) {
+		t.Errorf("Undo() for bob removed %v, want [%s]", resp.RemovedIds, bobs)
+	}
+}
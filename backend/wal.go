@@ -0,0 +1,495 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"todo-list/todo/v1"
+)
+
+// Sync policies of the WAL store: when appended log records are synced to
+// disk.
+const (
+	// SyncAlways syncs every record before the mutation returns, so an
+	// acknowledged change survives a power loss.
+	SyncAlways = "always"
+	// SyncInterval syncs in the background every WALOptions.SyncInterval; a
+	// power loss can lose the changes of the last interval.
+	SyncInterval = "interval"
+	// SyncNever leaves flushing to the operating system. Changes survive the
+	// process crashing, not the machine.
+	SyncNever = "never"
+)
+
+// WALOptions configure OpenWALStore.
+type WALOptions struct {
+	Sync         string        // SyncAlways, SyncInterval or SyncNever
+	SyncInterval time.Duration // how often SyncInterval syncs
+	// SnapshotEvery is the number of log records after which the log is
+	// compacted into a new snapshot.
+	SnapshotEvery int
+}
+
+// Operations recorded in the write-ahead log, one per mutating TaskStore
+// method.
+const (
+	opCreateTask  = "create_task"
+	opUpdateTask  = "update_task"
+	opDeleteTask  = "delete_task"
+	opTrashTask   = "trash_task"
+	opRestoreTask = "restore_task"
+	opPurgeTask   = "purge_task"
+	opCreateList  = "create_list"
+	opUpdateList  = "update_list"
+	opDeleteList  = "delete_list"
+	opAppendEvent = "append_event"
+	// opCompactHistory records a call to CompactHistory.
+	opCompactHistory = "compact_history"
+)
+
+// mutation is a call to one of the mutating methods of a TaskStore, with the
+// arguments that method takes.
+type mutation struct {
+	op        string
+	id        string
+	deletedAt int64
+	task      *todov1.Task
+	list      *todov1.List
+	event     *todov1.HistoryEvent
+	before    int64
+}
+
+// historyOnly reports whether m only changes the history.
+func (m *mutation) historyOnly() bool {
+	return m.op == opAppendEvent || m.op == opCompactHistory
+}
+
+// apply makes the call that m describes on mem.
+func (m *mutation) apply(mem *memoryStore) error {
+	switch m.op {
+	case opCreateTask:
+		return mem.CreateTask(m.task, m.event)
+	case opUpdateTask:
+		return mem.UpdateTask(m.task, m.event)
+	case opDeleteTask:
+		return mem.DeleteTask(m.id, m.event)
+	case opTrashTask:
+		return mem.TrashTask(m.id, m.deletedAt, m.event)
+	case opRestoreTask:
+		return mem.RestoreTask(m.task, m.event)
+	case opPurgeTask:
+		return mem.PurgeTask(m.id, m.event)
+	case opCreateList:
+		return mem.CreateList(m.list)
+	case opUpdateList:
+		return mem.UpdateList(m.list)
+	case opDeleteList:
+		return mem.DeleteList(m.id)
+	case opAppendEvent:
+		return mem.AppendEvent(m.event)
+	case opCompactHistory:
+		return mem.CompactHistory(m.before)
+	}
+	return fmt.Errorf("unknown operation %q", m.op)
+}
+
+// walRecord is the JSON payload of a log record.
+type walRecord struct {
+	Seq       uint64          `json:"seq"`
+	Op        string          `json:"op"`
+	ID        string          `json:"id,omitempty"`
+	DeletedAt int64           `json:"deleted_at,omitempty"`
+	Task      json.RawMessage `json:"task,omitempty"`
+	List      json.RawMessage `json:"list,omitempty"`
+	Event     json.RawMessage `json:"event,omitempty"`
+	Before    int64           `json:"before,omitempty"`
+}
+
+func encodeMutation(seq uint64, m *mutation) ([]byte, error) {
+	rec := walRecord{Seq: seq, Op: m.op, ID: m.id, DeletedAt: m.deletedAt, Before: m.before}
+	var err error
+	if m.task != nil {
+		if rec.Task, err = protojson.Marshal(m.task); err != nil {
+			return nil, fmt.Errorf("failed to encode task %s: %w", m.task.Id, err)
+		}
+	}
+	if m.list != nil {
+		if rec.List, err = protojson.Marshal(m.list); err != nil {
+			return nil, fmt.Errorf("failed to encode list %s: %w", m.list.Id, err)
+		}
+	}
+	if m.event != nil {
+		if rec.Event, err = protojson.Marshal(m.event); err != nil {
+			return nil, fmt.Errorf("failed to encode event %d: %w", m.event.Seq, err)
+		}
+	}
+	return json.Marshal(rec)
+}
+
+func decodeMutation(payload []byte) (uint64, *mutation, error) {
+	var rec walRecord
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return 0, nil, err
+	}
+	m := &mutation{op: rec.Op, id: rec.ID, deletedAt: rec.DeletedAt, before: rec.Before}
+	if rec.Task != nil {
+		m.task = &todov1.Task{}
+		if err := protojson.Unmarshal(rec.Task, m.task); err != nil {
+			return 0, nil, err
+		}
+	}
+	if rec.List != nil {
+		m.list = &todov1.List{}
+		if err := protojson.Unmarshal(rec.List, m.list); err != nil {
+			return 0, nil, err
+		}
+	}
+	if rec.Event != nil {
+		m.event = &todov1.HistoryEvent{}
+		if err := protojson.Unmarshal(rec.Event, m.event); err != nil {
+			return 0, nil, err
+		}
+	}
+	return rec.Seq, m, nil
+}
+
+// A log record is a header of the payload's length and CRC-32C, both little
+// endian uint32s, followed by the payload.
+const walHeaderSize = 8
+
+var walTable = crc32.MakeTable(crc32.Castagnoli)
+
+var (
+	// errTornRecord marks an incomplete last record, left by a crash in the
+	// middle of an append.
+	errTornRecord    = errors.New("torn record")
+	errCorruptRecord = errors.New("corrupt record")
+	errStoreClosed   = errors.New("store is closed")
+)
+
+func frameRecord(payload []byte) []byte {
+	buf := make([]byte, walHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, walTable))
+	copy(buf[walHeaderSize:], payload)
+	return buf
+}
+
+// readRecord returns the payload of the record at the start of b and the
+// size of the record. A record cut short by the end of b, or whose checksum
+// fails while nothing follows it, is torn. So is a tail of zero bytes, which
+// some file systems leave behind after a crash. A record whose length runs
+// past the end of b is only torn if no complete record follows its header;
+// otherwise the length itself is damaged.
+func readRecord(b []byte) ([]byte, int, error) {
+	if len(b) < walHeaderSize {
+		return nil, 0, errTornRecord
+	}
+	n := int(binary.LittleEndian.Uint32(b[0:4]))
+	if n == 0 {
+		if len(bytes.Trim(b, "\x00")) == 0 {
+			return nil, 0, errTornRecord
+		}
+		return nil, 0, errCorruptRecord
+	}
+	size := walHeaderSize + n
+	if size > len(b) {
+		if holdsRecord(b[walHeaderSize:]) {
+			return nil, 0, errCorruptRecord
+		}
+		return nil, 0, errTornRecord
+	}
+	payload := b[walHeaderSize:size]
+	if crc32.Checksum(payload, walTable) != binary.LittleEndian.Uint32(b[4:8]) {
+		if size == len(b) {
+			return nil, 0, errTornRecord
+		}
+		return nil, 0, errCorruptRecord
+	}
+	return payload, size, nil
+}
+
+// holdsRecord reports whether a complete record with a valid checksum starts
+// anywhere in b. A torn append leaves part of a single payload, which does not.
+func holdsRecord(b []byte) bool {
+	for off := 0; off+walHeaderSize < len(b); off++ {
+		n := int(binary.LittleEndian.Uint32(b[off : off+4]))
+		end := off + walHeaderSize + n
+		if n == 0 || end > len(b) {
+			continue
+		}
+		if crc32.Checksum(b[off+walHeaderSize:end], walTable) == binary.LittleEndian.Uint32(b[off+4:off+8]) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAheadLog is the storeWriter of OpenWALStore. It appends each mutation
+// to the log at path+".wal" and, every SnapshotEvery records, writes the
+// whole store to the snapshot at path and empties the log. Events go into
+// the log with the change they record and, at a snapshot, into the history
+// log at path+".history".
+//
+// Records carry increasing sequence numbers and the snapshot the number of
+// the last record it includes, so that a crash between writing a snapshot and
+// emptying the log does not apply records twice.
+type writeAheadLog struct {
+	path    string
+	opts    WALOptions
+	mem     *memoryStore
+	history *historyLog
+	seq     uint64 // of the last record written
+	records int    // written since the last snapshot
+
+	mu    sync.Mutex // guards the fields below against the background syncer
+	f     *os.File
+	size  int64 // of the log up to the end of the last complete record
+	dirty bool  // records were appended since the last sync
+	err   error // set if the log could not be cut back after a failed write
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// OpenWALStore opens the TaskStore whose snapshot is the file at path and
+// whose write-ahead log is path+".wal", recovering the contents left by a
+// previous run, crashed or not, from both. The snapshot has the layout of
+// OpenFileStore's file, so a file store's data can be opened by this store.
+//
+// Unlike the file store, which rewrites every task on each change, this
+// store only appends the change to the log.
+func OpenWALStore(path string, opts WALOptions) (TaskStore, error) {
+	switch opts.Sync {
+	case SyncAlways, SyncNever:
+	case SyncInterval:
+		if opts.SyncInterval <= 0 {
+			return nil, fmt.Errorf("sync interval must be positive, got %s", opts.SyncInterval)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sync policy %q (want %s, %s or %s)", opts.Sync, SyncAlways, SyncInterval, SyncNever)
+	}
+	if opts.SnapshotEvery < 1 {
+		return nil, fmt.Errorf("snapshot interval must be at least 1 record, got %d", opts.SnapshotEvery)
+	}
+
+	mem := newMemoryStore()
+	seq, err := readStoreFile(path, mem)
+	if err != nil {
+		return nil, err
+	}
+	legacy := len(mem.events) > 0
+	history, err := openHistoryLog(path+".history", mem)
+	if err != nil {
+		return nil, err
+	}
+	w := &writeAheadLog{path: path, opts: opts, mem: mem, history: history, seq: seq}
+	if err := w.replay(); err != nil {
+		return nil, err
+	}
+	w.f, err = os.OpenFile(w.logPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+	if legacy {
+		// Move the history out of the snapshot, where snapshots written
+		// before the history log kept it.
+		if err := w.snapshot(); err != nil {
+			w.f.Close()
+			return nil, err
+		}
+	}
+	if opts.Sync == SyncInterval {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.syncLoop()
+	}
+	return &fileStore{mem: mem, w: w}, nil
+}
+
+func (w *writeAheadLog) logPath() string {
+	return w.path + ".wal"
+}
+
+// replay applies the records of the log that the snapshot does not include
+// to w.mem, cutting off a torn last record.
+func (w *writeAheadLog) replay() error {
+	data, err := os.ReadFile(w.logPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+
+	var off int
+	for off < len(data) {
+		payload, n, err := readRecord(data[off:])
+		if errors.Is(err, errTornRecord) {
+			log.Printf("Discarding torn record at the end of %s (%d bytes)", w.logPath(), len(data)-off)
+			if err := os.Truncate(w.logPath(), int64(off)); err != nil {
+				return fmt.Errorf("failed to cut off torn record: %w", err)
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid record at offset %d of %s: %w", off, w.logPath(), err)
+		}
+		seq, m, err := decodeMutation(payload)
+		if err != nil {
+			return fmt.Errorf("failed to parse record at offset %d of %s: %w", off, w.logPath(), err)
+		}
+		off += n
+		if seq <= w.seq {
+			// Already in the snapshot.
+			continue
+		}
+		if seq != w.seq+1 {
+			return fmt.Errorf("record %d follows record %d in %s", seq, w.seq, w.logPath())
+		}
+		if m.event != nil && m.event.Seq <= w.mem.lastEventSeq() {
+			// Already in the history log, which a crash during the last
+			// snapshot left ahead of the snapshot.
+			m.event = nil
+		}
+		if err := m.apply(w.mem); err != nil {
+			return fmt.Errorf("failed to replay record %d (%s) of %s: %w", seq, m.op, w.logPath(), err)
+		}
+		w.seq = seq
+		w.records++
+	}
+	w.size = int64(off)
+	return nil
+}
+
+func (w *writeAheadLog) write(m *mutation) error {
+	payload, err := encodeMutation(w.seq+1, m)
+	if err != nil {
+		return err
+	}
+	if err := w.append(frameRecord(payload)); err != nil {
+		return err
+	}
+	w.seq++
+	w.records++
+	if w.records >= w.opts.SnapshotEvery {
+		// The record is safe in the log; try again after the next one.
+		if err := w.snapshot(); err != nil {
+			log.Printf("Failed to snapshot %s: %v", w.path, err)
+		}
+	}
+	return nil
+}
+
+// append writes record to the end of the log, syncing it if the policy says
+// so. If that fails, the log is cut back so that the record is not replayed.
+func (w *writeAheadLog) append(record []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return errStoreClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	_, err := w.f.Write(record)
+	if err == nil && w.opts.Sync == SyncAlways {
+		err = w.f.Sync()
+	}
+	if err != nil {
+		if terr := w.f.Truncate(w.size); terr != nil {
+			w.err = fmt.Errorf("log is damaged: %w", terr)
+		}
+		return fmt.Errorf("failed to append to log: %w", err)
+	}
+	w.size += int64(len(record))
+	w.dirty = w.opts.Sync != SyncAlways
+	return nil
+}
+
+// snapshot writes the history to the history log and the rest of the store
+// to the snapshot file, and empties the log.
+func (w *writeAheadLog) snapshot() error {
+	if err := w.history.sync(w.mem); err != nil {
+		return err
+	}
+	if err := writeStoreFile(w.path, w.mem, w.seq); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// writeStoreFile has synced the snapshot's directory, so the log can be
+	// emptied without the old snapshot coming back after a power loss.
+	// Records left behind by a failure from here on are skipped on replay.
+	if err := w.f.Truncate(0); err != nil {
+		return fmt.Errorf("failed to empty log: %w", err)
+	}
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+	w.size = 0
+	w.records = 0
+	w.dirty = false
+	w.err = nil
+	return nil
+}
+
+// syncLoop syncs the log every SyncInterval while records are appended.
+func (w *writeAheadLog) syncLoop() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.dirty {
+				if err := w.f.Sync(); err != nil {
+					log.Printf("Failed to sync %s: %v", w.logPath(), err)
+				} else {
+					w.dirty = false
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// close compacts the log into a snapshot, so that the next start has nothing
+// to replay, and closes it.
+func (w *writeAheadLog) close() error {
+	if w.f == nil {
+		return nil
+	}
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+	var err error
+	if w.records > 0 {
+		err = w.snapshot()
+	} else if w.dirty {
+		err = w.f.Sync()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err = errors.Join(err, w.f.Close())
+	w.f = nil
+	return err
+}
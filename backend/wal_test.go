@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"todo-list/todo/v1"
+)
+
+// storeState is everything a TaskStore holds, keyed by ID.
+type storeState struct {
+	tasks, trash map[string]*todov1.Task
+	lists        map[string]*todov1.List
+}
+
+func readStoreState(t *testing.T, store TaskStore) storeState {
+	t.Helper()
+	state := storeState{
+		tasks: make(map[string]*todov1.Task),
+		trash: make(map[string]*todov1.Task),
+		lists: make(map[string]*todov1.List),
+	}
+	tasks, err := store.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	for _, task := range tasks {
+		state.tasks[task.Id] = task
+	}
+	trash, err := store.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	for _, task := range trash {
+		state.trash[task.Id] = task
+	}
+	lists, err := store.ListLists()
+	if err != nil {
+		t.Fatalf("ListLists() error = %v", err)
+	}
+	for _, list := range lists {
+		state.lists[list.Id] = list
+	}
+	return state
+}
+
+func checkStoreState(t *testing.T, got, want storeState) {
+	t.Helper()
+	equal := func(a, b map[string]*todov1.Task) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for id, task := range a {
+			if !proto.Equal(task, b[id]) {
+				return false
+			}
+		}
+		return true
+	}
+	if !equal(got.tasks, want.tasks) {
+		t.Errorf("tasks = %v, want %v", got.tasks, want.tasks)
+	}
+	if !equal(got.trash, want.trash) {
+		t.Errorf("trash = %v, want %v", got.trash, want.trash)
+	}
+	if len(got.lists) != len(want.lists) {
+		t.Errorf("lists = %v, want %v", got.lists, want.lists)
+	}
+	for id, list := range want.lists {
+		if !proto.Equal(got.lists[id], list) {
+			t.Errorf("list %s = %v, want %v", id, got.lists[id], list)
+		}
+	}
+}
+
+// checkHistory checks that store holds the events of want.
+func checkHistory(t *testing.T, store TaskStore, want []*todov1.HistoryEvent) {
+	t.Helper()
+	got, err := store.ListEvents()
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ListEvents() returned %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("event %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func mustOpenWALStore(t *testing.T, path string, opts WALOptions) TaskStore {
+	t.Helper()
+	store, err := OpenWALStore(path, opts)
+	if err != nil {
+		t.Fatalf("OpenWALStore() error = %v", err)
+	}
+	return store
+}
+
+// fillStore makes one of each kind of change through a server on store.
+func fillStore(t *testing.T, store TaskStore) {
+	t.Helper()
+	server := mustNewServer(t, store)
+	ctx := context.Background()
+	list := mustCreateList(t, server, "Errands")
+	mustAddTask(t, server, "Buy milk", list.Id)
+	done := mustAddTask(t, server, "Post letter", "")
+	drop := mustAddTask(t, server, "Drop me", "")
+	purge := mustAddTask(t, server, "Purge me", "")
+	if _, err := server.CompleteTask(ctx, connect.NewRequest(&todov1.CompleteTaskRequest{Id: done.Id})); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	mustDeleteTask(t, server, drop.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	mustDeleteTask(t, server, purge.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: purge.Id})); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	if _, err := server.RenameList(ctx, connect.NewRequest(&todov1.RenameListRequest{Id: list.Id, Name: "Chores"})); err != nil {
+		t.Fatalf("RenameList() error = %v", err)
+	}
+}
+
+func TestWALStoreRecoversAfterCrash(t *testing.T) {
+	for _, opts := range []WALOptions{
+		{Sync: SyncAlways, SnapshotEvery: 1000},
+		{Sync: SyncAlways, SnapshotEvery: 3},
+		{Sync: SyncNever, SnapshotEvery: 1},
+	} {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		store := mustOpenWALStore(t, path, opts)
+		fillStore(t, store)
+		want := readStoreState(t, store)
+		history, err := store.ListEvents()
+		if err != nil {
+			t.Fatalf("ListEvents() error = %v", err)
+		}
+
+		// The store is not closed, as if the process had died.
+		reopened := mustOpenWALStore(t, path, opts)
+		checkStoreState(t, readStoreState(t, reopened), want)
+		checkHistory(t, reopened, history)
+
+		// Closing compacts the log, and the store opens from the snapshot.
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if info, err := os.Stat(path + ".wal"); err != nil || info.Size() != 0 {
+			t.Errorf("log after Close() = %v, %v; want it empty", info, err)
+		}
+		reopened = mustOpenWALStore(t, path, opts)
+		checkStoreState(t, readStoreState(t, reopened), want)
+		checkHistory(t, reopened, history)
+		reopened.Close()
+		store.Close()
+	}
+}
+
+func TestWALStoreTornRecord(t *testing.T) {
+	opts := WALOptions{Sync: SyncAlways, SnapshotEvery: 1000}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := mustOpenWALStore(t, path, opts)
+	fillStore(t, store)
+	want := readStoreState(t, store)
+	logData, err := os.ReadFile(path + ".wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := frameRecord([]byte(`{"seq": 100, "op": "delete_list", "id": "x"}`))
+	for name, tail := range map[string][]byte{
+		"partial header":  record[:5],
+		"partial payload": record[:len(record)-3],
+		"bad checksum":    append(append([]byte{}, record[:len(record)-1]...), '!'),
+		"zeroed":          make([]byte, 64),
+	} {
+		if err := os.WriteFile(path+".wal", append(append([]byte{}, logData...), tail...), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		reopened, err := OpenWALStore(path, opts)
+		if err != nil {
+			t.Fatalf("%s: OpenWALStore() error = %v", name, err)
+		}
+		checkStoreState(t, readStoreState(t, reopened), want)
+		if info, err := os.Stat(path + ".wal"); err != nil || info.Size() != int64(len(logData)) {
+			t.Errorf("%s: log is %v, %v after recovery; want the torn record cut off", name, info, err)
+		}
+
+		// Records appended after recovery are replayed next time.
+		if err := reopened.CreateTask(&todov1.Task{Id: "after", Text: "After recovery"}, nil); err != nil {
+			t.Fatalf("%s: CreateTask() error = %v", name, err)
+		}
+		again := mustOpenWALStore(t, path, opts)
+		if _, err := again.GetTask("after"); err != nil {
+			t.Errorf("%s: GetTask() of the task added after recovery error = %v", name, err)
+		}
+		again.Close()
+		reopened.Close()
+		// Closing wrote a snapshot; start from the log again.
+		os.Remove(path)
+		if err := os.WriteFile(path+".wal", logData, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+}
+
+func TestWALStoreRejectsCorruptRecord(t *testing.T) {
+	opts := WALOptions{Sync: SyncAlways, SnapshotEvery: 1000}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := mustOpenWALStore(t, path, opts)
+	defer store.Close()
+	fillStore(t, store)
+
+	logData, err := os.ReadFile(path + ".wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Damage the first record, which is followed by others.
+	logData[walHeaderSize+2] ^= 0xff
+	if err := os.WriteFile(path+".wal", logData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenWALStore(path, opts); err == nil {
+		t.Error("OpenWALStore() error = nil, want error for a corrupt record")
+	}
+}
+
+func TestWALStoreRejectsCorruptLength(t *testing.T) {
+	opts := WALOptions{Sync: SyncAlways, SnapshotEvery: 1000}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := mustOpenWALStore(t, path, opts)
+	defer store.Close()
+	fillStore(t, store)
+
+	logData, err := os.ReadFile(path + ".wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Make the first record, which is followed by others, claim to run past
+	// the end of the log.
+	binary.LittleEndian.PutUint32(logData[0:4], uint32(len(logData)))
+	if err := os.WriteFile(path+".wal", logData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenWALStore(path, opts); err == nil {
+		t.Error("OpenWALStore() error = nil, want error for a corrupt record length")
+	}
+	if info, err := os.Stat(path + ".wal"); err != nil || info.Size() != int64(len(logData)) {
+		t.Errorf("log is %v, %v after the failed open; want it left alone", info, err)
+	}
+}
+
+func TestWALStoreSkipsRecordsInSnapshot(t *testing.T) {
+	opts := WALOptions{Sync: SyncAlways, SnapshotEvery: 1000}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := mustOpenWALStore(t, path, opts)
+	fillStore(t, store)
+	want := readStoreState(t, store)
+	logData, err := os.ReadFile(path + ".wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// A crash after the snapshot was written but before the log was emptied
+	// leaves both.
+	if err := os.WriteFile(path+".wal", logData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reopened := mustOpenWALStore(t, path, opts)
+	defer reopened.Close()
+	checkStoreState(t, readStoreState(t, reopened), want)
+}
+
+func TestWALStoreSyncInterval(t *testing.T) {
+	opts := WALOptions{Sync: SyncInterval, SyncInterval: time.Millisecond, SnapshotEvery: 1000}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := mustOpenWALStore(t, path, opts)
+	fillStore(t, store)
+	want := readStoreState(t, store)
+	time.Sleep(10 * time.Millisecond)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := store.CreateTask(&todov1.Task{Id: "late"}, nil); err == nil {
+		t.Error("CreateTask() after Close() error = nil, want error")
+	}
+
+	reopened := mustOpenWALStore(t, path, opts)
+	defer reopened.Close()
+	checkStoreState(t, readStoreState(t, reopened), want)
+}
+
+func TestOpenWALStoreReadsFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	fillStore(t, store)
+	want := readStoreState(t, store)
+	store.Close()
+
+	reopened := mustOpenWALStore(t, path, WALOptions{Sync: SyncAlways, SnapshotEvery: 1000})
+	defer reopened.Close()
+	checkStoreState(t, readStoreState(t, reopened), want)
+}
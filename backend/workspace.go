@@ -0,0 +1,647 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"todo-list/todo/v1"
+)
+
+const (
+	// DefaultWorkspace holds the tasks of calls that name no workspace. It
+	// always exists and is kept in the store given by -data.
+	DefaultWorkspace = "default"
+	// WorkspaceHeader is the request header naming the workspace a
+	// TodoService call acts on.
+	WorkspaceHeader = "X-Workspace"
+)
+
+var (
+	ErrInvalidWorkspaceID   = errors.New("workspace ID must be 1 to 63 lower-case letters, digits or dashes, starting with a letter or digit")
+	ErrWorkspaceNotFound    = errors.New("workspace not found")
+	ErrWorkspaceExists      = errors.New("workspace already exists")
+	ErrWorkspaceDeleting    = errors.New("workspace is still being deleted; try again")
+	ErrWorkspaceDataLeft    = errors.New("data of another workspace is left where this one would be stored")
+	ErrDefaultWorkspace     = errors.New("the default workspace cannot be deleted")
+	ErrWorkspaceDenied      = errors.New("API key is bound to another workspace")
+	ErrAdminOnly            = errors.New("only admin keys may manage workspaces")
+	ErrInvalidWorkspaceText = fmt.Errorf("max_task_text_length must be 0 or between %d and %d", MinTaskTextLength, maxTaskLengthLimit)
+	ErrInvalidMaxTasks      = errors.New("max_tasks cannot be negative")
+)
+
+var workspaceIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// validWorkspaceID reports whether id can name a workspace. IDs double as
+// directory names, hence the restricted alphabet.
+func validWorkspaceID(id string) bool {
+	return workspaceIDPattern.MatchString(id)
+}
+
+// workspaceStorage keeps the stores of the workspaces other than the default
+// one and the list of those workspaces.
+type workspaceStorage interface {
+	// create returns the empty store of the new workspace id, or
+	// ErrWorkspaceDataLeft if something is already stored for it.
+	create(id string) (TaskStore, error)
+	// open returns the store of the existing workspace id.
+	open(id string) (TaskStore, error)
+	// remove deletes the closed store of workspace id. Removing a store
+	// that is already gone succeeds.
+	remove(id string) error
+	// load returns the saved workspaces and the IDs of the deleted ones
+	// whose stores may not have been removed yet.
+	load() (workspaces []*todov1.Workspace, deleting []string, err error)
+	save(workspaces []*todov1.Workspace, deleting []string) error
+}
+
+// memoryWorkspaces keeps workspaces for the lifetime of the process.
+type memoryWorkspaces struct{}
+
+func (memoryWorkspaces) create(string) (TaskStore, error)             { return NewMemoryStore(), nil }
+func (memoryWorkspaces) open(string) (TaskStore, error)               { return NewMemoryStore(), nil }
+func (memoryWorkspaces) remove(string) error                          { return nil }
+func (memoryWorkspaces) load() ([]*todov1.Workspace, []string, error) { return nil, nil, nil }
+func (memoryWorkspaces) save([]*todov1.Workspace, []string) error     { return nil }
+
+// dirWorkspaces keeps each workspace's store in a directory of its own under
+// dir, as "<dir>/<id>/tasks.json", and the list of workspaces in
+// "<dir>/workspaces.json".
+type dirWorkspaces struct {
+	dir       string
+	openStore func(path string) (TaskStore, error)
+}
+
+// workspaceFile is the layout of workspaces.json.
+type workspaceFile struct {
+	Workspaces []json.RawMessage `json:"workspaces"`
+	// Deleting holds the IDs of deleted workspaces whose directories may
+	// still be there, so that a crash cannot leave them for a new workspace
+	// with the same ID to find.
+	Deleting []string `json:"deleting,omitempty"`
+}
+
+func (d *dirWorkspaces) create(id string) (TaskStore, error) {
+	entries, err := os.ReadDir(filepath.Join(d.dir, id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read workspace directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return nil, ErrWorkspaceDataLeft
+	}
+	return d.open(id)
+}
+
+func (d *dirWorkspaces) open(id string) (TaskStore, error) {
+	dir := filepath.Join(d.dir, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	return d.openStore(filepath.Join(dir, "tasks.json"))
+}
+
+func (d *dirWorkspaces) remove(id string) error {
+	return os.RemoveAll(filepath.Join(d.dir, id))
+}
+
+func (d *dirWorkspaces) load() ([]*todov1.Workspace, []string, error) {
+	path := filepath.Join(d.dir, "workspaces.json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+	var file workspaceFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workspace file %s: %w", path, err)
+	}
+	workspaces := make([]*todov1.Workspace, 0, len(file.Workspaces))
+	for i, r := range file.Workspaces {
+		ws := &todov1.Workspace{}
+		if err := protojson.Unmarshal(r, ws); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse workspace %d in %s: %w", i, path, err)
+		}
+		if !validWorkspaceID(ws.Id) || ws.Id == DefaultWorkspace {
+			return nil, nil, fmt.Errorf("workspace %d in %s: %w", i, path, ErrInvalidWorkspaceID)
+		}
+		workspaces = append(workspaces, ws)
+	}
+	for _, id := range file.Deleting {
+		if !validWorkspaceID(id) || id == DefaultWorkspace {
+			return nil, nil, fmt.Errorf("deleted workspace %q in %s: %w", id, path, ErrInvalidWorkspaceID)
+		}
+	}
+	return workspaces, file.Deleting, nil
+}
+
+func (d *dirWorkspaces) save(workspaces []*todov1.Workspace, deleting []string) error {
+	file := workspaceFile{Workspaces: make([]json.RawMessage, 0, len(workspaces)), Deleting: deleting}
+	for _, ws := range workspaces {
+		b, err := protojson.Marshal(ws)
+		if err != nil {
+			return fmt.Errorf("failed to encode workspace %s: %w", ws.Id, err)
+		}
+		file.Workspaces = append(file.Workspaces, b)
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode workspaces: %w", err)
+	}
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(d.dir, "workspaces.json"), data)
+}
+
+// workspace is one workspace's TodoServer and the store it serves from.
+type workspace struct {
+	info   *todov1.Workspace
+	server *TodoServer
+	store  TaskStore // nil for the default workspace, whose store is not ours
+
+	// calls is held for reading by every call the workspace serves, so that
+	// closing it can wait for them to finish.
+	calls  sync.RWMutex
+	closed bool
+}
+
+// close stops the workspace's server, waits for the calls it is serving and
+// closes its store. It is safe to call more than once.
+func (ws *workspace) close() error {
+	// Closing the server first ends the WatchTasks streams, which would
+	// otherwise hold ws.calls for as long as their clients stay connected.
+	ws.server.Close()
+	ws.calls.Lock()
+	defer ws.calls.Unlock()
+	if ws.closed {
+		return nil
+	}
+	ws.closed = true
+	if ws.store == nil {
+		return nil
+	}
+	return ws.store.Close()
+}
+
+// workspaceRouter serves TodoService by handing each call to the TodoServer
+// of the caller's workspace, and serves WorkspaceService. Every workspace has
+// a store, history, undo log and watchers of its own, so nothing done in one
+// is seen from another.
+type workspaceRouter struct {
+	mu         sync.RWMutex // guards workspaces and serializes their creation and deletion
+	workspaces map[string]*workspace
+	// deleting holds the IDs of deleted workspaces whose stores have not
+	// been removed yet, which cannot be reused until they are: true while
+	// DeleteWorkspace is closing and removing the store, false if that
+	// failed or was cut short by a restart.
+	deleting map[string]bool
+	storage  workspaceStorage
+	opts     []ServerOption // for every workspace's server, before its own limits
+	now      func() time.Time
+}
+
+// newWorkspaceRouter returns a router serving the default workspace with
+// server and every workspace saved in storage with a server of its own,
+// configured with opts and the workspace's limits. The router does not take
+// ownership of server's store.
+func newWorkspaceRouter(server *TodoServer, storage workspaceStorage, opts ...ServerOption) (*workspaceRouter, error) {
+	r := &workspaceRouter{
+		workspaces: map[string]*workspace{
+			DefaultWorkspace: {info: &todov1.Workspace{Id: DefaultWorkspace, Name: "Default"}, server: server},
+		},
+		deleting: make(map[string]bool),
+		storage:  storage,
+		opts:     opts,
+		now:      time.Now,
+	}
+	saved, deleting, err := storage.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range deleting {
+		r.deleting[id] = false
+	}
+	for _, info := range saved {
+		ws, err := r.openWorkspace(info, false)
+		if err != nil {
+			for _, ws := range r.workspaces {
+				if ws.store != nil {
+					ws.close()
+				}
+			}
+			return nil, fmt.Errorf("failed to open workspace %s: %w", info.Id, err)
+		}
+		r.workspaces[info.Id] = ws
+	}
+	r.finishDeletes()
+	return r, nil
+}
+
+// finishDeletes removes the stores of the deleted workspaces that were left
+// behind, logging the ones that still cannot be removed.
+func (r *workspaceRouter) finishDeletes() {
+	removed := false
+	for id := range r.deleting {
+		if err := r.storage.remove(id); err != nil {
+			log.Printf("Failed to remove the data of deleted workspace %s: %v", id, err)
+			continue
+		}
+		delete(r.deleting, id)
+		removed = true
+	}
+	if removed {
+		if err := r.saveWorkspaces(); err != nil {
+			log.Printf("Failed to save workspaces: %v", err)
+		}
+	}
+}
+
+// openWorkspace starts serving the workspace described by info, which is
+// new if create is set.
+func (r *workspaceRouter) openWorkspace(info *todov1.Workspace, create bool) (*workspace, error) {
+	open := r.storage.open
+	if create {
+		open = r.storage.create
+	}
+	store, err := open(info.Id)
+	if err != nil {
+		return nil, err
+	}
+	opts := slices.Clone(r.opts)
+	if info.MaxTaskTextLength > 0 {
+		opts = append(opts, WithMaxTaskTextLength(int(info.MaxTaskTextLength)))
+	}
+	if info.MaxTasks > 0 {
+		opts = append(opts, WithMaxTasks(int(info.MaxTasks)))
+	}
+	server, err := NewTodoServer(store, opts...)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return &workspace{info: info, server: server, store: store}, nil
+}
+
+// Close stops the server of every workspace and closes the stores of all
+// but the default one. It is safe to call more than once.
+func (r *workspaceRouter) Close() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for id, ws := range r.workspaces {
+		if err := ws.close(); err != nil {
+			log.Printf("Failed to close workspace %s: %v", id, err)
+		}
+	}
+}
+
+// saveWorkspaces records the workspaces other than the default one and the
+// deleted ones whose stores are yet to be removed. Callers must hold r.mu.
+func (r *workspaceRouter) saveWorkspaces() error {
+	var infos []*todov1.Workspace
+	for _, id := range slices.Sorted(maps.Keys(r.workspaces)) {
+		if id != DefaultWorkspace {
+			infos = append(infos, r.workspaces[id].info)
+		}
+	}
+	return r.storage.save(infos, slices.Sorted(maps.Keys(r.deleting)))
+}
+
+// workspaceID returns the workspace a call with ctx and header acts on: the
+// one its API key is bound to, or else the one named by WorkspaceHeader, or
+// else the default one.
+func workspaceID(ctx context.Context, header http.Header) (string, error) {
+	id := strings.TrimSpace(header.Get(WorkspaceHeader))
+	if key, ok := apiKeyFromContext(ctx); ok && key.workspace != "" {
+		if id != "" && id != key.workspace {
+			return "", connect.NewError(connect.CodePermissionDenied, ErrWorkspaceDenied)
+		}
+		return key.workspace, nil
+	}
+	if id == "" {
+		return DefaultWorkspace, nil
+	}
+	return id, nil
+}
+
+// enter returns the workspace a call with ctx and header acts on. The caller
+// must release ws.calls for reading once the call is done.
+func (r *workspaceRouter) enter(ctx context.Context, header http.Header) (*workspace, error) {
+	id, err := workspaceID(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	ws := r.workspaces[id]
+	r.mu.RUnlock()
+	if ws == nil {
+		return nil, connect.NewError(connect.CodeNotFound, ErrWorkspaceNotFound)
+	}
+	ws.calls.RLock()
+	if ws.closed {
+		// Deleted since it was looked up.
+		ws.calls.RUnlock()
+		return nil, connect.NewError(connect.CodeNotFound, ErrWorkspaceNotFound)
+	}
+	return ws, nil
+}
+
+// forward hands a unary call to the server of the caller's workspace.
+func forward[Req, Res any](
+	r *workspaceRouter,
+	ctx context.Context,
+	req *connect.Request[Req],
+	call func(*TodoServer, context.Context, *connect.Request[Req]) (*connect.Response[Res], error),
+) (*connect.Response[Res], error) {
+	ws, err := r.enter(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+	defer ws.calls.RUnlock()
+	return call(ws.server, ctx, req)
+}
+
+// checkAdmin returns a permission-denied error unless the caller may manage
+// workspaces, as anyone may when authentication is disabled.
+func checkAdmin(ctx context.Context) error {
+	if key, ok := apiKeyFromContext(ctx); ok && !key.admin {
+		return connect.NewError(connect.CodePermissionDenied, ErrAdminOnly)
+	}
+	return nil
+}
+
+func (r *workspaceRouter) CreateWorkspace(
+	ctx context.Context,
+	req *connect.Request[todov1.CreateWorkspaceRequest],
+) (*connect.Response[todov1.CreateWorkspaceResponse], error) {
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	w := req.Msg.Workspace
+	info := &todov1.Workspace{
+		Id:                w.GetId(),
+		Name:              strings.TrimSpace(w.GetName()),
+		MaxTaskTextLength: w.GetMaxTaskTextLength(),
+		MaxTasks:          w.GetMaxTasks(),
+	}
+	switch {
+	case !validWorkspaceID(info.Id):
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidWorkspaceID)
+	case info.MaxTaskTextLength != 0 && (info.MaxTaskTextLength < MinTaskTextLength || info.MaxTaskTextLength > maxTaskLengthLimit):
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidWorkspaceText)
+	case info.MaxTasks < 0:
+		return nil, connect.NewError(connect.CodeInvalidArgument, ErrInvalidMaxTasks)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[info.Id]; ok {
+		return nil, connect.NewError(connect.CodeAlreadyExists, ErrWorkspaceExists)
+	}
+	if busy, ok := r.deleting[info.Id]; ok {
+		if busy {
+			return nil, connect.NewError(connect.CodeAborted, ErrWorkspaceDeleting)
+		}
+		// An earlier deletion left the store behind; finish it first.
+		if err := r.storage.remove(info.Id); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to remove the data of the deleted workspace: %w", err))
+		}
+		delete(r.deleting, info.Id)
+	}
+	info.CreatedAt = r.now().Unix()
+	ws, err := r.openWorkspace(info, true)
+	if errors.Is(err, ErrWorkspaceDataLeft) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to open workspace: %w", err))
+	}
+	r.workspaces[info.Id] = ws
+	if err := r.saveWorkspaces(); err != nil {
+		delete(r.workspaces, info.Id)
+		ws.close()
+		r.storage.remove(info.Id)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to save workspaces: %w", err))
+	}
+	return connect.NewResponse(&todov1.CreateWorkspaceResponse{Workspace: info}), nil
+}
+
+func (r *workspaceRouter) ListWorkspaces(
+	ctx context.Context,
+	req *connect.Request[todov1.ListWorkspacesRequest],
+) (*connect.Response[todov1.ListWorkspacesResponse], error) {
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resp := &todov1.ListWorkspacesResponse{Workspaces: []*todov1.Workspace{r.workspaces[DefaultWorkspace].info}}
+	for _, id := range slices.Sorted(maps.Keys(r.workspaces)) {
+		if id != DefaultWorkspace {
+			resp.Workspaces = append(resp.Workspaces, r.workspaces[id].info)
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// DeleteWorkspace ends the workspace's WatchTasks streams, waits for the
+// other calls it is serving and removes its store. The wait happens outside
+// r.mu so that calls to other workspaces are not held up behind it. The
+// workspace is saved as being deleted until its store is gone, so that a
+// store left behind by a failure or a crash is removed later rather than
+// found by a new workspace with the same ID.
+func (r *workspaceRouter) DeleteWorkspace(
+	ctx context.Context,
+	req *connect.Request[todov1.DeleteWorkspaceRequest],
+) (*connect.Response[todov1.DeleteWorkspaceResponse], error) {
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	id := req.Msg.Id
+	if id == DefaultWorkspace {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, ErrDefaultWorkspace)
+	}
+
+	r.mu.Lock()
+	ws, ok := r.workspaces[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, connect.NewError(connect.CodeNotFound, ErrWorkspaceNotFound)
+	}
+	delete(r.workspaces, id)
+	r.deleting[id] = true
+	if err := r.saveWorkspaces(); err != nil {
+		r.workspaces[id] = ws
+		delete(r.deleting, id)
+		r.mu.Unlock()
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to save workspaces: %w", err))
+	}
+	r.mu.Unlock()
+
+	if err := ws.close(); err != nil {
+		log.Printf("Failed to close workspace %s: %v", id, err)
+	}
+	err := r.storage.remove(id)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		// Left for CreateWorkspace or the next start to remove.
+		r.deleting[id] = false
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("workspace deleted but its data could not be removed: %w", err))
+	}
+	delete(r.deleting, id)
+	if err := r.saveWorkspaces(); err != nil {
+		// The store is gone, so the stale record only costs a second,
+		// harmless removal on the next start.
+		log.Printf("Failed to save workspaces: %v", err)
+	}
+	return connect.NewResponse(&todov1.DeleteWorkspaceResponse{}), nil
+}
+
+func (r *workspaceRouter) AddTask(ctx context.Context, req *connect.Request[todov1.AddTaskRequest]) (*connect.Response[todov1.AddTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).AddTask)
+}
+
+func (r *workspaceRouter) GetTasks(ctx context.Context, req *connect.Request[todov1.GetTasksRequest]) (*connect.Response[todov1.GetTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).GetTasks)
+}
+
+func (r *workspaceRouter) DeleteTask(ctx context.Context, req *connect.Request[todov1.DeleteTaskRequest]) (*connect.Response[todov1.DeleteTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).DeleteTask)
+}
+
+func (r *workspaceRouter) UpdateTask(ctx context.Context, req *connect.Request[todov1.UpdateTaskRequest]) (*connect.Response[todov1.UpdateTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).UpdateTask)
+}
+
+func (r *workspaceRouter) CompleteTask(ctx context.Context, req *connect.Request[todov1.CompleteTaskRequest]) (*connect.Response[todov1.CompleteTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).CompleteTask)
+}
+
+func (r *workspaceRouter) ReopenTask(ctx context.Context, req *connect.Request[todov1.ReopenTaskRequest]) (*connect.Response[todov1.ReopenTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ReopenTask)
+}
+
+func (r *workspaceRouter) WatchTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.WatchTasksRequest],
+	stream *todov1.ServerStream[todov1.WatchTasksResponse],
+) error {
+	ws, err := r.enter(ctx, req.Header())
+	if err != nil {
+		return err
+	}
+	defer ws.calls.RUnlock()
+	return ws.server.WatchTasks(ctx, req, stream)
+}
+
+func (r *workspaceRouter) CreateList(ctx context.Context, req *connect.Request[todov1.CreateListRequest]) (*connect.Response[todov1.CreateListResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).CreateList)
+}
+
+func (r *workspaceRouter) GetLists(ctx context.Context, req *connect.Request[todov1.GetListsRequest]) (*connect.Response[todov1.GetListsResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).GetLists)
+}
+
+func (r *workspaceRouter) RenameList(ctx context.Context, req *connect.Request[todov1.RenameListRequest]) (*connect.Response[todov1.RenameListResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).RenameList)
+}
+
+func (r *workspaceRouter) DeleteList(ctx context.Context, req *connect.Request[todov1.DeleteListRequest]) (*connect.Response[todov1.DeleteListResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).DeleteList)
+}
+
+func (r *workspaceRouter) ShareList(ctx context.Context, req *connect.Request[todov1.ShareListRequest]) (*connect.Response[todov1.ShareListResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ShareList)
+}
+
+func (r *workspaceRouter) RevokeListAccess(ctx context.Context, req *connect.Request[todov1.RevokeListAccessRequest]) (*connect.Response[todov1.RevokeListAccessResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).RevokeListAccess)
+}
+
+func (r *workspaceRouter) MoveTask(ctx context.Context, req *connect.Request[todov1.MoveTaskRequest]) (*connect.Response[todov1.MoveTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).MoveTask)
+}
+
+func (r *workspaceRouter) AddTags(ctx context.Context, req *connect.Request[todov1.AddTagsRequest]) (*connect.Response[todov1.AddTagsResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).AddTags)
+}
+
+func (r *workspaceRouter) RemoveTags(ctx context.Context, req *connect.Request[todov1.RemoveTagsRequest]) (*connect.Response[todov1.RemoveTagsResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).RemoveTags)
+}
+
+func (r *workspaceRouter) ListTags(ctx context.Context, req *connect.Request[todov1.ListTagsRequest]) (*connect.Response[todov1.ListTagsResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ListTags)
+}
+
+func (r *workspaceRouter) AddBlocker(ctx context.Context, req *connect.Request[todov1.AddBlockerRequest]) (*connect.Response[todov1.AddBlockerResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).AddBlocker)
+}
+
+func (r *workspaceRouter) RemoveBlocker(ctx context.Context, req *connect.Request[todov1.RemoveBlockerRequest]) (*connect.Response[todov1.RemoveBlockerResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).RemoveBlocker)
+}
+
+func (r *workspaceRouter) ListTrash(ctx context.Context, req *connect.Request[todov1.ListTrashRequest]) (*connect.Response[todov1.ListTrashResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ListTrash)
+}
+
+func (r *workspaceRouter) RestoreTask(ctx context.Context, req *connect.Request[todov1.RestoreTaskRequest]) (*connect.Response[todov1.RestoreTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).RestoreTask)
+}
+
+func (r *workspaceRouter) PurgeTask(ctx context.Context, req *connect.Request[todov1.PurgeTaskRequest]) (*connect.Response[todov1.PurgeTaskResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).PurgeTask)
+}
+
+func (r *workspaceRouter) Undo(ctx context.Context, req *connect.Request[todov1.UndoRequest]) (*connect.Response[todov1.UndoResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).Undo)
+}
+
+func (r *workspaceRouter) BatchAddTasks(ctx context.Context, req *connect.Request[todov1.BatchAddTasksRequest]) (*connect.Response[todov1.BatchAddTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).BatchAddTasks)
+}
+
+func (r *workspaceRouter) BatchUpdateTasks(ctx context.Context, req *connect.Request[todov1.BatchUpdateTasksRequest]) (*connect.Response[todov1.BatchUpdateTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).BatchUpdateTasks)
+}
+
+func (r *workspaceRouter) BatchDeleteTasks(ctx context.Context, req *connect.Request[todov1.BatchDeleteTasksRequest]) (*connect.Response[todov1.BatchDeleteTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).BatchDeleteTasks)
+}
+
+func (r *workspaceRouter) ExportTasks(ctx context.Context, req *connect.Request[todov1.ExportTasksRequest]) (*connect.Response[todov1.ExportTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ExportTasks)
+}
+
+func (r *workspaceRouter) ImportTasks(ctx context.Context, req *connect.Request[todov1.ImportTasksRequest]) (*connect.Response[todov1.ImportTasksResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ImportTasks)
+}
+
+func (r *workspaceRouter) GetTaskHistory(ctx context.Context, req *connect.Request[todov1.GetTaskHistoryRequest]) (*connect.Response[todov1.GetTaskHistoryResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).GetTaskHistory)
+}
+
+func (r *workspaceRouter) ListEvents(ctx context.Context, req *connect.Request[todov1.ListEventsRequest]) (*connect.Response[todov1.ListEventsResponse], error) {
+	return forward(r, ctx, req, (*TodoServer).ListEvents)
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"todo-list/todo/v1"
+)
+
+// inWorkspace returns a request for msg naming workspace id in its header.
+func inWorkspace[T any](id string, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set(WorkspaceHeader, id)
+	return req
+}
+
+func mustNewRouter(t *testing.T, storage workspaceStorage) *workspaceRouter {
+	t.Helper()
+	router, err := newWorkspaceRouter(mustNewServer(t, NewMemoryStore()), storage)
+	if err != nil {
+		t.Fatalf("newWorkspaceRouter() error = %v", err)
+	}
+	t.Cleanup(router.Close)
+	return router
+}
+
+func mustCreateWorkspace(t *testing.T, router *workspaceRouter, ws *todov1.Workspace) *todov1.Workspace {
+	t.Helper()
+	resp, err := router.CreateWorkspace(context.Background(), connect.NewRequest(&todov1.CreateWorkspaceRequest{Workspace: ws}))
+	if err != nil {
+		t.Fatalf("CreateWorkspace(%s) error = %v", ws.Id, err)
+	}
+	return resp.Msg.Workspace
+}
+
+// workspaceTaskTexts returns the text of the caller's tasks in workspace id.
+func workspaceTaskTexts(t *testing.T, ctx context.Context, router *workspaceRouter, id string) []string {
+	t.Helper()
+	resp, err := router.GetTasks(ctx, inWorkspace(id, &todov1.GetTasksRequest{}))
+	if err != nil {
+		t.Fatalf("GetTasks() in %s error = %v", id, err)
+	}
+	var texts []string
+	for _, task := range resp.Msg.Tasks {
+		texts = append(texts, task.Text)
+	}
+	return texts
+}
+
+func TestWorkspaceIsolation(t *testing.T) {
+	router := mustNewRouter(t, memoryWorkspaces{})
+	ctx := context.Background()
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a", Name: " Team A ", MaxTaskTextLength: 10})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-b"})
+
+	if _, err := router.AddTask(ctx, inWorkspace("team-a", &todov1.AddTaskRequest{Text: "Ship it"})); err != nil {
+		t.Fatalf("AddTask() in team-a error = %v", err)
+	}
+	if _, err := router.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Default task"})); err != nil {
+		t.Fatalf("AddTask() without a workspace error = %v", err)
+	}
+	for id, want := range map[string][]string{
+		"team-a":         {"Ship it"},
+		"team-b":         nil,
+		DefaultWorkspace: {"Default task"},
+	} {
+		if got := workspaceTaskTexts(t, ctx, router, id); !reflect.DeepEqual(got, want) {
+			t.Errorf("tasks in %s = %v, want %v", id, got, want)
+		}
+	}
+
+	// team-a's limit on task text does not apply elsewhere.
+	long := &todov1.AddTaskRequest{Text: "Far too long for team A"}
+	if _, err := router.AddTask(ctx, inWorkspace("team-a", long)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("AddTask() of long text in team-a error = %v, want code %v", err, connect.CodeInvalidArgument)
+	}
+	if _, err := router.AddTask(ctx, inWorkspace("team-b", long)); err != nil {
+		t.Errorf("AddTask() of long text in team-b error = %v", err)
+	}
+
+	resp, err := router.ListWorkspaces(ctx, connect.NewRequest(&todov1.ListWorkspacesRequest{}))
+	if err != nil {
+		t.Fatalf("ListWorkspaces() error = %v", err)
+	}
+	var ids []string
+	for _, ws := range resp.Msg.Workspaces {
+		ids = append(ids, ws.Id)
+	}
+	if want := []string{DefaultWorkspace, "team-a", "team-b"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListWorkspaces() = %v, want %v", ids, want)
+	}
+	if got := resp.Msg.Workspaces[1]; got.Name != "Team A" || got.CreatedAt == 0 {
+		t.Errorf("listed team-a = %v, want the trimmed name and a creation time", got)
+	}
+
+	if _, err := router.DeleteWorkspace(ctx, connect.NewRequest(&todov1.DeleteWorkspaceRequest{Id: "team-a"})); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if _, err := router.GetTasks(ctx, inWorkspace("team-a", &todov1.GetTasksRequest{})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("GetTasks() in a deleted workspace error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	// The ID can be used again, for an empty workspace.
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+	if got := workspaceTaskTexts(t, ctx, router, "team-a"); got != nil {
+		t.Errorf("tasks in a recreated workspace = %v, want none", got)
+	}
+}
+
+func TestWorkspaceErrors(t *testing.T) {
+	router := mustNewRouter(t, memoryWorkspaces{})
+	ctx := context.Background()
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+
+	for _, tt := range []struct {
+		ws   *todov1.Workspace
+		code connect.Code
+	}{
+		{ws: nil, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "Team-A"}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "-a"}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "../etc"}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: strings.Repeat("a", 64)}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "b", MaxTaskTextLength: -1}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "b", MaxTaskTextLength: maxTaskLengthLimit + 1}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "b", MaxTasks: -1}, code: connect.CodeInvalidArgument},
+		{ws: &todov1.Workspace{Id: "team-a"}, code: connect.CodeAlreadyExists},
+		{ws: &todov1.Workspace{Id: DefaultWorkspace}, code: connect.CodeAlreadyExists},
+	} {
+		_, err := router.CreateWorkspace(ctx, connect.NewRequest(&todov1.CreateWorkspaceRequest{Workspace: tt.ws}))
+		if connect.CodeOf(err) != tt.code {
+			t.Errorf("CreateWorkspace(%v) error = %v, want code %v", tt.ws, err, tt.code)
+		}
+	}
+
+	for id, code := range map[string]connect.Code{
+		DefaultWorkspace: connect.CodeFailedPrecondition,
+		"missing":        connect.CodeNotFound,
+	} {
+		if _, err := router.DeleteWorkspace(ctx, connect.NewRequest(&todov1.DeleteWorkspaceRequest{Id: id})); connect.CodeOf(err) != code {
+			t.Errorf("DeleteWorkspace(%s) error = %v, want code %v", id, err, code)
+		}
+	}
+	if _, err := router.GetTasks(ctx, inWorkspace("missing", &todov1.GetTasksRequest{})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("GetTasks() in an unknown workspace error = %v, want code %v", err, connect.CodeNotFound)
+	}
+}
+
+func TestDeleteWorkspaceWaitsOutsideLock(t *testing.T) {
+	router := mustNewRouter(t, memoryWorkspaces{})
+	ctx := context.Background()
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-b"})
+
+	// A call in flight in team-a keeps its deletion waiting.
+	ws, err := router.enter(ctx, inWorkspace("team-a", &todov1.GetTasksRequest{}).Header())
+	if err != nil {
+		t.Fatalf("enter(team-a) error = %v", err)
+	}
+	deleted := make(chan error, 1)
+	go func() {
+		_, err := router.DeleteWorkspace(ctx, connect.NewRequest(&todov1.DeleteWorkspaceRequest{Id: "team-a"}))
+		deleted <- err
+	}()
+	for {
+		router.mu.RLock()
+		deleting := router.deleting["team-a"]
+		router.mu.RUnlock()
+		if deleting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Meanwhile other workspaces are served, and team-a's ID is not reused.
+	if got := workspaceTaskTexts(t, ctx, router, "team-b"); got != nil {
+		t.Errorf("tasks in team-b = %v, want none", got)
+	}
+	_, err = router.CreateWorkspace(ctx, connect.NewRequest(&todov1.CreateWorkspaceRequest{Workspace: &todov1.Workspace{Id: "team-a"}}))
+	if connect.CodeOf(err) != connect.CodeAborted {
+		t.Errorf("CreateWorkspace() of a workspace being deleted error = %v, want code %v", err, connect.CodeAborted)
+	}
+
+	ws.calls.RUnlock()
+	if err := <-deleted; err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+}
+
+func TestWorkspaceTaskQuota(t *testing.T) {
+	server := mustNewServer(t, NewMemoryStore(), WithMaxTasks(3))
+	ctx := context.Background()
+	mustAddTask(t, server, "One", "")
+	two := mustAddTask(t, server, "Two", "")
+
+	// A batch that would go past the limit adds nothing.
+	_, err := server.BatchAddTasks(ctx, connect.NewRequest(&todov1.BatchAddTasksRequest{
+		Requests: []*todov1.AddTaskRequest{{Text: "Three"}, {Text: "Four"}},
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("BatchAddTasks() past the limit error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	mustAddTask(t, server, "Three", "")
+
+	// Trashed tasks count until they are purged.
+	mustDeleteTask(t, server, two.Id, todov1.SubtaskDeletePolicy_SUBTASK_DELETE_POLICY_UNSPECIFIED)
+	if _, err := server.AddTask(ctx, connect.NewRequest(&todov1.AddTaskRequest{Text: "Four"})); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("AddTask() with the trash full error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+	if _, err := server.PurgeTask(ctx, connect.NewRequest(&todov1.PurgeTaskRequest{Id: two.Id})); err != nil {
+		t.Fatalf("PurgeTask() error = %v", err)
+	}
+	mustAddTask(t, server, "Four", "")
+}
+
+func TestWorkspaceKeys(t *testing.T) {
+	router := mustNewRouter(t, memoryWorkspaces{})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-b"})
+	const rootToken = "root-token-0123456789"
+	auth := todov1.WithInterceptors(newAuthInterceptor(map[string]apiKey{
+		aliceToken: {user: "alice", workspace: "team-a"},
+		bobToken:   {user: "bob"},
+		rootToken:  {user: "root", admin: true},
+	}))
+	_, handler := todov1.NewTodoServiceHandler(router, auth)
+	todoServer := httptest.NewServer(handler)
+	defer todoServer.Close()
+	_, handler = todov1.NewWorkspaceServiceHandler(router, auth)
+	workspaceServer := httptest.NewServer(handler)
+	defer workspaceServer.Close()
+
+	addTask := func(token, workspace string) error {
+		client := connect.NewClient[todov1.AddTaskRequest, todov1.AddTaskResponse](
+			todoServer.Client(), todoServer.URL+"/todo.v1.TodoService/AddTask", connect.WithProtoJSON())
+		req := connect.NewRequest(&todov1.AddTaskRequest{Text: "From " + token[:3]})
+		req.Header().Set("Authorization", "Bearer "+token)
+		if workspace != "" {
+			req.Header().Set(WorkspaceHeader, workspace)
+		}
+		_, err := client.CallUnary(context.Background(), req)
+		return err
+	}
+	// alice's key is bound to team-a, with or without the header.
+	if err := addTask(aliceToken, ""); err != nil {
+		t.Errorf("AddTask() with a bound key error = %v", err)
+	}
+	if err := addTask(aliceToken, "team-a"); err != nil {
+		t.Errorf("AddTask() naming the key's workspace error = %v", err)
+	}
+	if err := addTask(aliceToken, "team-b"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("AddTask() naming another workspace error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+	// bob's key may use any workspace.
+	if err := addTask(bobToken, "team-b"); err != nil {
+		t.Errorf("AddTask() with an unbound key error = %v", err)
+	}
+	alice := withUser(context.Background(), "alice")
+	bob := withUser(context.Background(), "bob")
+	if got, want := workspaceTaskTexts(t, alice, router, "team-a"), []string{"From ali", "From ali"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alice's tasks in team-a = %v, want %v", got, want)
+	}
+	if got, want := workspaceTaskTexts(t, bob, router, "team-b"), []string{"From bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bob's tasks in team-b = %v, want %v", got, want)
+	}
+	if got := workspaceTaskTexts(t, bob, router, DefaultWorkspace); got != nil {
+		t.Errorf("bob's tasks in the default workspace = %v, want none", got)
+	}
+
+	createWorkspace := func(token string) error {
+		client := connect.NewClient[todov1.CreateWorkspaceRequest, todov1.CreateWorkspaceResponse](
+			workspaceServer.Client(), workspaceServer.URL+"/todo.v1.WorkspaceService/CreateWorkspace", connect.WithProtoJSON())
+		req := connect.NewRequest(&todov1.CreateWorkspaceRequest{Workspace: &todov1.Workspace{Id: "team-c"}})
+		req.Header().Set("Authorization", "Bearer "+token)
+		_, err := client.CallUnary(context.Background(), req)
+		return err
+	}
+	if err := createWorkspace(bobToken); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("CreateWorkspace() without admin rights error = %v, want code %v", err, connect.CodePermissionDenied)
+	}
+	if err := createWorkspace(rootToken); err != nil {
+		t.Errorf("CreateWorkspace() as admin error = %v", err)
+	}
+}
+
+func TestDirWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	storage := &dirWorkspaces{dir: dir, openStore: OpenFileStore}
+	router := mustNewRouter(t, storage)
+	ctx := context.Background()
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a", MaxTasks: 1})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-b"})
+	if _, err := router.AddTask(ctx, inWorkspace("team-a", &todov1.AddTaskRequest{Text: "Kept"})); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	router.Close()
+
+	// The workspaces and their limits survive a restart.
+	router = mustNewRouter(t, storage)
+	if got := workspaceTaskTexts(t, ctx, router, "team-a"); !reflect.DeepEqual(got, []string{"Kept"}) {
+		t.Errorf("tasks in team-a after a restart = %v, want [Kept]", got)
+	}
+	if _, err := router.AddTask(ctx, inWorkspace("team-a", &todov1.AddTaskRequest{Text: "Too many"})); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("AddTask() past team-a's limit after a restart error = %v, want code %v", err, connect.CodeResourceExhausted)
+	}
+
+	if _, err := router.DeleteWorkspace(ctx, connect.NewRequest(&todov1.DeleteWorkspaceRequest{Id: "team-a"})); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "team-a")); !os.IsNotExist(err) {
+		t.Errorf("team-a's directory after DeleteWorkspace() = %v, want it removed", err)
+	}
+	router.Close()
+	router = mustNewRouter(t, storage)
+	if _, err := router.GetTasks(ctx, inWorkspace("team-a", &todov1.GetTasksRequest{})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("GetTasks() in team-a after deleting it and restarting error = %v, want code %v", err, connect.CodeNotFound)
+	}
+	if got := workspaceTaskTexts(t, ctx, router, "team-b"); got != nil {
+		t.Errorf("tasks in team-b = %v, want none", got)
+	}
+}
+
+// stuckWorkspaces is dirWorkspaces whose stores cannot be removed.
+type stuckWorkspaces struct {
+	*dirWorkspaces
+}
+
+func (stuckWorkspaces) remove(string) error {
+	return errors.New("device busy")
+}
+
+func TestDirWorkspacesLeftoverData(t *testing.T) {
+	dir := t.TempDir()
+	storage := &dirWorkspaces{dir: dir, openStore: OpenFileStore}
+	ctx := context.Background()
+
+	// A deletion that cannot remove the store is finished later; until
+	// then the ID cannot be reused.
+	router := mustNewRouter(t, stuckWorkspaces{storage})
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+	if _, err := router.AddTask(ctx, inWorkspace("team-a", &todov1.AddTaskRequest{Text: "Old tenant's"})); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := router.DeleteWorkspace(ctx, connect.NewRequest(&todov1.DeleteWorkspaceRequest{Id: "team-a"})); connect.CodeOf(err) != connect.CodeInternal {
+		t.Fatalf("DeleteWorkspace() with a stuck store error = %v, want code %v", err, connect.CodeInternal)
+	}
+	create := &todov1.CreateWorkspaceRequest{Workspace: &todov1.Workspace{Id: "team-a"}}
+	if _, err := router.CreateWorkspace(ctx, connect.NewRequest(create)); connect.CodeOf(err) != connect.CodeInternal {
+		t.Errorf("CreateWorkspace() over a stuck store error = %v, want code %v", err, connect.CodeInternal)
+	}
+	router.Close()
+
+	router = mustNewRouter(t, storage)
+	if _, err := os.Stat(filepath.Join(dir, "team-a")); !os.IsNotExist(err) {
+		t.Errorf("team-a's directory after a restart = %v, want it removed", err)
+	}
+	mustCreateWorkspace(t, router, &todov1.Workspace{Id: "team-a"})
+	if got := workspaceTaskTexts(t, ctx, router, "team-a"); got != nil {
+		t.Errorf("tasks in the new team-a = %v, want none", got)
+	}
+
+	// Data that no deletion accounts for is never handed to a new workspace.
+	if err := os.MkdirAll(filepath.Join(dir, "team-b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "team-b", "tasks.json"), []byte(`{"tasks": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	create.Workspace.Id = "team-b"
+	if _, err := router.CreateWorkspace(ctx, connect.NewRequest(create)); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("CreateWorkspace() over leftover data error = %v, want code %v", err, connect.CodeFailedPrecondition)
+	}
+}
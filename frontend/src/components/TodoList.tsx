@@ -14,7 +14,10 @@ import { createTodoService, createRequests, type AppTask } from '@/lib/todo_conn
  * @returns The TodoList component as JSX.
  */
 export default function TodoList() {
-  const client = useMemo(() => createTodoService('http://localhost:8080'), []);
+  const client = useMemo(
+    () => createTodoService('http://localhost:8080', process.env.NEXT_PUBLIC_API_TOKEN, process.env.NEXT_PUBLIC_WORKSPACE),
+    [],
+  );
   const [tasks, setTasks] = useState<AppTask[]>([]);
   const [newTask, setNewTask] = useState('');
   const [loading, setLoading] = useState(false);
@@ -24,12 +27,7 @@ export default function TodoList() {
     try {
       setError('');
       const response = await client.getTasks(createRequests.getTasks());
-      const todoItems = (response.tasks || []).map((task: AppTask) => ({
-        id: task.id,
-        text: task.text,
-        createdAt: task.createdAt,
-      }));
-      setTasks(todoItems);
+      setTasks(response.tasks || []);
     } catch (err) {
       console.error('Error fetching tasks:', err);
       setError('Failed to fetch tasks. Please try again.');
@@ -92,6 +90,21 @@ export default function TodoList() {
     }
   };
 
+  const toggleCompleted = async (task: AppTask) => {
+    try {
+      setError('');
+      if (task.completed) {
+        await client.reopenTask(createRequests.reopenTask(task.id));
+      } else {
+        await client.completeTask(createRequests.completeTask(task.id));
+      }
+      await fetchTasks();
+    } catch (err) {
+      console.error('Error updating task status:', err);
+      setError('Failed to update task status. Please try again.');
+    }
+  };
+
   const handleDelete = (id: string, taskText: string) => {
     if (window.confirm(`Are you sure you want to delete this task?\n"${taskText}"`)) {
       deleteTask(id);
@@ -102,6 +115,30 @@ export default function TodoList() {
     fetchTasks();
   }, [fetchTasks]);
 
+  // Reload whenever anyone changes a task; reconnect if the stream drops.
+  useEffect(() => {
+    const controller = new AbortController();
+    const watch = async () => {
+      while (!controller.signal.aborted) {
+        try {
+          for await (const event of client.watchTasks(controller.signal)) {
+            void event;
+            await fetchTasks();
+          }
+        } catch (err) {
+          if (controller.signal.aborted) {
+            return;
+          }
+          console.error('Task stream interrupted:', err);
+        }
+        await new Promise((resolve) => setTimeout(resolve, 2000));
+        await fetchTasks();
+      }
+    };
+    watch();
+    return () => controller.abort();
+  }, [client, fetchTasks]);
+
   const formatDate = (timestamp: number) => {
     return new Date(timestamp * 1000).toLocaleString();
   };
@@ -166,10 +203,20 @@ export default function TodoList() {
                 key={task.id}
                 className="flex items-center justify-between p-4 bg-white border border-gray-200 rounded-lg shadow-sm hover:shadow-md transition-shadow"
               >
+                <input
+                  type="checkbox"
+                  checked={task.completed}
+                  onChange={() => toggleCompleted(task)}
+                  className="mr-4 h-5 w-5 flex-shrink-0"
+                  title={task.completed ? 'Mark as not done' : 'Mark as done'}
+                />
                 <div className="flex-1 min-w-0">
-                  <div className="text-gray-900 font-medium break-words">{task.text}</div>
+                  <div className={`font-medium break-words ${task.completed ? 'text-gray-400 line-through' : 'text-gray-900'}`}>
+                    {task.text}
+                  </div>
                   <div className="text-sm text-gray-500 mt-1">
                     Created: {formatDate(task.createdAt)}
+                    {task.completed && task.completedAt > 0 && ` • Done: ${formatDate(task.completedAt)}`}
                   </div>
                 </div>
                 <button
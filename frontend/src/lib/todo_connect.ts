@@ -1,5 +1,5 @@
 // ConnectRPC Web Client for Todo Service
-import { createClient } from '@connectrpc/connect';
+import { ConnectError, createClient, type Interceptor } from '@connectrpc/connect';
 import { createConnectTransport } from '@connectrpc/connect-web';
 import { create } from '@bufbuild/protobuf';
 import {
@@ -7,11 +7,78 @@ import {
   GetTasksRequest,
   DeleteTaskRequest,
   DeleteTaskResponse,
+  UpdateTaskRequest,
+  CompleteTaskRequest,
+  ReopenTaskRequest,
+  CreateListRequest,
+  RenameListRequest,
+  DeleteListRequest,
+  ShareListRequest,
+  RevokeListAccessRequest,
+  MoveTaskRequest,
+  AddTagsRequest,
+  RemoveTagsRequest,
+  ListTagsRequest,
+  AddBlockerRequest,
+  RemoveBlockerRequest,
+  RestoreTaskRequest,
+  PurgeTaskRequest,
+  BatchItemError,
+  ImportLineError,
+  RetryInfo,
+  HistoryEvent,
+  HistoryEventType,
+  ListEventsRequest,
+  DueFilter,
+  SubtaskDeletePolicy,
+  List,
+  ListDeletePolicy,
+  ListRole,
   Task,
+  TaskEvent,
+  TaskStatus,
+  TaskOrder,
+  TaskNode,
+  TaskView,
   TodoService as TodoServiceDef,
+  Workspace,
+  WorkspaceService as WorkspaceServiceDef,
   AddTaskRequestSchema,
   GetTasksRequestSchema,
   DeleteTaskRequestSchema,
+  UpdateTaskRequestSchema,
+  CompleteTaskRequestSchema,
+  ReopenTaskRequestSchema,
+  WatchTasksRequestSchema,
+  CreateListRequestSchema,
+  GetListsRequestSchema,
+  RenameListRequestSchema,
+  DeleteListRequestSchema,
+  ShareListRequestSchema,
+  RevokeListAccessRequestSchema,
+  MoveTaskRequestSchema,
+  AddTagsRequestSchema,
+  RemoveTagsRequestSchema,
+  ListTagsRequestSchema,
+  AddBlockerRequestSchema,
+  RemoveBlockerRequestSchema,
+  ListTrashRequestSchema,
+  RestoreTaskRequestSchema,
+  PurgeTaskRequestSchema,
+  UndoRequestSchema,
+  BatchAddTasksRequestSchema,
+  BatchUpdateTasksRequestSchema,
+  BatchDeleteTasksRequestSchema,
+  BatchItemErrorSchema,
+  ExportTasksRequestSchema,
+  ImportTasksRequestSchema,
+  ImportLineErrorSchema,
+  RetryInfoSchema,
+  GetTaskHistoryRequestSchema,
+  ListEventsRequestSchema,
+  WorkspaceSchema,
+  Priority,
+  TaskFormat,
 } from './todo_pb';
 
 // Re-export generated types for convenience
@@ -20,7 +87,39 @@ export type {
   GetTasksRequest,
   DeleteTaskRequest,
   DeleteTaskResponse,
+  UpdateTaskRequest,
+  CompleteTaskRequest,
+  ReopenTaskRequest,
+  CreateListRequest,
+  RenameListRequest,
+  DeleteListRequest,
+  ShareListRequest,
+  RevokeListAccessRequest,
+  MoveTaskRequest,
+  AddTagsRequest,
+  RemoveTagsRequest,
+  AddBlockerRequest,
+  RemoveBlockerRequest,
+  RestoreTaskRequest,
+  PurgeTaskRequest,
+  BatchItemError,
+  ImportLineError,
+  RetryInfo,
+  ListEventsRequest,
   Task,
+  TaskEvent,
+};
+export {
+  TaskStatus,
+  TaskOrder,
+  TaskView,
+  ListDeletePolicy,
+  ListRole,
+  SubtaskDeletePolicy,
+  DueFilter,
+  Priority,
+  TaskFormat,
+  HistoryEventType,
 };
 
 // Define application-level types derived from generated types
@@ -30,6 +129,57 @@ export type AppTask = {
   id: string;
   text: string;
   createdAt: number; // Convert bigint to number for easier use in React
+  completed: boolean;
+  completedAt: number; // 0 while the task is open
+  listId: string; // '' for the inbox
+  dueAt: number; // Unix seconds; 0 if the task has no due date
+  priority: Priority;
+  position: string; // compare as strings to get the manual order
+  tags: string[]; // normalized and sorted
+  parentId: string; // '' for a top-level task
+  blockedBy: string[]; // IDs of the tasks that must be completed first
+  recurrence: string; // RRULE such as 'FREQ=WEEKLY;BYDAY=MO'; '' if the task does not repeat
+  deletedAt: number; // Unix seconds the task went to the trash; 0 for a live task
+  version: number; // goes up with every change; pass as expectedVersion to detect races
+};
+
+export type AppTaskNode = {
+  task: AppTask;
+  subtasks: AppTaskNode[];
+};
+
+export type AppTagCount = {
+  name: string;
+  count: number;
+};
+
+export type AppListMember = {
+  userId: string;
+  role: ListRole;
+};
+
+export type AppList = {
+  id: string;
+  name: string;
+  createdAt: number;
+  ownerId: string; // the creator, who always has the owner role
+  members: AppListMember[]; // users the list is shared with, by user ID
+};
+
+export type AppHistoryEvent = {
+  seq: number;
+  type: HistoryEventType;
+  task?: AppTask; // after the change; as it was just before a purge
+  actor: string; // '' for the server itself or without authentication
+  occurredAt: number;
+};
+
+export type AppWorkspace = {
+  id: string;
+  name: string;
+  createdAt: number;
+  maxTaskTextLength: number; // 0 for the server's limit
+  maxTasks: number; // trashed tasks included; 0 for no limit
 };
 
 // Define the TodoClient interface using application-level types
@@ -39,10 +189,155 @@ export interface TodoClient {
   }>;
   getTasks(request: GetTasksRequest): Promise<{
     tasks: AppTask[];
+    tree: AppTaskNode[]; // filled instead of tasks for TaskView.TREE
   }>;
   deleteTask(request: DeleteTaskRequest): Promise<{
     success: boolean;
   }>;
+  updateTask(request: UpdateTaskRequest): Promise<{
+    task?: AppTask;
+  }>;
+  completeTask(request: CompleteTaskRequest): Promise<{
+    task?: AppTask;
+  }>;
+  reopenTask(request: ReopenTaskRequest): Promise<{
+    task?: AppTask;
+  }>;
+  // Yields an event for every task change until the signal aborts or the
+  // server ends the stream.
+  watchTasks(signal: AbortSignal): AsyncIterable<TaskEvent>;
+  createList(request: CreateListRequest): Promise<{
+    list?: AppList;
+  }>;
+  getLists(): Promise<{
+    lists: AppList[];
+  }>;
+  renameList(request: RenameListRequest): Promise<{
+    list?: AppList;
+  }>;
+  deleteList(request: DeleteListRequest): Promise<{
+    success: boolean;
+  }>;
+  shareList(request: ShareListRequest): Promise<{
+    list?: AppList;
+  }>;
+  revokeListAccess(request: RevokeListAccessRequest): Promise<{
+    list?: AppList;
+  }>;
+  moveTask(request: MoveTaskRequest): Promise<{
+    task?: AppTask;
+  }>;
+  addTags(request: AddTagsRequest): Promise<{
+    task?: AppTask;
+  }>;
+  removeTags(request: RemoveTagsRequest): Promise<{
+    task?: AppTask;
+  }>;
+  listTags(listId?: string): Promise<{
+    tags: AppTagCount[];
+  }>;
+  addBlocker(request: AddBlockerRequest): Promise<{
+    task?: AppTask;
+  }>;
+  removeBlocker(request: RemoveBlockerRequest): Promise<{
+    task?: AppTask;
+  }>;
+  // Most recently deleted first.
+  listTrash(): Promise<{
+    tasks: AppTask[];
+  }>;
+  restoreTask(request: RestoreTaskRequest): Promise<{
+    task?: AppTask;
+  }>;
+  purgeTask(request: PurgeTaskRequest): Promise<void>;
+  // Takes back the caller's latest AddTask, DeleteTask, task change or batch.
+  undo(): Promise<{
+    tasks: AppTask[]; // tasks put back, as they are now
+    removedIds: string[]; // tasks removed because the undone call added them
+  }>;
+  // The batch calls apply every request or none; see batchItemErrors for
+  // which requests failed. At most 100 requests per call.
+  batchAddTasks(requests: AddTaskRequest[]): Promise<{
+    tasks: AppTask[];
+  }>;
+  batchUpdateTasks(requests: UpdateTaskRequest[]): Promise<{
+    tasks: AppTask[];
+  }>;
+  batchDeleteTasks(requests: DeleteTaskRequest[]): Promise<void>;
+  // Serializes the caller's tasks, optionally only those of one list ('' for
+  // all) and one status, in position order.
+  exportTasks(format: TaskFormat, listId?: string, status?: TaskStatus): Promise<Uint8Array>;
+  // Adds the tasks of an exported file to a list ('' for the inbox). All or
+  // nothing; see importLineErrors for the lines that were refused.
+  importTasks(format: TaskFormat, data: Uint8Array, listId?: string): Promise<{
+    tasks: AppTask[];
+  }>;
+  // Every recorded change to a task, oldest first, even once it is purged.
+  getTaskHistory(taskId: string): Promise<{
+    events: AppHistoryEvent[];
+  }>;
+  // Changes to all of the caller's tasks in the order they were made.
+  listEvents(request: ListEventsRequest): Promise<{
+    events: AppHistoryEvent[];
+    nextPageToken: string; // '' on the last page
+  }>;
+}
+
+// Returns the failed requests described by an error from a batch call, by
+// their index in the batch.
+export function batchItemErrors(err: unknown): BatchItemError[] {
+  return ConnectError.from(err).findDetails(BatchItemErrorSchema);
+}
+
+// Returns the invalid lines described by an error from importTasks, by
+// their 1-based line number in the file (element number for JSON).
+export function importLineErrors(err: unknown): ImportLineError[] {
+  return ConnectError.from(err).findDetails(ImportLineErrorSchema);
+}
+
+// Returns which limit a resource_exhausted error ran into and how long to
+// wait before retrying, or undefined for other errors. A zero wait means
+// retrying will not help until tasks are purged.
+export function retryInfo(err: unknown): RetryInfo | undefined {
+  return ConnectError.from(err).findDetails(RetryInfoSchema)[0];
+}
+
+// Managing workspaces takes an admin key when the backend runs with
+// -auth-keys.
+export interface WorkspaceClient {
+  createWorkspace(workspace: Omit<AppWorkspace, 'createdAt'>): Promise<{
+    workspace?: AppWorkspace;
+  }>;
+  // The default workspace first, then by ID.
+  listWorkspaces(): Promise<{
+    workspaces: AppWorkspace[];
+  }>;
+  // Deletes the workspace with all of its tasks and lists.
+  deleteWorkspace(id: string): Promise<void>;
+}
+
+// Returns the transport shared by the service clients, which authenticates
+// with apiToken and names workspace in every call if they are set.
+function createTransport(baseUrl: string, apiToken?: string, workspace?: string) {
+  const interceptors: Interceptor[] = [];
+  if (apiToken) {
+    interceptors.push((next) => (req) => {
+      req.header.set('Authorization', `Bearer ${apiToken}`);
+      return next(req);
+    });
+  }
+  if (workspace) {
+    interceptors.push((next) => (req) => {
+      req.header.set('X-Workspace', workspace);
+      return next(req);
+    });
+  }
+
+  return createConnectTransport({
+    baseUrl,
+    useBinaryFormat: false,
+    interceptors,
+  });
 }
 
 /**
@@ -52,26 +347,63 @@ export interface TodoClient {
  * eliminating all fetch calls and providing true ConnectRPC protocol support.
  *
  * @param baseUrl - Base URL of the backend (e.g. "http://localhost:8080")
+ * @param apiToken - Bearer token sent with every call when the backend runs with -auth-keys
+ * @param workspace - Workspace to act on; the default one (or the key's) if unset
  * @returns A TodoService client with true ConnectRPC protocol support
  */
-export function createTodoService(baseUrl: string): TodoClient {
+export function createTodoService(baseUrl: string, apiToken?: string, workspace?: string): TodoClient {
   // Create the ConnectRPC transport
-  const transport = createConnectTransport({
-    baseUrl,
-    useBinaryFormat: false,
-  });
+  const transport = createTransport(baseUrl, apiToken, workspace);
 
   // Create the true ConnectRPC client using service definitions
   const client = createClient(TodoServiceDef, transport);
 
   // Helper function to convert Task to AppTask
-  const toAppTask = (task: Task): AppTask => {
-    const n = Number(task.createdAt);
+  const toSafeNumber = (value: bigint, field: string): number => {
+    const n = Number(value);
     if (!Number.isSafeInteger(n)) {
-      throw new Error('Task.createdAt exceeds Number.MAX_SAFE_INTEGER; confirm units (expected seconds or ms).');
+      throw new Error(`Task.${field} exceeds Number.MAX_SAFE_INTEGER; confirm units (expected seconds or ms).`);
     }
-    return { id: task.id, text: task.text, createdAt: n };
+    return n;
   };
+  const toAppTask = (task: Task): AppTask => ({
+    id: task.id,
+    text: task.text,
+    createdAt: toSafeNumber(task.createdAt, 'createdAt'),
+    completed: task.completed,
+    completedAt: toSafeNumber(task.completedAt, 'completedAt'),
+    listId: task.listId,
+    dueAt: toSafeNumber(task.dueAt, 'dueAt'),
+    priority: task.priority,
+    position: task.position,
+    tags: [...task.tags],
+    parentId: task.parentId,
+    blockedBy: [...task.blockedBy],
+    recurrence: task.recurrence,
+    deletedAt: toSafeNumber(task.deletedAt, 'deletedAt'),
+    version: toSafeNumber(task.version, 'version'),
+  });
+  const toAppTaskNode = (node: TaskNode): AppTaskNode | undefined =>
+    node.task
+      ? {
+          task: toAppTask(node.task),
+          subtasks: node.subtasks.map(toAppTaskNode).filter((n): n is AppTaskNode => n !== undefined),
+        }
+      : undefined;
+  const toAppHistoryEvent = (event: HistoryEvent): AppHistoryEvent => ({
+    seq: toSafeNumber(event.seq, 'seq'),
+    type: event.type,
+    task: event.task ? toAppTask(event.task) : undefined,
+    actor: event.actor,
+    occurredAt: toSafeNumber(event.occurredAt, 'occurredAt'),
+  });
+  const toAppList = (list: List): AppList => ({
+    id: list.id,
+    name: list.name,
+    createdAt: toSafeNumber(list.createdAt, 'createdAt'),
+    ownerId: list.ownerId,
+    members: list.members.map((m) => ({ userId: m.userId, role: m.role })),
+  });
 
   // Return a typed interface that matches our expected API
   return {
@@ -86,6 +418,7 @@ export function createTodoService(baseUrl: string): TodoClient {
       const response = await client.getTasks(request);
       return {
         tasks: response.tasks.map(toAppTask),
+        tree: response.tree.map(toAppTaskNode).filter((n): n is AppTaskNode => n !== undefined),
       };
     },
 
@@ -95,14 +428,251 @@ export function createTodoService(baseUrl: string): TodoClient {
         success: response.success,
       };
     },
+
+    async updateTask(request: UpdateTaskRequest) {
+      const response = await client.updateTask(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async completeTask(request: CompleteTaskRequest) {
+      const response = await client.completeTask(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async reopenTask(request: ReopenTaskRequest) {
+      const response = await client.reopenTask(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async *watchTasks(signal: AbortSignal) {
+      const request = create(WatchTasksRequestSchema, {});
+      for await (const response of client.watchTasks(request, { signal })) {
+        if (response.event) {
+          yield response.event;
+        }
+      }
+    },
+
+    async createList(request: CreateListRequest) {
+      const response = await client.createList(request);
+      return {
+        list: response.list ? toAppList(response.list) : undefined,
+      };
+    },
+
+    async getLists() {
+      const response = await client.getLists(create(GetListsRequestSchema, {}));
+      return {
+        lists: response.lists.map(toAppList),
+      };
+    },
+
+    async renameList(request: RenameListRequest) {
+      const response = await client.renameList(request);
+      return {
+        list: response.list ? toAppList(response.list) : undefined,
+      };
+    },
+
+    async deleteList(request: DeleteListRequest) {
+      const response = await client.deleteList(request);
+      return {
+        success: response.success,
+      };
+    },
+
+    async shareList(request: ShareListRequest) {
+      const response = await client.shareList(request);
+      return {
+        list: response.list ? toAppList(response.list) : undefined,
+      };
+    },
+
+    async revokeListAccess(request: RevokeListAccessRequest) {
+      const response = await client.revokeListAccess(request);
+      return {
+        list: response.list ? toAppList(response.list) : undefined,
+      };
+    },
+
+    async moveTask(request: MoveTaskRequest) {
+      const response = await client.moveTask(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async addTags(request: AddTagsRequest) {
+      const response = await client.addTags(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async removeTags(request: RemoveTagsRequest) {
+      const response = await client.removeTags(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async listTags(listId = '') {
+      const request: ListTagsRequest = create(ListTagsRequestSchema, { listId });
+      const response = await client.listTags(request);
+      return {
+        tags: response.tags.map((tag) => ({ name: tag.name, count: tag.count })),
+      };
+    },
+
+    async addBlocker(request: AddBlockerRequest) {
+      const response = await client.addBlocker(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async removeBlocker(request: RemoveBlockerRequest) {
+      const response = await client.removeBlocker(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async listTrash() {
+      const response = await client.listTrash(create(ListTrashRequestSchema, {}));
+      return {
+        tasks: response.tasks.map(toAppTask),
+      };
+    },
+
+    async restoreTask(request: RestoreTaskRequest) {
+      const response = await client.restoreTask(request);
+      return {
+        task: response.task ? toAppTask(response.task) : undefined,
+      };
+    },
+
+    async purgeTask(request: PurgeTaskRequest) {
+      await client.purgeTask(request);
+    },
+
+    async undo() {
+      const response = await client.undo(create(UndoRequestSchema, {}));
+      return {
+        tasks: response.tasks.map(toAppTask),
+        removedIds: [...response.removedIds],
+      };
+    },
+
+    async batchAddTasks(requests: AddTaskRequest[]) {
+      const response = await client.batchAddTasks(create(BatchAddTasksRequestSchema, { requests }));
+      return {
+        tasks: response.tasks.map(toAppTask),
+      };
+    },
+
+    async batchUpdateTasks(requests: UpdateTaskRequest[]) {
+      const response = await client.batchUpdateTasks(create(BatchUpdateTasksRequestSchema, { requests }));
+      return {
+        tasks: response.tasks.map(toAppTask),
+      };
+    },
+
+    async batchDeleteTasks(requests: DeleteTaskRequest[]) {
+      await client.batchDeleteTasks(create(BatchDeleteTasksRequestSchema, { requests }));
+    },
+
+    async exportTasks(format: TaskFormat, listId = '', status: TaskStatus = TaskStatus.UNSPECIFIED) {
+      const response = await client.exportTasks(create(ExportTasksRequestSchema, { format, listId, status }));
+      return response.data;
+    },
+
+    async importTasks(format: TaskFormat, data: Uint8Array, listId = '') {
+      const response = await client.importTasks(create(ImportTasksRequestSchema, { format, data, listId }));
+      return {
+        tasks: response.tasks.map(toAppTask),
+      };
+    },
+
+    async getTaskHistory(taskId: string) {
+      const response = await client.getTaskHistory(create(GetTaskHistoryRequestSchema, { taskId }));
+      return {
+        events: response.events.map(toAppHistoryEvent),
+      };
+    },
+
+    async listEvents(request: ListEventsRequest) {
+      const response = await client.listEvents(request);
+      return {
+        events: response.events.map(toAppHistoryEvent),
+        nextPageToken: response.nextPageToken,
+      };
+    },
   };
 }
 
 export type TodoServiceClient = ReturnType<typeof createTodoService>;
 
+/**
+ * Creates a client for WorkspaceService.
+ *
+ * @param baseUrl - Base URL of the backend (e.g. "http://localhost:8080")
+ * @param apiToken - Admin key, when the backend runs with -auth-keys
+ */
+export function createWorkspaceService(baseUrl: string, apiToken?: string): WorkspaceClient {
+  const client = createClient(WorkspaceServiceDef, createTransport(baseUrl, apiToken));
+
+  const toAppWorkspace = (workspace: Workspace): AppWorkspace => ({
+    id: workspace.id,
+    name: workspace.name,
+    createdAt: Number(workspace.createdAt),
+    maxTaskTextLength: workspace.maxTaskTextLength,
+    maxTasks: workspace.maxTasks,
+  });
+
+  return {
+    async createWorkspace(workspace: Omit<AppWorkspace, 'createdAt'>) {
+      if (!workspace.id || workspace.id.trim() === '') {
+        throw new Error('Workspace ID cannot be empty');
+      }
+      const response = await client.createWorkspace({
+        workspace: create(WorkspaceSchema, { ...workspace, id: workspace.id.trim() }),
+      });
+      return {
+        workspace: response.workspace ? toAppWorkspace(response.workspace) : undefined,
+      };
+    },
+
+    async listWorkspaces() {
+      const response = await client.listWorkspaces({});
+      return {
+        workspaces: response.workspaces.map(toAppWorkspace),
+      };
+    },
+
+    async deleteWorkspace(id: string) {
+      await client.deleteWorkspace({ id });
+    },
+  };
+}
+
 // Export helper functions for creating requests using generated types
 export const createRequests = {
-  addTask: (text: string): AddTaskRequest => {
+  addTask: (
+    text: string,
+    listId = '',
+    dueAt = 0,
+    priority: Priority = Priority.UNSPECIFIED,
+    tags: string[] = [],
+    parentId = '',
+    recurrence = '',
+  ): AddTaskRequest => {
     const t = text.trim();
 
     if (t.length === 0) {
@@ -111,13 +681,235 @@ export const createRequests = {
     if (t.length > 500) {
       throw new Error('Task text cannot exceed 500 characters');
     }
-    return create(AddTaskRequestSchema, { text: t });
+    if (!Number.isSafeInteger(dueAt) || dueAt < 0) {
+      throw new Error('Due time must be a non-negative number of seconds');
+    }
+    if (recurrence.trim() !== '' && dueAt === 0) {
+      throw new Error('A recurring task needs a due time');
+    }
+    return create(AddTaskRequestSchema, {
+      text: t,
+      listId,
+      dueAt: BigInt(dueAt),
+      priority,
+      tags,
+      parentId,
+      recurrence: recurrence.trim(),
+    });
+  },
+  getTasks: (
+    status: TaskStatus = TaskStatus.UNSPECIFIED,
+    query = '',
+    orderBy: TaskOrder = TaskOrder.UNSPECIFIED,
+    listId = '',
+    dueFilter: DueFilter = DueFilter.UNSPECIFIED,
+    dueWithinSeconds = 0,
+    anyTags: string[] = [],
+    allTags: string[] = [],
+    view: TaskView = TaskView.UNSPECIFIED,
+    actionable = false,
+  ): GetTasksRequest =>
+    create(GetTasksRequestSchema, {
+      status,
+      query: query.trim(),
+      orderBy,
+      listId,
+      dueFilter,
+      dueWithinSeconds: BigInt(dueWithinSeconds),
+      timeZone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+      anyTags,
+      allTags,
+      view,
+      actionable,
+    }),
+  // A non-zero expectedVersion makes the call fail with Code.Aborted if the
+  // task has changed since it was read.
+  deleteTask: (
+    id: string,
+    subtaskPolicy: SubtaskDeletePolicy = SubtaskDeletePolicy.UNSPECIFIED,
+    expectedVersion = 0,
+  ): DeleteTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(DeleteTaskRequestSchema, { id: id.trim(), subtaskPolicy, expectedVersion: BigInt(expectedVersion) });
+  },
+  restoreTask: (id: string): RestoreTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(RestoreTaskRequestSchema, { id: id.trim() });
+  },
+  purgeTask: (id: string): PurgeTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(PurgeTaskRequestSchema, { id: id.trim() });
+  },
+  updateTaskText: (id: string, text: string): UpdateTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    const t = text.trim();
+    if (t.length === 0) {
+      throw new Error('Task text cannot be empty');
+    }
+    if (t.length > 500) {
+      throw new Error('Task text cannot exceed 500 characters');
+    }
+    return create(UpdateTaskRequestSchema, {
+      id: id.trim(),
+      task: { text: t },
+      updateMask: { paths: ['text'] },
+    });
+  },
+  completeTask: (id: string, expectedVersion = 0): CompleteTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(CompleteTaskRequestSchema, { id: id.trim(), expectedVersion: BigInt(expectedVersion) });
+  },
+  reopenTask: (id: string, expectedVersion = 0): ReopenTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(ReopenTaskRequestSchema, { id: id.trim(), expectedVersion: BigInt(expectedVersion) });
+  },
+  createList: (name: string): CreateListRequest => {
+    const n = name.trim();
+    if (n.length === 0) {
+      throw new Error('List name cannot be empty');
+    }
+    if (n.length > 100) {
+      throw new Error('List name cannot exceed 100 characters');
+    }
+    return create(CreateListRequestSchema, { name: n });
+  },
+  renameList: (id: string, name: string): RenameListRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('List ID cannot be empty');
+    }
+    const n = name.trim();
+    if (n.length === 0) {
+      throw new Error('List name cannot be empty');
+    }
+    if (n.length > 100) {
+      throw new Error('List name cannot exceed 100 characters');
+    }
+    return create(RenameListRequestSchema, { id: id.trim(), name: n });
+  },
+  deleteList: (
+    id: string,
+    policy: ListDeletePolicy = ListDeletePolicy.UNSPECIFIED,
+    moveToListId = '',
+  ): DeleteListRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('List ID cannot be empty');
+    }
+    return create(DeleteListRequestSchema, { id: id.trim(), policy, moveToListId });
   },
-  getTasks: (): GetTasksRequest => create(GetTasksRequestSchema, {}),
-  deleteTask: (id: string): DeleteTaskRequest => {
+  shareList: (listId: string, userId: string, role: ListRole): ShareListRequest => {
+    if (!listId || listId.trim() === '') {
+      throw new Error('List ID cannot be empty');
+    }
+    if (!userId || userId.trim() === '') {
+      throw new Error('User ID cannot be empty');
+    }
+    if (role === ListRole.UNSPECIFIED) {
+      throw new Error('Choose a role to share the list with');
+    }
+    return create(ShareListRequestSchema, { listId: listId.trim(), userId: userId.trim(), role });
+  },
+  revokeListAccess: (listId: string, userId: string): RevokeListAccessRequest => {
+    if (!listId || listId.trim() === '') {
+      throw new Error('List ID cannot be empty');
+    }
+    if (!userId || userId.trim() === '') {
+      throw new Error('User ID cannot be empty');
+    }
+    return create(RevokeListAccessRequestSchema, { listId: listId.trim(), userId: userId.trim() });
+  },
+  // Nests the task under parentId; an empty parentId makes it top-level.
+  updateTaskParent: (id: string, parentId: string): UpdateTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(UpdateTaskRequestSchema, {
+      id: id.trim(),
+      task: { parentId: parentId.trim() },
+      updateMask: { paths: ['parent_id'] },
+    });
+  },
+  // Makes the task repeat by an RRULE such as 'FREQ=DAILY'; an empty rule
+  // stops it from repeating.
+  updateTaskRecurrence: (id: string, recurrence: string): UpdateTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(UpdateTaskRequestSchema, {
+      id: id.trim(),
+      task: { recurrence: recurrence.trim() },
+      updateMask: { paths: ['recurrence'] },
+    });
+  },
+  updateTaskPriority: (id: string, priority: Priority): UpdateTaskRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(UpdateTaskRequestSchema, {
+      id: id.trim(),
+      task: { priority },
+      updateMask: { paths: ['priority'] },
+    });
+  },
+  addTags: (id: string, tags: string[]): AddTagsRequest => {
     if (!id || id.trim() === '') {
       throw new Error('Task ID cannot be empty');
     }
-    return create(DeleteTaskRequestSchema, { id: id.trim() });
+    if (tags.length === 0) {
+      throw new Error('At least one tag is required');
+    }
+    return create(AddTagsRequestSchema, { id: id.trim(), tags });
+  },
+  removeTags: (id: string, tags: string[]): RemoveTagsRequest => {
+    if (!id || id.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    if (tags.length === 0) {
+      throw new Error('At least one tag is required');
+    }
+    return create(RemoveTagsRequestSchema, { id: id.trim(), tags });
+  },
+  // Makes the task wait until blockerId is completed.
+  addBlocker: (id: string, blockerId: string): AddBlockerRequest => {
+    if (!id || id.trim() === '' || !blockerId || blockerId.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(AddBlockerRequestSchema, { id: id.trim(), blockerId: blockerId.trim() });
+  },
+  removeBlocker: (id: string, blockerId: string): RemoveBlockerRequest => {
+    if (!id || id.trim() === '' || !blockerId || blockerId.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    return create(RemoveBlockerRequestSchema, { id: id.trim(), blockerId: blockerId.trim() });
+  },
+  // Places the task right before (or, with placement 'after', right after)
+  // the target task in the manual order.
+  moveTask: (id: string, targetId: string, placement: 'before' | 'after' = 'before'): MoveTaskRequest => {
+    if (!id || id.trim() === '' || !targetId || targetId.trim() === '') {
+      throw new Error('Task ID cannot be empty');
+    }
+    const target = targetId.trim();
+    return create(MoveTaskRequestSchema, {
+      id: id.trim(),
+      ...(placement === 'before' ? { beforeId: target } : { afterId: target }),
+    });
+  },
+  // since and until are Unix seconds; 0 leaves that end of the range open.
+  listEvents: (since = 0, until = 0, pageSize = 0, pageToken = ''): ListEventsRequest => {
+    if (until !== 0 && until <= since) {
+      throw new Error('until must be later than since');
+    }
+    return create(ListEventsRequestSchema, { since: BigInt(since), until: BigInt(until), pageSize, pageToken });
   },
 };
\ No newline at end of file
@@ -2,15 +2,17 @@
 // @generated from file todo.proto (package todo.v1, syntax proto3)
 /* eslint-disable */
 
-import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
-import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { GenEnum, GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { enumDesc, fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { FieldMask } from "@bufbuild/protobuf/wkt";
+import { file_google_protobuf_field_mask } from "@bufbuild/protobuf/wkt";
 import type { Message } from "@bufbuild/protobuf";
 
 /**
  * Describes the file todo.proto.
  */
 export const file_todo: GenFile = /*@__PURE__*/
-  fileDesc("Cgp0b2RvLnByb3RvEgd0b2RvLnYxIh4KDkFkZFRhc2tSZXF1ZXN0EgwKBHRleHQYASABKAkiLgoPQWRkVGFza1Jlc3BvbnNlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2siEQoPR2V0VGFza3NSZXF1ZXN0IjAKEEdldFRhc2tzUmVzcG9uc2USHAoFdGFza3MYASADKAsyDS50b2RvLnYxLlRhc2siHwoRRGVsZXRlVGFza1JlcXVlc3QSCgoCaWQYASABKAkiJQoSRGVsZXRlVGFza1Jlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgiNAoEVGFzaxIKCgJpZBgBIAEoCRIMCgR0ZXh0GAIgASgJEhIKCmNyZWF0ZWRfYXQYAyABKAMy2QEKC1RvZG9TZXJ2aWNlEj4KB0FkZFRhc2sSFy50b2RvLnYxLkFkZFRhc2tSZXF1ZXN0GhgudG9kby52MS5BZGRUYXNrUmVzcG9uc2UiABJBCghHZXRUYXNrcxIYLnRvZG8udjEuR2V0VGFza3NSZXF1ZXN0GhkudG9kby52MS5HZXRUYXNrc1Jlc3BvbnNlIgASRwoKRGVsZXRlVGFzaxIaLnRvZG8udjEuRGVsZXRlVGFza1JlcXVlc3QaGy50b2RvLnYxLkRlbGV0ZVRhc2tSZXNwb25zZSIAQhpaGHRvZG8tbGlzdC90b2RvL3YxO3RvZG92MWIGcHJvdG8z");
+  fileDesc("Cgp0b2RvLnByb3RvEgd0b2RvLnYxGiBnb29nbGUvcHJvdG9idWYvZmllbGRfbWFzay5wcm90byKZAQoOQWRkVGFza1JlcXVlc3QSDAoEdGV4dBgBIAEoCRIPCgdsaXN0X2lkGAIgASgJEg4KBmR1ZV9hdBgDIAEoAxIjCghwcmlvcml0eRgEIAEoDjIRLnRvZG8udjEuUHJpb3JpdHkSDAoEdGFncxgFIAMoCRIRCglwYXJlbnRfaWQYBiABKAkSEgoKcmVjdXJyZW5jZRgHIAEoCSIuCg9BZGRUYXNrUmVzcG9uc2USGwoEdGFzaxgBIAEoCzINLnRvZG8udjEuVGFzayKCAwoPR2V0VGFza3NSZXF1ZXN0EiMKBnN0YXR1cxgBIAEoDjITLnRvZG8udjEuVGFza1N0YXR1cxIRCglwYWdlX3NpemUYAiABKAUSEgoKcGFnZV90b2tlbhgDIAEoCRINCgVxdWVyeRgEIAEoCRIVCg1jcmVhdGVkX2FmdGVyGAUgASgDEhYKDmNyZWF0ZWRfYmVmb3JlGAYgASgDEiQKCG9yZGVyX2J5GAcgASgOMhIudG9kby52MS5UYXNrT3JkZXISDwoHbGlzdF9pZBgIIAEoCRImCgpkdWVfZmlsdGVyGAkgASgOMhIudG9kby52MS5EdWVGaWx0ZXISGgoSZHVlX3dpdGhpbl9zZWNvbmRzGAogASgDEhEKCXRpbWVfem9uZRgLIAEoCRIQCghhbnlfdGFncxgMIAMoCRIQCghhbGxfdGFncxgNIAMoCRIfCgR2aWV3GA4gASgOMhEudG9kby52MS5UYXNrVmlldxISCgphY3Rpb25hYmxlGA8gASgIImoKEEdldFRhc2tzUmVzcG9uc2USHAoFdGFza3MYASADKAsyDS50b2RvLnYxLlRhc2sSFwoPbmV4dF9wYWdlX3Rva2VuGAIgASgJEh8KBHRyZWUYAyADKAsyES50b2RvLnYxLlRhc2tOb2RlIkwKCFRhc2tOb2RlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2sSIwoIc3VidGFza3MYAiADKAsyES50b2RvLnYxLlRhc2tOb2RlIoABChFEZWxldGVUYXNrUmVxdWVzdBIKCgJpZBgBIAEoCRIPCgdsaXN0X2lkGAIgASgJEjQKDnN1YnRhc2tfcG9saWN5GAMgASgOMhwudG9kby52MS5TdWJ0YXNrRGVsZXRlUG9saWN5EhgKEGV4cGVjdGVkX3ZlcnNpb24YBCABKAMiJQoSRGVsZXRlVGFza1Jlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgihwEKEVVwZGF0ZVRhc2tSZXF1ZXN0EgoKAmlkGAEgASgJEhsKBHRhc2sYAiABKAsyDS50b2RvLnYxLlRhc2sSLwoLdXBkYXRlX21hc2sYAyABKAsyGi5nb29nbGUucHJvdG9idWYuRmllbGRNYXNrEhgKEGV4cGVjdGVkX3ZlcnNpb24YBCABKAMiMQoSVXBkYXRlVGFza1Jlc3BvbnNlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2siOwoTQ29tcGxldGVUYXNrUmVxdWVzdBIKCgJpZBgBIAEoCRIYChBleHBlY3RlZF92ZXJzaW9uGAIgASgDIjMKFENvbXBsZXRlVGFza1Jlc3BvbnNlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2siOQoRUmVvcGVuVGFza1JlcXVlc3QSCgoCaWQYASABKAkSGAoQZXhwZWN0ZWRfdmVyc2lvbhgCIAEoAyIxChJSZW9wZW5UYXNrUmVzcG9uc2USGwoEdGFzaxgBIAEoCzINLnRvZG8udjEuVGFzayITChFXYXRjaFRhc2tzUmVxdWVzdCI3ChJXYXRjaFRhc2tzUmVzcG9uc2USIQoFZXZlbnQYASABKAsyEi50b2RvLnYxLlRhc2tFdmVudCJjCglUYXNrRXZlbnQSJAoEdHlwZRgBIAEoDjIWLnRvZG8udjEuVGFza0V2ZW50VHlwZRIbCgR0YXNrGAIgASgLMg0udG9kby52MS5UYXNrEhMKC29jY3VycmVkX2F0GAMgASgDIoUBCgxIaXN0b3J5RXZlbnQSCwoDc2VxGAEgASgDEicKBHR5cGUYAiABKA4yGS50b2RvLnYxLkhpc3RvcnlFdmVudFR5cGUSGwoEdGFzaxgDIAEoCzINLnRvZG8udjEuVGFzaxINCgVhY3RvchgEIAEoCRITCgtvY2N1cnJlZF9hdBgFIAEoAyIoChVHZXRUYXNrSGlzdG9yeVJlcXVlc3QSDwoHdGFza19pZBgBIAEoCSI/ChZHZXRUYXNrSGlzdG9yeVJlc3BvbnNlEiUKBmV2ZW50cxgBIAMoCzIVLnRvZG8udjEuSGlzdG9yeUV2ZW50IlgKEUxpc3RFdmVudHNSZXF1ZXN0Eg0KBXNpbmNlGAEgASgDEg0KBXVudGlsGAIgASgDEhEKCXBhZ2Vfc2l6ZRgDIAEoBRISCgpwYWdlX3Rva2VuGAQgASgJIlQKEkxpc3RFdmVudHNSZXNwb25zZRIlCgZldmVudHMYASADKAsyFS50b2RvLnYxLkhpc3RvcnlFdmVudBIXCg9uZXh0X3BhZ2VfdG9rZW4YAiABKAkitQIKBFRhc2sSCgoCaWQYASABKAkSDAoEdGV4dBgCIAEoCRISCgpjcmVhdGVkX2F0GAMgASgDEhEKCWNvbXBsZXRlZBgEIAEoCBIUCgxjb21wbGV0ZWRfYXQYBSABKAMSDwoHbGlzdF9pZBgGIAEoCRIQCghvd25lcl9pZBgHIAEoCRIOCgZkdWVfYXQYCCABKAMSIwoIcHJpb3JpdHkYCSABKA4yES50b2RvLnYxLlByaW9yaXR5EhAKCHBvc2l0aW9uGAogASgJEgwKBHRhZ3MYCyADKAkSEQoJcGFyZW50X2lkGAwgASgJEhIKCmJsb2NrZWRfYnkYDSADKAkSEgoKcmVjdXJyZW5jZRgOIAEoCRISCgpkZWxldGVkX2F0GA8gASgDEg8KB3ZlcnNpb24YECABKAMiXAoPTW92ZVRhc2tSZXF1ZXN0EgoKAmlkGAEgASgJEhEKCWJlZm9yZV9pZBgCIAEoCRIQCghhZnRlcl9pZBgDIAEoCRIYChBleHBlY3RlZF92ZXJzaW9uGAQgASgDIi8KEE1vdmVUYXNrUmVzcG9uc2USGwoEdGFzaxgBIAEoCzINLnRvZG8udjEuVGFzayJECg5BZGRUYWdzUmVxdWVzdBIKCgJpZBgBIAEoCRIMCgR0YWdzGAIgAygJEhgKEGV4cGVjdGVkX3ZlcnNpb24YAyABKAMiLgoPQWRkVGFnc1Jlc3BvbnNlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2siRwoRUmVtb3ZlVGFnc1JlcXVlc3QSCgoCaWQYASABKAkSDAoEdGFncxgCIAMoCRIYChBleHBlY3RlZF92ZXJzaW9uGAMgASgDIjEKElJlbW92ZVRhZ3NSZXNwb25zZRIbCgR0YXNrGAEgASgLMg0udG9kby52MS5UYXNrIiIKD0xpc3RUYWdzUmVxdWVzdBIPCgdsaXN0X2lkGAEgASgJIjMKEExpc3RUYWdzUmVzcG9uc2USHwoEdGFncxgBIAMoCzIRLnRvZG8udjEuVGFnQ291bnQiJwoIVGFnQ291bnQSDAoEbmFtZRgBIAEoCRINCgVjb3VudBgCIAEoBSJNChFBZGRCbG9ja2VyUmVxdWVzdBIKCgJpZBgBIAEoCRISCgpibG9ja2VyX2lkGAIgASgJEhgKEGV4cGVjdGVkX3ZlcnNpb24YAyABKAMiMQoSQWRkQmxvY2tlclJlc3BvbnNlEhsKBHRhc2sYASABKAsyDS50b2RvLnYxLlRhc2siUAoUUmVtb3ZlQmxvY2tlclJlcXVlc3QSCgoCaWQYASABKAkSEgoKYmxvY2tlcl9pZBgCIAEoCRIYChBleHBlY3RlZF92ZXJzaW9uGAMgASgDIjQKFVJlbW92ZUJsb2NrZXJSZXNwb25zZRIbCgR0YXNrGAEgASgLMg0udG9kby52MS5UYXNrIhIKEExpc3RUcmFzaFJlcXVlc3QiMQoRTGlzdFRyYXNoUmVzcG9uc2USHAoFdGFza3MYASADKAsyDS50b2RvLnYxLlRhc2siIAoSUmVzdG9yZVRhc2tSZXF1ZXN0EgoKAmlkGAEgASgJIjIKE1Jlc3RvcmVUYXNrUmVzcG9uc2USGwoEdGFzaxgBIAEoCzINLnRvZG8udjEuVGFzayIeChBQdXJnZVRhc2tSZXF1ZXN0EgoKAmlkGAEgASgJIhMKEVB1cmdlVGFza1Jlc3BvbnNlIg0KC1VuZG9SZXF1ZXN0IkEKDFVuZG9SZXNwb25zZRIcCgV0YXNrcxgBIAMoCzINLnRvZG8udjEuVGFzaxITCgtyZW1vdmVkX2lkcxgCIAMoCSJBChRCYXRjaEFkZFRhc2tzUmVxdWVzdBIpCghyZXF1ZXN0cxgBIAMoCzIXLnRvZG8udjEuQWRkVGFza1JlcXVlc3QiNQoVQmF0Y2hBZGRUYXNrc1Jlc3BvbnNlEhwKBXRhc2tzGAEgAygLMg0udG9kby52MS5UYXNrIkcKF0JhdGNoVXBkYXRlVGFza3NSZXF1ZXN0EiwKCHJlcXVlc3RzGAEgAygLMhoudG9kby52MS5VcGRhdGVUYXNrUmVxdWVzdCI4ChhCYXRjaFVwZGF0ZVRhc2tzUmVzcG9uc2USHAoFdGFza3MYASADKAsyDS50b2RvLnYxLlRhc2siRwoXQmF0Y2hEZWxldGVUYXNrc1JlcXVlc3QSLAoIcmVxdWVzdHMYASADKAsyGi50b2RvLnYxLkRlbGV0ZVRhc2tSZXF1ZXN0IhoKGEJhdGNoRGVsZXRlVGFza3NSZXNwb25zZSI+Cg5CYXRjaEl0ZW1FcnJvchINCgVpbmRleBgBIAEoBRIMCgRjb2RlGAIgASgJEg8KB21lc3NhZ2UYAyABKAkibwoSRXhwb3J0VGFza3NSZXF1ZXN0EiMKBmZvcm1hdBgBIAEoDjITLnRvZG8udjEuVGFza0Zvcm1hdBIPCgdsaXN0X2lkGAIgASgJEiMKBnN0YXR1cxgDIAEoDjITLnRvZG8udjEuVGFza1N0YXR1cyIjChNFeHBvcnRUYXNrc1Jlc3BvbnNlEgwKBGRhdGEYASABKAwiWAoSSW1wb3J0VGFza3NSZXF1ZXN0EiMKBmZvcm1hdBgBIAEoDjITLnRvZG8udjEuVGFza0Zvcm1hdBIMCgRkYXRhGAIgASgMEg8KB2xpc3RfaWQYAyABKAkiMwoTSW1wb3J0VGFza3NSZXNwb25zZRIcCgV0YXNrcxgBIAMoCzINLnRvZG8udjEuVGFzayIwCg9JbXBvcnRMaW5lRXJyb3ISDAoEbGluZRgBIAEoBRIPCgdtZXNzYWdlGAIgASgJIjIKCVJldHJ5SW5mbxINCgVsaW1pdBgBIAEoCRIWCg5yZXRyeV9hZnRlcl9tcxgCIAEoAyIhChFDcmVhdGVMaXN0UmVxdWVzdBIMCgRuYW1lGAEgASgJIjEKEkNyZWF0ZUxpc3RSZXNwb25zZRIbCgRsaXN0GAEgASgLMg0udG9kby52MS5MaXN0IhEKD0dldExpc3RzUmVxdWVzdCIwChBHZXRMaXN0c1Jlc3BvbnNlEhwKBWxpc3RzGAEgAygLMg0udG9kby52MS5MaXN0Ii0KEVJlbmFtZUxpc3RSZXF1ZXN0EgoKAmlkGAEgASgJEgwKBG5hbWUYAiABKAkiMQoSUmVuYW1lTGlzdFJlc3BvbnNlEhsKBGxpc3QYASABKAsyDS50b2RvLnYxLkxpc3QiYwoRRGVsZXRlTGlzdFJlcXVlc3QSCgoCaWQYASABKAkSKQoGcG9saWN5GAIgASgOMhkudG9kby52MS5MaXN0RGVsZXRlUG9saWN5EhcKD21vdmVfdG9fbGlzdF9pZBgDIAEoCSIlChJEZWxldGVMaXN0UmVzcG9uc2USDwoHc3VjY2VzcxgBIAEoCCJVChBTaGFyZUxpc3RSZXF1ZXN0Eg8KB2xpc3RfaWQYASABKAkSDwoHdXNlcl9pZBgCIAEoCRIfCgRyb2xlGAMgASgOMhEudG9kby52MS5MaXN0Um9sZSIwChFTaGFyZUxpc3RSZXNwb25zZRIbCgRsaXN0GAEgASgLMg0udG9kby52MS5MaXN0IjsKF1Jldm9rZUxpc3RBY2Nlc3NSZXF1ZXN0Eg8KB2xpc3RfaWQYASABKAkSDwoHdXNlcl9pZBgCIAEoCSI3ChhSZXZva2VMaXN0QWNjZXNzUmVzcG9uc2USGwoEbGlzdBgBIAEoCzINLnRvZG8udjEuTGlzdCJsCgRMaXN0EgoKAmlkGAEgASgJEgwKBG5hbWUYAiABKAkSEgoKY3JlYXRlZF9hdBgDIAEoAxIQCghvd25lcl9pZBgEIAEoCRIkCgdtZW1iZXJzGAUgAygLMhMudG9kby52MS5MaXN0TWVtYmVyIj4KCkxpc3RNZW1iZXISDwoHdXNlcl9pZBgBIAEoCRIfCgRyb2xlGAIgASgOMhEudG9kby52MS5MaXN0Um9sZSJqCglXb3Jrc3BhY2USCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRISCgpjcmVhdGVkX2F0GAMgASgDEhwKFG1heF90YXNrX3RleHRfbGVuZ3RoGAQgASgFEhEKCW1heF90YXNrcxgFIAEoBSI/ChZDcmVhdGVXb3Jrc3BhY2VSZXF1ZXN0EiUKCXdvcmtzcGFjZRgBIAEoCzISLnRvZG8udjEuV29ya3NwYWNlIkAKF0NyZWF0ZVdvcmtzcGFjZVJlc3BvbnNlEiUKCXdvcmtzcGFjZRgBIAEoCzISLnRvZG8udjEuV29ya3NwYWNlIhcKFUxpc3RXb3Jrc3BhY2VzUmVxdWVzdCJAChZMaXN0V29ya3NwYWNlc1Jlc3BvbnNlEiYKCndvcmtzcGFjZXMYASADKAsyEi50b2RvLnYxLldvcmtzcGFjZSIkChZEZWxldGVXb3Jrc3BhY2VSZXF1ZXN0EgoKAmlkGAEgASgJIhkKF0RlbGV0ZVdvcmtzcGFjZVJlc3BvbnNlKogBCgpUYXNrRm9ybWF0EhsKF1RBU0tfRk9STUFUX1VOU1BFQ0lGSUVEEAASFAoQVEFTS19GT1JNQVRfSlNPThABEhMKD1RBU0tfRk9STUFUX0NTVhACEhgKFFRBU0tfRk9STUFUX01BUktET1dOEAMSGAoUVEFTS19GT1JNQVRfVE9ET19UWFQQBCpmCghMaXN0Um9sZRIZChVMSVNUX1JPTEVfVU5TUEVDSUZJRUQQABIUChBMSVNUX1JPTEVfVklFV0VSEAESFAoQTElTVF9ST0xFX0VESVRPUhACEhMKD0xJU1RfUk9MRV9PV05FUhADKloKClRhc2tTdGF0dXMSGwoXVEFTS19TVEFUVVNfVU5TUEVDSUZJRUQQABIUChBUQVNLX1NUQVRVU19PUEVOEAESGQoVVEFTS19TVEFUVVNfQ09NUExFVEVEEAIqwwEKCVRhc2tPcmRlchIaChZUQVNLX09SREVSX1VOU1BFQ0lGSUVEEAASGwoXVEFTS19PUkRFUl9ORVdFU1RfRklSU1QQARIbChdUQVNLX09SREVSX09MREVTVF9GSVJTVBACEhsKF1RBU0tfT1JERVJfQUxQSEFCRVRJQ0FMEAMSEQoNVEFTS19PUkRFUl9JRBAEEhcKE1RBU0tfT1JERVJfUE9TSVRJT04QBRIXChNUQVNLX09SREVSX1BSSU9SSVRZEAYqaAoIUHJpb3JpdHkSGAoUUFJJT1JJVFlfVU5TUEVDSUZJRUQQABIPCgtQUklPUklUWV9QMBABEg8KC1BSSU9SSVRZX1AxEAISDwoLUFJJT1JJVFlfUDIQAxIPCgtQUklPUklUWV9QMxAEKnQKCUR1ZUZpbHRlchIaChZEVUVfRklMVEVSX1VOU1BFQ0lGSUVEEAASFgoSRFVFX0ZJTFRFUl9PVkVSRFVFEAESGAoURFVFX0ZJTFRFUl9EVUVfVE9EQVkQAhIZChVEVUVfRklMVEVSX0RVRV9XSVRISU4QAypzChBMaXN0RGVsZXRlUG9saWN5EiIKHkxJU1RfREVMRVRFX1BPTElDWV9VTlNQRUNJRklFRBAAEh4KGkxJU1RfREVMRVRFX1BPTElDWV9DQVNDQURFEAESGwoXTElTVF9ERUxFVEVfUE9MSUNZX01PVkUQAipNCghUYXNrVmlldxIZChVUQVNLX1ZJRVdfVU5TUEVDSUZJRUQQABISCg5UQVNLX1ZJRVdfRkxBVBABEhIKDlRBU0tfVklFV19UUkVFEAIqggEKE1N1YnRhc2tEZWxldGVQb2xpY3kSJQohU1VCVEFTS19ERUxFVEVfUE9MSUNZX1VOU1BFQ0lGSUVEEAASIQodU1VCVEFTS19ERUxFVEVfUE9MSUNZX0NBU0NBREUQARIhCh1TVUJUQVNLX0RFTEVURV9QT0xJQ1lfUFJPTU9URRACKp4BCg1UYXNrRXZlbnRUeXBlEh8KG1RBU0tfRVZFTlRfVFlQRV9VTlNQRUNJRklFRBAAEhkKFVRBU0tfRVZFTlRfVFlQRV9BRERFRBABEhsKF1RBU0tfRVZFTlRfVFlQRV9VUERBVEVEEAISGwoXVEFTS19FVkVOVF9UWVBFX0RFTEVURUQQAxIXChNUQVNLX0VWRU5UX1RZUEVfRFVFEAQq1gEKEEhpc3RvcnlFdmVudFR5cGUSIgoeSElTVE9SWV9FVkVOVF9UWVBFX1VOU1BFQ0lGSUVEEAASHgoaSElTVE9SWV9FVkVOVF9UWVBFX0NSRUFURUQQARIeChpISVNUT1JZX0VWRU5UX1RZUEVfVVBEQVRFRBACEh4KGkhJU1RPUllfRVZFTlRfVFlQRV9ERUxFVEVEEAMSHwobSElTVE9SWV9FVkVOVF9UWVBFX1JFU1RPUkVEEAQSHQoZSElTVE9SWV9FVkVOVF9UWVBFX1BVUkdFRBAFMrsRCgtUb2RvU2VydmljZRI+CgdBZGRUYXNrEhcudG9kby52MS5BZGRUYXNrUmVxdWVzdBoYLnRvZG8udjEuQWRkVGFza1Jlc3BvbnNlIgASQQoIR2V0VGFza3MSGC50b2RvLnYxLkdldFRhc2tzUmVxdWVzdBoZLnRvZG8udjEuR2V0VGFza3NSZXNwb25zZSIAEkcKCkRlbGV0ZVRhc2sSGi50b2RvLnYxLkRlbGV0ZVRhc2tSZXF1ZXN0GhsudG9kby52MS5EZWxldGVUYXNrUmVzcG9uc2UiABJHCgpVcGRhdGVUYXNrEhoudG9kby52MS5VcGRhdGVUYXNrUmVxdWVzdBobLnRvZG8udjEuVXBkYXRlVGFza1Jlc3BvbnNlIgASTQoMQ29tcGxldGVUYXNrEhwudG9kby52MS5Db21wbGV0ZVRhc2tSZXF1ZXN0Gh0udG9kby52MS5Db21wbGV0ZVRhc2tSZXNwb25zZSIAEkcKClJlb3BlblRhc2sSGi50b2RvLnYxLlJlb3BlblRhc2tSZXF1ZXN0GhsudG9kby52MS5SZW9wZW5UYXNrUmVzcG9uc2UiABJJCgpXYXRjaFRhc2tzEhoudG9kby52MS5XYXRjaFRhc2tzUmVxdWVzdBobLnRvZG8udjEuV2F0Y2hUYXNrc1Jlc3BvbnNlIgAwARJHCgpDcmVhdGVMaXN0EhoudG9kby52MS5DcmVhdGVMaXN0UmVxdWVzdBobLnRvZG8udjEuQ3JlYXRlTGlzdFJlc3BvbnNlIgASQQoIR2V0TGlzdHMSGC50b2RvLnYxLkdldExpc3RzUmVxdWVzdBoZLnRvZG8udjEuR2V0TGlzdHNSZXNwb25zZSIAEkcKClJlbmFtZUxpc3QSGi50b2RvLnYxLlJlbmFtZUxpc3RSZXF1ZXN0GhsudG9kby52MS5SZW5hbWVMaXN0UmVzcG9uc2UiABJHCgpEZWxldGVMaXN0EhoudG9kby52MS5EZWxldGVMaXN0UmVxdWVzdBobLnRvZG8udjEuRGVsZXRlTGlzdFJlc3BvbnNlIgASRAoJU2hhcmVMaXN0EhkudG9kby52MS5TaGFyZUxpc3RSZXF1ZXN0GhoudG9kby52MS5TaGFyZUxpc3RSZXNwb25zZSIAElkKEFJldm9rZUxpc3RBY2Nlc3MSIC50b2RvLnYxLlJldm9rZUxpc3RBY2Nlc3NSZXF1ZXN0GiEudG9kby52MS5SZXZva2VMaXN0QWNjZXNzUmVzcG9uc2UiABJBCghNb3ZlVGFzaxIYLnRvZG8udjEuTW92ZVRhc2tSZXF1ZXN0GhkudG9kby52MS5Nb3ZlVGFza1Jlc3BvbnNlIgASPgoHQWRkVGFncxIXLnRvZG8udjEuQWRkVGFnc1JlcXVlc3QaGC50b2RvLnYxLkFkZFRhZ3NSZXNwb25zZSIAEkcKClJlbW92ZVRhZ3MSGi50b2RvLnYxLlJlbW92ZVRhZ3NSZXF1ZXN0GhsudG9kby52MS5SZW1vdmVUYWdzUmVzcG9uc2UiABJBCghMaXN0VGFncxIYLnRvZG8udjEuTGlzdFRhZ3NSZXF1ZXN0GhkudG9kby52MS5MaXN0VGFnc1Jlc3BvbnNlIgASRwoKQWRkQmxvY2tlchIaLnRvZG8udjEuQWRkQmxvY2tlclJlcXVlc3QaGy50b2RvLnYxLkFkZEJsb2NrZXJSZXNwb25zZSIAElAKDVJlbW92ZUJsb2NrZXISHS50b2RvLnYxLlJlbW92ZUJsb2NrZXJSZXF1ZXN0Gh4udG9kby52MS5SZW1vdmVCbG9ja2VyUmVzcG9uc2UiABJECglMaXN0VHJhc2gSGS50b2RvLnYxLkxpc3RUcmFzaFJlcXVlc3QaGi50b2RvLnYxLkxpc3RUcmFzaFJlc3BvbnNlIgASSgoLUmVzdG9yZVRhc2sSGy50b2RvLnYxLlJlc3RvcmVUYXNrUmVxdWVzdBocLnRvZG8udjEuUmVzdG9yZVRhc2tSZXNwb25zZSIAEkQKCVB1cmdlVGFzaxIZLnRvZG8udjEuUHVyZ2VUYXNrUmVxdWVzdBoaLnRvZG8udjEuUHVyZ2VUYXNrUmVzcG9uc2UiABI1CgRVbmRvEhQudG9kby52MS5VbmRvUmVxdWVzdBoVLnRvZG8udjEuVW5kb1Jlc3BvbnNlIgASUAoNQmF0Y2hBZGRUYXNrcxIdLnRvZG8udjEuQmF0Y2hBZGRUYXNrc1JlcXVlc3QaHi50b2RvLnYxLkJhdGNoQWRkVGFza3NSZXNwb25zZSIAElkKEEJhdGNoVXBkYXRlVGFza3MSIC50b2RvLnYxLkJhdGNoVXBkYXRlVGFza3NSZXF1ZXN0GiEudG9kby52MS5CYXRjaFVwZGF0ZVRhc2tzUmVzcG9uc2UiABJZChBCYXRjaERlbGV0ZVRhc2tzEiAudG9kby52MS5CYXRjaERlbGV0ZVRhc2tzUmVxdWVzdBohLnRvZG8udjEuQmF0Y2hEZWxldGVUYXNrc1Jlc3BvbnNlIgASSgoLRXhwb3J0VGFza3MSGy50b2RvLnYxLkV4cG9ydFRhc2tzUmVxdWVzdBocLnRvZG8udjEuRXhwb3J0VGFza3NSZXNwb25zZSIAEkoKC0ltcG9ydFRhc2tzEhsudG9kby52MS5JbXBvcnRUYXNrc1JlcXVlc3QaHC50b2RvLnYxLkltcG9ydFRhc2tzUmVzcG9uc2UiABJTCg5HZXRUYXNrSGlzdG9yeRIeLnRvZG8udjEuR2V0VGFza0hpc3RvcnlSZXF1ZXN0Gh8udG9kby52MS5HZXRUYXNrSGlzdG9yeVJlc3BvbnNlIgASRwoKTGlzdEV2ZW50cxIaLnRvZG8udjEuTGlzdEV2ZW50c1JlcXVlc3QaGy50b2RvLnYxLkxpc3RFdmVudHNSZXNwb25zZSIAMpcCChBXb3Jrc3BhY2VTZXJ2aWNlElYKD0NyZWF0ZVdvcmtzcGFjZRIfLnRvZG8udjEuQ3JlYXRlV29ya3NwYWNlUmVxdWVzdBogLnRvZG8udjEuQ3JlYXRlV29ya3NwYWNlUmVzcG9uc2UiABJTCg5MaXN0V29ya3NwYWNlcxIeLnRvZG8udjEuTGlzdFdvcmtzcGFjZXNSZXF1ZXN0Gh8udG9kby52MS5MaXN0V29ya3NwYWNlc1Jlc3BvbnNlIgASVgoPRGVsZXRlV29ya3NwYWNlEh8udG9kby52MS5EZWxldGVXb3Jrc3BhY2VSZXF1ZXN0GiAudG9kby52MS5EZWxldGVXb3Jrc3BhY2VSZXNwb25zZSIAQhpaGHRvZG8tbGlzdC90b2RvL3YxO3RvZG92MWIGcHJvdG8z", [file_google_protobuf_field_mask]);
 
 /**
  * @generated from message todo.v1.AddTaskRequest
@@ -20,6 +22,46 @@ export type AddTaskRequest = Message<"todo.v1.AddTaskRequest"> & {
    * @generated from field: string text = 1;
    */
   text: string;
+
+  /**
+   * List to add the task to; empty adds it to the inbox.
+   *
+   * @generated from field: string list_id = 2;
+   */
+  listId: string;
+
+  /**
+   * Unix time the task is due; zero for none.
+   *
+   * @generated from field: int64 due_at = 3;
+   */
+  dueAt: bigint;
+
+  /**
+   * @generated from field: todo.v1.Priority priority = 4;
+   */
+  priority: Priority;
+
+  /**
+   * @generated from field: repeated string tags = 5;
+   */
+  tags: string[];
+
+  /**
+   * Task to add this one under as a subtask; empty adds a top-level task.
+   * A subtask always lives in its parent's list, so list_id must be empty
+   * or name that list.
+   *
+   * @generated from field: string parent_id = 6;
+   */
+  parentId: string;
+
+  /**
+   * Recurrence rule; see Task.recurrence. Requires due_at.
+   *
+   * @generated from field: string recurrence = 7;
+   */
+  recurrence: string;
 };
 
 /**
@@ -50,6 +92,116 @@ export const AddTaskResponseSchema: GenMessage<AddTaskResponse> = /*@__PURE__*/
  * @generated from message todo.v1.GetTasksRequest
  */
 export type GetTasksRequest = Message<"todo.v1.GetTasksRequest"> & {
+  /**
+   * Restricts the result to tasks in the given state. Unspecified returns
+   * tasks in any state.
+   *
+   * @generated from field: todo.v1.TaskStatus status = 1;
+   */
+  status: TaskStatus;
+
+  /**
+   * Maximum number of tasks to return, capped at 1000. Zero returns every
+   * matching task.
+   *
+   * @generated from field: int32 page_size = 2;
+   */
+  pageSize: number;
+
+  /**
+   * next_page_token from a previous response; resumes the listing after the
+   * last task of that page. Must be used with the same filters and order.
+   *
+   * @generated from field: string page_token = 3;
+   */
+  pageToken: string;
+
+  /**
+   * Case-insensitive search over task text. Every word in the query must
+   * match the start of a word in the task, so "rep" finds "Weekly report".
+   *
+   * @generated from field: string query = 4;
+   */
+  query: string;
+
+  /**
+   * Only tasks created at or after this Unix time, if non-zero.
+   *
+   * @generated from field: int64 created_after = 5;
+   */
+  createdAfter: bigint;
+
+  /**
+   * Only tasks created before this Unix time, if non-zero.
+   *
+   * @generated from field: int64 created_before = 6;
+   */
+  createdBefore: bigint;
+
+  /**
+   * Defaults to newest first.
+   *
+   * @generated from field: todo.v1.TaskOrder order_by = 7;
+   */
+  orderBy: TaskOrder;
+
+  /**
+   * Only tasks in this list, if set. Empty returns tasks from every list,
+   * including the inbox.
+   *
+   * @generated from field: string list_id = 8;
+   */
+  listId: string;
+
+  /**
+   * Only tasks whose due date falls in the given window.
+   *
+   * @generated from field: todo.v1.DueFilter due_filter = 9;
+   */
+  dueFilter: DueFilter;
+
+  /**
+   * Length of the window for DUE_FILTER_DUE_WITHIN, in seconds.
+   *
+   * @generated from field: int64 due_within_seconds = 10;
+   */
+  dueWithinSeconds: bigint;
+
+  /**
+   * IANA time zone, such as "Europe/Berlin", that decides where today starts
+   * and ends for DUE_FILTER_DUE_TODAY. Defaults to the server's time zone.
+   *
+   * @generated from field: string time_zone = 11;
+   */
+  timeZone: string;
+
+  /**
+   * Only tasks with at least one of these tags, if any are given.
+   *
+   * @generated from field: repeated string any_tags = 12;
+   */
+  anyTags: string[];
+
+  /**
+   * Only tasks with every one of these tags, if any are given.
+   *
+   * @generated from field: repeated string all_tags = 13;
+   */
+  allTags: string[];
+
+  /**
+   * Defaults to a flat list.
+   *
+   * @generated from field: todo.v1.TaskView view = 14;
+   */
+  view: TaskView;
+
+  /**
+   * Only open tasks whose blockers are all completed, if set.
+   *
+   * @generated from field: bool actionable = 15;
+   */
+  actionable: boolean;
 };
 
 /**
@@ -64,9 +216,27 @@ export const GetTasksRequestSchema: GenMessage<GetTasksRequest> = /*@__PURE__*/
  */
 export type GetTasksResponse = Message<"todo.v1.GetTasksResponse"> & {
   /**
+   * The matching tasks, in a flat list. Empty for TASK_VIEW_TREE.
+   *
    * @generated from field: repeated todo.v1.Task tasks = 1;
    */
   tasks: Task[];
+
+  /**
+   * Token for the following page; empty when there are no more tasks.
+   *
+   * @generated from field: string next_page_token = 2;
+   */
+  nextPageToken: string;
+
+  /**
+   * The matching tasks nested under their parents, for TASK_VIEW_TREE. A
+   * task whose parent does not match the filters is returned at the top
+   * level. Pages count top-level nodes only.
+   *
+   * @generated from field: repeated todo.v1.TaskNode tree = 3;
+   */
+  tree: TaskNode[];
 };
 
 /**
@@ -76,6 +246,30 @@ export type GetTasksResponse = Message<"todo.v1.GetTasksResponse"> & {
 export const GetTasksResponseSchema: GenMessage<GetTasksResponse> = /*@__PURE__*/
   messageDesc(file_todo, 3);
 
+/**
+ * A task and its subtasks, in the requested order.
+ *
+ * @generated from message todo.v1.TaskNode
+ */
+export type TaskNode = Message<"todo.v1.TaskNode"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+
+  /**
+   * @generated from field: repeated todo.v1.TaskNode subtasks = 2;
+   */
+  subtasks: TaskNode[];
+};
+
+/**
+ * Describes the message todo.v1.TaskNode.
+ * Use `create(TaskNodeSchema)` to create a new message.
+ */
+export const TaskNodeSchema: GenMessage<TaskNode> = /*@__PURE__*/
+  messageDesc(file_todo, 4);
+
 /**
  * @generated from message todo.v1.DeleteTaskRequest
  */
@@ -84,6 +278,28 @@ export type DeleteTaskRequest = Message<"todo.v1.DeleteTaskRequest"> & {
    * @generated from field: string id = 1;
    */
   id: string;
+
+  /**
+   * If set, the task is only deleted when it belongs to this list.
+   *
+   * @generated from field: string list_id = 2;
+   */
+  listId: string;
+
+  /**
+   * What to do with the task's subtasks.
+   *
+   * @generated from field: todo.v1.SubtaskDeletePolicy subtask_policy = 3;
+   */
+  subtaskPolicy: SubtaskDeletePolicy;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 4;
+   */
+  expectedVersion: bigint;
 };
 
 /**
@@ -91,7 +307,7 @@ export type DeleteTaskRequest = Message<"todo.v1.DeleteTaskRequest"> & {
  * Use `create(DeleteTaskRequestSchema)` to create a new message.
  */
 export const DeleteTaskRequestSchema: GenMessage<DeleteTaskRequest> = /*@__PURE__*/
-  messageDesc(file_todo, 4);
+  messageDesc(file_todo, 5);
 
 /**
  * @generated from message todo.v1.DeleteTaskResponse
@@ -108,63 +324,2454 @@ export type DeleteTaskResponse = Message<"todo.v1.DeleteTaskResponse"> & {
  * Use `create(DeleteTaskResponseSchema)` to create a new message.
  */
 export const DeleteTaskResponseSchema: GenMessage<DeleteTaskResponse> = /*@__PURE__*/
-  messageDesc(file_todo, 5);
+  messageDesc(file_todo, 6);
 
 /**
- * @generated from message todo.v1.Task
+ * @generated from message todo.v1.UpdateTaskRequest
  */
-export type Task = Message<"todo.v1.Task"> & {
+export type UpdateTaskRequest = Message<"todo.v1.UpdateTaskRequest"> & {
   /**
    * @generated from field: string id = 1;
    */
   id: string;
 
   /**
-   * @generated from field: string text = 2;
+   * Carries the new values for the fields named in update_mask.
+   *
+   * @generated from field: todo.v1.Task task = 2;
    */
-  text: string;
+  task?: Task;
 
   /**
-   * @generated from field: int64 created_at = 3;
+   * Paths of the Task fields to overwrite. Supported paths: "text",
+   * "list_id" (empty moves the task to the inbox), "due_at" (zero clears
+   * the due date), "priority", "tags" (replaces every tag), "parent_id"
+   * (empty makes the task top-level) and "recurrence" (empty stops the task
+   * from repeating). Use MoveTask to change the position.
+   *
+   * @generated from field: google.protobuf.FieldMask update_mask = 3;
    */
-  createdAt: bigint;
+  updateMask?: FieldMask;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 4;
+   */
+  expectedVersion: bigint;
 };
 
 /**
- * Describes the message todo.v1.Task.
- * Use `create(TaskSchema)` to create a new message.
+ * Describes the message todo.v1.UpdateTaskRequest.
+ * Use `create(UpdateTaskRequestSchema)` to create a new message.
  */
-export const TaskSchema: GenMessage<Task> = /*@__PURE__*/
-  messageDesc(file_todo, 6);
+export const UpdateTaskRequestSchema: GenMessage<UpdateTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 7);
 
 /**
- * @generated from service todo.v1.TodoService
+ * @generated from message todo.v1.UpdateTaskResponse
  */
-export const TodoService: GenService<{
+export type UpdateTaskResponse = Message<"todo.v1.UpdateTaskResponse"> & {
   /**
-   * @generated from rpc todo.v1.TodoService.AddTask
+   * @generated from field: todo.v1.Task task = 1;
    */
-  addTask: {
-    methodKind: "unary";
-    input: typeof AddTaskRequestSchema;
-    output: typeof AddTaskResponseSchema;
-  },
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.UpdateTaskResponse.
+ * Use `create(UpdateTaskResponseSchema)` to create a new message.
+ */
+export const UpdateTaskResponseSchema: GenMessage<UpdateTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 8);
+
+/**
+ * @generated from message todo.v1.CompleteTaskRequest
+ */
+export type CompleteTaskRequest = Message<"todo.v1.CompleteTaskRequest"> & {
   /**
-   * @generated from rpc todo.v1.TodoService.GetTasks
+   * @generated from field: string id = 1;
    */
-  getTasks: {
-    methodKind: "unary";
-    input: typeof GetTasksRequestSchema;
-    output: typeof GetTasksResponseSchema;
-  },
+  id: string;
+
   /**
-   * @generated from rpc todo.v1.TodoService.DeleteTask
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 2;
    */
-  deleteTask: {
-    methodKind: "unary";
-    input: typeof DeleteTaskRequestSchema;
-    output: typeof DeleteTaskResponseSchema;
-  },
-}> = /*@__PURE__*/
-  serviceDesc(file_todo, 0);
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.CompleteTaskRequest.
+ * Use `create(CompleteTaskRequestSchema)` to create a new message.
+ */
+export const CompleteTaskRequestSchema: GenMessage<CompleteTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 9);
+
+/**
+ * @generated from message todo.v1.CompleteTaskResponse
+ */
+export type CompleteTaskResponse = Message<"todo.v1.CompleteTaskResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.CompleteTaskResponse.
+ * Use `create(CompleteTaskResponseSchema)` to create a new message.
+ */
+export const CompleteTaskResponseSchema: GenMessage<CompleteTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 10);
+
+/**
+ * @generated from message todo.v1.ReopenTaskRequest
+ */
+export type ReopenTaskRequest = Message<"todo.v1.ReopenTaskRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 2;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.ReopenTaskRequest.
+ * Use `create(ReopenTaskRequestSchema)` to create a new message.
+ */
+export const ReopenTaskRequestSchema: GenMessage<ReopenTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 11);
+
+/**
+ * @generated from message todo.v1.ReopenTaskResponse
+ */
+export type ReopenTaskResponse = Message<"todo.v1.ReopenTaskResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.ReopenTaskResponse.
+ * Use `create(ReopenTaskResponseSchema)` to create a new message.
+ */
+export const ReopenTaskResponseSchema: GenMessage<ReopenTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 12);
+
+/**
+ * @generated from message todo.v1.WatchTasksRequest
+ */
+export type WatchTasksRequest = Message<"todo.v1.WatchTasksRequest"> & {
+};
+
+/**
+ * Describes the message todo.v1.WatchTasksRequest.
+ * Use `create(WatchTasksRequestSchema)` to create a new message.
+ */
+export const WatchTasksRequestSchema: GenMessage<WatchTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 13);
+
+/**
+ * @generated from message todo.v1.WatchTasksResponse
+ */
+export type WatchTasksResponse = Message<"todo.v1.WatchTasksResponse"> & {
+  /**
+   * @generated from field: todo.v1.TaskEvent event = 1;
+   */
+  event?: TaskEvent;
+};
+
+/**
+ * Describes the message todo.v1.WatchTasksResponse.
+ * Use `create(WatchTasksResponseSchema)` to create a new message.
+ */
+export const WatchTasksResponseSchema: GenMessage<WatchTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 14);
+
+/**
+ * @generated from message todo.v1.TaskEvent
+ */
+export type TaskEvent = Message<"todo.v1.TaskEvent"> & {
+  /**
+   * @generated from field: todo.v1.TaskEventType type = 1;
+   */
+  type: TaskEventType;
+
+  /**
+   * The task after the change. Deletion events carry the task as it was
+   * just before it was removed.
+   *
+   * @generated from field: todo.v1.Task task = 2;
+   */
+  task?: Task;
+
+  /**
+   * @generated from field: int64 occurred_at = 3;
+   */
+  occurredAt: bigint;
+};
+
+/**
+ * Describes the message todo.v1.TaskEvent.
+ * Use `create(TaskEventSchema)` to create a new message.
+ */
+export const TaskEventSchema: GenMessage<TaskEvent> = /*@__PURE__*/
+  messageDesc(file_todo, 15);
+
+/**
+ * HistoryEvent is an immutable record of a change to a task. Replaying every
+ * event in seq order rebuilds the live and trashed tasks.
+ *
+ * @generated from message todo.v1.HistoryEvent
+ */
+export type HistoryEvent = Message<"todo.v1.HistoryEvent"> & {
+  /**
+   * Increases by one with every event the server records.
+   *
+   * @generated from field: int64 seq = 1;
+   */
+  seq: bigint;
+
+  /**
+   * @generated from field: todo.v1.HistoryEventType type = 2;
+   */
+  type: HistoryEventType;
+
+  /**
+   * The task after the change. Purge events carry only the task's id,
+   * owner_id and list_id, and replace the earlier events of the task.
+   *
+   * @generated from field: todo.v1.Task task = 3;
+   */
+  task?: Task;
+
+  /**
+   * User who made the change; empty for changes the server made on its own,
+   * such as emptying the trash, and when authentication is disabled.
+   *
+   * @generated from field: string actor = 4;
+   */
+  actor: string;
+
+  /**
+   * @generated from field: int64 occurred_at = 5;
+   */
+  occurredAt: bigint;
+};
+
+/**
+ * Describes the message todo.v1.HistoryEvent.
+ * Use `create(HistoryEventSchema)` to create a new message.
+ */
+export const HistoryEventSchema: GenMessage<HistoryEvent> = /*@__PURE__*/
+  messageDesc(file_todo, 16);
+
+/**
+ * @generated from message todo.v1.GetTaskHistoryRequest
+ */
+export type GetTaskHistoryRequest = Message<"todo.v1.GetTaskHistoryRequest"> & {
+  /**
+   * @generated from field: string task_id = 1;
+   */
+  taskId: string;
+};
+
+/**
+ * Describes the message todo.v1.GetTaskHistoryRequest.
+ * Use `create(GetTaskHistoryRequestSchema)` to create a new message.
+ */
+export const GetTaskHistoryRequestSchema: GenMessage<GetTaskHistoryRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 17);
+
+/**
+ * @generated from message todo.v1.GetTaskHistoryResponse
+ */
+export type GetTaskHistoryResponse = Message<"todo.v1.GetTaskHistoryResponse"> & {
+  /**
+   * @generated from field: repeated todo.v1.HistoryEvent events = 1;
+   */
+  events: HistoryEvent[];
+};
+
+/**
+ * Describes the message todo.v1.GetTaskHistoryResponse.
+ * Use `create(GetTaskHistoryResponseSchema)` to create a new message.
+ */
+export const GetTaskHistoryResponseSchema: GenMessage<GetTaskHistoryResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 18);
+
+/**
+ * @generated from message todo.v1.ListEventsRequest
+ */
+export type ListEventsRequest = Message<"todo.v1.ListEventsRequest"> & {
+  /**
+   * Only events that occurred at or after this Unix time, if set.
+   *
+   * @generated from field: int64 since = 1;
+   */
+  since: bigint;
+
+  /**
+   * Only events that occurred before this Unix time, if set.
+   *
+   * @generated from field: int64 until = 2;
+   */
+  until: bigint;
+
+  /**
+   * Maximum number of events to return; zero returns all of them, values
+   * above 1000 are reduced to 1000.
+   *
+   * @generated from field: int32 page_size = 3;
+   */
+  pageSize: number;
+
+  /**
+   * next_page_token from a previous response; resumes the listing after the
+   * last event of that page.
+   *
+   * @generated from field: string page_token = 4;
+   */
+  pageToken: string;
+};
+
+/**
+ * Describes the message todo.v1.ListEventsRequest.
+ * Use `create(ListEventsRequestSchema)` to create a new message.
+ */
+export const ListEventsRequestSchema: GenMessage<ListEventsRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 19);
+
+/**
+ * @generated from message todo.v1.ListEventsResponse
+ */
+export type ListEventsResponse = Message<"todo.v1.ListEventsResponse"> & {
+  /**
+   * @generated from field: repeated todo.v1.HistoryEvent events = 1;
+   */
+  events: HistoryEvent[];
+
+  /**
+   * Token for the following page; empty when there are no more events.
+   *
+   * @generated from field: string next_page_token = 2;
+   */
+  nextPageToken: string;
+};
+
+/**
+ * Describes the message todo.v1.ListEventsResponse.
+ * Use `create(ListEventsResponseSchema)` to create a new message.
+ */
+export const ListEventsResponseSchema: GenMessage<ListEventsResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 20);
+
+/**
+ * @generated from message todo.v1.Task
+ */
+export type Task = Message<"todo.v1.Task"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string text = 2;
+   */
+  text: string;
+
+  /**
+   * @generated from field: int64 created_at = 3;
+   */
+  createdAt: bigint;
+
+  /**
+   * @generated from field: bool completed = 4;
+   */
+  completed: boolean;
+
+  /**
+   * Unix time the task was marked done; zero while the task is open.
+   *
+   * @generated from field: int64 completed_at = 5;
+   */
+  completedAt: bigint;
+
+  /**
+   * List the task belongs to; empty for the inbox.
+   *
+   * @generated from field: string list_id = 6;
+   */
+  listId: string;
+
+  /**
+   * User who created the task. Set by the server; empty when authentication
+   * is disabled.
+   *
+   * @generated from field: string owner_id = 7;
+   */
+  ownerId: string;
+
+  /**
+   * Unix time the task is due; zero for none.
+   *
+   * @generated from field: int64 due_at = 8;
+   */
+  dueAt: bigint;
+
+  /**
+   * @generated from field: todo.v1.Priority priority = 9;
+   */
+  priority: Priority;
+
+  /**
+   * Rank of the task in the owner's manual order. Positions compare as
+   * strings; they are assigned by the server, new tasks going last.
+   *
+   * @generated from field: string position = 10;
+   */
+  position: string;
+
+  /**
+   * Normalized tag names, sorted and without duplicates.
+   *
+   * @generated from field: repeated string tags = 11;
+   */
+  tags: string[];
+
+  /**
+   * Task this one is a subtask of; empty for a top-level task.
+   *
+   * @generated from field: string parent_id = 12;
+   */
+  parentId: string;
+
+  /**
+   * IDs of the tasks that must be completed before this one can start,
+   * sorted. Deleting a blocker removes it from the list.
+   *
+   * @generated from field: repeated string blocked_by = 13;
+   */
+  blockedBy: string[];
+
+  /**
+   * RFC 5545 RRULE subset saying how the task repeats, such as
+   * "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH": FREQ is DAILY, WEEKLY or MONTHLY,
+   * INTERVAL defaults to 1 and BYDAY is only allowed with WEEKLY. Empty for
+   * a task that does not repeat. Once the task is completed or comes due, the
+   * server adds its next instance, due at the rule's next occurrence in the
+   * server's time zone, and moves the rule onto it. Stored in canonical form.
+   *
+   * @generated from field: string recurrence = 14;
+   */
+  recurrence: string;
+
+  /**
+   * Unix time the task was moved to the trash; zero for a live task.
+   *
+   * @generated from field: int64 deleted_at = 15;
+   */
+  deletedAt: bigint;
+
+  /**
+   * Starts at 1 and goes up every time the task changes, including when it
+   * is restored from the trash or a change to it is undone. Pass it as the
+   * expected_version of a mutating call to make the call fail if the task
+   * has changed since it was read.
+   *
+   * @generated from field: int64 version = 16;
+   */
+  version: bigint;
+};
+
+/**
+ * Describes the message todo.v1.Task.
+ * Use `create(TaskSchema)` to create a new message.
+ */
+export const TaskSchema: GenMessage<Task> = /*@__PURE__*/
+  messageDesc(file_todo, 21);
+
+/**
+ * @generated from message todo.v1.MoveTaskRequest
+ */
+export type MoveTaskRequest = Message<"todo.v1.MoveTaskRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * Exactly one of before_id and after_id names the task to place this one
+   * next to.
+   *
+   * @generated from field: string before_id = 2;
+   */
+  beforeId: string;
+
+  /**
+   * @generated from field: string after_id = 3;
+   */
+  afterId: string;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 4;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.MoveTaskRequest.
+ * Use `create(MoveTaskRequestSchema)` to create a new message.
+ */
+export const MoveTaskRequestSchema: GenMessage<MoveTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 22);
+
+/**
+ * @generated from message todo.v1.MoveTaskResponse
+ */
+export type MoveTaskResponse = Message<"todo.v1.MoveTaskResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.MoveTaskResponse.
+ * Use `create(MoveTaskResponseSchema)` to create a new message.
+ */
+export const MoveTaskResponseSchema: GenMessage<MoveTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 23);
+
+/**
+ * @generated from message todo.v1.AddTagsRequest
+ */
+export type AddTagsRequest = Message<"todo.v1.AddTagsRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * Tags to add; tags the task already has are ignored.
+   *
+   * @generated from field: repeated string tags = 2;
+   */
+  tags: string[];
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 3;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.AddTagsRequest.
+ * Use `create(AddTagsRequestSchema)` to create a new message.
+ */
+export const AddTagsRequestSchema: GenMessage<AddTagsRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 24);
+
+/**
+ * @generated from message todo.v1.AddTagsResponse
+ */
+export type AddTagsResponse = Message<"todo.v1.AddTagsResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.AddTagsResponse.
+ * Use `create(AddTagsResponseSchema)` to create a new message.
+ */
+export const AddTagsResponseSchema: GenMessage<AddTagsResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 25);
+
+/**
+ * @generated from message todo.v1.RemoveTagsRequest
+ */
+export type RemoveTagsRequest = Message<"todo.v1.RemoveTagsRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * Tags to remove; tags the task does not have are ignored.
+   *
+   * @generated from field: repeated string tags = 2;
+   */
+  tags: string[];
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 3;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.RemoveTagsRequest.
+ * Use `create(RemoveTagsRequestSchema)` to create a new message.
+ */
+export const RemoveTagsRequestSchema: GenMessage<RemoveTagsRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 26);
+
+/**
+ * @generated from message todo.v1.RemoveTagsResponse
+ */
+export type RemoveTagsResponse = Message<"todo.v1.RemoveTagsResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.RemoveTagsResponse.
+ * Use `create(RemoveTagsResponseSchema)` to create a new message.
+ */
+export const RemoveTagsResponseSchema: GenMessage<RemoveTagsResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 27);
+
+/**
+ * @generated from message todo.v1.ListTagsRequest
+ */
+export type ListTagsRequest = Message<"todo.v1.ListTagsRequest"> & {
+  /**
+   * Only count tasks in this list, if set.
+   *
+   * @generated from field: string list_id = 1;
+   */
+  listId: string;
+};
+
+/**
+ * Describes the message todo.v1.ListTagsRequest.
+ * Use `create(ListTagsRequestSchema)` to create a new message.
+ */
+export const ListTagsRequestSchema: GenMessage<ListTagsRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 28);
+
+/**
+ * @generated from message todo.v1.ListTagsResponse
+ */
+export type ListTagsResponse = Message<"todo.v1.ListTagsResponse"> & {
+  /**
+   * Sorted by name.
+   *
+   * @generated from field: repeated todo.v1.TagCount tags = 1;
+   */
+  tags: TagCount[];
+};
+
+/**
+ * Describes the message todo.v1.ListTagsResponse.
+ * Use `create(ListTagsResponseSchema)` to create a new message.
+ */
+export const ListTagsResponseSchema: GenMessage<ListTagsResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 29);
+
+/**
+ * @generated from message todo.v1.TagCount
+ */
+export type TagCount = Message<"todo.v1.TagCount"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * Number of tasks, open or completed, that carry the tag.
+   *
+   * @generated from field: int32 count = 2;
+   */
+  count: number;
+};
+
+/**
+ * Describes the message todo.v1.TagCount.
+ * Use `create(TagCountSchema)` to create a new message.
+ */
+export const TagCountSchema: GenMessage<TagCount> = /*@__PURE__*/
+  messageDesc(file_todo, 30);
+
+/**
+ * @generated from message todo.v1.AddBlockerRequest
+ */
+export type AddBlockerRequest = Message<"todo.v1.AddBlockerRequest"> & {
+  /**
+   * The task that has to wait.
+   *
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * The task it waits for. Adding a blocker the task already has does
+   * nothing; one that would make the task wait on itself is refused.
+   *
+   * @generated from field: string blocker_id = 2;
+   */
+  blockerId: string;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 3;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.AddBlockerRequest.
+ * Use `create(AddBlockerRequestSchema)` to create a new message.
+ */
+export const AddBlockerRequestSchema: GenMessage<AddBlockerRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 31);
+
+/**
+ * @generated from message todo.v1.AddBlockerResponse
+ */
+export type AddBlockerResponse = Message<"todo.v1.AddBlockerResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.AddBlockerResponse.
+ * Use `create(AddBlockerResponseSchema)` to create a new message.
+ */
+export const AddBlockerResponseSchema: GenMessage<AddBlockerResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 32);
+
+/**
+ * @generated from message todo.v1.RemoveBlockerRequest
+ */
+export type RemoveBlockerRequest = Message<"todo.v1.RemoveBlockerRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * Removing a blocker the task does not have does nothing.
+   *
+   * @generated from field: string blocker_id = 2;
+   */
+  blockerId: string;
+
+  /**
+   * If non-zero, the call fails with ABORTED unless the task is at this
+   * version.
+   *
+   * @generated from field: int64 expected_version = 3;
+   */
+  expectedVersion: bigint;
+};
+
+/**
+ * Describes the message todo.v1.RemoveBlockerRequest.
+ * Use `create(RemoveBlockerRequestSchema)` to create a new message.
+ */
+export const RemoveBlockerRequestSchema: GenMessage<RemoveBlockerRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 33);
+
+/**
+ * @generated from message todo.v1.RemoveBlockerResponse
+ */
+export type RemoveBlockerResponse = Message<"todo.v1.RemoveBlockerResponse"> & {
+  /**
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.RemoveBlockerResponse.
+ * Use `create(RemoveBlockerResponseSchema)` to create a new message.
+ */
+export const RemoveBlockerResponseSchema: GenMessage<RemoveBlockerResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 34);
+
+/**
+ * @generated from message todo.v1.ListTrashRequest
+ */
+export type ListTrashRequest = Message<"todo.v1.ListTrashRequest"> & {
+};
+
+/**
+ * Describes the message todo.v1.ListTrashRequest.
+ * Use `create(ListTrashRequestSchema)` to create a new message.
+ */
+export const ListTrashRequestSchema: GenMessage<ListTrashRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 35);
+
+/**
+ * @generated from message todo.v1.ListTrashResponse
+ */
+export type ListTrashResponse = Message<"todo.v1.ListTrashResponse"> & {
+  /**
+   * @generated from field: repeated todo.v1.Task tasks = 1;
+   */
+  tasks: Task[];
+};
+
+/**
+ * Describes the message todo.v1.ListTrashResponse.
+ * Use `create(ListTrashResponseSchema)` to create a new message.
+ */
+export const ListTrashResponseSchema: GenMessage<ListTrashResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 36);
+
+/**
+ * @generated from message todo.v1.RestoreTaskRequest
+ */
+export type RestoreTaskRequest = Message<"todo.v1.RestoreTaskRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message todo.v1.RestoreTaskRequest.
+ * Use `create(RestoreTaskRequestSchema)` to create a new message.
+ */
+export const RestoreTaskRequestSchema: GenMessage<RestoreTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 37);
+
+/**
+ * @generated from message todo.v1.RestoreTaskResponse
+ */
+export type RestoreTaskResponse = Message<"todo.v1.RestoreTaskResponse"> & {
+  /**
+   * The restored task. If its parent or list is gone it comes back at the
+   * top level or in the inbox, and blockers that are gone are dropped.
+   *
+   * @generated from field: todo.v1.Task task = 1;
+   */
+  task?: Task;
+};
+
+/**
+ * Describes the message todo.v1.RestoreTaskResponse.
+ * Use `create(RestoreTaskResponseSchema)` to create a new message.
+ */
+export const RestoreTaskResponseSchema: GenMessage<RestoreTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 38);
+
+/**
+ * @generated from message todo.v1.PurgeTaskRequest
+ */
+export type PurgeTaskRequest = Message<"todo.v1.PurgeTaskRequest"> & {
+  /**
+   * ID of a trashed task.
+   *
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message todo.v1.PurgeTaskRequest.
+ * Use `create(PurgeTaskRequestSchema)` to create a new message.
+ */
+export const PurgeTaskRequestSchema: GenMessage<PurgeTaskRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 39);
+
+/**
+ * @generated from message todo.v1.PurgeTaskResponse
+ */
+export type PurgeTaskResponse = Message<"todo.v1.PurgeTaskResponse"> & {
+};
+
+/**
+ * Describes the message todo.v1.PurgeTaskResponse.
+ * Use `create(PurgeTaskResponseSchema)` to create a new message.
+ */
+export const PurgeTaskResponseSchema: GenMessage<PurgeTaskResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 40);
+
+/**
+ * @generated from message todo.v1.UndoRequest
+ */
+export type UndoRequest = Message<"todo.v1.UndoRequest"> & {
+};
+
+/**
+ * Describes the message todo.v1.UndoRequest.
+ * Use `create(UndoRequestSchema)` to create a new message.
+ */
+export const UndoRequestSchema: GenMessage<UndoRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 41);
+
+/**
+ * @generated from message todo.v1.UndoResponse
+ */
+export type UndoResponse = Message<"todo.v1.UndoResponse"> & {
+  /**
+   * The tasks the undo put back, as they are now.
+   *
+   * @generated from field: repeated todo.v1.Task tasks = 1;
+   */
+  tasks: Task[];
+
+  /**
+   * IDs of the tasks removed because the undone operation added them.
+   *
+   * @generated from field: repeated string removed_ids = 2;
+   */
+  removedIds: string[];
+};
+
+/**
+ * Describes the message todo.v1.UndoResponse.
+ * Use `create(UndoResponseSchema)` to create a new message.
+ */
+export const UndoResponseSchema: GenMessage<UndoResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 42);
+
+/**
+ * @generated from message todo.v1.BatchAddTasksRequest
+ */
+export type BatchAddTasksRequest = Message<"todo.v1.BatchAddTasksRequest"> & {
+  /**
+   * At most 100 requests.
+   *
+   * @generated from field: repeated todo.v1.AddTaskRequest requests = 1;
+   */
+  requests: AddTaskRequest[];
+};
+
+/**
+ * Describes the message todo.v1.BatchAddTasksRequest.
+ * Use `create(BatchAddTasksRequestSchema)` to create a new message.
+ */
+export const BatchAddTasksRequestSchema: GenMessage<BatchAddTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 43);
+
+/**
+ * @generated from message todo.v1.BatchAddTasksResponse
+ */
+export type BatchAddTasksResponse = Message<"todo.v1.BatchAddTasksResponse"> & {
+  /**
+   * The added tasks, in request order.
+   *
+   * @generated from field: repeated todo.v1.Task tasks = 1;
+   */
+  tasks: Task[];
+};
+
+/**
+ * Describes the message todo.v1.BatchAddTasksResponse.
+ * Use `create(BatchAddTasksResponseSchema)` to create a new message.
+ */
+export const BatchAddTasksResponseSchema: GenMessage<BatchAddTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 44);
+
+/**
+ * @generated from message todo.v1.BatchUpdateTasksRequest
+ */
+export type BatchUpdateTasksRequest = Message<"todo.v1.BatchUpdateTasksRequest"> & {
+  /**
+   * At most 100 requests. A task updated more than once sees the earlier
+   * updates applied.
+   *
+   * @generated from field: repeated todo.v1.UpdateTaskRequest requests = 1;
+   */
+  requests: UpdateTaskRequest[];
+};
+
+/**
+ * Describes the message todo.v1.BatchUpdateTasksRequest.
+ * Use `create(BatchUpdateTasksRequestSchema)` to create a new message.
+ */
+export const BatchUpdateTasksRequestSchema: GenMessage<BatchUpdateTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 45);
+
+/**
+ * @generated from message todo.v1.BatchUpdateTasksResponse
+ */
+export type BatchUpdateTasksResponse = Message<"todo.v1.BatchUpdateTasksResponse"> & {
+  /**
+   * The task each request left behind, in request order.
+   *
+   * @generated from field: repeated todo.v1.Task tasks = 1;
+   */
+  tasks: Task[];
+};
+
+/**
+ * Describes the message todo.v1.BatchUpdateTasksResponse.
+ * Use `create(BatchUpdateTasksResponseSchema)` to create a new message.
+ */
+export const BatchUpdateTasksResponseSchema: GenMessage<BatchUpdateTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 46);
+
+/**
+ * @generated from message todo.v1.BatchDeleteTasksRequest
+ */
+export type BatchDeleteTasksRequest = Message<"todo.v1.BatchDeleteTasksRequest"> & {
+  /**
+   * At most 100 requests.
+   *
+   * @generated from field: repeated todo.v1.DeleteTaskRequest requests = 1;
+   */
+  requests: DeleteTaskRequest[];
+};
+
+/**
+ * Describes the message todo.v1.BatchDeleteTasksRequest.
+ * Use `create(BatchDeleteTasksRequestSchema)` to create a new message.
+ */
+export const BatchDeleteTasksRequestSchema: GenMessage<BatchDeleteTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 47);
+
+/**
+ * @generated from message todo.v1.BatchDeleteTasksResponse
+ */
+export type BatchDeleteTasksResponse = Message<"todo.v1.BatchDeleteTasksResponse"> & {
+};
+
+/**
+ * Describes the message todo.v1.BatchDeleteTasksResponse.
+ * Use `create(BatchDeleteTasksResponseSchema)` to create a new message.
+ */
+export const BatchDeleteTasksResponseSchema: GenMessage<BatchDeleteTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 48);
+
+/**
+ * BatchItemError is the error detail describing a failed request of a batch.
+ * Requests that fail validation are all reported; otherwise only the first
+ * request that failed is.
+ *
+ * @generated from message todo.v1.BatchItemError
+ */
+export type BatchItemError = Message<"todo.v1.BatchItemError"> & {
+  /**
+   * Position of the request in the batch, from zero.
+   *
+   * @generated from field: int32 index = 1;
+   */
+  index: number;
+
+  /**
+   * The Connect error code the request would have failed with on its own,
+   * such as "not_found".
+   *
+   * @generated from field: string code = 2;
+   */
+  code: string;
+
+  /**
+   * @generated from field: string message = 3;
+   */
+  message: string;
+};
+
+/**
+ * Describes the message todo.v1.BatchItemError.
+ * Use `create(BatchItemErrorSchema)` to create a new message.
+ */
+export const BatchItemErrorSchema: GenMessage<BatchItemError> = /*@__PURE__*/
+  messageDesc(file_todo, 49);
+
+/**
+ * @generated from message todo.v1.ExportTasksRequest
+ */
+export type ExportTasksRequest = Message<"todo.v1.ExportTasksRequest"> & {
+  /**
+   * @generated from field: todo.v1.TaskFormat format = 1;
+   */
+  format: TaskFormat;
+
+  /**
+   * Only export this list's tasks, if set.
+   *
+   * @generated from field: string list_id = 2;
+   */
+  listId: string;
+
+  /**
+   * @generated from field: todo.v1.TaskStatus status = 3;
+   */
+  status: TaskStatus;
+};
+
+/**
+ * Describes the message todo.v1.ExportTasksRequest.
+ * Use `create(ExportTasksRequestSchema)` to create a new message.
+ */
+export const ExportTasksRequestSchema: GenMessage<ExportTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 50);
+
+/**
+ * @generated from message todo.v1.ExportTasksResponse
+ */
+export type ExportTasksResponse = Message<"todo.v1.ExportTasksResponse"> & {
+  /**
+   * @generated from field: bytes data = 1;
+   */
+  data: Uint8Array;
+};
+
+/**
+ * Describes the message todo.v1.ExportTasksResponse.
+ * Use `create(ExportTasksResponseSchema)` to create a new message.
+ */
+export const ExportTasksResponseSchema: GenMessage<ExportTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 51);
+
+/**
+ * @generated from message todo.v1.ImportTasksRequest
+ */
+export type ImportTasksRequest = Message<"todo.v1.ImportTasksRequest"> & {
+  /**
+   * @generated from field: todo.v1.TaskFormat format = 1;
+   */
+  format: TaskFormat;
+
+  /**
+   * At most 1000 tasks.
+   *
+   * @generated from field: bytes data = 2;
+   */
+  data: Uint8Array;
+
+  /**
+   * List to add the tasks to; empty for the inbox.
+   *
+   * @generated from field: string list_id = 3;
+   */
+  listId: string;
+};
+
+/**
+ * Describes the message todo.v1.ImportTasksRequest.
+ * Use `create(ImportTasksRequestSchema)` to create a new message.
+ */
+export const ImportTasksRequestSchema: GenMessage<ImportTasksRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 52);
+
+/**
+ * @generated from message todo.v1.ImportTasksResponse
+ */
+export type ImportTasksResponse = Message<"todo.v1.ImportTasksResponse"> & {
+  /**
+   * The added tasks, in file order.
+   *
+   * @generated from field: repeated todo.v1.Task tasks = 1;
+   */
+  tasks: Task[];
+};
+
+/**
+ * Describes the message todo.v1.ImportTasksResponse.
+ * Use `create(ImportTasksResponseSchema)` to create a new message.
+ */
+export const ImportTasksResponseSchema: GenMessage<ImportTasksResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 53);
+
+/**
+ * ImportLineError is the error detail describing an invalid line of an
+ * import.
+ *
+ * @generated from message todo.v1.ImportLineError
+ */
+export type ImportLineError = Message<"todo.v1.ImportLineError"> & {
+  /**
+   * Line number, counting from 1. For JSON it is the position of the task in
+   * the array instead.
+   *
+   * @generated from field: int32 line = 1;
+   */
+  line: number;
+
+  /**
+   * @generated from field: string message = 2;
+   */
+  message: string;
+};
+
+/**
+ * Describes the message todo.v1.ImportLineError.
+ * Use `create(ImportLineErrorSchema)` to create a new message.
+ */
+export const ImportLineErrorSchema: GenMessage<ImportLineError> = /*@__PURE__*/
+  messageDesc(file_todo, 54);
+
+/**
+ * RetryInfo is the error detail of RESOURCE_EXHAUSTED errors, saying which
+ * limit the call ran into and when retrying it may succeed.
+ *
+ * @generated from message todo.v1.RetryInfo
+ */
+export type RetryInfo = Message<"todo.v1.RetryInfo"> & {
+  /**
+   * The limit that was reached: "requests" for the rate limit, or "tasks",
+   * "tasks_per_owner" or "tasks_per_list" for the caps on stored tasks.
+   *
+   * @generated from field: string limit = 1;
+   */
+  limit: string;
+
+  /**
+   * How long to wait before retrying, in milliseconds. Zero when waiting
+   * alone will not help: a task cap only frees up as tasks are purged.
+   *
+   * @generated from field: int64 retry_after_ms = 2;
+   */
+  retryAfterMs: bigint;
+};
+
+/**
+ * Describes the message todo.v1.RetryInfo.
+ * Use `create(RetryInfoSchema)` to create a new message.
+ */
+export const RetryInfoSchema: GenMessage<RetryInfo> = /*@__PURE__*/
+  messageDesc(file_todo, 55);
+
+/**
+ * @generated from message todo.v1.CreateListRequest
+ */
+export type CreateListRequest = Message<"todo.v1.CreateListRequest"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+};
+
+/**
+ * Describes the message todo.v1.CreateListRequest.
+ * Use `create(CreateListRequestSchema)` to create a new message.
+ */
+export const CreateListRequestSchema: GenMessage<CreateListRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 56);
+
+/**
+ * @generated from message todo.v1.CreateListResponse
+ */
+export type CreateListResponse = Message<"todo.v1.CreateListResponse"> & {
+  /**
+   * @generated from field: todo.v1.List list = 1;
+   */
+  list?: List;
+};
+
+/**
+ * Describes the message todo.v1.CreateListResponse.
+ * Use `create(CreateListResponseSchema)` to create a new message.
+ */
+export const CreateListResponseSchema: GenMessage<CreateListResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 57);
+
+/**
+ * @generated from message todo.v1.GetListsRequest
+ */
+export type GetListsRequest = Message<"todo.v1.GetListsRequest"> & {
+};
+
+/**
+ * Describes the message todo.v1.GetListsRequest.
+ * Use `create(GetListsRequestSchema)` to create a new message.
+ */
+export const GetListsRequestSchema: GenMessage<GetListsRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 58);
+
+/**
+ * @generated from message todo.v1.GetListsResponse
+ */
+export type GetListsResponse = Message<"todo.v1.GetListsResponse"> & {
+  /**
+   * Every list the caller has a role on, oldest first. The inbox is implicit
+   * and not included.
+   *
+   * @generated from field: repeated todo.v1.List lists = 1;
+   */
+  lists: List[];
+};
+
+/**
+ * Describes the message todo.v1.GetListsResponse.
+ * Use `create(GetListsResponseSchema)` to create a new message.
+ */
+export const GetListsResponseSchema: GenMessage<GetListsResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 59);
+
+/**
+ * @generated from message todo.v1.RenameListRequest
+ */
+export type RenameListRequest = Message<"todo.v1.RenameListRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+};
+
+/**
+ * Describes the message todo.v1.RenameListRequest.
+ * Use `create(RenameListRequestSchema)` to create a new message.
+ */
+export const RenameListRequestSchema: GenMessage<RenameListRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 60);
+
+/**
+ * @generated from message todo.v1.RenameListResponse
+ */
+export type RenameListResponse = Message<"todo.v1.RenameListResponse"> & {
+  /**
+   * @generated from field: todo.v1.List list = 1;
+   */
+  list?: List;
+};
+
+/**
+ * Describes the message todo.v1.RenameListResponse.
+ * Use `create(RenameListResponseSchema)` to create a new message.
+ */
+export const RenameListResponseSchema: GenMessage<RenameListResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 61);
+
+/**
+ * @generated from message todo.v1.DeleteListRequest
+ */
+export type DeleteListRequest = Message<"todo.v1.DeleteListRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * What to do with the tasks still in the list.
+   *
+   * @generated from field: todo.v1.ListDeletePolicy policy = 2;
+   */
+  policy: ListDeletePolicy;
+
+  /**
+   * Destination for the tasks under LIST_DELETE_POLICY_MOVE; empty moves
+   * them to the inbox.
+   *
+   * @generated from field: string move_to_list_id = 3;
+   */
+  moveToListId: string;
+};
+
+/**
+ * Describes the message todo.v1.DeleteListRequest.
+ * Use `create(DeleteListRequestSchema)` to create a new message.
+ */
+export const DeleteListRequestSchema: GenMessage<DeleteListRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 62);
+
+/**
+ * @generated from message todo.v1.DeleteListResponse
+ */
+export type DeleteListResponse = Message<"todo.v1.DeleteListResponse"> & {
+  /**
+   * @generated from field: bool success = 1;
+   */
+  success: boolean;
+};
+
+/**
+ * Describes the message todo.v1.DeleteListResponse.
+ * Use `create(DeleteListResponseSchema)` to create a new message.
+ */
+export const DeleteListResponseSchema: GenMessage<DeleteListResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 63);
+
+/**
+ * @generated from message todo.v1.ShareListRequest
+ */
+export type ShareListRequest = Message<"todo.v1.ShareListRequest"> & {
+  /**
+   * @generated from field: string list_id = 1;
+   */
+  listId: string;
+
+  /**
+   * @generated from field: string user_id = 2;
+   */
+  userId: string;
+
+  /**
+   * @generated from field: todo.v1.ListRole role = 3;
+   */
+  role: ListRole;
+};
+
+/**
+ * Describes the message todo.v1.ShareListRequest.
+ * Use `create(ShareListRequestSchema)` to create a new message.
+ */
+export const ShareListRequestSchema: GenMessage<ShareListRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 64);
+
+/**
+ * @generated from message todo.v1.ShareListResponse
+ */
+export type ShareListResponse = Message<"todo.v1.ShareListResponse"> & {
+  /**
+   * @generated from field: todo.v1.List list = 1;
+   */
+  list?: List;
+};
+
+/**
+ * Describes the message todo.v1.ShareListResponse.
+ * Use `create(ShareListResponseSchema)` to create a new message.
+ */
+export const ShareListResponseSchema: GenMessage<ShareListResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 65);
+
+/**
+ * @generated from message todo.v1.RevokeListAccessRequest
+ */
+export type RevokeListAccessRequest = Message<"todo.v1.RevokeListAccessRequest"> & {
+  /**
+   * @generated from field: string list_id = 1;
+   */
+  listId: string;
+
+  /**
+   * @generated from field: string user_id = 2;
+   */
+  userId: string;
+};
+
+/**
+ * Describes the message todo.v1.RevokeListAccessRequest.
+ * Use `create(RevokeListAccessRequestSchema)` to create a new message.
+ */
+export const RevokeListAccessRequestSchema: GenMessage<RevokeListAccessRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 66);
+
+/**
+ * @generated from message todo.v1.RevokeListAccessResponse
+ */
+export type RevokeListAccessResponse = Message<"todo.v1.RevokeListAccessResponse"> & {
+  /**
+   * @generated from field: todo.v1.List list = 1;
+   */
+  list?: List;
+};
+
+/**
+ * Describes the message todo.v1.RevokeListAccessResponse.
+ * Use `create(RevokeListAccessResponseSchema)` to create a new message.
+ */
+export const RevokeListAccessResponseSchema: GenMessage<RevokeListAccessResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 67);
+
+/**
+ * A named group of tasks, such as a project.
+ *
+ * @generated from message todo.v1.List
+ */
+export type List = Message<"todo.v1.List"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * Unique among the owner's lists, ignoring case.
+   *
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: int64 created_at = 3;
+   */
+  createdAt: bigint;
+
+  /**
+   * User who created the list. Set by the server. The creator always has
+   * the owner role.
+   *
+   * @generated from field: string owner_id = 4;
+   */
+  ownerId: string;
+
+  /**
+   * Users the list is shared with, by user ID.
+   *
+   * @generated from field: repeated todo.v1.ListMember members = 5;
+   */
+  members: ListMember[];
+};
+
+/**
+ * Describes the message todo.v1.List.
+ * Use `create(ListSchema)` to create a new message.
+ */
+export const ListSchema: GenMessage<List> = /*@__PURE__*/
+  messageDesc(file_todo, 68);
+
+/**
+ * A user's access to a list shared with them.
+ *
+ * @generated from message todo.v1.ListMember
+ */
+export type ListMember = Message<"todo.v1.ListMember"> & {
+  /**
+   * @generated from field: string user_id = 1;
+   */
+  userId: string;
+
+  /**
+   * @generated from field: todo.v1.ListRole role = 2;
+   */
+  role: ListRole;
+};
+
+/**
+ * Describes the message todo.v1.ListMember.
+ * Use `create(ListMemberSchema)` to create a new message.
+ */
+export const ListMemberSchema: GenMessage<ListMember> = /*@__PURE__*/
+  messageDesc(file_todo, 69);
+
+/**
+ * A workspace is an isolated set of tasks and lists, such as a team's.
+ *
+ * @generated from message todo.v1.Workspace
+ */
+export type Workspace = Message<"todo.v1.Workspace"> & {
+  /**
+   * Lower-case letters, digits and dashes, starting with a letter or digit,
+   * at most 63 characters.
+   *
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: int64 created_at = 3;
+   */
+  createdAt: bigint;
+
+  /**
+   * Limit on the length of task text in bytes; zero for the server's.
+   *
+   * @generated from field: int32 max_task_text_length = 4;
+   */
+  maxTaskTextLength: number;
+
+  /**
+   * Limit on the tasks in the workspace, trashed ones included; zero for
+   * none. AddTask and the calls that add tasks fail with RESOURCE_EXHAUSTED
+   * once it is reached.
+   *
+   * @generated from field: int32 max_tasks = 5;
+   */
+  maxTasks: number;
+};
+
+/**
+ * Describes the message todo.v1.Workspace.
+ * Use `create(WorkspaceSchema)` to create a new message.
+ */
+export const WorkspaceSchema: GenMessage<Workspace> = /*@__PURE__*/
+  messageDesc(file_todo, 70);
+
+/**
+ * @generated from message todo.v1.CreateWorkspaceRequest
+ */
+export type CreateWorkspaceRequest = Message<"todo.v1.CreateWorkspaceRequest"> & {
+  /**
+   * The new workspace. created_at is set by the server.
+   *
+   * @generated from field: todo.v1.Workspace workspace = 1;
+   */
+  workspace?: Workspace;
+};
+
+/**
+ * Describes the message todo.v1.CreateWorkspaceRequest.
+ * Use `create(CreateWorkspaceRequestSchema)` to create a new message.
+ */
+export const CreateWorkspaceRequestSchema: GenMessage<CreateWorkspaceRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 71);
+
+/**
+ * @generated from message todo.v1.CreateWorkspaceResponse
+ */
+export type CreateWorkspaceResponse = Message<"todo.v1.CreateWorkspaceResponse"> & {
+  /**
+   * @generated from field: todo.v1.Workspace workspace = 1;
+   */
+  workspace?: Workspace;
+};
+
+/**
+ * Describes the message todo.v1.CreateWorkspaceResponse.
+ * Use `create(CreateWorkspaceResponseSchema)` to create a new message.
+ */
+export const CreateWorkspaceResponseSchema: GenMessage<CreateWorkspaceResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 72);
+
+/**
+ * @generated from message todo.v1.ListWorkspacesRequest
+ */
+export type ListWorkspacesRequest = Message<"todo.v1.ListWorkspacesRequest"> & {
+};
+
+/**
+ * Describes the message todo.v1.ListWorkspacesRequest.
+ * Use `create(ListWorkspacesRequestSchema)` to create a new message.
+ */
+export const ListWorkspacesRequestSchema: GenMessage<ListWorkspacesRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 73);
+
+/**
+ * @generated from message todo.v1.ListWorkspacesResponse
+ */
+export type ListWorkspacesResponse = Message<"todo.v1.ListWorkspacesResponse"> & {
+  /**
+   * @generated from field: repeated todo.v1.Workspace workspaces = 1;
+   */
+  workspaces: Workspace[];
+};
+
+/**
+ * Describes the message todo.v1.ListWorkspacesResponse.
+ * Use `create(ListWorkspacesResponseSchema)` to create a new message.
+ */
+export const ListWorkspacesResponseSchema: GenMessage<ListWorkspacesResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 74);
+
+/**
+ * @generated from message todo.v1.DeleteWorkspaceRequest
+ */
+export type DeleteWorkspaceRequest = Message<"todo.v1.DeleteWorkspaceRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message todo.v1.DeleteWorkspaceRequest.
+ * Use `create(DeleteWorkspaceRequestSchema)` to create a new message.
+ */
+export const DeleteWorkspaceRequestSchema: GenMessage<DeleteWorkspaceRequest> = /*@__PURE__*/
+  messageDesc(file_todo, 75);
+
+/**
+ * @generated from message todo.v1.DeleteWorkspaceResponse
+ */
+export type DeleteWorkspaceResponse = Message<"todo.v1.DeleteWorkspaceResponse"> & {
+};
+
+/**
+ * Describes the message todo.v1.DeleteWorkspaceResponse.
+ * Use `create(DeleteWorkspaceResponseSchema)` to create a new message.
+ */
+export const DeleteWorkspaceResponseSchema: GenMessage<DeleteWorkspaceResponse> = /*@__PURE__*/
+  messageDesc(file_todo, 76);
+
+/**
+ * TaskFormat is a file format for exporting and importing tasks. Each format
+ * carries only some of a task's fields; imported tasks always get new IDs
+ * and creation times, and IDs, lists, subtasks and blockers are not carried
+ * over.
+ *
+ * @generated from enum todo.v1.TaskFormat
+ */
+export enum TaskFormat {
+  /**
+   * Same as TASK_FORMAT_JSON.
+   *
+   * @generated from enum value: TASK_FORMAT_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * A JSON array of Task objects in the protobuf JSON mapping. Text,
+   * completion, due time, priority, tags and recurrence are imported.
+   *
+   * @generated from enum value: TASK_FORMAT_JSON = 1;
+   */
+  JSON = 1,
+
+  /**
+   * CSV with a header row. The columns are text, completed (true or false),
+   * completed_at and due_at (RFC 3339, empty for none), priority (P0 to P3,
+   * empty for none), tags (separated by spaces) and recurrence. Only text is
+   * required on import; unknown columns are ignored.
+   *
+   * @generated from enum value: TASK_FORMAT_CSV = 2;
+   */
+  CSV = 2,
+
+  /**
+   * A Markdown checklist, one "- [ ] text" or "- [x] text" item per task.
+   * Blank lines and headings are skipped on import.
+   *
+   * @generated from enum value: TASK_FORMAT_MARKDOWN = 3;
+   */
+  MARKDOWN = 3,
+
+  /**
+   * The todo.txt format: "x" and the completion date for done tasks,
+   * priorities (A) to (D) for P0 to P3, "+tag" for each tag and "due:" with
+   * the due date in the server's time zone. Recurrence is not carried.
+   *
+   * @generated from enum value: TASK_FORMAT_TODO_TXT = 4;
+   */
+  TODO_TXT = 4,
+}
+
+/**
+ * Describes the enum todo.v1.TaskFormat.
+ */
+export const TaskFormatSchema: GenEnum<TaskFormat> = /*@__PURE__*/
+  enumDesc(file_todo, 0);
+
+/**
+ * What a user may do with a list and the tasks in it. Each role allows
+ * everything the ones before it do.
+ *
+ * @generated from enum todo.v1.ListRole
+ */
+export enum ListRole {
+  /**
+   * @generated from enum value: LIST_ROLE_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * May read the list and its tasks.
+   *
+   * @generated from enum value: LIST_ROLE_VIEWER = 1;
+   */
+  VIEWER = 1,
+
+  /**
+   * May also add, change, move and delete tasks in the list.
+   *
+   * @generated from enum value: LIST_ROLE_EDITOR = 2;
+   */
+  EDITOR = 2,
+
+  /**
+   * May also rename, share and delete the list and purge its tasks.
+   *
+   * @generated from enum value: LIST_ROLE_OWNER = 3;
+   */
+  OWNER = 3,
+}
+
+/**
+ * Describes the enum todo.v1.ListRole.
+ */
+export const ListRoleSchema: GenEnum<ListRole> = /*@__PURE__*/
+  enumDesc(file_todo, 1);
+
+/**
+ * @generated from enum todo.v1.TaskStatus
+ */
+export enum TaskStatus {
+  /**
+   * @generated from enum value: TASK_STATUS_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: TASK_STATUS_OPEN = 1;
+   */
+  OPEN = 1,
+
+  /**
+   * @generated from enum value: TASK_STATUS_COMPLETED = 2;
+   */
+  COMPLETED = 2,
+}
+
+/**
+ * Describes the enum todo.v1.TaskStatus.
+ */
+export const TaskStatusSchema: GenEnum<TaskStatus> = /*@__PURE__*/
+  enumDesc(file_todo, 2);
+
+/**
+ * @generated from enum todo.v1.TaskOrder
+ */
+export enum TaskOrder {
+  /**
+   * @generated from enum value: TASK_ORDER_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: TASK_ORDER_NEWEST_FIRST = 1;
+   */
+  NEWEST_FIRST = 1,
+
+  /**
+   * @generated from enum value: TASK_ORDER_OLDEST_FIRST = 2;
+   */
+  OLDEST_FIRST = 2,
+
+  /**
+   * Case-insensitive by text.
+   *
+   * @generated from enum value: TASK_ORDER_ALPHABETICAL = 3;
+   */
+  ALPHABETICAL = 3,
+
+  /**
+   * @generated from enum value: TASK_ORDER_ID = 4;
+   */
+  ID = 4,
+
+  /**
+   * Manual order, as arranged with MoveTask.
+   *
+   * @generated from enum value: TASK_ORDER_POSITION = 5;
+   */
+  POSITION = 5,
+
+  /**
+   * Most urgent first, tasks without a priority last; ties keep their
+   * manual order.
+   *
+   * @generated from enum value: TASK_ORDER_PRIORITY = 6;
+   */
+  PRIORITY = 6,
+}
+
+/**
+ * Describes the enum todo.v1.TaskOrder.
+ */
+export const TaskOrderSchema: GenEnum<TaskOrder> = /*@__PURE__*/
+  enumDesc(file_todo, 3);
+
+/**
+ * @generated from enum todo.v1.Priority
+ */
+export enum Priority {
+  /**
+   * @generated from enum value: PRIORITY_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Most urgent.
+   *
+   * @generated from enum value: PRIORITY_P0 = 1;
+   */
+  P0 = 1,
+
+  /**
+   * @generated from enum value: PRIORITY_P1 = 2;
+   */
+  P1 = 2,
+
+  /**
+   * @generated from enum value: PRIORITY_P2 = 3;
+   */
+  P2 = 3,
+
+  /**
+   * @generated from enum value: PRIORITY_P3 = 4;
+   */
+  P3 = 4,
+}
+
+/**
+ * Describes the enum todo.v1.Priority.
+ */
+export const PrioritySchema: GenEnum<Priority> = /*@__PURE__*/
+  enumDesc(file_todo, 4);
+
+/**
+ * @generated from enum todo.v1.DueFilter
+ */
+export enum DueFilter {
+  /**
+   * @generated from enum value: DUE_FILTER_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Open tasks whose due time has passed.
+   *
+   * @generated from enum value: DUE_FILTER_OVERDUE = 1;
+   */
+  OVERDUE = 1,
+
+  /**
+   * Tasks due at any time today.
+   *
+   * @generated from enum value: DUE_FILTER_DUE_TODAY = 2;
+   */
+  DUE_TODAY = 2,
+
+  /**
+   * Tasks due from now until due_within_seconds from now.
+   *
+   * @generated from enum value: DUE_FILTER_DUE_WITHIN = 3;
+   */
+  DUE_WITHIN = 3,
+}
+
+/**
+ * Describes the enum todo.v1.DueFilter.
+ */
+export const DueFilterSchema: GenEnum<DueFilter> = /*@__PURE__*/
+  enumDesc(file_todo, 5);
+
+/**
+ * @generated from enum todo.v1.ListDeletePolicy
+ */
+export enum ListDeletePolicy {
+  /**
+   * Refuses to delete a list that still has tasks.
+   *
+   * @generated from enum value: LIST_DELETE_POLICY_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Deletes the list's tasks along with it.
+   *
+   * @generated from enum value: LIST_DELETE_POLICY_CASCADE = 1;
+   */
+  CASCADE = 1,
+
+  /**
+   * Moves the list's tasks to move_to_list_id.
+   *
+   * @generated from enum value: LIST_DELETE_POLICY_MOVE = 2;
+   */
+  MOVE = 2,
+}
+
+/**
+ * Describes the enum todo.v1.ListDeletePolicy.
+ */
+export const ListDeletePolicySchema: GenEnum<ListDeletePolicy> = /*@__PURE__*/
+  enumDesc(file_todo, 6);
+
+/**
+ * @generated from enum todo.v1.TaskView
+ */
+export enum TaskView {
+  /**
+   * @generated from enum value: TASK_VIEW_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Every matching task in GetTasksResponse.tasks.
+   *
+   * @generated from enum value: TASK_VIEW_FLAT = 1;
+   */
+  FLAT = 1,
+
+  /**
+   * Matching tasks nested under their parents in GetTasksResponse.tree.
+   *
+   * @generated from enum value: TASK_VIEW_TREE = 2;
+   */
+  TREE = 2,
+}
+
+/**
+ * Describes the enum todo.v1.TaskView.
+ */
+export const TaskViewSchema: GenEnum<TaskView> = /*@__PURE__*/
+  enumDesc(file_todo, 7);
+
+/**
+ * @generated from enum todo.v1.SubtaskDeletePolicy
+ */
+export enum SubtaskDeletePolicy {
+  /**
+   * Refuses to delete a task that still has subtasks.
+   *
+   * @generated from enum value: SUBTASK_DELETE_POLICY_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Deletes every subtask, however deeply nested, along with the task.
+   *
+   * @generated from enum value: SUBTASK_DELETE_POLICY_CASCADE = 1;
+   */
+  CASCADE = 1,
+
+  /**
+   * Moves the task's direct subtasks up to the task's own parent.
+   *
+   * @generated from enum value: SUBTASK_DELETE_POLICY_PROMOTE = 2;
+   */
+  PROMOTE = 2,
+}
+
+/**
+ * Describes the enum todo.v1.SubtaskDeletePolicy.
+ */
+export const SubtaskDeletePolicySchema: GenEnum<SubtaskDeletePolicy> = /*@__PURE__*/
+  enumDesc(file_todo, 8);
+
+/**
+ * @generated from enum todo.v1.TaskEventType
+ */
+export enum TaskEventType {
+  /**
+   * @generated from enum value: TASK_EVENT_TYPE_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: TASK_EVENT_TYPE_ADDED = 1;
+   */
+  ADDED = 1,
+
+  /**
+   * @generated from enum value: TASK_EVENT_TYPE_UPDATED = 2;
+   */
+  UPDATED = 2,
+
+  /**
+   * @generated from enum value: TASK_EVENT_TYPE_DELETED = 3;
+   */
+  DELETED = 3,
+
+  /**
+   * Reminder that an open task has just come due.
+   *
+   * @generated from enum value: TASK_EVENT_TYPE_DUE = 4;
+   */
+  DUE = 4,
+}
+
+/**
+ * Describes the enum todo.v1.TaskEventType.
+ */
+export const TaskEventTypeSchema: GenEnum<TaskEventType> = /*@__PURE__*/
+  enumDesc(file_todo, 9);
+
+/**
+ * @generated from enum todo.v1.HistoryEventType
+ */
+export enum HistoryEventType {
+  /**
+   * @generated from enum value: HISTORY_EVENT_TYPE_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: HISTORY_EVENT_TYPE_CREATED = 1;
+   */
+  CREATED = 1,
+
+  /**
+   * @generated from enum value: HISTORY_EVENT_TYPE_UPDATED = 2;
+   */
+  UPDATED = 2,
+
+  /**
+   * Moved to the trash.
+   *
+   * @generated from enum value: HISTORY_EVENT_TYPE_DELETED = 3;
+   */
+  DELETED = 3,
+
+  /**
+   * Brought back from the trash.
+   *
+   * @generated from enum value: HISTORY_EVENT_TYPE_RESTORED = 4;
+   */
+  RESTORED = 4,
+
+  /**
+   * Removed for good: purged from the trash, or an undone AddTask.
+   *
+   * @generated from enum value: HISTORY_EVENT_TYPE_PURGED = 5;
+   */
+  PURGED = 5,
+}
+
+/**
+ * Describes the enum todo.v1.HistoryEventType.
+ */
+export const HistoryEventTypeSchema: GenEnum<HistoryEventType> = /*@__PURE__*/
+  enumDesc(file_todo, 10);
+
+/**
+ * @generated from service todo.v1.TodoService
+ */
+export const TodoService: GenService<{
+  /**
+   * @generated from rpc todo.v1.TodoService.AddTask
+   */
+  addTask: {
+    methodKind: "unary";
+    input: typeof AddTaskRequestSchema;
+    output: typeof AddTaskResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.GetTasks
+   */
+  getTasks: {
+    methodKind: "unary";
+    input: typeof GetTasksRequestSchema;
+    output: typeof GetTasksResponseSchema;
+  },
+  /**
+   * Moves a task to the trash, from which it can be restored until it is
+   * purged.
+   *
+   * @generated from rpc todo.v1.TodoService.DeleteTask
+   */
+  deleteTask: {
+    methodKind: "unary";
+    input: typeof DeleteTaskRequestSchema;
+    output: typeof DeleteTaskResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.UpdateTask
+   */
+  updateTask: {
+    methodKind: "unary";
+    input: typeof UpdateTaskRequestSchema;
+    output: typeof UpdateTaskResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.CompleteTask
+   */
+  completeTask: {
+    methodKind: "unary";
+    input: typeof CompleteTaskRequestSchema;
+    output: typeof CompleteTaskResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.ReopenTask
+   */
+  reopenTask: {
+    methodKind: "unary";
+    input: typeof ReopenTaskRequestSchema;
+    output: typeof ReopenTaskResponseSchema;
+  },
+  /**
+   * Streams an event for every task change made after the call starts.
+   *
+   * @generated from rpc todo.v1.TodoService.WatchTasks
+   */
+  watchTasks: {
+    methodKind: "server_streaming";
+    input: typeof WatchTasksRequestSchema;
+    output: typeof WatchTasksResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.CreateList
+   */
+  createList: {
+    methodKind: "unary";
+    input: typeof CreateListRequestSchema;
+    output: typeof CreateListResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.GetLists
+   */
+  getLists: {
+    methodKind: "unary";
+    input: typeof GetListsRequestSchema;
+    output: typeof GetListsResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.RenameList
+   */
+  renameList: {
+    methodKind: "unary";
+    input: typeof RenameListRequestSchema;
+    output: typeof RenameListResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.DeleteList
+   */
+  deleteList: {
+    methodKind: "unary";
+    input: typeof DeleteListRequestSchema;
+    output: typeof DeleteListResponseSchema;
+  },
+  /**
+   * Gives a user a role on a list, or changes the role they have. Only the
+   * list's owners may share it.
+   *
+   * @generated from rpc todo.v1.TodoService.ShareList
+   */
+  shareList: {
+    methodKind: "unary";
+    input: typeof ShareListRequestSchema;
+    output: typeof ShareListResponseSchema;
+  },
+  /**
+   * Takes a user's role on a list away. Owners may revoke anyone's access;
+   * other members only their own.
+   *
+   * @generated from rpc todo.v1.TodoService.RevokeListAccess
+   */
+  revokeListAccess: {
+    methodKind: "unary";
+    input: typeof RevokeListAccessRequestSchema;
+    output: typeof RevokeListAccessResponseSchema;
+  },
+  /**
+   * Places a task directly before or after another in the manual order.
+   *
+   * @generated from rpc todo.v1.TodoService.MoveTask
+   */
+  moveTask: {
+    methodKind: "unary";
+    input: typeof MoveTaskRequestSchema;
+    output: typeof MoveTaskResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.AddTags
+   */
+  addTags: {
+    methodKind: "unary";
+    input: typeof AddTagsRequestSchema;
+    output: typeof AddTagsResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.RemoveTags
+   */
+  removeTags: {
+    methodKind: "unary";
+    input: typeof RemoveTagsRequestSchema;
+    output: typeof RemoveTagsResponseSchema;
+  },
+  /**
+   * Lists the tags in use on the caller's tasks with how many tasks carry
+   * each.
+   *
+   * @generated from rpc todo.v1.TodoService.ListTags
+   */
+  listTags: {
+    methodKind: "unary";
+    input: typeof ListTagsRequestSchema;
+    output: typeof ListTagsResponseSchema;
+  },
+  /**
+   * Records that a task cannot start until another one is completed.
+   *
+   * @generated from rpc todo.v1.TodoService.AddBlocker
+   */
+  addBlocker: {
+    methodKind: "unary";
+    input: typeof AddBlockerRequestSchema;
+    output: typeof AddBlockerResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.RemoveBlocker
+   */
+  removeBlocker: {
+    methodKind: "unary";
+    input: typeof RemoveBlockerRequestSchema;
+    output: typeof RemoveBlockerResponseSchema;
+  },
+  /**
+   * Lists the caller's trashed tasks, most recently deleted first.
+   *
+   * @generated from rpc todo.v1.TodoService.ListTrash
+   */
+  listTrash: {
+    methodKind: "unary";
+    input: typeof ListTrashRequestSchema;
+    output: typeof ListTrashResponseSchema;
+  },
+  /**
+   * Brings a trashed task back, along with the subtasks trashed with it.
+   *
+   * @generated from rpc todo.v1.TodoService.RestoreTask
+   */
+  restoreTask: {
+    methodKind: "unary";
+    input: typeof RestoreTaskRequestSchema;
+    output: typeof RestoreTaskResponseSchema;
+  },
+  /**
+   * Permanently removes a trashed task.
+   *
+   * @generated from rpc todo.v1.TodoService.PurgeTask
+   */
+  purgeTask: {
+    methodKind: "unary";
+    input: typeof PurgeTaskRequestSchema;
+    output: typeof PurgeTaskResponseSchema;
+  },
+  /**
+   * Takes back the caller's most recent AddTask, DeleteTask, change to a
+   * task or batch. Fails with FAILED_PRECONDITION if there is nothing left to undo or
+   * a task the operation changed has changed again since; in the latter case
+   * the operation is forgotten.
+   *
+   * @generated from rpc todo.v1.TodoService.Undo
+   */
+  undo: {
+    methodKind: "unary";
+    input: typeof UndoRequestSchema;
+    output: typeof UndoResponseSchema;
+  },
+  /**
+   * The batch RPCs apply every request in order or none of them. If any
+   * request fails, the error carries a BatchItemError detail for it and the
+   * requests before it are rolled back. A batch is undone as one operation.
+   *
+   * @generated from rpc todo.v1.TodoService.BatchAddTasks
+   */
+  batchAddTasks: {
+    methodKind: "unary";
+    input: typeof BatchAddTasksRequestSchema;
+    output: typeof BatchAddTasksResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.BatchUpdateTasks
+   */
+  batchUpdateTasks: {
+    methodKind: "unary";
+    input: typeof BatchUpdateTasksRequestSchema;
+    output: typeof BatchUpdateTasksResponseSchema;
+  },
+  /**
+   * @generated from rpc todo.v1.TodoService.BatchDeleteTasks
+   */
+  batchDeleteTasks: {
+    methodKind: "unary";
+    input: typeof BatchDeleteTasksRequestSchema;
+    output: typeof BatchDeleteTasksResponseSchema;
+  },
+  /**
+   * Serializes the caller's tasks, in manual order, in one of the
+   * TaskFormats.
+   *
+   * @generated from rpc todo.v1.TodoService.ExportTasks
+   */
+  exportTasks: {
+    methodKind: "unary";
+    input: typeof ExportTasksRequestSchema;
+    output: typeof ExportTasksResponseSchema;
+  },
+  /**
+   * Adds the tasks described by a file in one of the TaskFormats, all of them
+   * or none: if any line is invalid the error carries an ImportLineError
+   * detail for each invalid line. An import is undone as one operation.
+   *
+   * @generated from rpc todo.v1.TodoService.ImportTasks
+   */
+  importTasks: {
+    methodKind: "unary";
+    input: typeof ImportTasksRequestSchema;
+    output: typeof ImportTasksResponseSchema;
+  },
+  /**
+   * Returns every recorded change to one of the caller's tasks, oldest
+   * first. Once the task is purged only the purge event is left.
+   *
+   * @generated from rpc todo.v1.TodoService.GetTaskHistory
+   */
+  getTaskHistory: {
+    methodKind: "unary";
+    input: typeof GetTaskHistoryRequestSchema;
+    output: typeof GetTaskHistoryResponseSchema;
+  },
+  /**
+   * Returns the recorded changes to all of the caller's tasks in the order
+   * they were made, optionally only those made in a time range.
+   *
+   * @generated from rpc todo.v1.TodoService.ListEvents
+   */
+  listEvents: {
+    methodKind: "unary";
+    input: typeof ListEventsRequestSchema;
+    output: typeof ListEventsResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_todo, 0);
+
+/**
+ * WorkspaceService manages workspaces, each an isolated set of tasks and
+ * lists with its own limits. TodoService calls act on the workspace named
+ * by the X-Workspace header, or the one the caller's API key is bound to,
+ * and on the default workspace if neither names one. With authentication
+ * enabled only admin keys may call this service.
+ *
+ * @generated from service todo.v1.WorkspaceService
+ */
+export const WorkspaceService: GenService<{
+  /**
+   * @generated from rpc todo.v1.WorkspaceService.CreateWorkspace
+   */
+  createWorkspace: {
+    methodKind: "unary";
+    input: typeof CreateWorkspaceRequestSchema;
+    output: typeof CreateWorkspaceResponseSchema;
+  },
+  /**
+   * Lists every workspace, the default one first and then by ID.
+   *
+   * @generated from rpc todo.v1.WorkspaceService.ListWorkspaces
+   */
+  listWorkspaces: {
+    methodKind: "unary";
+    input: typeof ListWorkspacesRequestSchema;
+    output: typeof ListWorkspacesResponseSchema;
+  },
+  /**
+   * Deletes a workspace along with all of its tasks and lists. The default
+   * workspace cannot be deleted.
+   *
+   * @generated from rpc todo.v1.WorkspaceService.DeleteWorkspace
+   */
+  deleteWorkspace: {
+    methodKind: "unary";
+    input: typeof DeleteWorkspaceRequestSchema;
+    output: typeof DeleteWorkspaceResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_todo, 1);
 